@@ -5,6 +5,7 @@ package admin
 
 import (
 	"context"
+	"time"
 
 	"github.com/flare-foundation/flare/api"
 	"github.com/flare-foundation/flare/ids"
@@ -25,6 +26,19 @@ type Client interface {
 	GetChainAliases(ctx context.Context, chainID string) ([]string, error)
 	Stacktrace(context.Context) (bool, error)
 	LoadVMs(context.Context) (map[ids.ID][]string, map[ids.ID]string, error)
+	SnapshotDatabase(ctx context.Context, path string) (*SnapshotDatabaseReply, error)
+	InspectDatabase(ctx context.Context) (*InspectDatabaseReply, error)
+	DisconnectPeer(ctx context.Context, nodeID string) (bool, error)
+	BanPeer(ctx context.Context, nodeID string, ip string, duration time.Duration, reason string) (bool, error)
+	UnbanPeer(ctx context.Context, nodeID string, ip string) (bool, error)
+	ListBans(ctx context.Context) ([]APIBan, error)
+	TrackPeer(ctx context.Context, nodeID string, ip string) (bool, error)
+	ReloadConfig(ctx context.Context) (*ReloadConfigReply, error)
+	SetFault(ctx context.Context, args *SetFaultArgs) (bool, error)
+	RemoveFault(ctx context.Context, nodeID string) (bool, error)
+	Partition(ctx context.Context, nodeIDs []string) (bool, error)
+	Heal(ctx context.Context, nodeIDs []string) (bool, error)
+	ListFaults(ctx context.Context) ([]APIFault, error)
 }
 
 // Client implementation for the Avalanche Platform Info API Endpoint
@@ -100,3 +114,108 @@ func (c *client) LoadVMs(ctx context.Context) (map[ids.ID][]string, map[ids.ID]s
 	err := c.requester.SendRequest(ctx, "loadVMs", struct{}{}, res)
 	return res.NewVMs, res.FailedVMs, err
 }
+
+func (c *client) SnapshotDatabase(ctx context.Context, path string) (*SnapshotDatabaseReply, error) {
+	res := &SnapshotDatabaseReply{}
+	err := c.requester.SendRequest(ctx, "snapshotDatabase", &SnapshotDatabaseArgs{
+		Path: path,
+	}, res)
+	return res, err
+}
+
+func (c *client) InspectDatabase(ctx context.Context) (*InspectDatabaseReply, error) {
+	res := &InspectDatabaseReply{}
+	err := c.requester.SendRequest(ctx, "inspectDatabase", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) DisconnectPeer(ctx context.Context, nodeID string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "disconnectPeer", &DisconnectPeerArgs{
+		NodeID: nodeID,
+	}, res)
+	return res.Success, err
+}
+
+// BanPeer bans [nodeID] or [ip]. If [duration] is 0, the ban doesn't expire.
+func (c *client) BanPeer(ctx context.Context, nodeID string, ip string, duration time.Duration, reason string) (bool, error) {
+	args := &BanPeerArgs{
+		BanTargetArgs: BanTargetArgs{
+			NodeID: nodeID,
+			IP:     ip,
+		},
+		Reason: reason,
+	}
+	if duration != 0 {
+		args.Duration = duration.String()
+	}
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "banPeer", args, res)
+	return res.Success, err
+}
+
+func (c *client) UnbanPeer(ctx context.Context, nodeID string, ip string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "unbanPeer", &BanTargetArgs{
+		NodeID: nodeID,
+		IP:     ip,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) ListBans(ctx context.Context) ([]APIBan, error) {
+	res := &ListBansReply{}
+	err := c.requester.SendRequest(ctx, "listBans", struct{}{}, res)
+	return res.Bans, err
+}
+
+func (c *client) TrackPeer(ctx context.Context, nodeID string, ip string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "trackPeer", &TrackPeerArgs{
+		NodeID: nodeID,
+		IP:     ip,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) ReloadConfig(ctx context.Context) (*ReloadConfigReply, error) {
+	res := &ReloadConfigReply{}
+	err := c.requester.SendRequest(ctx, "reloadConfig", struct{}{}, res)
+	return res, err
+}
+
+func (c *client) SetFault(ctx context.Context, args *SetFaultArgs) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "setFault", args, res)
+	return res.Success, err
+}
+
+func (c *client) RemoveFault(ctx context.Context, nodeID string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "removeFault", &RemoveFaultArgs{
+		NodeID: nodeID,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) Partition(ctx context.Context, nodeIDs []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "partition", &PartitionArgs{
+		NodeIDs: nodeIDs,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) Heal(ctx context.Context, nodeIDs []string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest(ctx, "heal", &PartitionArgs{
+		NodeIDs: nodeIDs,
+	}, res)
+	return res.Success, err
+}
+
+func (c *client) ListFaults(ctx context.Context) ([]APIFault, error) {
+	res := &ListFaultsReply{}
+	err := c.requester.SendRequest(ctx, "listFaults", struct{}{}, res)
+	return res.Faults, err
+}
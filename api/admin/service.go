@@ -5,15 +5,26 @@ package admin
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/flare-foundation/flare/api"
 	"github.com/flare-foundation/flare/api/server"
 	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/snapshot"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/network/fault"
 	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/utils"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/perms"
@@ -34,6 +45,16 @@ const (
 var (
 	errAliasTooLong = errors.New("alias length is too long")
 	errNoLogLevel   = errors.New("need to specify either displayLevel or logLevel")
+
+	errNoSnapshotPath = errors.New("need to specify the path of the snapshot")
+
+	errNotConnected = errors.New("not connected to the node")
+	errNotBanned    = errors.New("not banned")
+	errNoBanTarget  = errors.New("need to specify exactly one of nodeID and ip")
+	errInvalidIP    = errors.New("invalid ip")
+	errBanDuration  = errors.New("ban duration must be positive")
+
+	errFaultInjectionDisabled = errors.New("fault injection isn't enabled")
 )
 
 type Config struct {
@@ -45,6 +66,17 @@ type Config struct {
 	HTTPServer   server.PathAdderWithReadLock
 	VMRegistry   registry.VMRegistry
 	VMManager    vms.Manager
+	DBManager    manager.Manager
+	Network      network.Network
+	// FaultInjector injects faults in the connections with the peers. Nil if
+	// fault injection isn't enabled.
+	FaultInjector fault.Injector
+	// DatabaseLayout returns the layout of a database of [DBManager]
+	DatabaseLayout func(database.Database) (*inspect.Layout, error)
+	// ReloadConfig re-reads the node's config and applies the changes that
+	// don't require a restart. Returns the changed keys that were applied and
+	// the ones that require a restart.
+	ReloadConfig func() (reloaded []string, requireRestart []string, err error)
 }
 
 // Admin is the API service for node admin management
@@ -301,3 +333,491 @@ func (service *Admin) LoadVMs(_ *http.Request, _ *struct{}, reply *LoadVMsReply)
 	reply.NewVMs, err = ids.GetRelevantAliases(service.VMManager, loadedVMs)
 	return err
 }
+
+// SnapshotDatabaseArgs are the arguments for calling SnapshotDatabase
+type SnapshotDatabaseArgs struct {
+	// Path of the archive to create. Must not exist yet.
+	Path string `json:"path"`
+}
+
+// APIDatabaseSnapshot describes the snapshot of one database version
+type APIDatabaseSnapshot struct {
+	Version  string       `json:"version"`
+	NumKeys  cjson.Uint64 `json:"numKeys"`
+	NumBytes cjson.Uint64 `json:"numBytes"`
+	Checksum ids.ID       `json:"checksum"`
+}
+
+// SnapshotDatabaseReply are the results from calling SnapshotDatabase
+type SnapshotDatabaseReply struct {
+	Path      string                `json:"path"`
+	Timestamp time.Time             `json:"timestamp"`
+	Checksum  ids.ID                `json:"checksum"`
+	Databases []APIDatabaseSnapshot `json:"databases"`
+}
+
+// SnapshotDatabase writes a point-in-time snapshot of the node's databases to
+// a new archive at [args.Path], from which a database directory can be
+// restored while the node is stopped.
+func (service *Admin) SnapshotDatabase(_ *http.Request, args *SnapshotDatabaseArgs, reply *SnapshotDatabaseReply) error {
+	service.Log.Debug("Admin: SnapshotDatabase called with Path: %s", args.Path)
+
+	if args.Path == "" {
+		return errNoSnapshotPath
+	}
+	summary, err := snapshot.WriteFile(args.Path, service.DBManager.GetDatabases())
+	if err != nil {
+		return err
+	}
+	service.Log.Info("wrote database snapshot %s to %s", summary.Checksum, args.Path)
+
+	reply.Path = args.Path
+	reply.Timestamp = summary.Timestamp
+	reply.Checksum = summary.Checksum
+	reply.Databases = make([]APIDatabaseSnapshot, len(summary.Databases))
+	for i, db := range summary.Databases {
+		reply.Databases[i] = APIDatabaseSnapshot{
+			Version:  db.Version.String(),
+			NumKeys:  cjson.Uint64(db.NumKeys),
+			NumBytes: cjson.Uint64(db.NumBytes),
+			Checksum: db.Checksum,
+		}
+	}
+	return nil
+}
+
+// APIKeyStats are the stats of a set of keys
+type APIKeyStats struct {
+	NumKeys    cjson.Uint64 `json:"numKeys"`
+	KeyBytes   cjson.Uint64 `json:"keyBytes"`
+	ValueBytes cjson.Uint64 `json:"valueBytes"`
+}
+
+// APIChainKeyStats are the stats of the keys of a chain
+type APIChainKeyStats struct {
+	Chain string `json:"chain"`
+	APIKeyStats
+}
+
+// APIPrefixKeyStats are the stats of the keys written under a prefix
+type APIPrefixKeyStats struct {
+	Chain string `json:"chain,omitempty"`
+	Name  string `json:"name"`
+	APIKeyStats
+}
+
+// APIDatabaseInspection describes the keys of one database version
+type APIDatabaseInspection struct {
+	Version  string              `json:"version"`
+	Total    APIKeyStats         `json:"total"`
+	Chains   []APIChainKeyStats  `json:"chains"`
+	Prefixes []APIPrefixKeyStats `json:"prefixes"`
+	Unknown  APIKeyStats         `json:"unknown"`
+}
+
+// InspectDatabaseReply are the results from calling InspectDatabase
+type InspectDatabaseReply struct {
+	Databases []APIDatabaseInspection `json:"databases"`
+}
+
+// InspectDatabase reports the number and size of the keys of the node's
+// databases by chain and by the prefix they were written under. It iterates
+// over every key, so it can take a long time on large databases.
+func (service *Admin) InspectDatabase(_ *http.Request, _ *struct{}, reply *InspectDatabaseReply) error {
+	service.Log.Debug("Admin: InspectDatabase called")
+
+	dbs := service.DBManager.GetDatabases()
+	reply.Databases = make([]APIDatabaseInspection, len(dbs))
+	for i, db := range dbs {
+		layout, err := service.DatabaseLayout(db.Database)
+		if err != nil {
+			return err
+		}
+		report, err := inspect.Inspect(db.Database, layout)
+		if err != nil {
+			return err
+		}
+
+		inspection := APIDatabaseInspection{
+			Version:  db.Version.String(),
+			Total:    newAPIKeyStats(report.Total),
+			Chains:   make([]APIChainKeyStats, len(report.Chains)),
+			Prefixes: make([]APIPrefixKeyStats, len(report.Prefixes)),
+			Unknown:  newAPIKeyStats(report.Unknown),
+		}
+		for j, chain := range report.Chains {
+			inspection.Chains[j] = APIChainKeyStats{
+				Chain:       chain.Chain,
+				APIKeyStats: newAPIKeyStats(chain.Stats),
+			}
+		}
+		for j, prefix := range report.Prefixes {
+			inspection.Prefixes[j] = APIPrefixKeyStats{
+				Chain:       prefix.Chain,
+				Name:        prefix.Name,
+				APIKeyStats: newAPIKeyStats(prefix.Stats),
+			}
+		}
+		reply.Databases[i] = inspection
+	}
+	return nil
+}
+
+func newAPIKeyStats(stats inspect.Stats) APIKeyStats {
+	return APIKeyStats{
+		NumKeys:    cjson.Uint64(stats.NumKeys),
+		KeyBytes:   cjson.Uint64(stats.KeyBytes),
+		ValueBytes: cjson.Uint64(stats.ValueBytes),
+	}
+}
+
+// DisconnectPeerArgs are the arguments for calling DisconnectPeer
+type DisconnectPeerArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// DisconnectPeer closes the connection to a peer. If the node wants a
+// connection to the peer, for example because it is a validator, it will
+// attempt to reconnect. Use BanPeer to keep the peer disconnected.
+func (service *Admin) DisconnectPeer(_ *http.Request, args *DisconnectPeerArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: DisconnectPeer called with NodeID: %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	if !service.Network.Disconnect(nodeID) {
+		return fmt.Errorf("%w %s", errNotConnected, args.NodeID)
+	}
+	reply.Success = true
+	return nil
+}
+
+// BanTargetArgs identify a banned node ID or IP. Exactly one of them must be
+// set.
+type BanTargetArgs struct {
+	NodeID string `json:"nodeID"`
+	IP     string `json:"ip"`
+}
+
+func (args *BanTargetArgs) parse() (ids.ShortID, net.IP, error) {
+	switch {
+	case args.NodeID == "" && args.IP == "":
+		return ids.ShortEmpty, nil, errNoBanTarget
+	case args.IP == "":
+		nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+		return nodeID, nil, err
+	case args.NodeID == "":
+		ip := net.ParseIP(args.IP)
+		if ip == nil {
+			return ids.ShortEmpty, nil, fmt.Errorf("%w %q", errInvalidIP, args.IP)
+		}
+		return ids.ShortEmpty, ip, nil
+	default:
+		return ids.ShortEmpty, nil, errNoBanTarget
+	}
+}
+
+// BanPeerArgs are the arguments for calling BanPeer
+type BanPeerArgs struct {
+	BanTargetArgs
+	// Duration of the ban, such as "30m" or "24h". If empty, the ban doesn't
+	// expire.
+	Duration string `json:"duration"`
+	Reason   string `json:"reason"`
+}
+
+// BanPeer disconnects from a node ID, or from the peers at an IP, and refuses
+// to connect to it until the ban expires or is lifted with UnbanPeer. Bans
+// are kept across restarts.
+func (service *Admin) BanPeer(_ *http.Request, args *BanPeerArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: BanPeer called with NodeID: %s, IP: %s, Duration: %s", args.NodeID, args.IP, args.Duration)
+
+	nodeID, ip, err := args.parse()
+	if err != nil {
+		return err
+	}
+	ban := network.Ban{
+		NodeID: nodeID,
+		IP:     ip,
+		Reason: args.Reason,
+	}
+	var duration time.Duration
+	if args.Duration != "" {
+		duration, err = time.ParseDuration(args.Duration)
+		if err != nil {
+			return fmt.Errorf("couldn't parse duration: %w", err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("%w: %s", errBanDuration, args.Duration)
+		}
+	}
+	if err := service.Network.Ban(ban, duration); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnbanPeer lifts the ban of a node ID or of an IP
+func (service *Admin) UnbanPeer(_ *http.Request, args *BanTargetArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: UnbanPeer called with NodeID: %s, IP: %s", args.NodeID, args.IP)
+
+	nodeID, ip, err := args.parse()
+	if err != nil {
+		return err
+	}
+	unbanned, err := service.Network.Unban(nodeID, ip)
+	if err != nil {
+		return err
+	}
+	if !unbanned {
+		return errNotBanned
+	}
+	reply.Success = true
+	return nil
+}
+
+// APIBan describes the ban of a node ID or of an IP
+type APIBan struct {
+	NodeID string `json:"nodeID,omitempty"`
+	IP     string `json:"ip,omitempty"`
+	// Expiry is omitted if the ban doesn't expire
+	Expiry *time.Time `json:"expiry,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// ListBansReply are the results from calling ListBans
+type ListBansReply struct {
+	Bans []APIBan `json:"bans"`
+}
+
+// ListBans returns the bans that haven't expired
+func (service *Admin) ListBans(_ *http.Request, _ *struct{}, reply *ListBansReply) error {
+	service.Log.Debug("Admin: ListBans called")
+
+	bans := service.Network.Bans()
+	reply.Bans = make([]APIBan, len(bans))
+	for i, ban := range bans {
+		apiBan := APIBan{Reason: ban.Reason}
+		if ban.IP != nil {
+			apiBan.IP = ban.IP.String()
+		} else {
+			apiBan.NodeID = ban.NodeID.PrefixedString(constants.NodeIDPrefix)
+		}
+		if !ban.Expiry.IsZero() {
+			expiry := ban.Expiry
+			apiBan.Expiry = &expiry
+		}
+		reply.Bans[i] = apiBan
+	}
+	return nil
+}
+
+// TrackPeerArgs are the arguments for calling TrackPeer
+type TrackPeerArgs struct {
+	NodeID string `json:"nodeID"`
+	// IP and port the peer is reachable at, such as "1.2.3.4:9651"
+	IP string `json:"ip"`
+}
+
+// TrackPeer makes the node connect to a peer, and stay connected to it, even
+// if it isn't a validator
+func (service *Admin) TrackPeer(_ *http.Request, args *TrackPeerArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: TrackPeer called with NodeID: %s, IP: %s", args.NodeID, args.IP)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	ip, err := utils.ToIPDesc(args.IP)
+	if err != nil {
+		return err
+	}
+	service.Network.ManuallyTrack(nodeID, ip)
+	reply.Success = true
+	return nil
+}
+
+// ReloadConfigReply are the results from calling ReloadConfig
+type ReloadConfigReply struct {
+	// Keys changed since the node started that were applied
+	Reloaded []string `json:"reloaded"`
+	// Keys changed since the node started that are only applied on restart
+	RequireRestart []string `json:"requireRestart"`
+}
+
+// ReloadConfig re-reads the node's config file and flags, and applies the
+// changes that can be made while the node is running. If the config is
+// invalid, nothing is applied.
+func (service *Admin) ReloadConfig(_ *http.Request, _ *struct{}, reply *ReloadConfigReply) error {
+	service.Log.Debug("Admin: ReloadConfig called")
+
+	reloaded, requireRestart, err := service.Config.ReloadConfig()
+	if err != nil {
+		return err
+	}
+	reply.Reloaded = reloaded
+	reply.RequireRestart = requireRestart
+	return nil
+}
+
+// SetFaultArgs are the arguments for calling SetFault
+type SetFaultArgs struct {
+	NodeID string `json:"nodeID"`
+	// Delay of each message, such as "100ms"
+	Delay string `json:"delay"`
+	// Maximum random delay added to [Delay], such as "20ms"
+	Jitter string `json:"jitter"`
+	// Probability, in [0, 1], that a message is dropped
+	DropRate cjson.Float64 `json:"dropRate"`
+	// Maximum number of bytes sent per second in each direction. 0 means
+	// unlimited.
+	Bandwidth   cjson.Uint64 `json:"bandwidth"`
+	Partitioned bool         `json:"partitioned"`
+}
+
+// SetFault replaces the faults injected in the traffic with a peer. Only
+// available if fault injection is enabled, for testing.
+func (service *Admin) SetFault(_ *http.Request, args *SetFaultArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: SetFault called with NodeID: %s", args.NodeID)
+
+	if service.FaultInjector == nil {
+		return errFaultInjectionDisabled
+	}
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	f := fault.Fault{
+		DropRate:    float64(args.DropRate),
+		Bandwidth:   uint64(args.Bandwidth),
+		Partitioned: args.Partitioned,
+	}
+	if args.Delay != "" {
+		if f.Delay, err = time.ParseDuration(args.Delay); err != nil {
+			return fmt.Errorf("couldn't parse delay: %w", err)
+		}
+	}
+	if args.Jitter != "" {
+		if f.Jitter, err = time.ParseDuration(args.Jitter); err != nil {
+			return fmt.Errorf("couldn't parse jitter: %w", err)
+		}
+	}
+	if err := service.FaultInjector.SetFault(nodeID, f); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// RemoveFaultArgs are the arguments for calling RemoveFault
+type RemoveFaultArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// RemoveFault stops injecting faults in the traffic with a peer
+func (service *Admin) RemoveFault(_ *http.Request, args *RemoveFaultArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: RemoveFault called with NodeID: %s", args.NodeID)
+
+	if service.FaultInjector == nil {
+		return errFaultInjectionDisabled
+	}
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	service.FaultInjector.RemoveFault(nodeID)
+	reply.Success = true
+	return nil
+}
+
+// PartitionArgs are the arguments for calling Partition and Heal
+type PartitionArgs struct {
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+func (args *PartitionArgs) parse() ([]ids.ShortID, error) {
+	nodeIDs := make([]ids.ShortID, len(args.NodeIDs))
+	for i, nodeIDStr := range args.NodeIDs {
+		nodeID, err := ids.ShortFromPrefixedString(nodeIDStr, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		nodeIDs[i] = nodeID
+	}
+	return nodeIDs, nil
+}
+
+// Partition drops all the traffic with the peers, in both directions, until
+// they are healed
+func (service *Admin) Partition(_ *http.Request, args *PartitionArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: Partition called with NodeIDs: %s", args.NodeIDs)
+
+	if service.FaultInjector == nil {
+		return errFaultInjectionDisabled
+	}
+	nodeIDs, err := args.parse()
+	if err != nil {
+		return err
+	}
+	service.FaultInjector.Partition(nodeIDs...)
+	reply.Success = true
+	return nil
+}
+
+// Heal ends the partition from the peers
+func (service *Admin) Heal(_ *http.Request, args *PartitionArgs, reply *api.SuccessResponse) error {
+	service.Log.Debug("Admin: Heal called with NodeIDs: %s", args.NodeIDs)
+
+	if service.FaultInjector == nil {
+		return errFaultInjectionDisabled
+	}
+	nodeIDs, err := args.parse()
+	if err != nil {
+		return err
+	}
+	service.FaultInjector.Heal(nodeIDs...)
+	reply.Success = true
+	return nil
+}
+
+// APIFault describes the faults injected in the traffic with a peer
+type APIFault struct {
+	NodeID      string        `json:"nodeID"`
+	Delay       string        `json:"delay"`
+	Jitter      string        `json:"jitter"`
+	DropRate    cjson.Float64 `json:"dropRate"`
+	Bandwidth   cjson.Uint64  `json:"bandwidth"`
+	Partitioned bool          `json:"partitioned"`
+}
+
+// ListFaultsReply are the results from calling ListFaults
+type ListFaultsReply struct {
+	Faults []APIFault `json:"faults"`
+}
+
+// ListFaults returns the faults injected in the traffic with each peer
+func (service *Admin) ListFaults(_ *http.Request, _ *struct{}, reply *ListFaultsReply) error {
+	service.Log.Debug("Admin: ListFaults called")
+
+	if service.FaultInjector == nil {
+		return errFaultInjectionDisabled
+	}
+	faults := service.FaultInjector.Faults()
+	reply.Faults = make([]APIFault, 0, len(faults))
+	for nodeID, f := range faults {
+		reply.Faults = append(reply.Faults, APIFault{
+			NodeID:      nodeID.PrefixedString(constants.NodeIDPrefix),
+			Delay:       f.Delay.String(),
+			Jitter:      f.Jitter.String(),
+			DropRate:    cjson.Float64(f.DropRate),
+			Bandwidth:   cjson.Uint64(f.Bandwidth),
+			Partitioned: f.Partitioned,
+		})
+	}
+	sort.Slice(reply.Faults, func(i, j int) bool {
+		return reply.Faults[i].NodeID < reply.Faults[j].NodeID
+	})
+	return nil
+}
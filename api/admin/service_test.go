@@ -5,13 +5,27 @@ package admin
 
 import (
 	"errors"
+	"net"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/database/snapshot"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/network"
+	"github.com/flare-foundation/flare/network/fault"
+	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+	"github.com/flare-foundation/flare/version"
 	"github.com/flare-foundation/flare/vms"
 	"github.com/flare-foundation/flare/vms/registry"
 )
@@ -119,3 +133,205 @@ func TestLoadVMsGetAliasesFails(t *testing.T) {
 
 	assert.Equal(t, err, errOops)
 }
+
+func TestSnapshotDatabase(t *testing.T) {
+	assert := assert.New(t)
+
+	dbManager := manager.NewMemDB(version.NewDefaultVersion(1, 0, 0))
+	assert.NoError(dbManager.Current().Database.Put([]byte("key"), []byte("value")))
+	admin := &Admin{Config: Config{
+		Log:       logging.NoLog{},
+		DBManager: dbManager,
+	}}
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	reply := SnapshotDatabaseReply{}
+	assert.NoError(admin.SnapshotDatabase(nil, &SnapshotDatabaseArgs{Path: path}, &reply))
+	assert.Equal(path, reply.Path)
+	assert.Len(reply.Databases, 1)
+	assert.EqualValues(1, reply.Databases[0].NumKeys)
+
+	summary, err := snapshot.VerifyFile(path)
+	assert.NoError(err)
+	assert.Equal(reply.Checksum, summary.Checksum)
+
+	// Existing archives aren't overwritten
+	assert.Error(admin.SnapshotDatabase(nil, &SnapshotDatabaseArgs{Path: path}, &reply))
+	assert.ErrorIs(admin.SnapshotDatabase(nil, &SnapshotDatabaseArgs{}, &reply), errNoSnapshotPath)
+}
+
+func TestInspectDatabase(t *testing.T) {
+	assert := assert.New(t)
+
+	dbManager := manager.NewMemDB(version.NewDefaultVersion(1, 0, 0))
+	db := dbManager.Current().Database
+	assert.NoError(prefixdb.New([]byte("known"), db).Put([]byte("key"), []byte("value")))
+	assert.NoError(db.Put([]byte("unknown"), nil))
+	admin := &Admin{Config: Config{
+		Log:       logging.NoLog{},
+		DBManager: dbManager,
+		DatabaseLayout: func(database.Database) (*inspect.Layout, error) {
+			layout := inspect.NewLayout()
+			layout.Add("C", "known", inspect.Prefix{}.New([]byte("known")))
+			return layout, nil
+		},
+	}}
+
+	reply := InspectDatabaseReply{}
+	assert.NoError(admin.InspectDatabase(nil, nil, &reply))
+	assert.Len(reply.Databases, 1)
+	inspection := reply.Databases[0]
+	assert.Equal("v1.0.0", inspection.Version)
+	assert.EqualValues(2, inspection.Total.NumKeys)
+	assert.EqualValues(1, inspection.Unknown.NumKeys)
+	assert.Equal([]APIChainKeyStats{{
+		Chain:       "C",
+		APIKeyStats: APIKeyStats{NumKeys: 1, KeyBytes: 32 + 3, ValueBytes: 5},
+	}}, inspection.Chains)
+	assert.Equal([]APIPrefixKeyStats{{
+		Chain:       "C",
+		Name:        "known",
+		APIKeyStats: APIKeyStats{NumKeys: 1, KeyBytes: 32 + 3, ValueBytes: 5},
+	}}, inspection.Prefixes)
+}
+
+// banNetwork keeps the bans it is given in memory
+type banNetwork struct {
+	network.Network
+	clock mockable.Clock
+	bans  []network.Ban
+}
+
+func (n *banNetwork) Ban(ban network.Ban, duration time.Duration) error {
+	if err := ban.Verify(); err != nil {
+		return err
+	}
+	if duration > 0 {
+		ban.Expiry = n.clock.Time().Add(duration)
+	}
+	n.bans = append(n.bans, ban)
+	return nil
+}
+
+func (n *banNetwork) Unban(nodeID ids.ShortID, ip net.IP) (bool, error) {
+	for i, ban := range n.bans {
+		if ban.NodeID == nodeID && ban.IP.Equal(ip) {
+			n.bans = append(n.bans[:i], n.bans[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (n *banNetwork) Bans() []network.Ban { return n.bans }
+
+func TestBanPeer(t *testing.T) {
+	assert := assert.New(t)
+
+	network := &banNetwork{}
+	now := time.Unix(1000, 0)
+	network.clock.Set(now)
+	admin := &Admin{Config: Config{
+		Log:     logging.NoLog{},
+		Network: network,
+	}}
+	nodeID := ids.GenerateTestShortID()
+	nodeIDStr := nodeID.PrefixedString(constants.NodeIDPrefix)
+
+	reply := api.SuccessResponse{}
+	assert.NoError(admin.BanPeer(nil, &BanPeerArgs{
+		BanTargetArgs: BanTargetArgs{NodeID: nodeIDStr},
+		Duration:      "1h",
+		Reason:        "spam",
+	}, &reply))
+	assert.True(reply.Success)
+	assert.NoError(admin.BanPeer(nil, &BanPeerArgs{
+		BanTargetArgs: BanTargetArgs{IP: "1.2.3.4"},
+	}, &reply))
+
+	listReply := ListBansReply{}
+	assert.NoError(admin.ListBans(nil, nil, &listReply))
+	assert.Len(listReply.Bans, 2)
+	assert.Equal(nodeIDStr, listReply.Bans[0].NodeID)
+	assert.Equal("spam", listReply.Bans[0].Reason)
+	if assert.NotNil(listReply.Bans[0].Expiry) {
+		assert.Equal(now.Add(time.Hour), *listReply.Bans[0].Expiry)
+	}
+	assert.Equal("1.2.3.4", listReply.Bans[1].IP)
+	assert.Nil(listReply.Bans[1].Expiry)
+
+	// A ban targets exactly one node ID or IP
+	assert.ErrorIs(admin.BanPeer(nil, &BanPeerArgs{}, &reply), errNoBanTarget)
+	assert.ErrorIs(admin.BanPeer(nil, &BanPeerArgs{
+		BanTargetArgs: BanTargetArgs{NodeID: nodeIDStr, IP: "1.2.3.4"},
+	}, &reply), errNoBanTarget)
+	assert.ErrorIs(admin.BanPeer(nil, &BanPeerArgs{
+		BanTargetArgs: BanTargetArgs{IP: "1.2.3"},
+	}, &reply), errInvalidIP)
+
+	// The duration of a ban must be positive
+	for _, duration := range []string{"0s", "-1h"} {
+		assert.ErrorIs(admin.BanPeer(nil, &BanPeerArgs{
+			BanTargetArgs: BanTargetArgs{NodeID: nodeIDStr},
+			Duration:      duration,
+		}, &reply), errBanDuration)
+	}
+	assert.Error(admin.BanPeer(nil, &BanPeerArgs{
+		BanTargetArgs: BanTargetArgs{NodeID: nodeIDStr},
+		Duration:      "soon",
+	}, &reply))
+
+	assert.NoError(admin.UnbanPeer(nil, &BanTargetArgs{IP: "1.2.3.4"}, &reply))
+	assert.ErrorIs(admin.UnbanPeer(nil, &BanTargetArgs{IP: "1.2.3.4"}, &reply), errNotBanned)
+	assert.NoError(admin.ListBans(nil, nil, &listReply))
+	assert.Len(listReply.Bans, 1)
+}
+
+func TestFaultInjection(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeID := ids.GenerateTestShortID()
+	nodeIDStr := nodeID.PrefixedString(constants.NodeIDPrefix)
+	reply := api.SuccessResponse{}
+
+	// Faults can't be injected unless fault injection is enabled
+	admin := &Admin{Config: Config{
+		Log: logging.NoLog{},
+	}}
+	assert.ErrorIs(admin.Partition(nil, &PartitionArgs{NodeIDs: []string{nodeIDStr}}, &reply), errFaultInjectionDisabled)
+
+	injector := fault.NewInjector(logging.NoLog{})
+	admin = &Admin{Config: Config{
+		Log:           logging.NoLog{},
+		FaultInjector: injector,
+	}}
+	assert.NoError(admin.SetFault(nil, &SetFaultArgs{
+		NodeID:    nodeIDStr,
+		Delay:     "100ms",
+		DropRate:  0.1,
+		Bandwidth: 1024,
+	}, &reply))
+	assert.True(reply.Success)
+	assert.Error(admin.SetFault(nil, &SetFaultArgs{
+		NodeID:   nodeIDStr,
+		DropRate: 2,
+	}, &reply))
+	assert.NoError(admin.Partition(nil, &PartitionArgs{NodeIDs: []string{nodeIDStr}}, &reply))
+
+	listReply := ListFaultsReply{}
+	assert.NoError(admin.ListFaults(nil, nil, &listReply))
+	assert.Equal([]APIFault{{
+		NodeID:      nodeIDStr,
+		Delay:       "100ms",
+		Jitter:      "0s",
+		DropRate:    0.1,
+		Bandwidth:   1024,
+		Partitioned: true,
+	}}, listReply.Faults)
+
+	assert.NoError(admin.Heal(nil, &PartitionArgs{NodeIDs: []string{nodeIDStr}}, &reply))
+	assert.False(injector.Faults()[nodeID].Partitioned)
+	assert.NoError(admin.RemoveFault(nil, &RemoveFaultArgs{NodeID: nodeIDStr}, &reply))
+	assert.NoError(admin.ListFaults(nil, nil, &listReply))
+	assert.Empty(listReply.Faults)
+}
@@ -15,12 +15,16 @@ var _ Client = &client{}
 
 // Client interface for Avalanche Health API Endpoint
 type Client interface {
-	// Readiness returns if the node has finished initialization
-	Readiness(context.Context) (*APIHealthReply, error)
+	// Readiness returns if the node has finished initialization. If [tags]
+	// are provided, only the checks with one of the tags and the checks of the
+	// whole node are reported.
+	Readiness(ctx context.Context, tags ...string) (*APIHealthReply, error)
 	// Health returns a summation of the health of the node
-	Health(context.Context) (*APIHealthReply, error)
+	Health(ctx context.Context, tags ...string) (*APIHealthReply, error)
 	// Liveness returns if the node is in need of a restart
-	Liveness(context.Context) (*APIHealthReply, error)
+	Liveness(ctx context.Context, tags ...string) (*APIHealthReply, error)
+	// History returns the recent results and transitions of a check
+	History(ctx context.Context, check string) (*APIHistoryReply, error)
 	// AwaitHealthy queries the Health endpoint with a pause of [interval]
 	// in between checks and returns early if Health returns healthy
 	AwaitHealthy(ctx context.Context, freq time.Duration) (bool, error)
@@ -38,21 +42,27 @@ func NewClient(uri string) Client {
 	}
 }
 
-func (c *client) Readiness(ctx context.Context) (*APIHealthReply, error) {
+func (c *client) Readiness(ctx context.Context, tags ...string) (*APIHealthReply, error) {
 	res := &APIHealthReply{}
-	err := c.requester.SendRequest(ctx, "readiness", struct{}{}, res)
+	err := c.requester.SendRequest(ctx, "readiness", &APIHealthArgs{Tags: tags}, res)
 	return res, err
 }
 
-func (c *client) Health(ctx context.Context) (*APIHealthReply, error) {
+func (c *client) Health(ctx context.Context, tags ...string) (*APIHealthReply, error) {
 	res := &APIHealthReply{}
-	err := c.requester.SendRequest(ctx, "health", struct{}{}, res)
+	err := c.requester.SendRequest(ctx, "health", &APIHealthArgs{Tags: tags}, res)
 	return res, err
 }
 
-func (c *client) Liveness(ctx context.Context) (*APIHealthReply, error) {
+func (c *client) Liveness(ctx context.Context, tags ...string) (*APIHealthReply, error) {
 	res := &APIHealthReply{}
-	err := c.requester.SendRequest(ctx, "liveness", struct{}{}, res)
+	err := c.requester.SendRequest(ctx, "liveness", &APIHealthArgs{Tags: tags}, res)
+	return res, err
+}
+
+func (c *client) History(ctx context.Context, check string) (*APIHistoryReply, error) {
+	res := &APIHistoryReply{}
+	err := c.requester.SendRequest(ctx, "history", &APIHistoryArgs{Check: check}, res)
 	return res, err
 }
 
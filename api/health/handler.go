@@ -14,6 +14,10 @@ import (
 	"github.com/flare-foundation/flare/utils/logging"
 )
 
+// TagQueryParams are the query parameters whose values are used as tags to
+// restrict the checks reported to GET requests
+var TagQueryParams = []string{"tag", "chain", "subnet"}
+
 // NewGetAndPostHandler returns a health handler that supports GET and jsonrpc
 // POST requests.
 func NewGetAndPostHandler(log logging.Logger, reporter Reporter) (http.Handler, error) {
@@ -46,13 +50,20 @@ func NewGetAndPostHandler(log logging.Logger, reporter Reporter) (http.Handler,
 }
 
 // NewGetHandler return a health handler that supports GET requests reporting
-// the result of the provided [reporter].
-func NewGetHandler(reporter func() (map[string]Result, bool)) http.Handler {
+// the result of the provided [reporter]. The results can be restricted to the
+// checks of chains and subnets with the [TagQueryParams], for example
+// "?subnet=<subnetID>" or "?chain=C".
+func NewGetHandler(reporter func(tags ...string) (map[string]Result, bool)) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Make sure the content type is set before writing the header.
 		w.Header().Set("Content-Type", "application/json")
 
-		checks, healthy := reporter()
+		query := r.URL.Query()
+		var tags []string
+		for _, param := range TagQueryParams {
+			tags = append(tags, query[param]...)
+		}
+		checks, healthy := reporter(tags...)
 		if !healthy {
 			// If a health check has failed, we should return a 503.
 			w.WriteHeader(http.StatusServiceUnavailable)
@@ -4,12 +4,20 @@
 package health
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/utils/logging"
 )
 
-var _ Health = &health{}
+var (
+	errUnknownCheck = errors.New("unknown check")
+
+	_ Health = &health{}
+)
 
 // Health defines the full health service interface for registering, reporting
 // and refreshing health checks.
@@ -22,66 +30,96 @@ type Health interface {
 }
 
 // Registerer defines how to register new components to check the health of.
+// A check may be registered with tags, such as the IDs and aliases of the chain
+// and subnet it checks. Checks registered without tags apply to the whole node.
 type Registerer interface {
-	RegisterReadinessCheck(name string, checker Checker) error
-	RegisterHealthCheck(name string, checker Checker) error
-	RegisterLivenessCheck(name string, checker Checker) error
+	RegisterReadinessCheck(name string, checker Checker, tags ...string) error
+	RegisterHealthCheck(name string, checker Checker, tags ...string) error
+	RegisterLivenessCheck(name string, checker Checker, tags ...string) error
 }
 
-// Reporter returns the current health status.
+// Reporter returns the current health status. If tags are provided, only the
+// checks with one of the tags and the checks that apply to the whole node are
+// reported.
 type Reporter interface {
-	Readiness() (map[string]Result, bool)
-	Health() (map[string]Result, bool)
-	Liveness() (map[string]Result, bool)
+	Readiness(tags ...string) (map[string]Result, bool)
+	Health(tags ...string) (map[string]Result, bool)
+	Liveness(tags ...string) (map[string]Result, bool)
+
+	// History returns the history of the checks registered as [name], keyed by
+	// their kind: readiness, health or liveness
+	History(name string) (map[string]History, error)
 }
 
 type health struct {
 	readiness *worker
 	health    *worker
 	liveness  *worker
+
+	// webhooks is nil if no webhook is configured
+	webhooks *webhooks
 }
 
-func New(registerer prometheus.Registerer) (Health, error) {
-	readinessWorker, err := newWorker("readiness", registerer)
+func New(config Config, log logging.Logger, registerer prometheus.Registerer) (Health, error) {
+	var webhooks *webhooks
+	if len(config.WebhookURLs) > 0 {
+		webhooks = newWebhooks(log, config.WebhookURLs, config.WebhookTimeout)
+	}
+
+	readinessWorker, err := newWorker("readiness", config, webhooks, registerer)
 	if err != nil {
 		return nil, err
 	}
 
-	healthWorker, err := newWorker("health", registerer)
+	healthWorker, err := newWorker("health", config, webhooks, registerer)
 	if err != nil {
 		return nil, err
 	}
 
-	livenessWorker, err := newWorker("liveness", registerer)
+	livenessWorker, err := newWorker("liveness", config, webhooks, registerer)
 	return &health{
 		readiness: readinessWorker,
 		health:    healthWorker,
 		liveness:  livenessWorker,
+		webhooks:  webhooks,
 	}, err
 }
 
-func (h *health) RegisterReadinessCheck(name string, checker Checker) error {
-	return h.readiness.RegisterMonotonicCheck(name, checker)
+func (h *health) RegisterReadinessCheck(name string, checker Checker, tags ...string) error {
+	return h.readiness.RegisterMonotonicCheck(name, checker, tags...)
+}
+
+func (h *health) RegisterHealthCheck(name string, checker Checker, tags ...string) error {
+	return h.health.RegisterCheck(name, checker, tags...)
 }
 
-func (h *health) RegisterHealthCheck(name string, checker Checker) error {
-	return h.health.RegisterCheck(name, checker)
+func (h *health) RegisterLivenessCheck(name string, checker Checker, tags ...string) error {
+	return h.liveness.RegisterCheck(name, checker, tags...)
 }
 
-func (h *health) RegisterLivenessCheck(name string, checker Checker) error {
-	return h.liveness.RegisterCheck(name, checker)
+func (h *health) Readiness(tags ...string) (map[string]Result, bool) {
+	return h.readiness.Results(tags...)
 }
 
-func (h *health) Readiness() (map[string]Result, bool) {
-	return h.readiness.Results()
+func (h *health) Health(tags ...string) (map[string]Result, bool) {
+	return h.health.Results(tags...)
 }
 
-func (h *health) Health() (map[string]Result, bool) {
-	return h.health.Results()
+func (h *health) Liveness(tags ...string) (map[string]Result, bool) {
+	return h.liveness.Results(tags...)
 }
 
-func (h *health) Liveness() (map[string]Result, bool) {
-	return h.liveness.Results()
+func (h *health) History(name string) (map[string]History, error) {
+	histories := make(map[string]History, 3)
+	for _, w := range []*worker{h.readiness, h.health, h.liveness} {
+		if history, ok := w.History(name); ok {
+			histories[w.kind] = history
+		}
+	}
+	if len(histories) == 0 {
+		return nil, fmt.Errorf("%w: %q", errUnknownCheck, name)
+	}
+	return histories, nil
 }
 
 func (h *health) Start(freq time.Duration) {
@@ -94,4 +132,7 @@ func (h *health) Stop() {
 	h.readiness.Stop()
 	h.health.Stop()
 	h.liveness.Stop()
+	if h.webhooks != nil {
+		h.webhooks.stop()
+	}
 }
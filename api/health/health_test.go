@@ -4,16 +4,21 @@
 package health
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/logging"
 )
 
 const (
@@ -58,7 +63,7 @@ func TestDuplicatedRegistations(t *testing.T) {
 		return "", nil
 	})
 
-	h, err := New(prometheus.NewRegistry())
+	h, err := New(Config{}, logging.NoLog{}, prometheus.NewRegistry())
 	assert.NoError(err)
 
 	err = h.RegisterReadinessCheck("check", check)
@@ -84,7 +89,7 @@ func TestDefaultFailing(t *testing.T) {
 		return "", nil
 	})
 
-	h, err := New(prometheus.NewRegistry())
+	h, err := New(Config{}, logging.NoLog{}, prometheus.NewRegistry())
 	assert.NoError(err)
 
 	{
@@ -128,7 +133,7 @@ func TestPassingChecks(t *testing.T) {
 		return "", nil
 	})
 
-	h, err := New(prometheus.NewRegistry())
+	h, err := New(Config{}, logging.NoLog{}, prometheus.NewRegistry())
 	assert.NoError(err)
 
 	err = h.RegisterReadinessCheck("check", check)
@@ -198,7 +203,7 @@ func TestPassingThenFailingChecks(t *testing.T) {
 		return "", nil
 	})
 
-	h, err := New(prometheus.NewRegistry())
+	h, err := New(Config{}, logging.NoLog{}, prometheus.NewRegistry())
 	assert.NoError(err)
 
 	err = h.RegisterReadinessCheck("check", check)
@@ -248,7 +253,7 @@ func TestPassingThenFailingChecks(t *testing.T) {
 func TestDeadlockRegression(t *testing.T) {
 	assert := assert.New(t)
 
-	h, err := New(prometheus.NewRegistry())
+	h, err := New(Config{}, logging.NoLog{}, prometheus.NewRegistry())
 	assert.NoError(err)
 
 	var lock sync.Mutex
@@ -271,3 +276,158 @@ func TestDeadlockRegression(t *testing.T) {
 
 	awaitHealthy(h, true)
 }
+
+func TestHistoryAndFlapping(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var shouldCheckErr utils.AtomicBool
+	check := CheckerFunc(func() (interface{}, error) {
+		if shouldCheckErr.GetValue() {
+			return nil, errors.New("unhealthy")
+		}
+		return nil, nil
+	})
+
+	h, err := New(Config{HistorySize: 4, FlapThreshold: 2}, logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+	require.NoError(h.RegisterHealthCheck("check", check))
+	w := h.(*health).health
+
+	// healthy, unhealthy, unhealthy, healthy, healthy
+	for _, fail := range []bool{false, true, true, false, false} {
+		shouldCheckErr.SetValue(fail)
+		w.runChecks()
+	}
+
+	histories, err := h.History("check")
+	require.NoError(err)
+	require.Contains(histories, "health")
+	history := histories["health"]
+
+	// Only the last 4 results are kept
+	require.Len(history.Results, 4)
+	assert.NotNil(history.Results[0].Error)
+	assert.NotNil(history.Results[1].Error)
+	assert.Nil(history.Results[2].Error)
+	assert.Nil(history.Results[3].Error)
+
+	// The first result that passed was a transition as the check was failing
+	// before it ran
+	require.Len(history.Transitions, 3)
+	assert.True(history.Transitions[0].Healthy)
+	assert.False(history.Transitions[1].Healthy)
+	assert.True(history.Transitions[2].Healthy)
+	assert.Equal("health", history.Transitions[2].Kind)
+	assert.Equal("check", history.Transitions[2].Check)
+
+	// The kept results changed only once
+	assert.False(history.Flapping)
+
+	shouldCheckErr.SetValue(true)
+	w.runChecks()
+
+	results, _ := h.Health()
+	assert.True(results["check"].Flapping)
+	histories, err = h.History("check")
+	require.NoError(err)
+	assert.True(histories["health"].Flapping)
+
+	_, err = h.History("unknown")
+	assert.ErrorIs(err, errUnknownCheck)
+}
+
+func TestWebhooks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	transitions := make(chan Transition, 2)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var transition Transition
+		if err := json.NewDecoder(r.Body).Decode(&transition); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		transitions <- transition
+	}))
+	defer webhook.Close()
+
+	var shouldCheckErr utils.AtomicBool
+	check := CheckerFunc(func() (interface{}, error) {
+		if shouldCheckErr.GetValue() {
+			return nil, errors.New("unhealthy")
+		}
+		return nil, nil
+	})
+
+	h, err := New(Config{WebhookURLs: []string{webhook.URL}}, logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+	defer h.Stop()
+	require.NoError(h.RegisterLivenessCheck("check", check))
+	w := h.(*health).liveness
+
+	w.runChecks()
+	// Results that don't change the health of the check aren't posted
+	w.runChecks()
+	shouldCheckErr.SetValue(true)
+	w.runChecks()
+
+	for _, healthy := range []bool{true, false} {
+		select {
+		case transition := <-transitions:
+			assert.Equal("liveness", transition.Kind)
+			assert.Equal("check", transition.Check)
+			assert.Equal(healthy, transition.Healthy)
+		case <-time.After(10 * time.Second):
+			t.Fatal("webhook wasn't called")
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	passing := CheckerFunc(func() (interface{}, error) {
+		return nil, nil
+	})
+	failing := CheckerFunc(func() (interface{}, error) {
+		return nil, errors.New("unhealthy")
+	})
+
+	h, err := New(Config{}, logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+	require.NoError(h.RegisterHealthCheck("network", passing))
+	require.NoError(h.RegisterHealthCheck("C", passing, "C", "subnet"))
+	require.NoError(h.RegisterHealthCheck("X", failing, "X", "subnet"))
+	h.(*health).health.runChecks()
+
+	results, healthy := h.Health()
+	assert.Len(results, 3)
+	assert.False(healthy)
+
+	// The checks without tags apply to the whole node
+	results, healthy = h.Health("C")
+	assert.Len(results, 2)
+	assert.Contains(results, "network")
+	assert.Contains(results, "C")
+	assert.True(healthy)
+
+	results, healthy = h.Health("subnet")
+	assert.Len(results, 3)
+	assert.False(healthy)
+
+	handler := NewGetHandler(h.Health)
+	for query, expectedStatus := range map[string]int{
+		"":                    http.StatusServiceUnavailable,
+		"?chain=C":            http.StatusOK,
+		"?chain=X":            http.StatusServiceUnavailable,
+		"?subnet=subnet":      http.StatusServiceUnavailable,
+		"?tag=C&tag=unknown":  http.StatusOK,
+		"?chain=C&subnet=any": http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+query, nil))
+		assert.Equal(expectedStatus, w.Code, query)
+	}
+}
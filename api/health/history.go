@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"time"
+)
+
+// DefaultHistorySize is the number of past results kept for each check if the
+// size isn't configured
+const DefaultHistorySize = 64
+
+// Config of the health checks
+type Config struct {
+	// HistorySize is the number of past results kept for each check. If zero,
+	// [DefaultHistorySize] results are kept.
+	HistorySize int `json:"historySize"`
+
+	// FlapThreshold is the number of changes between healthy and unhealthy
+	// among the kept results of a check at which the check is reported as
+	// flapping. If zero, flapping isn't detected.
+	FlapThreshold int `json:"flapThreshold"`
+
+	// WebhookURLs are sent a POST request with every Transition of a check
+	WebhookURLs []string `json:"webhookURLs"`
+
+	// WebhookTimeout is the timeout of each webhook request
+	WebhookTimeout time.Duration `json:"webhookTimeout"`
+}
+
+// Transition of a check from healthy to unhealthy, or back
+type Transition struct {
+	// Kind of the check: readiness, health or liveness
+	Kind string `json:"kind"`
+
+	// Check is the name of the check
+	Check string `json:"check"`
+
+	// Healthy is true if the check started passing
+	Healthy bool `json:"healthy"`
+
+	// Error of the check, if it started failing
+	Error *string `json:"error,omitempty"`
+
+	// Timestamp of the result that caused the transition
+	Timestamp time.Time `json:"timestamp"`
+
+	// Flapping is true if the check is flapping after the transition
+	Flapping bool `json:"flapping"`
+}
+
+// History of a check
+type History struct {
+	// Results of the check, oldest first
+	Results []Result `json:"results"`
+
+	// Transitions of the check, oldest first
+	Transitions []Transition `json:"transitions"`
+
+	// Flapping is true if the check changed between healthy and unhealthy too
+	// often among its kept results
+	Flapping bool `json:"flapping"`
+}
+
+// history keeps a bounded number of the past results and transitions of a
+// check
+type history struct {
+	size        int
+	results     []Result
+	transitions []Transition
+}
+
+func newHistory(size int) *history {
+	return &history{
+		size:        size,
+		results:     make([]Result, 0, size),
+		transitions: make([]Transition, 0, size),
+	}
+}
+
+// addResult keeps [result], dropping the oldest result if the history is full
+func (h *history) addResult(result Result) {
+	if len(h.results) < h.size {
+		h.results = append(h.results, result)
+		return
+	}
+	copy(h.results, h.results[1:])
+	h.results[len(h.results)-1] = result
+}
+
+// addTransition keeps [transition], dropping the oldest transition if the
+// history is full
+func (h *history) addTransition(transition Transition) {
+	if len(h.transitions) < h.size {
+		h.transitions = append(h.transitions, transition)
+		return
+	}
+	copy(h.transitions, h.transitions[1:])
+	h.transitions[len(h.transitions)-1] = transition
+}
+
+// isFlapping returns true if the kept results changed between healthy and
+// unhealthy at least [threshold] times. Never flapping if [threshold] is zero.
+func (h *history) isFlapping(threshold int) bool {
+	if threshold <= 0 {
+		return false
+	}
+	changes := 0
+	for i := 1; i < len(h.results); i++ {
+		if (h.results[i].Error == nil) != (h.results[i-1].Error == nil) {
+			changes++
+		}
+	}
+	return changes >= threshold
+}
+
+func (h *history) History() History {
+	return History{
+		Results:     append([]Result{}, h.results...),
+		Transitions: append([]Transition{}, h.transitions...),
+		Flapping:    len(h.results) > 0 && h.results[len(h.results)-1].Flapping,
+	}
+}
@@ -5,11 +5,15 @@ package health
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/utils/wrappers"
 )
 
 type metrics struct {
 	// failingChecks keeps track of the number of check failing
 	failingChecks prometheus.Gauge
+	// flappingChecks keeps track of the number of checks flapping
+	flappingChecks prometheus.Gauge
 }
 
 func newMetrics(namespace string, registerer prometheus.Registerer) (*metrics, error) {
@@ -19,6 +23,16 @@ func newMetrics(namespace string, registerer prometheus.Registerer) (*metrics, e
 			Name:      "checks_failing",
 			Help:      "number of currently failing health checks",
 		}),
+		flappingChecks: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "checks_flapping",
+			Help:      "number of currently flapping health checks",
+		}),
 	}
-	return metrics, registerer.Register(metrics.failingChecks)
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(metrics.failingChecks),
+		registerer.Register(metrics.flappingChecks),
+	)
+	return metrics, errs.Err
 }
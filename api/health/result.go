@@ -37,4 +37,8 @@ type Result struct {
 
 	// TimeOfFirstFailure of the HealthCheck,
 	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure,omitempty"`
+
+	// Flapping is true if the HealthCheck changed between passing and failing
+	// too often recently.
+	Flapping bool `json:"flapping,omitempty"`
 }
@@ -15,6 +15,21 @@ type Service struct {
 	health Reporter
 }
 
+// APIHealthArgs are the arguments for Readiness, Health and Liveness
+type APIHealthArgs struct {
+	// Tags restrict the reported checks to those with one of the tags, such as
+	// the ID or alias of a chain or the ID of a subnet, and to the checks that
+	// apply to the whole node. If empty, all the checks are reported.
+	Tags []string `json:"tags"`
+}
+
+func (args *APIHealthArgs) tags() []string {
+	if args == nil {
+		return nil
+	}
+	return args.Tags
+}
+
 // APIHealthReply is the response for Health
 type APIHealthReply struct {
 	Checks  map[string]Result `json:"checks"`
@@ -22,9 +37,9 @@ type APIHealthReply struct {
 }
 
 // Readiness returns if the node has finished initialization
-func (s *Service) Readiness(_ *http.Request, _ *struct{}, reply *APIHealthReply) error {
+func (s *Service) Readiness(_ *http.Request, args *APIHealthArgs, reply *APIHealthReply) error {
 	s.log.Debug("Health.readiness called")
-	reply.Checks, reply.Healthy = s.health.Readiness()
+	reply.Checks, reply.Healthy = s.health.Readiness(args.tags()...)
 	if reply.Healthy {
 		return nil
 	}
@@ -34,9 +49,9 @@ func (s *Service) Readiness(_ *http.Request, _ *struct{}, reply *APIHealthReply)
 }
 
 // Health returns a summation of the health of the node
-func (s *Service) Health(_ *http.Request, _ *struct{}, reply *APIHealthReply) error {
+func (s *Service) Health(_ *http.Request, args *APIHealthArgs, reply *APIHealthReply) error {
 	s.log.Debug("Health.health called")
-	reply.Checks, reply.Healthy = s.health.Health()
+	reply.Checks, reply.Healthy = s.health.Health(args.tags()...)
 	if reply.Healthy {
 		return nil
 	}
@@ -46,9 +61,9 @@ func (s *Service) Health(_ *http.Request, _ *struct{}, reply *APIHealthReply) er
 }
 
 // Liveness returns if the node is in need of a restart
-func (s *Service) Liveness(_ *http.Request, _ *struct{}, reply *APIHealthReply) error {
+func (s *Service) Liveness(_ *http.Request, args *APIHealthArgs, reply *APIHealthReply) error {
 	s.log.Debug("Health.liveness called")
-	reply.Checks, reply.Healthy = s.health.Liveness()
+	reply.Checks, reply.Healthy = s.health.Liveness(args.tags()...)
 	if reply.Healthy {
 		return nil
 	}
@@ -56,3 +71,23 @@ func (s *Service) Liveness(_ *http.Request, _ *struct{}, reply *APIHealthReply)
 	s.log.Warn("Health.liveness is returning an error: %s", string(replyStr))
 	return err
 }
+
+// APIHistoryArgs are the arguments for History
+type APIHistoryArgs struct {
+	Check string `json:"check"`
+}
+
+// APIHistoryReply is the response for History
+type APIHistoryReply struct {
+	// History of the check, keyed by the kind of the check: readiness, health
+	// or liveness
+	History map[string]History `json:"history"`
+}
+
+// History returns the recent results and transitions of a check
+func (s *Service) History(_ *http.Request, args *APIHistoryArgs, reply *APIHistoryReply) error {
+	s.log.Debug("Health.history called with check: %s", args.Check)
+	var err error
+	reply.History, err = s.health.History(args.Check)
+	return err
+}
@@ -19,7 +19,7 @@ func TestServiceResponses(t *testing.T) {
 		return "", nil
 	})
 
-	h, err := New(prometheus.NewRegistry())
+	h, err := New(Config{}, logging.NoLog{}, prometheus.NewRegistry())
 	assert.NoError(err)
 
 	s := &Service{
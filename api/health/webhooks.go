@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+const (
+	// Maximum number of transitions waiting to be posted to the webhooks
+	maxPendingTransitions = 1024
+
+	// Timeout of a webhook request if none is configured
+	defaultWebhookTimeout = 5 * time.Second
+)
+
+// webhooks posts the transitions of the checks, as JSON, to the configured
+// URLs. Transitions are posted in order by a single goroutine so that slow
+// webhooks don't delay the checks.
+type webhooks struct {
+	log     logging.Logger
+	urls    []string
+	client  *http.Client
+	pending chan Transition
+
+	closeOnce sync.Once
+	closer    chan struct{}
+	done      chan struct{}
+}
+
+func newWebhooks(log logging.Logger, urls []string, timeout time.Duration) *webhooks {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	w := &webhooks{
+		log:     log,
+		urls:    urls,
+		client:  &http.Client{Timeout: timeout},
+		pending: make(chan Transition, maxPendingTransitions),
+		closer:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// notify queues [transition] to be posted. The transition is dropped if too
+// many transitions are pending.
+func (w *webhooks) notify(transition Transition) {
+	select {
+	case w.pending <- transition:
+	default:
+		w.log.Warn("dropping transition of %s check %q because too many webhook requests are pending",
+			transition.Kind,
+			transition.Check,
+		)
+	}
+}
+
+func (w *webhooks) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case transition := <-w.pending:
+			w.post(transition)
+		case <-w.closer:
+			return
+		}
+	}
+}
+
+func (w *webhooks) post(transition Transition) {
+	body, err := json.Marshal(transition)
+	if err != nil {
+		w.log.Error("couldn't marshal transition of %s check %q: %s", transition.Kind, transition.Check, err)
+		return
+	}
+	for _, url := range w.urls {
+		if err := w.postTo(url, body); err != nil {
+			w.log.Warn("couldn't post transition of %s check %q to webhook %s: %s",
+				transition.Kind,
+				transition.Check,
+				url,
+				err,
+			)
+		}
+	}
+}
+
+func (w *webhooks) postTo(url string, body []byte) error {
+	resp, err := w.client.Post(url, "application/json", bytes.NewReader(body)) // #nosec G107
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// stop posting transitions. Pending transitions are dropped.
+func (w *webhooks) stop() {
+	w.closeOnce.Do(func() {
+		close(w.closer)
+	})
+	<-w.done
+}
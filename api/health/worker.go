@@ -17,29 +17,54 @@ import (
 var errDuplicateCheck = errors.New("duplicated check")
 
 type worker struct {
+	// kind of the checks run by this worker: readiness, health or liveness
+	kind          string
+	historySize   int
+	flapThreshold int
+	// webhooks is nil if no webhook is configured
+	webhooks *webhooks
+
 	metrics    *metrics
 	checksLock sync.RWMutex
 	checks     map[string]Checker
+	// tags of the checks. Checks without tags apply to the whole node.
+	tags map[string]map[string]struct{}
 
 	resultsLock sync.RWMutex
 	results     map[string]Result
+	histories   map[string]*history
 
 	startOnce sync.Once
 	closeOnce sync.Once
 	closer    chan struct{}
 }
 
-func newWorker(namespace string, registerer prometheus.Registerer) (*worker, error) {
+func newWorker(
+	namespace string,
+	config Config,
+	webhooks *webhooks,
+	registerer prometheus.Registerer,
+) (*worker, error) {
+	historySize := config.HistorySize
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
 	metrics, err := newMetrics(namespace, registerer)
 	return &worker{
-		metrics: metrics,
-		checks:  make(map[string]Checker),
-		results: make(map[string]Result),
-		closer:  make(chan struct{}),
+		kind:          namespace,
+		historySize:   historySize,
+		flapThreshold: config.FlapThreshold,
+		webhooks:      webhooks,
+		metrics:       metrics,
+		checks:        make(map[string]Checker),
+		tags:          make(map[string]map[string]struct{}),
+		results:       make(map[string]Result),
+		histories:     make(map[string]*history),
+		closer:        make(chan struct{}),
 	}, err
 }
 
-func (w *worker) RegisterCheck(name string, checker Checker) error {
+func (w *worker) RegisterCheck(name string, checker Checker, tags ...string) error {
 	w.checksLock.Lock()
 	defer w.checksLock.Unlock()
 
@@ -51,14 +76,22 @@ func (w *worker) RegisterCheck(name string, checker Checker) error {
 	defer w.resultsLock.Unlock()
 
 	w.checks[name] = checker
+	if len(tags) > 0 {
+		tagSet := make(map[string]struct{}, len(tags))
+		for _, tag := range tags {
+			tagSet[tag] = struct{}{}
+		}
+		w.tags[name] = tagSet
+	}
 	w.results[name] = notYetRunResult
+	w.histories[name] = newHistory(w.historySize)
 
 	// Whenever a new check is added - it is failing
 	w.metrics.failingChecks.Inc()
 	return nil
 }
 
-func (w *worker) RegisterMonotonicCheck(name string, checker Checker) error {
+func (w *worker) RegisterMonotonicCheck(name string, checker Checker, tags ...string) error {
 	var result utils.AtomicInterface
 	return w.RegisterCheck(name, CheckerFunc(func() (interface{}, error) {
 		details := result.GetValue()
@@ -71,22 +104,58 @@ func (w *worker) RegisterMonotonicCheck(name string, checker Checker) error {
 			result.SetValue(details)
 		}
 		return details, err
-	}))
+	}), tags...)
 }
 
-func (w *worker) Results() (map[string]Result, bool) {
+// Results returns the results of the checks that have one of [tags], and of
+// the checks without tags. If no tags are provided, the results of all the
+// checks are returned.
+func (w *worker) Results(tags ...string) (map[string]Result, bool) {
+	w.checksLock.RLock()
+	defer w.checksLock.RUnlock()
 	w.resultsLock.RLock()
 	defer w.resultsLock.RUnlock()
 
 	results := make(map[string]Result, len(w.results))
 	healthy := true
 	for name, result := range w.results {
+		if !w.hasAnyTag(name, tags) {
+			continue
+		}
 		results[name] = result
 		healthy = healthy && result.Error == nil
 	}
 	return results, healthy
 }
 
+// hasAnyTag returns true if the check registered as [name] has one of [tags],
+// or if it has no tags. Assumes [w.checksLock] is held.
+func (w *worker) hasAnyTag(name string, tags []string) bool {
+	checkTags, ok := w.tags[name]
+	if len(tags) == 0 || !ok {
+		return true
+	}
+	for _, tag := range tags {
+		if _, ok := checkTags[tag]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// History returns the history of the check registered as [name], or false if
+// there is no such check
+func (w *worker) History(name string) (History, bool) {
+	w.resultsLock.RLock()
+	defer w.resultsLock.RUnlock()
+
+	history, ok := w.histories[name]
+	if !ok {
+		return History{}, false
+	}
+	return history.History(), true
+}
+
 func (w *worker) Start(freq time.Duration) {
 	w.startOnce.Do(func() {
 		go func() {
@@ -169,5 +238,32 @@ func (w *worker) runCheck(wg *sync.WaitGroup, name string, check Checker) {
 	} else if prevResult.Error != nil {
 		w.metrics.failingChecks.Dec()
 	}
+
+	history := w.histories[name]
+	history.addResult(result)
+	result.Flapping = history.isFlapping(w.flapThreshold)
+	// Keep the flapping status in the history as well
+	history.results[len(history.results)-1].Flapping = result.Flapping
+	switch {
+	case result.Flapping && !prevResult.Flapping:
+		w.metrics.flappingChecks.Inc()
+	case !result.Flapping && prevResult.Flapping:
+		w.metrics.flappingChecks.Dec()
+	}
+
+	if (result.Error == nil) != (prevResult.Error == nil) {
+		transition := Transition{
+			Kind:      w.kind,
+			Check:     name,
+			Healthy:   result.Error == nil,
+			Error:     result.Error,
+			Timestamp: end,
+			Flapping:  result.Flapping,
+		}
+		history.addTransition(transition)
+		if w.webhooks != nil {
+			w.webhooks.notify(transition)
+		}
+	}
 	w.results[name] = result
 }
@@ -21,7 +21,7 @@ type Client interface {
 	GetNetworkID(context.Context) (uint32, error)
 	GetNetworkName(context.Context) (string, error)
 	GetBlockchainID(context.Context, string) (ids.ID, error)
-	Peers(context.Context) ([]Peer, error)
+	Peers(ctx context.Context, nodeIDs []string, sortBy string) ([]Peer, error)
 	IsBootstrapped(context.Context, string) (bool, error)
 	GetTxFee(context.Context) (*GetTxFeeResponse, error)
 	Uptime(context.Context) (*UptimeResponse, error)
@@ -78,9 +78,14 @@ func (c *client) GetBlockchainID(ctx context.Context, alias string) (ids.ID, err
 	return res.BlockchainID, err
 }
 
-func (c *client) Peers(ctx context.Context) ([]Peer, error) {
+// Peers returns the peers in [nodeIDs], or all peers if it is empty, sorted by
+// [sortBy]
+func (c *client) Peers(ctx context.Context, nodeIDs []string, sortBy string) ([]Peer, error) {
 	res := &PeersReply{}
-	err := c.requester.SendRequest(ctx, "peers", struct{}{}, res)
+	err := c.requester.SendRequest(ctx, "peers", &PeersArgs{
+		NodeIDs: nodeIDs,
+		SortBy:  sortBy,
+	}, res)
 	return res.Peers, err
 }
 
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -28,6 +29,7 @@ import (
 var (
 	errNoChainProvided = errors.New("argument 'chain' not given")
 	errNotValidator    = errors.New("this is not a validator node")
+	errUnknownSortKey  = errors.New("unknown sort key")
 )
 
 // Info is the API service for unprivileged info on a node
@@ -181,15 +183,59 @@ func (service *Info) GetBlockchainID(_ *http.Request, args *GetBlockchainIDArgs,
 	return err
 }
 
+// Keys that peers can be sorted by. Peers are sorted by node ID in ascending
+// order, and by the other keys in descending order.
+const (
+	SortByNodeID              = "nodeID"
+	SortByWeight              = "weight"
+	SortByBytesSent           = "bytesSent"
+	SortByBytesReceived       = "bytesReceived"
+	SortBySendQueueBytes      = "sendQueueBytes"
+	SortByPingRTT             = "pingRTT"
+	SortByInboundThrottleWait = "inboundThrottleWait"
+)
+
 // PeersArgs are the arguments for calling Peers
 type PeersArgs struct {
+	// If non-empty, only these peers are returned
 	NodeIDs []string `json:"nodeIDs"`
+	// If non-empty, one of the SortBy keys that the peers are sorted by
+	SortBy string `json:"sortBy"`
 }
 
 type Peer struct {
 	peer.Info
 
+	// Chains the peer is benched on
 	Benched []ids.ID `json:"benched"`
+	// Weight of the peer in the primary network validator set. Zero if it
+	// isn't a validator.
+	Weight json.Uint64 `json:"weight"`
+}
+
+// sortPeers sorts [peers] by [sortBy]
+func sortPeers(peers []Peer, sortBy string) error {
+	var less func(a, b *Peer) bool
+	switch sortBy {
+	case "", SortByNodeID:
+		less = func(a, b *Peer) bool { return a.ID < b.ID }
+	case SortByWeight:
+		less = func(a, b *Peer) bool { return a.Weight > b.Weight }
+	case SortByBytesSent:
+		less = func(a, b *Peer) bool { return a.Stats.BytesSent > b.Stats.BytesSent }
+	case SortByBytesReceived:
+		less = func(a, b *Peer) bool { return a.Stats.BytesReceived > b.Stats.BytesReceived }
+	case SortBySendQueueBytes:
+		less = func(a, b *Peer) bool { return a.Stats.SendQueueBytes > b.Stats.SendQueueBytes }
+	case SortByPingRTT:
+		less = func(a, b *Peer) bool { return a.Stats.PingRTT > b.Stats.PingRTT }
+	case SortByInboundThrottleWait:
+		less = func(a, b *Peer) bool { return a.Stats.InboundThrottleWait > b.Stats.InboundThrottleWait }
+	default:
+		return fmt.Errorf("%w %q", errUnknownSortKey, sortBy)
+	}
+	sort.SliceStable(peers, func(i, j int) bool { return less(&peers[i], &peers[j]) })
+	return nil
 }
 
 // PeersReply are the results from calling Peers
@@ -200,7 +246,8 @@ type PeersReply struct {
 	Peers []Peer `json:"peers"`
 }
 
-// Peers returns the list of current validators
+// Peers returns the peers this node is connected to, along with the traffic
+// with each of them
 func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply) error {
 	service.log.Debug("Info: Peers called")
 	nodeIDs := make([]ids.ShortID, 0, len(args.NodeIDs))
@@ -220,11 +267,16 @@ func (service *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply)
 			return err
 		}
 
+		weight, _ := service.validators.GetWeight(nodeID)
 		peerInfo[i] = Peer{
 			Info:    peer,
 			Benched: service.benchlist.GetBenched(nodeID),
+			Weight:  json.Uint64(weight),
 		}
 	}
+	if err := sortPeers(peerInfo, args.SortBy); err != nil {
+		return err
+	}
 
 	reply.Peers = peerInfo
 	reply.NumPeers = json.Uint64(len(reply.Peers))
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/network/peer"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/vms"
 )
@@ -106,3 +107,28 @@ func TestGetVMsGetAliasesFails(t *testing.T) {
 
 	assert.Equal(t, err, errOops)
 }
+
+func TestSortPeers(t *testing.T) {
+	assert := assert.New(t)
+
+	peers := []Peer{
+		{Info: peer.Info{ID: "b", Stats: peer.Stats{BytesSent: 1}}, Weight: 2},
+		{Info: peer.Info{ID: "a", Stats: peer.Stats{BytesSent: 3}}, Weight: 1},
+		{Info: peer.Info{ID: "c", Stats: peer.Stats{BytesSent: 2}}, Weight: 3},
+	}
+	order := func() []string {
+		order := make([]string, len(peers))
+		for i, peer := range peers {
+			order[i] = peer.ID
+		}
+		return order
+	}
+
+	assert.NoError(sortPeers(peers, ""))
+	assert.Equal([]string{"a", "b", "c"}, order())
+	assert.NoError(sortPeers(peers, SortByWeight))
+	assert.Equal([]string{"c", "b", "a"}, order())
+	assert.NoError(sortPeers(peers, SortByBytesSent))
+	assert.Equal([]string{"a", "c", "b"}, order())
+	assert.ErrorIs(sortPeers(peers, "unknown"), errUnknownSortKey)
+}
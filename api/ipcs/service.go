@@ -54,6 +54,11 @@ type PublishBlockchainArgs struct {
 type PublishBlockchainReply struct {
 	ConsensusURL string `json:"consensusURL"`
 	DecisionsURL string `json:"decisionsURL"`
+	// Names used to subscribe to the sockets over TLS
+	ConsensusName string `json:"consensusName"`
+	DecisionsName string `json:"decisionsName"`
+	// Address the sockets are served at over TLS, empty if they aren't
+	TLSAddress string `json:"tlsAddress,omitempty"`
 }
 
 // PublishBlockchain publishes the finalized accepted transactions from the blockchainID over the IPC
@@ -74,6 +79,9 @@ func (ipc *IPCServer) PublishBlockchain(r *http.Request, args *PublishBlockchain
 
 	reply.ConsensusURL = ipcs.ConsensusURL()
 	reply.DecisionsURL = ipcs.DecisionsURL()
+	reply.ConsensusName = ipcs.ConsensusName()
+	reply.DecisionsName = ipcs.DecisionsName()
+	reply.TLSAddress = ipc.ipcs.TLSAddress()
 
 	return nil
 }
@@ -0,0 +1,26 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/database/prefixdb"
+)
+
+// AddLayout adds the prefixes of the keystore to [layout], where [prefix] is
+// the prefix of the keystore's database [db]. The data of the users is kept
+// under prefixes derived from their usernames, which are read from [db].
+func AddLayout(layout *inspect.Layout, prefix inspect.Prefix, db database.Database) error {
+	layout.Add("", "keystore/users", prefix.New(usersPrefix))
+
+	bcDB := prefix.New(bcsPrefix)
+	userDB := prefixdb.New(usersPrefix, db)
+	it := userDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		layout.Add("", "keystore/user data", bcDB.New(it.Key()))
+	}
+	return it.Error()
+}
@@ -9,6 +9,7 @@ package server
 
 import (
 	io "io"
+	http "net/http"
 	reflect "reflect"
 	sync "sync"
 	time "time"
@@ -235,6 +236,20 @@ func (mr *MockServerMockRecorder) AddRouteWithReadLock(handler, lock, base, endp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRouteWithReadLock", reflect.TypeOf((*MockServer)(nil).AddRouteWithReadLock), handler, lock, base, endpoint, loggingWriter)
 }
 
+// CheckOrigin mocks base method.
+func (m *MockServer) CheckOrigin(r *http.Request) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckOrigin", r)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CheckOrigin indicates an expected call of CheckOrigin.
+func (mr *MockServerMockRecorder) CheckOrigin(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckOrigin", reflect.TypeOf((*MockServer)(nil).CheckOrigin), r)
+}
+
 // Dispatch mocks base method.
 func (m *MockServer) Dispatch() error {
 	m.ctrl.T.Helper()
@@ -292,6 +307,30 @@ func (mr *MockServerMockRecorder) RegisterChain(chainName, engine interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterChain", reflect.TypeOf((*MockServer)(nil).RegisterChain), chainName, engine)
 }
 
+// SetAllowedOrigins mocks base method.
+func (m *MockServer) SetAllowedOrigins(allowedOrigins []string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAllowedOrigins", allowedOrigins)
+}
+
+// SetAllowedOrigins indicates an expected call of SetAllowedOrigins.
+func (mr *MockServerMockRecorder) SetAllowedOrigins(allowedOrigins interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllowedOrigins", reflect.TypeOf((*MockServer)(nil).SetAllowedOrigins), allowedOrigins)
+}
+
+// SetRouteEnabled mocks base method.
+func (m *MockServer) SetRouteEnabled(base string, enabled bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRouteEnabled", base, enabled)
+}
+
+// SetRouteEnabled indicates an expected call of SetRouteEnabled.
+func (mr *MockServerMockRecorder) SetRouteEnabled(base, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRouteEnabled", reflect.TypeOf((*MockServer)(nil).SetRouteEnabled), base, enabled)
+}
+
 // Shutdown mocks base method.
 func (m *MockServer) Shutdown() error {
 	m.ctrl.T.Helper()
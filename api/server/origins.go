@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// allowedOrigins matches the origins allowed to make cross-origin requests
+// with the same rules as the CORS handler: "*" allows all origins, and an
+// origin may contain a single "*" wildcard. Matching is case-insensitive.
+type allowedOrigins struct {
+	all       bool
+	origins   map[string]struct{}
+	wildcards []originWildcard
+}
+
+type originWildcard struct {
+	prefix, suffix string
+}
+
+func newAllowedOrigins(origins []string) *allowedOrigins {
+	a := &allowedOrigins{
+		// No origins is treated like "*" by the CORS handler
+		all:     len(origins) == 0,
+		origins: make(map[string]struct{}, len(origins)),
+	}
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			a.all = true
+			continue
+		}
+		if i := strings.IndexByte(origin, '*'); i >= 0 {
+			a.wildcards = append(a.wildcards, originWildcard{
+				prefix: origin[:i],
+				suffix: origin[i+1:],
+			})
+			continue
+		}
+		a.origins[origin] = struct{}{}
+	}
+	return a
+}
+
+func (a *allowedOrigins) allows(origin string) bool {
+	if a.all {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := a.origins[origin]; ok {
+		return true
+	}
+	for _, w := range a.wildcards {
+		if len(origin) >= len(w.prefix)+len(w.suffix) && strings.HasPrefix(origin, w.prefix) && strings.HasSuffix(origin, w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOrigin returns true if [r] has no Origin header, comes from the same
+// origin as the server, or comes from an allowed origin
+func (a *allowedOrigins) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return a.allows(origin)
+}
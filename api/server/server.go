@@ -86,6 +86,16 @@ type Server interface {
 		base, endpoint string,
 		loggingWriter io.Writer,
 	) error
+	// SetAllowedOrigins changes the origins allowed to make cross-origin
+	// requests
+	SetAllowedOrigins(allowedOrigins []string)
+	// CheckOrigin returns true if the origin of the websocket handshake [r]
+	// is allowed. Websocket handshakes aren't subject to CORS, so the handlers
+	// that upgrade connections must check their origin.
+	CheckOrigin(r *http.Request) bool
+	// SetRouteEnabled enables or disables the routes added at [base] with
+	// AddRoute. Calls to a disabled route are rejected.
+	SetRouteEnabled(base string, enabled bool)
 	// Shutdown this server
 	Shutdown() error
 }
@@ -106,6 +116,16 @@ type server struct {
 	// Maps endpoints to handlers
 	router *router
 
+	// Handles cross-origin requests before calling [router]
+	corsLock    sync.RWMutex
+	corsHandler http.Handler
+	// Origins allowed to make cross-origin requests
+	allowedOrigins *allowedOrigins
+
+	// Bases of the routes that were disabled with SetRouteEnabled
+	disabledBasesLock sync.RWMutex
+	disabledBases     map[string]bool
+
 	srv *http.Server
 }
 
@@ -130,14 +150,20 @@ func (s *server) Initialize(
 	s.listenPort = port
 	s.shutdownTimeout = shutdownTimeout
 	s.router = newRouter()
+	s.disabledBases = make(map[string]bool)
 
 	s.log.Info("API created with allowed origins: %v", allowedOrigins)
 
-	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:   allowedOrigins,
-		AllowCredentials: true,
-	}).Handler(s.router)
-	gzipHandler := gziphandler.GzipHandler(corsHandler)
+	s.corsHandler = s.newCORSHandler(allowedOrigins)
+	s.allowedOrigins = newAllowedOrigins(allowedOrigins)
+	gzipHandler := gziphandler.GzipHandler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			s.corsLock.RLock()
+			corsHandler := s.corsHandler
+			s.corsLock.RUnlock()
+			corsHandler.ServeHTTP(w, r)
+		},
+	))
 	s.handler = http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			// Attach this node's ID as a header
@@ -151,6 +177,51 @@ func (s *server) Initialize(
 	}
 }
 
+func (s *server) newCORSHandler(allowedOrigins []string) http.Handler {
+	return cors.New(cors.Options{
+		AllowedOrigins:   allowedOrigins,
+		AllowCredentials: true,
+	}).Handler(s.router)
+}
+
+func (s *server) SetAllowedOrigins(allowedOrigins []string) {
+	s.log.Info("API allowed origins changed to: %v", allowedOrigins)
+
+	corsHandler := s.newCORSHandler(allowedOrigins)
+	origins := newAllowedOrigins(allowedOrigins)
+	s.corsLock.Lock()
+	defer s.corsLock.Unlock()
+
+	s.corsHandler = corsHandler
+	s.allowedOrigins = origins
+}
+
+func (s *server) CheckOrigin(r *http.Request) bool {
+	s.corsLock.RLock()
+	origins := s.allowedOrigins
+	s.corsLock.RUnlock()
+
+	return origins.checkOrigin(r)
+}
+
+func (s *server) SetRouteEnabled(base string, enabled bool) {
+	s.disabledBasesLock.Lock()
+	defer s.disabledBasesLock.Unlock()
+
+	if enabled {
+		delete(s.disabledBases, base)
+	} else {
+		s.disabledBases[base] = true
+	}
+}
+
+func (s *server) routeDisabled(base string) bool {
+	s.disabledBasesLock.RLock()
+	defer s.disabledBasesLock.RUnlock()
+
+	return s.disabledBases[base]
+}
+
 func (s *server) Dispatch() error {
 	listenAddress := fmt.Sprintf("%s:%d", s.listenHost, s.listenPort)
 	listener, err := net.Listen("tcp", listenAddress)
@@ -275,9 +346,25 @@ func (s *server) addRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
+	// Apply middleware to reject calls to the handler while it's disabled
+	h = s.disabledMiddleware(h, base)
 	return s.router.AddRouter(url, endpoint, h)
 }
 
+// Disabled middleware wraps a handler. If the routes at [base] are disabled,
+// writes back an error.
+func (s *server) disabledMiddleware(handler http.Handler, base string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.routeDisabled(base) {
+			w.WriteHeader(http.StatusNotFound)
+			// Doesn't matter if there's an error while writing. They'll get the StatusNotFound code.
+			_, _ = w.Write([]byte("API call rejected because the API is disabled"))
+		} else {
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
 // Wraps a handler by grabbing and releasing a lock before calling the handler.
 func lockMiddleware(handler http.Handler, lockOption common.LockOption, lock *sync.RWMutex) (http.Handler, error) {
 	switch lockOption {
@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+func TestSetRouteEnabled(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := &server{}
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, []string{"*"}, time.Second, ids.ShortEmpty)
+
+	handler := &testHandler{}
+	require.NoError(s.AddRoute(&common.HTTPHandler{LockOptions: common.NoLock, Handler: handler}, &sync.RWMutex{}, "admin", "", ioutil.Discard))
+
+	serve := func() int {
+		w := httptest.NewRecorder()
+		s.handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ext/admin", nil))
+		return w.Code
+	}
+
+	assert.Equal(http.StatusOK, serve())
+	assert.True(handler.called)
+
+	handler.called = false
+	s.SetRouteEnabled("admin", false)
+	assert.Equal(http.StatusNotFound, serve())
+	assert.False(handler.called)
+
+	s.SetRouteEnabled("admin", true)
+	assert.Equal(http.StatusOK, serve())
+	assert.True(handler.called)
+}
+
+func TestSetAllowedOrigins(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := &server{}
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, []string{"https://a.example"}, time.Second, ids.ShortEmpty)
+	require.NoError(s.AddRoute(&common.HTTPHandler{LockOptions: common.NoLock, Handler: &testHandler{}}, &sync.RWMutex{}, "info", "", ioutil.Discard))
+
+	allowedOrigin := func(origin string) string {
+		r := httptest.NewRequest(http.MethodPost, "/ext/info", nil)
+		r.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		s.handler.ServeHTTP(w, r)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	assert.Equal("https://a.example", allowedOrigin("https://a.example"))
+	assert.Empty(allowedOrigin("https://b.example"))
+
+	s.SetAllowedOrigins([]string{"https://b.example"})
+	assert.Empty(allowedOrigin("https://a.example"))
+	assert.Equal("https://b.example", allowedOrigin("https://b.example"))
+}
+
+func TestCheckOrigin(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &server{}
+	s.Initialize(logging.NoLog{}, nil, "127.0.0.1", 0, []string{"https://a.example", "https://*.b.example"}, time.Second, ids.ShortEmpty)
+
+	checkOrigin := func(origin string) bool {
+		r := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9650/ext/index/C/block/events", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return s.CheckOrigin(r)
+	}
+
+	// Requests without an origin, or from the same origin, are allowed
+	assert.True(checkOrigin(""))
+	assert.True(checkOrigin("http://127.0.0.1:9650"))
+
+	assert.True(checkOrigin("https://a.example"))
+	assert.True(checkOrigin("https://A.example"))
+	assert.True(checkOrigin("https://c.b.example"))
+	assert.False(checkOrigin("https://c.example"))
+
+	s.SetAllowedOrigins([]string{"*"})
+	assert.True(checkOrigin("https://c.example"))
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"context"
+
+	"github.com/flare-foundation/flare/utils/json"
+	"github.com/flare-foundation/flare/utils/rpc"
+)
+
+// Interface compliance
+var _ Client = &client{}
+
+// Client interface for the Validators API Endpoint
+type Client interface {
+	GetValidatorsAt(ctx context.Context, block BlockRef) (*GetValidatorsAtReply, error)
+	GetValidatorSetDiff(ctx context.Context, from BlockRef, to BlockRef) (*GetValidatorSetDiffReply, error)
+	GetJournal(ctx context.Context, startIndex uint64, numToFetch uint64) (*GetJournalReply, error)
+}
+
+// Client implementation for the Validators API Endpoint
+type client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a new Validators API Client
+func NewClient(uri string) Client {
+	return &client{
+		requester: rpc.NewEndpointRequester(uri, "/ext/validators", "validators"),
+	}
+}
+
+func (c *client) GetValidatorsAt(ctx context.Context, block BlockRef) (*GetValidatorsAtReply, error) {
+	res := &GetValidatorsAtReply{}
+	err := c.requester.SendRequest(ctx, "getValidatorsAt", &block, res)
+	return res, err
+}
+
+func (c *client) GetValidatorSetDiff(ctx context.Context, from BlockRef, to BlockRef) (*GetValidatorSetDiffReply, error) {
+	res := &GetValidatorSetDiffReply{}
+	err := c.requester.SendRequest(ctx, "getValidatorSetDiff", &GetValidatorSetDiffArgs{
+		From: from,
+		To:   to,
+	}, res)
+	return res, err
+}
+
+func (c *client) GetJournal(ctx context.Context, startIndex uint64, numToFetch uint64) (*GetJournalReply, error) {
+	res := &GetJournalReply{}
+	err := c.requester.SendRequest(ctx, "getJournal", &GetJournalArgs{
+		StartIndex: json.Uint64(startIndex),
+		NumToFetch: json.Uint64(numToFetch),
+	}, res)
+	return res, err
+}
@@ -0,0 +1,278 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/validation"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+
+	cjson "github.com/flare-foundation/flare/utils/json"
+)
+
+var (
+	errBlockIDAndHeight = errors.New("only one of 'blockID' and 'height' can be given")
+	errInvalidBlockID   = errors.New("'blockID' must be a CB58 or hex encoded ID")
+	errNoJournal        = errors.New("validator journal is not available")
+)
+
+type Config struct {
+	Log          logging.Logger
+	ChainManager chains.Manager
+	Journal      validation.Journal
+}
+
+// Validators is the API service for inspecting the validator sets provided by
+// the EVM.
+type Validators struct {
+	Config
+}
+
+// NewService returns a new validators API service.
+func NewService(config Config) (*common.HTTPHandler, error) {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	if err := newServer.RegisterService(&Validators{Config: config}, "validators"); err != nil {
+		return nil, err
+	}
+	return &common.HTTPHandler{Handler: newServer}, nil
+}
+
+// BlockRef references a block of the chain providing the validator sets,
+// either by its ID or by its height. If neither is given, the last accepted
+// block is referenced.
+type BlockRef struct {
+	// BlockID is either CB58 encoded or hex encoded, with an optional 0x
+	// prefix.
+	BlockID string        `json:"blockID"`
+	Height  *cjson.Uint64 `json:"height"`
+}
+
+// APIValidator is a validator and its weight
+type APIValidator struct {
+	NodeID string       `json:"nodeID"`
+	Weight cjson.Uint64 `json:"weight"`
+}
+
+// APIWeightChange is a validator whose weight changed
+type APIWeightChange struct {
+	NodeID     string       `json:"nodeID"`
+	PrevWeight cjson.Uint64 `json:"prevWeight"`
+	Weight     cjson.Uint64 `json:"weight"`
+}
+
+// GetValidatorsAtReply are the results from calling GetValidatorsAt
+type GetValidatorsAtReply struct {
+	BlockID     ids.ID         `json:"blockID"`
+	Validators  []APIValidator `json:"validators"`
+	TotalWeight cjson.Uint64   `json:"totalWeight"`
+}
+
+// GetValidatorsAt returns the validator set at the given block
+func (service *Validators) GetValidatorsAt(_ *http.Request, args *BlockRef, reply *GetValidatorsAtReply) error {
+	service.Log.Debug("Validators: GetValidatorsAt called with BlockID: %q, Height: %v", args.BlockID, args.Height)
+
+	source, err := service.ChainManager.ValidatorsSource()
+	if err != nil {
+		return err
+	}
+	blockID, err := resolveBlock(source, args)
+	if err != nil {
+		return err
+	}
+	set, err := source.GetValidators(blockID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validators at block %s: %w", blockID, err)
+	}
+
+	// Diffing against the empty set lists the validators sorted by node ID
+	reply.BlockID = blockID
+	reply.Validators = toAPIValidators(validation.NewDiff(nil, set).Added)
+	reply.TotalWeight = cjson.Uint64(set.Weight())
+	return nil
+}
+
+// GetValidatorSetDiffArgs are the arguments for calling GetValidatorSetDiff
+type GetValidatorSetDiffArgs struct {
+	From BlockRef `json:"from"`
+	To   BlockRef `json:"to"`
+}
+
+// GetValidatorSetDiffReply are the results from calling GetValidatorSetDiff
+type GetValidatorSetDiffReply struct {
+	FromBlockID ids.ID            `json:"fromBlockID"`
+	ToBlockID   ids.ID            `json:"toBlockID"`
+	Added       []APIValidator    `json:"added"`
+	Removed     []APIValidator    `json:"removed"`
+	Reweighted  []APIWeightChange `json:"reweighted"`
+}
+
+// GetValidatorSetDiff returns the changes that updating the validators from
+// the set at [args.From] to the set at [args.To] applies
+func (service *Validators) GetValidatorSetDiff(_ *http.Request, args *GetValidatorSetDiffArgs, reply *GetValidatorSetDiffReply) error {
+	service.Log.Debug("Validators: GetValidatorSetDiff called")
+
+	source, err := service.ChainManager.ValidatorsSource()
+	if err != nil {
+		return err
+	}
+	fromID, err := resolveBlock(source, &args.From)
+	if err != nil {
+		return fmt.Errorf("couldn't resolve 'from': %w", err)
+	}
+	toID, err := resolveBlock(source, &args.To)
+	if err != nil {
+		return fmt.Errorf("couldn't resolve 'to': %w", err)
+	}
+	from, err := source.GetValidators(fromID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validators at block %s: %w", fromID, err)
+	}
+	to, err := source.GetValidators(toID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validators at block %s: %w", toID, err)
+	}
+
+	diff := validation.NewDiff(from, to)
+	reply.FromBlockID = fromID
+	reply.ToBlockID = toID
+	reply.Added = toAPIValidators(diff.Added)
+	reply.Removed = toAPIValidators(diff.Removed)
+	reply.Reweighted = toAPIWeightChanges(diff.Reweighted)
+	return nil
+}
+
+// GetJournalArgs are the arguments for calling GetJournal
+type GetJournalArgs struct {
+	StartIndex cjson.Uint64 `json:"startIndex"`
+	NumToFetch cjson.Uint64 `json:"numToFetch"`
+}
+
+// APIJournalEntry is a recorded change of the validator set
+type APIJournalEntry struct {
+	Index      cjson.Uint64      `json:"index"`
+	BlockID    ids.ID            `json:"blockID"`
+	Height     cjson.Uint64      `json:"height"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Added      []APIValidator    `json:"added"`
+	Removed    []APIValidator    `json:"removed"`
+	Reweighted []APIWeightChange `json:"reweighted"`
+}
+
+// GetJournalReply are the results from calling GetJournal
+type GetJournalReply struct {
+	Entries    []APIJournalEntry `json:"entries"`
+	NumEntries cjson.Uint64      `json:"numEntries"`
+}
+
+// GetJournal returns the recorded changes of the validator set, starting at
+// the entry with index [args.StartIndex]
+func (service *Validators) GetJournal(_ *http.Request, args *GetJournalArgs, reply *GetJournalReply) error {
+	service.Log.Debug("Validators: GetJournal called with StartIndex: %d, NumToFetch: %d", args.StartIndex, args.NumToFetch)
+
+	if service.Journal == nil {
+		return errNoJournal
+	}
+	entries, err := service.Journal.GetEntries(uint64(args.StartIndex), uint64(args.NumToFetch))
+	if err != nil {
+		return err
+	}
+
+	reply.Entries = make([]APIJournalEntry, len(entries))
+	for i, entry := range entries {
+		reply.Entries[i] = APIJournalEntry{
+			Index:      cjson.Uint64(entry.Index),
+			BlockID:    entry.BlockID,
+			Height:     cjson.Uint64(entry.Height),
+			Timestamp:  time.Unix(entry.Timestamp, 0).UTC(),
+			Added:      toAPIJournalValidators(entry.Added),
+			Removed:    toAPIJournalValidators(entry.Removed),
+			Reweighted: toAPIWeightChanges(entry.Reweighted),
+		}
+	}
+	reply.NumEntries = cjson.Uint64(service.Journal.NumEntries())
+	return nil
+}
+
+func resolveBlock(source chains.ValidatorsSource, ref *BlockRef) (ids.ID, error) {
+	switch {
+	case ref.BlockID != "" && ref.Height != nil:
+		return ids.Empty, errBlockIDAndHeight
+	case ref.BlockID != "":
+		return parseBlockID(ref.BlockID)
+	case ref.Height != nil:
+		blockID, err := source.GetBlockIDAtHeight(uint64(*ref.Height))
+		if err != nil {
+			return ids.Empty, fmt.Errorf("couldn't get block at height %d: %w", *ref.Height, err)
+		}
+		return blockID, nil
+	default:
+		return source.LastAccepted()
+	}
+}
+
+// parseBlockID accepts both the CB58 encoding used throughout the node and the
+// hex encoding used by the EVM for block hashes.
+func parseBlockID(s string) (ids.ID, error) {
+	if blockID, err := ids.FromString(s); err == nil {
+		return blockID, nil
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return ids.Empty, errInvalidBlockID
+	}
+	blockID, err := ids.ToID(b)
+	if err != nil {
+		return ids.Empty, errInvalidBlockID
+	}
+	return blockID, nil
+}
+
+func toAPIValidators(vdrs []validation.Validator) []APIValidator {
+	apiVdrs := make([]APIValidator, len(vdrs))
+	for i, vdr := range vdrs {
+		apiVdrs[i] = APIValidator{
+			NodeID: vdr.ID().PrefixedString(constants.NodeIDPrefix),
+			Weight: cjson.Uint64(vdr.Weight()),
+		}
+	}
+	return apiVdrs
+}
+
+func toAPIJournalValidators(vdrs []validation.JournalValidator) []APIValidator {
+	apiVdrs := make([]APIValidator, len(vdrs))
+	for i, vdr := range vdrs {
+		apiVdrs[i] = APIValidator{
+			NodeID: vdr.NodeID.PrefixedString(constants.NodeIDPrefix),
+			Weight: cjson.Uint64(vdr.Weight),
+		}
+	}
+	return apiVdrs
+}
+
+func toAPIWeightChanges(changes []validation.WeightChange) []APIWeightChange {
+	apiChanges := make([]APIWeightChange, len(changes))
+	for i, change := range changes {
+		apiChanges[i] = APIWeightChange{
+			NodeID:     change.NodeID.PrefixedString(constants.NodeIDPrefix),
+			PrevWeight: cjson.Uint64(change.PrevWeight),
+			Weight:     cjson.Uint64(change.Weight),
+		}
+	}
+	return apiChanges
+}
@@ -0,0 +1,221 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/validation"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+
+	cjson "github.com/flare-foundation/flare/utils/json"
+)
+
+var errUnknownBlock = errors.New("unknown block")
+
+type testSource struct {
+	sets         map[ids.ID]validation.Set
+	heights      map[uint64]ids.ID
+	lastAccepted ids.ID
+}
+
+func (s *testSource) GetValidators(blockID ids.ID) (validation.Set, error) {
+	set, ok := s.sets[blockID]
+	if !ok {
+		return nil, errUnknownBlock
+	}
+	return set, nil
+}
+
+func (s *testSource) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	blockID, ok := s.heights[height]
+	if !ok {
+		return ids.Empty, errUnknownBlock
+	}
+	return blockID, nil
+}
+
+func (s *testSource) LastAccepted() (ids.ID, error) { return s.lastAccepted, nil }
+
+type testManager struct {
+	chains.MockManager
+	source chains.ValidatorsSource
+}
+
+func (m *testManager) ValidatorsSource() (chains.ValidatorsSource, error) {
+	return m.source, nil
+}
+
+func newTestService(t *testing.T) (*Validators, ids.ShortID, ids.ShortID, ids.ShortID) {
+	kept := ids.ShortID{1}
+	removed := ids.ShortID{2}
+	added := ids.ShortID{3}
+
+	first := validation.NewSet()
+	require.NoError(t, first.AddWeight(kept, 10))
+	require.NoError(t, first.AddWeight(removed, 20))
+
+	second := validation.NewSet()
+	require.NoError(t, second.AddWeight(kept, 15))
+	require.NoError(t, second.AddWeight(added, 30))
+
+	firstID := ids.ID{1}
+	secondID := ids.ID{2}
+	source := &testSource{
+		sets: map[ids.ID]validation.Set{
+			firstID:  first,
+			secondID: second,
+		},
+		heights: map[uint64]ids.ID{
+			1: firstID,
+			2: secondID,
+		},
+		lastAccepted: secondID,
+	}
+
+	service := &Validators{Config: Config{
+		Log:          logging.NoLog{},
+		ChainManager: &testManager{source: source},
+	}}
+	return service, kept, removed, added
+}
+
+func TestGetValidatorsAt(t *testing.T) {
+	service, kept, removed, _ := newTestService(t)
+
+	height := cjson.Uint64(1)
+	reply := GetValidatorsAtReply{}
+	err := service.GetValidatorsAt(nil, &BlockRef{Height: &height}, &reply)
+	require.NoError(t, err)
+
+	assert.Equal(t, ids.ID{1}, reply.BlockID)
+	assert.EqualValues(t, 30, reply.TotalWeight)
+	assert.Equal(t, []APIValidator{
+		{NodeID: kept.PrefixedString(constants.NodeIDPrefix), Weight: 10},
+		{NodeID: removed.PrefixedString(constants.NodeIDPrefix), Weight: 20},
+	}, reply.Validators)
+}
+
+func TestGetValidatorsAtLastAccepted(t *testing.T) {
+	service, _, _, _ := newTestService(t)
+
+	reply := GetValidatorsAtReply{}
+	err := service.GetValidatorsAt(nil, &BlockRef{}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, ids.ID{2}, reply.BlockID)
+	assert.EqualValues(t, 45, reply.TotalWeight)
+}
+
+func TestGetValidatorsAtHexBlockID(t *testing.T) {
+	service, _, _, _ := newTestService(t)
+
+	blockID := ids.ID{1}
+	reply := GetValidatorsAtReply{}
+	err := service.GetValidatorsAt(nil, &BlockRef{BlockID: "0x" + blockID.Hex()}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, blockID, reply.BlockID)
+}
+
+func TestGetValidatorsAtInvalidArgs(t *testing.T) {
+	service, _, _, _ := newTestService(t)
+
+	height := cjson.Uint64(1)
+	reply := GetValidatorsAtReply{}
+	err := service.GetValidatorsAt(nil, &BlockRef{BlockID: ids.ID{1}.String(), Height: &height}, &reply)
+	assert.ErrorIs(t, err, errBlockIDAndHeight)
+
+	err = service.GetValidatorsAt(nil, &BlockRef{BlockID: "not an ID"}, &reply)
+	assert.ErrorIs(t, err, errInvalidBlockID)
+}
+
+func TestGetValidatorSetDiff(t *testing.T) {
+	service, kept, removed, added := newTestService(t)
+
+	from := cjson.Uint64(1)
+	to := cjson.Uint64(2)
+	reply := GetValidatorSetDiffReply{}
+	err := service.GetValidatorSetDiff(nil, &GetValidatorSetDiffArgs{
+		From: BlockRef{Height: &from},
+		To:   BlockRef{Height: &to},
+	}, &reply)
+	require.NoError(t, err)
+
+	assert.Equal(t, ids.ID{1}, reply.FromBlockID)
+	assert.Equal(t, ids.ID{2}, reply.ToBlockID)
+	assert.Equal(t, []APIValidator{
+		{NodeID: added.PrefixedString(constants.NodeIDPrefix), Weight: 30},
+	}, reply.Added)
+	assert.Equal(t, []APIValidator{
+		{NodeID: removed.PrefixedString(constants.NodeIDPrefix), Weight: 20},
+	}, reply.Removed)
+	assert.Equal(t, []APIWeightChange{
+		{NodeID: kept.PrefixedString(constants.NodeIDPrefix), PrevWeight: 10, Weight: 15},
+	}, reply.Reweighted)
+}
+
+func TestGetValidatorSetDiffUnknownHeight(t *testing.T) {
+	service, _, _, _ := newTestService(t)
+
+	from := cjson.Uint64(3)
+	reply := GetValidatorSetDiffReply{}
+	err := service.GetValidatorSetDiff(nil, &GetValidatorSetDiffArgs{
+		From: BlockRef{Height: &from},
+	}, &reply)
+	assert.ErrorIs(t, err, errUnknownBlock)
+}
+
+func TestGetJournal(t *testing.T) {
+	service, kept, removed, added := newTestService(t)
+
+	_, err := service.getJournalReply(0, 1)
+	assert.ErrorIs(t, err, errNoJournal)
+
+	journal, err := validation.NewJournal(memdb.New(), "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	service.Journal = journal
+	for _, blockID := range []ids.ID{{1}, {2}} {
+		set, err := service.ChainManager.(*testManager).source.GetValidators(blockID)
+		require.NoError(t, err)
+		require.NoError(t, journal.Record(blockID, uint64(blockID[0]), time.Unix(int64(blockID[0]), 0), set))
+	}
+
+	reply, err := service.getJournalReply(1, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, reply.NumEntries)
+	require.Len(t, reply.Entries, 1)
+
+	entry := reply.Entries[0]
+	assert.EqualValues(t, 1, entry.Index)
+	assert.Equal(t, ids.ID{2}, entry.BlockID)
+	assert.EqualValues(t, 2, entry.Height)
+	assert.Equal(t, time.Unix(2, 0).UTC(), entry.Timestamp)
+	assert.Equal(t, []APIValidator{
+		{NodeID: added.PrefixedString(constants.NodeIDPrefix), Weight: 30},
+	}, entry.Added)
+	assert.Equal(t, []APIValidator{
+		{NodeID: removed.PrefixedString(constants.NodeIDPrefix), Weight: 20},
+	}, entry.Removed)
+	assert.Equal(t, []APIWeightChange{
+		{NodeID: kept.PrefixedString(constants.NodeIDPrefix), PrevWeight: 10, Weight: 15},
+	}, entry.Reweighted)
+}
+
+func (service *Validators) getJournalReply(startIndex, numToFetch uint64) (*GetJournalReply, error) {
+	reply := &GetJournalReply{}
+	err := service.GetJournal(nil, &GetJournalArgs{
+		StartIndex: cjson.Uint64(startIndex),
+		NumToFetch: cjson.Uint64(numToFetch),
+	}, reply)
+	return reply, err
+}
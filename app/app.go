@@ -26,6 +26,14 @@ type App interface {
 	ExitCode() (int, error)
 }
 
+// Reloader is an App whose config can be reloaded while it's running
+type Reloader interface {
+	// Reload re-reads the config of the application and applies the changes
+	// that don't require a restart.
+	// Reload should only be called after [Start] returns with no error.
+	Reload() error
+}
+
 func Run(app App) int {
 	// start running the application
 	if err := app.Start(); err != nil {
@@ -46,9 +54,25 @@ func Run(app App) int {
 		return nil
 	})
 
+	// register SIGHUP to reload the config of the application
+	reloads := make(chan os.Signal, 1)
+	if reloader, ok := app.(Reloader); ok {
+		signal.Notify(reloads, syscall.SIGHUP)
+		go func() {
+			for range reloads {
+				// Errors are reported by the application
+				_ = reloader.Reload()
+			}
+		}()
+	}
+
 	// wait for the app to exit and get the exit code response
 	exitCode, err := app.ExitCode()
 
+	// stop reloading the config
+	signal.Stop(reloads)
+	close(reloads)
+
 	// shut down the signal go routine
 	signal.Stop(signals)
 	close(signals)
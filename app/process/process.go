@@ -5,14 +5,10 @@ package process
 
 import (
 	"fmt"
-	"path/filepath"
 	"sync"
 
 	"github.com/flare-foundation/flare/app"
-	"github.com/flare-foundation/flare/database/leveldb"
 	"github.com/flare-foundation/flare/database/manager"
-	"github.com/flare-foundation/flare/database/memdb"
-	"github.com/flare-foundation/flare/database/rocksdb"
 	"github.com/flare-foundation/flare/nat"
 	"github.com/flare-foundation/flare/node"
 	"github.com/flare-foundation/flare/utils/constants"
@@ -40,7 +36,8 @@ var (
 	stakingPortName = fmt.Sprintf("%s-staking", constants.AppName)
 	httpPortName    = fmt.Sprintf("%s-http", constants.AppName)
 
-	_ app.App = &process{}
+	_ app.App      = &process{}
+	_ app.Reloader = &process{}
 )
 
 // process is a wrapper around a node that runs in this process
@@ -86,24 +83,13 @@ func (p *process) Start() error {
 	}
 
 	// start the db manager
-	var dbManager manager.Manager
-	switch p.config.DatabaseConfig.Name {
-	case rocksdb.Name:
-		path := filepath.Join(p.config.DatabaseConfig.Path, rocksdb.Name)
-		dbManager, err = manager.NewRocksDB(path, p.config.DatabaseConfig.Config, log, version.CurrentDatabase)
-	case leveldb.Name:
-		dbManager, err = manager.NewLevelDB(p.config.DatabaseConfig.Path, p.config.DatabaseConfig.Config, log, version.CurrentDatabase)
-	case memdb.Name:
-		dbManager = manager.NewMemDB(version.CurrentDatabase)
-	default:
-		err = fmt.Errorf(
-			"db-type was %q but should have been one of {%s, %s, %s}",
-			p.config.DatabaseConfig.Name,
-			leveldb.Name,
-			rocksdb.Name,
-			memdb.Name,
-		)
-	}
+	dbManager, err := manager.New(
+		p.config.DatabaseConfig.Name,
+		p.config.DatabaseConfig.Path,
+		p.config.DatabaseConfig.Config,
+		log,
+		version.CurrentDatabase,
+	)
 	if err != nil {
 		log.Fatal("couldn't create %q db manager at %s: %s", p.config.DatabaseConfig.Name, p.config.DatabaseConfig.Path, err)
 		logFactory.Close()
@@ -221,6 +207,13 @@ func (p *process) Stop() error {
 	return nil
 }
 
+// Reload re-reads the node's config and applies the changes that don't require
+// a restart. The outcome is logged by the node.
+func (p *process) Reload() error {
+	_, _, err := p.node.ReloadConfig()
+	return err
+}
+
 // ExitCode returns the exit code that the node is reporting. This function
 // blocks until the node has been shut down.
 func (p *process) ExitCode() (int, error) {
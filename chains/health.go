@@ -0,0 +1,205 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/api/health"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/networking/handler"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+)
+
+var (
+	errUnknownState = errors.New("unknown state; could not check health")
+
+	_ snow.Acceptor = &chainHealth{}
+)
+
+// HealthConfig of the health check of a chain. The zero value only reports the
+// health of the chain's consensus engine and VM.
+type HealthConfig struct {
+	// Reports unhealthy if no container was accepted for longer than this,
+	// once the chain is bootstrapped. Disabled if zero.
+	MaxTimeSinceAccepted time.Duration `json:"maxTimeSinceAccepted"`
+
+	// Reports unhealthy if more than this number of messages are waiting to
+	// be handled by the chain. Disabled if zero.
+	MaxPendingMessages int `json:"maxPendingMessages"`
+
+	// Tags of the health check, in addition to the ID and aliases of the chain
+	// and the ID of its subnet
+	Tags []string `json:"tags"`
+}
+
+// Valid returns an error if the config can't be used
+func (c *HealthConfig) Valid() error {
+	switch {
+	case c.MaxTimeSinceAccepted < 0:
+		return fmt.Errorf("maxTimeSinceAccepted (%s) can't be negative", c.MaxTimeSinceAccepted)
+	case c.MaxPendingMessages < 0:
+		return fmt.Errorf("maxPendingMessages (%d) can't be negative", c.MaxPendingMessages)
+	default:
+		return nil
+	}
+}
+
+// chainHealth checks the health of a chain's engines and, if configured, how
+// long ago the chain accepted a container and how many messages are waiting to
+// be handled by the chain.
+type chainHealth struct {
+	ctx          *snow.ConsensusContext
+	config       HealthConfig
+	handler      handler.Handler
+	bootstrapper health.Checker
+	engine       health.Checker
+	clock        mockable.Clock
+
+	lock sync.Mutex
+	// The last time a container was accepted, or the time the chain was
+	// created if no container was accepted since
+	lastAccepted time.Time
+}
+
+func newChainHealth(
+	ctx *snow.ConsensusContext,
+	config HealthConfig,
+	handler handler.Handler,
+	bootstrapper health.Checker,
+	engine health.Checker,
+) *chainHealth {
+	c := &chainHealth{
+		ctx:          ctx,
+		config:       config,
+		handler:      handler,
+		bootstrapper: bootstrapper,
+		engine:       engine,
+	}
+	c.lastAccepted = c.clock.Time()
+	return c
+}
+
+// Accept records the time at which the chain accepted a container
+func (c *chainHealth) Accept(*snow.ConsensusContext, ids.ID, []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lastAccepted = c.clock.Time()
+	return nil
+}
+
+func (c *chainHealth) HealthCheck() (interface{}, error) {
+	// Grab the context lock before calling the chain's health check
+	c.ctx.Lock.Lock()
+	state := c.ctx.GetState()
+	var (
+		engineDetails interface{}
+		engineErr     error
+	)
+	switch state {
+	case snow.StateSyncing:
+		if stateSyncer := c.handler.StateSyncer(); stateSyncer != nil {
+			engineDetails, engineErr = stateSyncer.HealthCheck()
+		} else {
+			engineErr = errUnknownState
+		}
+	case snow.Bootstrapping:
+		engineDetails, engineErr = c.bootstrapper.HealthCheck()
+	case snow.NormalOp:
+		engineDetails, engineErr = c.engine.HealthCheck()
+	default:
+		engineErr = errUnknownState
+	}
+	c.ctx.Lock.Unlock()
+
+	if c.config.MaxTimeSinceAccepted == 0 && c.config.MaxPendingMessages == 0 {
+		return engineDetails, engineErr
+	}
+
+	details := map[string]interface{}{
+		"engine": engineDetails,
+	}
+	var errorReasons []string
+	if engineErr != nil {
+		errorReasons = append(errorReasons, engineErr.Error())
+	}
+
+	if c.config.MaxTimeSinceAccepted != 0 && state == snow.NormalOp {
+		c.lock.Lock()
+		timeSinceAccepted := c.clock.Time().Sub(c.lastAccepted)
+		c.lock.Unlock()
+
+		details["timeSinceAccepted"] = timeSinceAccepted.String()
+		if timeSinceAccepted > c.config.MaxTimeSinceAccepted {
+			errorReasons = append(errorReasons, fmt.Sprintf("time since a container was accepted %s > %s", timeSinceAccepted, c.config.MaxTimeSinceAccepted))
+		}
+	}
+	if c.config.MaxPendingMessages != 0 {
+		pendingMessages := c.handler.Len()
+		details["pendingMessages"] = pendingMessages
+		if pendingMessages > c.config.MaxPendingMessages {
+			errorReasons = append(errorReasons, fmt.Sprintf("number of pending messages %d > %d", pendingMessages, c.config.MaxPendingMessages))
+		}
+	}
+
+	if len(errorReasons) > 0 {
+		return details, fmt.Errorf("the chain is not healthy reason: %s", strings.Join(errorReasons, ", "))
+	}
+	return details, nil
+}
+
+// healthConfig returns the health config of [chainID], which is the config of
+// the chain's ID or of one of its [aliases] if set in its subnet's config, or
+// the default health config of its subnet otherwise.
+func (m *manager) healthConfig(subnetID, chainID ids.ID, aliases []string) HealthConfig {
+	subnetConfig, ok := m.SubnetConfigs[subnetID]
+	if !ok {
+		return HealthConfig{}
+	}
+	if config, ok := subnetConfig.ChainHealth[chainID.String()]; ok {
+		return config
+	}
+	for _, alias := range aliases {
+		if config, ok := subnetConfig.ChainHealth[alias]; ok {
+			return config
+		}
+	}
+	return subnetConfig.Health
+}
+
+// registerHealthCheck registers the health check of the chain of [ctx], tagged
+// with the chain's ID and aliases, the ID of its subnet, and its configured
+// tags.
+func (m *manager) registerHealthCheck(
+	ctx *snow.ConsensusContext,
+	h handler.Handler,
+	bootstrapper health.Checker,
+	engine health.Checker,
+) error {
+	chainAlias := m.PrimaryAliasOrDefault(ctx.ChainID)
+	aliases, _ := m.Aliases(ctx.ChainID)
+	config := m.healthConfig(ctx.SubnetID, ctx.ChainID, aliases)
+
+	tags := make([]string, 0, len(aliases)+len(config.Tags)+2)
+	tags = append(tags, ctx.ChainID.String(), ctx.SubnetID.String())
+	tags = append(tags, aliases...)
+	tags = append(tags, config.Tags...)
+
+	check := newChainHealth(ctx, config, h, bootstrapper, engine)
+	if config.MaxTimeSinceAccepted != 0 {
+		if err := m.ConsensusEvents.RegisterChain(ctx.ChainID, "health", check, false); err != nil {
+			return fmt.Errorf("couldn't track the accepted containers of chain %s: %w", chainAlias, err)
+		}
+	}
+	if err := m.Health.RegisterHealthCheck(chainAlias, check, tags...); err != nil {
+		return fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
+	}
+	return nil
+}
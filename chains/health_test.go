@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/api/health"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+)
+
+func TestChainHealthMaxTimeSinceAccepted(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.SetState(snow.Bootstrapping)
+	errBootstrapping := errors.New("bootstrapping")
+	bootstrapper := health.CheckerFunc(func() (interface{}, error) {
+		return nil, errBootstrapping
+	})
+	engine := health.CheckerFunc(func() (interface{}, error) {
+		return "engine", nil
+	})
+
+	check := newChainHealth(ctx, HealthConfig{MaxTimeSinceAccepted: time.Minute}, nil, bootstrapper, engine)
+	now := time.Now()
+	check.clock.Set(now)
+	assert.NoError(check.Accept(ctx, ids.GenerateTestID(), nil))
+
+	// The time since the last accepted container isn't checked while
+	// bootstrapping
+	check.clock.Set(now.Add(time.Hour))
+	_, err := check.HealthCheck()
+	assert.Error(err)
+	assert.Contains(err.Error(), errBootstrapping.Error())
+	assert.NotContains(err.Error(), "time since a container was accepted")
+
+	ctx.SetState(snow.NormalOp)
+	details, err := check.HealthCheck()
+	assert.Error(err)
+	assert.Contains(err.Error(), "time since a container was accepted")
+	assert.Equal("engine", details.(map[string]interface{})["engine"])
+
+	assert.NoError(check.Accept(ctx, ids.GenerateTestID(), nil))
+	_, err = check.HealthCheck()
+	assert.NoError(err)
+}
+
+func TestChainHealthWithoutThresholds(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.SetState(snow.NormalOp)
+	engine := health.CheckerFunc(func() (interface{}, error) {
+		return "engine", nil
+	})
+
+	// The details of the engine are reported as is
+	check := newChainHealth(ctx, HealthConfig{}, nil, nil, engine)
+	details, err := check.HealthCheck()
+	assert.NoError(err)
+	assert.Equal("engine", details)
+}
+
+func TestHealthConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	subnetID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	otherChainID := ids.GenerateTestID()
+	subnetHealth := HealthConfig{MaxPendingMessages: 1}
+	chainHealth := HealthConfig{MaxPendingMessages: 2}
+	aliasHealth := HealthConfig{MaxPendingMessages: 3}
+
+	m := &manager{ManagerConfig: ManagerConfig{
+		SubnetConfigs: map[ids.ID]SubnetConfig{
+			subnetID: {
+				Health: subnetHealth,
+				ChainHealth: map[string]HealthConfig{
+					chainID.String(): chainHealth,
+					"alias":          aliasHealth,
+				},
+			},
+		},
+	}}
+	assert.Equal(chainHealth, m.healthConfig(subnetID, chainID, []string{"alias"}))
+	assert.Equal(aliasHealth, m.healthConfig(subnetID, otherChainID, []string{"alias"}))
+	assert.Equal(subnetHealth, m.healthConfig(subnetID, otherChainID, nil))
+	assert.Equal(HealthConfig{}, m.healthConfig(ids.GenerateTestID(), chainID, nil))
+}
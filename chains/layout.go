@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"github.com/flare-foundation/flare/database/inspect"
+)
+
+// AddLayout adds the prefixes of the chain described by [info] to [layout],
+// where [db] is the prefix of the database given to the manager. It returns
+// the prefix of the database given to the chain's VM.
+func AddLayout(layout *inspect.Layout, db inspect.Prefix, info ChainInfo, chain string) inspect.Prefix {
+	chainDB := db.New(info.ID[:])
+	layout.Add(chain, "consensus", chainDB)
+	if info.DAG {
+		layout.Add(chain, "consensus/vertices", chainDB.New(vertexDBPrefix))
+		layout.Add(chain, "bootstrapping/vertices", chainDB.New(vertexBootstrappingDBPrefix))
+		layout.Add(chain, "bootstrapping/txs", chainDB.New(txBootstrappingDBPrefix))
+	} else {
+		layout.Add(chain, "bootstrapping/blocks", chainDB.New(bootstrappingDBPrefix))
+	}
+
+	vmDB := chainDB.New(vmDBPrefix)
+	layout.Add(chain, "vm", vmDB)
+	return vmDB
+}
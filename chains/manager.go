@@ -4,10 +4,12 @@
 package chains
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -43,6 +45,7 @@ import (
 	"github.com/flare-foundation/flare/vms"
 	"github.com/flare-foundation/flare/vms/metervm"
 	"github.com/flare-foundation/flare/vms/proposervm"
+	"github.com/flare-foundation/flare/vms/proposervm/proposer"
 
 	dbManager "github.com/flare-foundation/flare/database/manager"
 
@@ -55,6 +58,7 @@ import (
 	smeng "github.com/flare-foundation/flare/snow/engine/snowman"
 	smbootstrap "github.com/flare-foundation/flare/snow/engine/snowman/bootstrap"
 	snowgetter "github.com/flare-foundation/flare/snow/engine/snowman/getter"
+	smsyncer "github.com/flare-foundation/flare/snow/engine/snowman/syncer"
 )
 
 const defaultChannelSize = 1
@@ -64,6 +68,13 @@ var (
 	errUnknownVMType    = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
 	errCreatePlatformVM = errors.New("attempted to create a chain running the PlatformVM")
 
+	// Prefixes of the databases of a chain
+	vmDBPrefix                  = []byte("vm")
+	vertexDBPrefix              = []byte("vertex")
+	vertexBootstrappingDBPrefix = []byte("vertex_bs")
+	txBootstrappingDBPrefix     = []byte("tx_bs")
+	bootstrappingDBPrefix       = []byte("bs")
+
 	_ Manager = &manager{}
 )
 
@@ -101,6 +112,13 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns the chains that were created, sorted by chain ID
+	Chains() []ChainInfo
+
+	// Returns the source of validator sets, once the chain providing them has
+	// been created
+	ValidatorsSource() (ValidatorsSource, error)
+
 	Shutdown()
 }
 
@@ -120,6 +138,17 @@ type chain struct {
 	Engine  common.Engine
 	Handler handler.Handler
 	Beacons validation.Set
+	Info    ChainInfo
+}
+
+// ChainInfo describes a chain that was created
+type ChainInfo struct {
+	ID       ids.ID
+	SubnetID ids.ID
+	VMID     ids.ID
+	// DAG is true if the chain runs an avalanche.DAGVM rather than a
+	// snowman.ChainVM
+	DAG bool
 }
 
 // ChainConfig is configuration settings for the current execution.
@@ -128,6 +157,8 @@ type chain struct {
 type ChainConfig struct {
 	Config  []byte
 	Upgrade []byte
+	// Proposer configures the windowing policies of the chain's proposervm
+	Proposer []byte
 }
 
 type ManagerConfig struct {
@@ -139,14 +170,15 @@ type ManagerConfig struct {
 	DecisionEvents              *triggers.EventDispatcher
 	ConsensusEvents             *triggers.EventDispatcher
 	DBManager                   dbManager.Manager
-	MsgCreator                  message.Creator  // message creator, shared with network
-	Router                      router.Router    // Routes incoming messages to the appropriate chain
-	Net                         network.Network  // Sends consensus messages to other validators
-	ConsensusParams             avcon.Parameters // The consensus parameters (alpha, beta, etc.) for new chains
-	Validators                  validation.Set   // Validators validating the chain
-	NodeID                      ids.ShortID      // The ID of this node
-	NetworkID                   uint32           // ID of the network this node is connected to
-	Server                      server.Server    // Handles HTTP API calls
+	MsgCreator                  message.Creator    // message creator, shared with network
+	Router                      router.Router      // Routes incoming messages to the appropriate chain
+	Net                         network.Network    // Sends consensus messages to other validators
+	ConsensusParams             avcon.Parameters   // The consensus parameters (alpha, beta, etc.) for new chains
+	Validators                  validation.Set     // Validators validating the chain
+	ValidatorJournal            validation.Journal // Records changes of [Validators]
+	NodeID                      ids.ShortID        // The ID of this node
+	NetworkID                   uint32             // ID of the network this node is connected to
+	Server                      server.Server      // Handles HTTP API calls
 	Keystore                    keystore.Keystore
 	AtomicMemory                *atomic.Memory
 	AVAXAssetID                 ids.ID
@@ -166,6 +198,9 @@ type ManagerConfig struct {
 
 	ConsensusGossipFrequency time.Duration
 
+	// Weight of each message class in the handlers' message queues
+	ConsensusMessageClassWeights map[handler.MessageClass]uint64
+
 	GossipConfig sender.GossipConfig
 
 	// Max Time to spend fetching a container and its
@@ -181,6 +216,9 @@ type ManagerConfig struct {
 	ApricotPhase4MinPChainHeight uint64
 
 	ResetProposerVMHeightIndex bool
+
+	// Validators of the network if the EVM doesn't provide them
+	ValidatorsSchedule *validation.Schedule
 }
 
 type manager struct {
@@ -203,9 +241,17 @@ type manager struct {
 	// Key: Chain's ID
 	// Value: The chain
 	chains map[ids.ID]handler.Handler
+	// Key: Chain's ID
+	// Value: The description of the chain
+	chainInfos map[ids.ID]ChainInfo
 
 	// snowman++ related interface to allow retrieval of P-chain height
 	platformVMState platform.VMState
+
+	// Validator sets of the chain implementing [validation.Retriever]. Nil
+	// until that chain has been created.
+	validatorsSourceLock sync.RWMutex
+	validatorsSource     ValidatorsSource
 }
 
 // New returns a new Manager
@@ -215,6 +261,7 @@ func New(config *ManagerConfig) Manager {
 		ManagerConfig: *config,
 		subnets:       make(map[ids.ID]Subnet),
 		chains:        make(map[ids.ID]handler.Handler),
+		chainInfos:    make(map[ids.ID]ChainInfo),
 	}
 }
 
@@ -285,6 +332,7 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 
 	m.chainsLock.Lock()
 	m.chains[chainParams.ID] = chain.Handler
+	m.chainInfos[chainParams.ID] = chain.Info
 	m.chainsLock.Unlock()
 
 	// Associate the newly created chain with its default alias
@@ -302,8 +350,13 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
-	// Notify the bootstrapper that it has started executing.
-	err = chain.Handler.Bootstrapper().Start(0)
+	// Notify the state syncer, or the bootstrapper if the chain doesn't state
+	// sync, that it has started executing.
+	if stateSyncer := chain.Handler.StateSyncer(); stateSyncer != nil {
+		err = stateSyncer.Start(0)
+	} else {
+		err = chain.Handler.Bootstrapper().Start(0)
+	}
 
 	// Tell the chain to start processing messages.
 	// If the X, P, or C Chain panics, do not attempt to recover
@@ -380,7 +433,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	// cause a panic.
 	ctx.SetState(snow.Bootstrapping)
 
-	if sbConfigs, ok := m.SubnetConfigs[chainParams.SubnetID]; ok {
+	if sbConfigs, ok := m.SubnetConfigs[chainParams.SubnetID]; ok && chainParams.SubnetID != constants.PrimaryNetworkID {
 		if sbConfigs.ValidatorOnly {
 			ctx.SetValidatorOnly()
 		}
@@ -457,6 +510,7 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	case block.ChainVM:
 		chain, err = m.createSnowmanChain(
 			ctx,
+			vmID,
 			chainParams.GenesisData,
 			m.Validators,
 			beacons,
@@ -472,6 +526,12 @@ func (m *manager) buildChain(chainParams ChainParameters, sb Subnet) (*chain, er
 	default:
 		return nil, errUnknownVMType
 	}
+	chain.Info = ChainInfo{
+		ID:       chainParams.ID,
+		SubnetID: chainParams.SubnetID,
+		VMID:     vmID,
+	}
+	_, chain.Info.DAG = vm.(vertex.DAGVM)
 
 	// Register the chain with the timeout manager
 	if err := m.TimeoutManager.RegisterChain(ctx); err != nil {
@@ -512,12 +572,12 @@ func (m *manager) createAvalancheChain(
 		return nil, err
 	}
 	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
-	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
+	vmDBManager := prefixDBManager.NewPrefixDBManager(vmDBPrefix)
 
 	db := prefixDBManager.Current()
-	vertexDB := prefixdb.New([]byte("vertex"), db.Database)
-	vertexBootstrappingDB := prefixdb.New([]byte("vertex_bs"), db.Database)
-	txBootstrappingDB := prefixdb.New([]byte("tx_bs"), db.Database)
+	vertexDB := prefixdb.New(vertexDBPrefix, db.Database)
+	vertexBootstrappingDB := prefixdb.New(vertexBootstrappingDBPrefix, db.Database)
+	txBootstrappingDB := prefixdb.New(txBootstrappingDBPrefix, db.Database)
 
 	vtxBlocker, err := queue.NewWithMissing(vertexBootstrappingDB, "vtx", ctx.Registerer)
 	if err != nil {
@@ -600,6 +660,7 @@ func (m *manager) createAvalancheChain(
 		msgChan,
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
+		m.ConsensusMessageClassWeights,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing network handler: %w", err)
@@ -669,22 +730,8 @@ func (m *manager) createAvalancheChain(
 
 	// Register health check for this chain
 	chainAlias := m.PrimaryAliasOrDefault(ctx.ChainID)
-
-	// Grab the context lock before calling the chain's health check
-	check := health.CheckerFunc(func() (interface{}, error) {
-		ctx.Lock.Lock()
-		defer ctx.Lock.Unlock()
-		switch ctx.GetState() {
-		case snow.Bootstrapping:
-			return bootstrapper.HealthCheck()
-		case snow.NormalOp:
-			return engine.HealthCheck()
-		default:
-			return nil, fmt.Errorf("unknown state; could not check health")
-		}
-	})
-	if err := m.Health.RegisterHealthCheck(chainAlias, check); err != nil {
-		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
+	if err := m.registerHealthCheck(ctx, handler, bootstrapper, engine); err != nil {
+		return nil, err
 	}
 
 	return &chain{
@@ -697,6 +744,7 @@ func (m *manager) createAvalancheChain(
 // Create a linear chain using the Snowman consensus engine
 func (m *manager) createSnowmanChain(
 	ctx *snow.ConsensusContext,
+	vmID ids.ID,
 	genesisData []byte,
 	validators validation.Set,
 	beacons validation.Set,
@@ -714,10 +762,10 @@ func (m *manager) createSnowmanChain(
 		return nil, err
 	}
 	prefixDBManager := meterDBManager.NewPrefixDBManager(ctx.ChainID[:])
-	vmDBManager := prefixDBManager.NewPrefixDBManager([]byte("vm"))
+	vmDBManager := prefixDBManager.NewPrefixDBManager(vmDBPrefix)
 
 	db := prefixDBManager.Current()
-	bootstrappingDB := prefixdb.New([]byte("bs"), db.Database)
+	bootstrappingDB := prefixdb.New(bootstrappingDBPrefix, db.Database)
 
 	blocked, err := queue.NewWithMissing(bootstrappingDB, "block", ctx.Registerer)
 	if err != nil {
@@ -769,10 +817,32 @@ func (m *manager) createSnowmanChain(
 	// called by the EVM every time the last accepted block changes in order to
 	// propagate validator changes across all components.
 	// We wrap the retriever into a caching retriever to improve performance.
+	// If the EVM doesn't provide the validators, we fall back to the static
+	// validators schedule from the node config, if there is one.
 	retriever, ok := vm.(validation.Retriever)
+	if !ok && vmID == constants.EVMID && m.ValidatorsSchedule != nil {
+		m.Log.Info("using the configured validators schedule for %s", ctx.ChainID)
+		innerVM := vm
+		retriever = validation.NewScheduleRetriever(m.ValidatorsSchedule, func(blockID ids.ID) (uint64, error) {
+			blk, err := innerVM.GetBlock(blockID)
+			if err != nil {
+				return 0, err
+			}
+			return blk.Height(), nil
+		})
+		ok = true
+	}
 	if ok {
 		ctx.ValidatorsRetriever = validation.NewCachingRetriever(retriever)
-		ctx.ValidatorsUpdater = validation.NewRetrievingUpdater(m.Log, m.Validators, ctx.ValidatorsRetriever)
+		ctx.ValidatorsUpdater = validation.NewRetrievingUpdater(m.Log, m.Validators, ctx.ValidatorsRetriever, m.ValidatorJournal)
+
+		m.validatorsSourceLock.Lock()
+		m.validatorsSource = &lockedValidatorsSource{
+			lock:      &ctx.Lock,
+			retriever: ctx.ValidatorsRetriever,
+			vm:        vm,
+		}
+		m.validatorsSourceLock.Unlock()
 	}
 
 	// Initialize the ProposerVM and the vm wrapped inside it
@@ -781,8 +851,13 @@ func (m *manager) createSnowmanChain(
 		return nil, fmt.Errorf("error while fetching chain config: %w", err)
 	}
 
+	windowingPolicies, err := proposer.ParsePolicies(chainConfig.Proposer)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing proposer config: %w", err)
+	}
+
 	// enable ProposerVM on this VM
-	vm = proposervm.New(vm, m.ApricotPhase4Time, m.ApricotPhase4MinPChainHeight, m.ResetProposerVMHeightIndex)
+	vm = proposervm.New(vm, m.ApricotPhase4Time, m.ApricotPhase4MinPChainHeight, m.ResetProposerVMHeightIndex, windowingPolicies)
 
 	if m.MeterVMEnabled {
 		vm = metervm.NewBlockVM(vm)
@@ -813,6 +888,7 @@ func (m *manager) createSnowmanChain(
 		msgChan,
 		sb.afterBootstrapped(),
 		m.ConsensusGossipFrequency,
+		m.ConsensusMessageClassWeights,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't initialize message handler: %w", err)
@@ -862,6 +938,22 @@ func (m *manager) createSnowmanChain(
 	}
 	handler.SetBootstrapper(bootstrapper)
 
+	// create state sync gear. It has its own weight tracker so that the
+	// bootstrapper starts right away once state syncing is done.
+	stateSyncCfg := smsyncer.Config{
+		Config:        commonCfg,
+		AllGetsServer: snowGetHandler,
+		VM:            vm,
+		WeightTracker: tracker.NewWeightTracker(beacons, commonCfg.StartupAlpha),
+	}
+	stateSyncer := smsyncer.New(
+		stateSyncCfg,
+		func(lastReqID uint32) error {
+			return handler.Bootstrapper().Start(lastReqID + 1)
+		},
+	)
+	handler.SetStateSyncer(stateSyncer)
+
 	// create engine gear
 	engineConfig := smeng.Config{
 		Ctx:           bootstrapCfg.Ctx,
@@ -880,21 +972,8 @@ func (m *manager) createSnowmanChain(
 
 	// Register health checks
 	chainAlias := m.PrimaryAliasOrDefault(ctx.ChainID)
-
-	check := health.CheckerFunc(func() (interface{}, error) {
-		ctx.Lock.Lock()
-		defer ctx.Lock.Unlock()
-		switch ctx.GetState() {
-		case snow.Bootstrapping:
-			return bootstrapper.HealthCheck()
-		case snow.NormalOp:
-			return engine.HealthCheck()
-		default:
-			return nil, fmt.Errorf("unknown state; could not check health")
-		}
-	})
-	if err := m.Health.RegisterHealthCheck(chainAlias, check); err != nil {
-		return nil, fmt.Errorf("couldn't add health check for chain %s: %w", chainAlias, err)
+	if err := m.registerHealthCheck(ctx, handler, bootstrapper, engine); err != nil {
+		return nil, err
 	}
 
 	return &chain{
@@ -926,6 +1005,30 @@ func (m *manager) IsBootstrapped(id ids.ID) bool {
 	return chain.Context().GetState() == snow.NormalOp
 }
 
+func (m *manager) Chains() []ChainInfo {
+	m.chainsLock.Lock()
+	defer m.chainsLock.Unlock()
+
+	chains := make([]ChainInfo, 0, len(m.chainInfos))
+	for _, info := range m.chainInfos {
+		chains = append(chains, info)
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return bytes.Compare(chains[i].ID[:], chains[j].ID[:]) < 0
+	})
+	return chains
+}
+
+func (m *manager) ValidatorsSource() (ValidatorsSource, error) {
+	m.validatorsSourceLock.RLock()
+	defer m.validatorsSourceLock.RUnlock()
+
+	if m.validatorsSource == nil {
+		return nil, errNoValidatorsSource
+	}
+	return m.validatorsSource, nil
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() {
 	m.Log.Info("shutting down chain manager")
@@ -26,6 +26,11 @@ func (mm MockManager) RemoveAliases(ids.ID)                {}
 func (mm MockManager) Shutdown()                           {}
 func (mm MockManager) SubnetID(ids.ID) (ids.ID, error)     { return ids.ID{}, nil }
 func (mm MockManager) IsBootstrapped(ids.ID) bool          { return false }
+func (mm MockManager) Chains() []ChainInfo                 { return nil }
+
+func (mm MockManager) ValidatorsSource() (ValidatorsSource, error) {
+	return nil, errNoValidatorsSource
+}
 
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
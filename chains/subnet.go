@@ -4,6 +4,7 @@
 package chains
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/flare-foundation/flare/ids"
@@ -32,6 +33,24 @@ type SubnetConfig struct {
 	// ValidatorOnly indicates that this Subnet's Chains are available to only subnet validators.
 	ValidatorOnly       bool                 `json:"validatorOnly"`
 	ConsensusParameters avalanche.Parameters `json:"consensusParameters"`
+
+	// Health is the health config of the Subnet's Chains, unless overridden
+	// in [ChainHealth] by the ID or an alias of a Chain.
+	Health      HealthConfig            `json:"health"`
+	ChainHealth map[string]HealthConfig `json:"chainHealth"`
+}
+
+// ValidHealth returns an error if one of the health configs can't be used
+func (c *SubnetConfig) ValidHealth() error {
+	if err := c.Health.Valid(); err != nil {
+		return fmt.Errorf("invalid health config: %w", err)
+	}
+	for chain, config := range c.ChainHealth {
+		if err := config.Valid(); err != nil {
+			return fmt.Errorf("invalid health config of chain %q: %w", chain, err)
+		}
+	}
+	return nil
 }
 
 type subnet struct {
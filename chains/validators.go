@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/snow/validation"
+)
+
+var (
+	errNoValidatorsSource = errors.New("no chain provides validator sets yet")
+
+	_ ValidatorsSource = &lockedValidatorsSource{}
+)
+
+// ValidatorsSource gives access to the validator sets of the chain that
+// provides them (the EVM), outside of the chain's consensus engine.
+type ValidatorsSource interface {
+	validation.Retriever
+
+	// GetBlockIDAtHeight returns the ID of the block of the providing chain
+	// that was accepted at [height].
+	GetBlockIDAtHeight(height uint64) (ids.ID, error)
+
+	// LastAccepted returns the ID of the last accepted block of the providing
+	// chain.
+	LastAccepted() (ids.ID, error)
+}
+
+// lockedValidatorsSource grabs the context lock of the providing chain before
+// calling into its VM.
+type lockedValidatorsSource struct {
+	lock      sync.Locker
+	retriever validation.Retriever
+	vm        block.ChainVM
+}
+
+func (s *lockedValidatorsSource) GetValidators(blockID ids.ID) (validation.Set, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.retriever.GetValidators(blockID)
+}
+
+func (s *lockedValidatorsSource) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	hVM, ok := s.vm.(block.HeightIndexedChainVM)
+	if !ok {
+		return ids.Empty, block.ErrHeightIndexedVMNotImplemented
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := hVM.VerifyHeightIndex(); err != nil {
+		return ids.Empty, err
+	}
+	return hVM.GetBlockIDAtHeight(height)
+}
+
+func (s *lockedValidatorsSource) LastAccepted() (ids.ID, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.vm.LastAccepted()
+}
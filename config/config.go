@@ -11,18 +11,23 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/flare-foundation/flare/api/health"
 	"github.com/flare-foundation/flare/app/runner"
 	"github.com/flare-foundation/flare/chains"
 	"github.com/flare-foundation/flare/genesis"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/indexer"
 	"github.com/flare-foundation/flare/ipcs"
+	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/nat"
 	"github.com/flare-foundation/flare/network"
 	"github.com/flare-foundation/flare/network/dialer"
@@ -31,10 +36,13 @@ import (
 	"github.com/flare-foundation/flare/snow/consensus/avalanche"
 	"github.com/flare-foundation/flare/snow/consensus/snowball"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/snow/networking/handler"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/snow/networking/sender"
+	"github.com/flare-foundation/flare/snow/validation"
 	"github.com/flare-foundation/flare/staking"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/dynamicip"
 	"github.com/flare-foundation/flare/utils/logging"
@@ -46,10 +54,11 @@ import (
 )
 
 const (
-	pluginsDirName       = "plugins"
-	chainConfigFileName  = "config"
-	chainUpgradeFileName = "upgrade"
-	subnetConfigFileExt  = ".json"
+	pluginsDirName        = "plugins"
+	chainConfigFileName   = "config"
+	chainUpgradeFileName  = "upgrade"
+	chainProposerFileName = "proposer"
+	subnetConfigFileExt   = ".json"
 )
 
 var (
@@ -65,6 +74,7 @@ var (
 	errMinStakeDurationAboveMax      = errors.New("max stake duration can't be less than min stake duration")
 	errStakeMaxConsumptionBelowMin   = errors.New("stake max consumption can't be less than min stake consumption")
 	errStakeMintingPeriodBelowMin    = errors.New("stake minting period can't be less than max stake duration")
+	errIPCTLSClientCARequired        = fmt.Errorf("%s must be specified if %s is specified", IpcsTLSClientCAFileKey, IpcsTLSAddressKey)
 	errCannotWhitelistPrimaryNetwork = errors.New("cannot whitelist primary network")
 	errStakingKeyContentUnset        = fmt.Errorf("%s key not set but %s set", StakingKeyContentKey, StakingCertContentKey)
 	errStakingCertContentUnset       = fmt.Errorf("%s key set but %s not set", StakingKeyContentKey, StakingCertContentKey)
@@ -178,7 +188,56 @@ func getAPIAuthConfig(v *viper.Viper) (node.APIAuthConfig, error) {
 	return config, nil
 }
 
-func getIPCConfig(v *viper.Viper) node.IPCConfig {
+// getChainList returns the comma separated chain IDs or aliases of [key]
+func getChainList(v *viper.Viper, key string) []string {
+	var chains []string
+	for _, chain := range strings.Split(v.GetString(key), ",") {
+		chain = strings.TrimSpace(chain)
+		if chain != "" {
+			chains = append(chains, chain)
+		}
+	}
+	return chains
+}
+
+// getIndexChainRetention parses the index retention of each chain from a JSON
+// object that maps chain IDs or aliases to retention policies. Durations are
+// given as strings, e.g. "720h".
+func getIndexChainRetention(v *viper.Viper) (map[string]indexer.RetentionPolicy, error) {
+	content := v.GetString(IndexChainRetentionKey)
+	if content == "" {
+		return nil, nil
+	}
+
+	var rawRetention map[string]struct {
+		MaxContainers uint64 `json:"maxContainers"`
+		MaxAge        string `json:"maxAge"`
+	}
+	if err := json.Unmarshal([]byte(content), &rawRetention); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", IndexChainRetentionKey, err)
+	}
+
+	chainRetention := make(map[string]indexer.RetentionPolicy, len(rawRetention))
+	for chain, raw := range rawRetention {
+		retention := indexer.RetentionPolicy{
+			MaxContainers: raw.MaxContainers,
+		}
+		if raw.MaxAge != "" {
+			maxAge, err := time.ParseDuration(raw.MaxAge)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s of chain %s: %w", IndexChainRetentionKey, chain, err)
+			}
+			if maxAge < 0 {
+				return nil, fmt.Errorf("%s of chain %s can't be negative", IndexChainRetentionKey, chain)
+			}
+			retention.MaxAge = maxAge
+		}
+		chainRetention[chain] = retention
+	}
+	return chainRetention, nil
+}
+
+func getIPCConfig(v *viper.Viper) (node.IPCConfig, error) {
 	config := node.IPCConfig{
 		IPCAPIEnabled: v.GetBool(IpcAPIEnabledKey),
 		IPCPath:       ipcs.DefaultBaseURL,
@@ -189,7 +248,43 @@ func getIPCConfig(v *viper.Viper) node.IPCConfig {
 	if v.IsSet(IpcsPathKey) {
 		config.IPCPath = os.ExpandEnv(v.GetString(IpcsPathKey))
 	}
-	return config
+	config.IPCTLSAddress = v.GetString(IpcsTLSAddressKey)
+	if config.IPCTLSAddress == "" {
+		return config, nil
+	}
+	if !v.IsSet(IpcsTLSClientCAFileKey) {
+		return node.IPCConfig{}, errIPCTLSClientCARequired
+	}
+	clientCAFilepath := os.ExpandEnv(v.GetString(IpcsTLSClientCAFileKey))
+	clientCA, err := os.ReadFile(filepath.Clean(clientCAFilepath))
+	if err != nil {
+		return node.IPCConfig{}, err
+	}
+	config.IPCTLSClientCA = clientCA
+	return config, nil
+}
+
+func getHealthConfig(v *viper.Viper) (health.Config, error) {
+	config := health.Config{
+		HistorySize:    v.GetInt(HealthHistorySizeKey),
+		FlapThreshold:  v.GetInt(HealthFlapThresholdKey),
+		WebhookURLs:    v.GetStringSlice(HealthWebhookURLsKey),
+		WebhookTimeout: v.GetDuration(HealthWebhookTimeoutKey),
+	}
+	switch {
+	case config.HistorySize <= 0:
+		return health.Config{}, fmt.Errorf("%s must be positive", HealthHistorySizeKey)
+	case config.FlapThreshold < 0:
+		return health.Config{}, fmt.Errorf("%s can't be negative", HealthFlapThresholdKey)
+	case config.WebhookTimeout <= 0:
+		return health.Config{}, fmt.Errorf("%s must be positive", HealthWebhookTimeoutKey)
+	}
+	for _, webhookURL := range config.WebhookURLs {
+		if _, err := url.ParseRequestURI(webhookURL); err != nil {
+			return health.Config{}, fmt.Errorf("invalid %s %q: %w", HealthWebhookURLsKey, webhookURL, err)
+		}
+	}
+	return config, nil
 }
 
 func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
@@ -231,12 +326,19 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			APIIndexerConfig: node.APIIndexerConfig{
 				IndexAPIEnabled:      v.GetBool(IndexEnabledKey),
 				IndexAllowIncomplete: v.GetBool(IndexAllowIncompleteKey),
+				IndexIncludedChains:  getChainList(v, IndexIncludedChainsKey),
+				IndexExcludedChains:  getChainList(v, IndexExcludedChainsKey),
+				IndexRetention: indexer.RetentionPolicy{
+					MaxContainers: v.GetUint64(IndexRetentionContainersKey),
+					MaxAge:        v.GetDuration(IndexRetentionDurationKey),
+				},
 			},
-			AdminAPIEnabled:    v.GetBool(AdminAPIEnabledKey),
-			InfoAPIEnabled:     v.GetBool(InfoAPIEnabledKey),
-			KeystoreAPIEnabled: v.GetBool(KeystoreAPIEnabledKey),
-			MetricsAPIEnabled:  v.GetBool(MetricsAPIEnabledKey),
-			HealthAPIEnabled:   v.GetBool(HealthAPIEnabledKey),
+			AdminAPIEnabled:      v.GetBool(AdminAPIEnabledKey),
+			InfoAPIEnabled:       v.GetBool(InfoAPIEnabledKey),
+			KeystoreAPIEnabled:   v.GetBool(KeystoreAPIEnabledKey),
+			MetricsAPIEnabled:    v.GetBool(MetricsAPIEnabledKey),
+			HealthAPIEnabled:     v.GetBool(HealthAPIEnabledKey),
+			ValidatorsAPIEnabled: v.GetBool(ValidatorsAPIEnabledKey),
 		},
 		HTTPHost:          v.GetString(HTTPHostKey),
 		HTTPPort:          uint16(v.GetUint(HTTPPortKey)),
@@ -249,11 +351,22 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		ShutdownWait:    v.GetDuration(HTTPShutdownWaitKey),
 	}
 
+	if config.IndexRetention.MaxAge < 0 {
+		return node.HTTPConfig{}, fmt.Errorf("%s can't be negative", IndexRetentionDurationKey)
+	}
+	config.IndexChainRetention, err = getIndexChainRetention(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
+
 	config.APIAuthConfig, err = getAPIAuthConfig(v)
 	if err != nil {
 		return node.HTTPConfig{}, err
 	}
-	config.IPCConfig = getIPCConfig(v)
+	config.IPCConfig, err = getIPCConfig(v)
+	if err != nil {
+		return node.HTTPConfig{}, err
+	}
 	return config, nil
 }
 
@@ -377,7 +490,6 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		},
 
 		MaxClockDifference:           v.GetDuration(NetworkMaxClockDifferenceKey),
-		CompressionEnabled:           v.GetBool(NetworkCompressionEnabledKey),
 		PingFrequency:                v.GetDuration(NetworkPingFrequencyKey),
 		AllowPrivateIPs:              v.GetBool(NetworkAllowPrivateIPsKey),
 		UptimeMetricFreq:             v.GetDuration(UptimeMetricFreqKey),
@@ -386,7 +498,14 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 		RequireValidatorToConnect: v.GetBool(NetworkRequireValidatorToConnectKey),
 		PeerReadBufferSize:        int(v.GetUint(NetworkPeerReadBufferSizeKey)),
 		PeerWriteBufferSize:       int(v.GetUint(NetworkPeerWriteBufferSizeKey)),
+		FaultInjectionEnabled:     v.GetBool(NetworkFaultInjectionEnabledKey),
+	}
+
+	compressionTypes, err := getCompressionTypes(v)
+	if err != nil {
+		return network.Config{}, err
 	}
+	config.CompressionTypes = compressionTypes
 
 	switch {
 	case config.HealthConfig.MaxTimeSinceMsgSent < 0:
@@ -422,6 +541,88 @@ func getNetworkConfig(v *viper.Viper, halflife time.Duration) (network.Config, e
 	return config, nil
 }
 
+// getCompressionTypes returns the compression type of the outbound messages of
+// each op
+func getCompressionTypes(v *viper.Viper) (map[message.Op]compression.Type, error) {
+	compressionType, err := compression.TypeFromString(v.GetString(NetworkCompressionTypeKey))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %w", NetworkCompressionTypeKey, err)
+	}
+	if !v.GetBool(NetworkCompressionEnabledKey) {
+		compressionType = compression.TypeNone
+	}
+	compressionTypes := message.CompressAllOps(compressionType)
+
+	opCompressionTypes := v.GetString(NetworkOpCompressionTypesKey)
+	if opCompressionTypes == "" {
+		return compressionTypes, nil
+	}
+
+	ops := make(map[string]message.Op, len(message.ExternalOps))
+	for _, op := range message.ExternalOps {
+		ops[op.String()] = op
+	}
+	for _, opCompressionType := range strings.Split(opCompressionTypes, ",") {
+		opCompressionTypeParts := strings.Split(opCompressionType, "=")
+		if len(opCompressionTypeParts) != 2 {
+			return nil, fmt.Errorf("%s has an invalid entry %q, expected <op>=<compression type>", NetworkOpCompressionTypesKey, opCompressionType)
+		}
+		opName := strings.TrimSpace(opCompressionTypeParts[0])
+		op, ok := ops[opName]
+		if !ok {
+			return nil, fmt.Errorf("%s has an unknown message type %q", NetworkOpCompressionTypesKey, opName)
+		}
+		compressionType, err := compression.TypeFromString(strings.TrimSpace(opCompressionTypeParts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", NetworkOpCompressionTypesKey, err)
+		}
+		if compressionType != compression.TypeNone && !op.Compressible() {
+			return nil, fmt.Errorf("%s can't compress %s messages", NetworkOpCompressionTypesKey, op)
+		}
+		compressionTypes[op] = compressionType
+	}
+	return compressionTypes, nil
+}
+
+// getMessageClassWeights returns the weight of each class of inbound consensus
+// messages. Classes that aren't given a weight keep their default weight.
+func getMessageClassWeights(v *viper.Viper) (map[handler.MessageClass]uint64, error) {
+	classWeights := make(map[handler.MessageClass]uint64, len(handler.DefaultMessageClassWeights))
+	for class, weight := range handler.DefaultMessageClassWeights {
+		classWeights[class] = weight
+	}
+
+	weightsStr := v.GetString(ConsensusMessageClassWeightsKey)
+	if weightsStr == "" {
+		return classWeights, nil
+	}
+
+	classes := make(map[string]handler.MessageClass, len(handler.WeightedMessageClasses))
+	for _, class := range handler.WeightedMessageClasses {
+		classes[class.String()] = class
+	}
+	for _, classWeight := range strings.Split(weightsStr, ",") {
+		classWeightParts := strings.Split(classWeight, "=")
+		if len(classWeightParts) != 2 {
+			return nil, fmt.Errorf("%s has an invalid entry %q, expected <class>=<weight>", ConsensusMessageClassWeightsKey, classWeight)
+		}
+		className := strings.TrimSpace(classWeightParts[0])
+		class, ok := classes[className]
+		if !ok {
+			return nil, fmt.Errorf("%s has an unknown message class %q", ConsensusMessageClassWeightsKey, className)
+		}
+		weight, err := strconv.ParseUint(strings.TrimSpace(classWeightParts[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", ConsensusMessageClassWeightsKey, err)
+		}
+		if weight == 0 {
+			return nil, fmt.Errorf("%s: weight of the %s message class must be > 0", ConsensusMessageClassWeightsKey, class)
+		}
+		classWeights[class] = weight
+	}
+	return classWeights, nil
+}
+
 func getBenchlistConfig(v *viper.Viper, alpha, k int) (benchlist.Config, error) {
 	config := benchlist.Config{
 		Threshold:              v.GetInt(BenchlistFailThresholdKey),
@@ -688,6 +889,17 @@ func getTxFeeConfig(v *viper.Viper, networkID uint32) genesis.TxFeeConfig {
 	return genesis.GetTxFeeConfig(networkID)
 }
 
+// GetGenesisBytes returns the genesis of the network selected by the node's
+// config flags in [v]
+func GetGenesisBytes(v *viper.Viper) ([]byte, error) {
+	networkID, err := constants.NetworkID(v.GetString(NetworkNameKey))
+	if err != nil {
+		return nil, err
+	}
+	genesisBytes, _, err := getGenesisData(v, networkID)
+	return genesisBytes, err
+}
+
 func getGenesisData(v *viper.Viper, networkID uint32) ([]byte, ids.ID, error) {
 	// try first loading genesis content directly from flag/env-var
 	if v.IsSet(GenesisConfigContentKey) {
@@ -724,6 +936,16 @@ func getWhitelistedSubnets(v *viper.Viper) (ids.Set, error) {
 	return whitelistedSubnetIDs, nil
 }
 
+// GetDatabaseConfig returns the config of the database of the network
+// configured in [v]
+func GetDatabaseConfig(v *viper.Viper) (node.DatabaseConfig, error) {
+	networkID, err := constants.NetworkID(v.GetString(NetworkNameKey))
+	if err != nil {
+		return node.DatabaseConfig{}, err
+	}
+	return getDatabaseConfig(v, networkID)
+}
+
 func getDatabaseConfig(v *viper.Viper, networkID uint32) (node.DatabaseConfig, error) {
 	var (
 		configBytes []byte
@@ -789,6 +1011,28 @@ func getVMAliases(v *viper.Viper) (map[ids.ID][]string, error) {
 	return vmAliasMap, nil
 }
 
+func getValidatorsSchedule(v *viper.Viper) (*validation.Schedule, error) {
+	if !v.IsSet(ValidatorsScheduleFileKey) {
+		return nil, nil
+	}
+	schedulePath := filepath.Clean(os.ExpandEnv(v.GetString(ValidatorsScheduleFileKey)))
+	scheduleBytes, err := os.ReadFile(schedulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "json"
+	switch filepath.Ext(schedulePath) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+	schedule, err := validation.ParseSchedule(scheduleBytes, format)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing validators schedule: %w", err)
+	}
+	return schedule, nil
+}
+
 func getVMManager(v *viper.Viper) (vms.Manager, error) {
 	vmAliases, err := getVMAliases(v)
 	if err != nil {
@@ -893,9 +1137,16 @@ func readChainConfigPath(chainConfigPath string) (map[string]chains.ChainConfig,
 			return chainConfigMap, err
 		}
 
+		// chainconfigdir/chainId/proposer.*
+		proposerData, err := storage.ReadFileWithName(chainDir, chainProposerFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+
 		chainConfigMap[dirInfo.Name()] = chains.ChainConfig{
-			Config:  configData,
-			Upgrade: upgradeData,
+			Config:   configData,
+			Upgrade:  upgradeData,
+			Proposer: proposerData,
 		}
 	}
 	return chainConfigMap, nil
@@ -924,6 +1175,9 @@ func getSubnetConfigsFromFlags(v *viper.Viper, subnetIDs []ids.ID) (map[ids.ID]c
 			if err := subnetConfig.ConsensusParameters.Valid(); err != nil {
 				return nil, err
 			}
+			if err := subnetConfig.ValidHealth(); err != nil {
+				return nil, err
+			}
 			res[subnetID] = subnetConfig
 		}
 	}
@@ -984,6 +1238,9 @@ func readSubnetConfigs(subnetConfigPath string, subnetIDs []ids.ID, defaultSubne
 		if err := configData.ConsensusParameters.Valid(); err != nil {
 			return nil, err
 		}
+		if err := configData.ValidHealth(); err != nil {
+			return nil, err
+		}
 		subnetConfigs[subnetID] = configData
 	}
 
@@ -1021,6 +1278,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	}
 
 	var err error
+	// Message queues
+	nodeConfig.ConsensusMessageClassWeights, err = getMessageClassWeights(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// Logging
 	nodeConfig.LoggingConfig, err = getLoggingConfig(v)
 	if err != nil {
@@ -1068,6 +1331,10 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 	if nodeConfig.HealthCheckFreq < 0 {
 		return node.Config{}, fmt.Errorf("%s must be positive", HealthCheckFreqKey)
 	}
+	nodeConfig.HealthConfig, err = getHealthConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
 	// Halflife of continuous averager used in health checks
 	healthCheckAveragerHalflife := v.GetDuration(HealthCheckAveragerHalflifeKey)
 	if healthCheckAveragerHalflife <= 0 {
@@ -1128,8 +1395,9 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
-	// Subnet Configs
-	subnetConfigs, err := getSubnetConfigs(v, nodeConfig.WhitelistedSubnets.List())
+	// Subnet Configs. The config of the primary network only configures the
+	// health checks of its chains.
+	subnetConfigs, err := getSubnetConfigs(v, append(nodeConfig.WhitelistedSubnets.List(), constants.PrimaryNetworkID))
 	if err != nil {
 		return node.Config{}, err
 	}
@@ -1153,6 +1421,12 @@ func GetNodeConfig(v *viper.Viper, buildDir string) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Validators Schedule
+	nodeConfig.ValidatorsSchedule, err = getValidatorsSchedule(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// reset proposerVM height index
 	nodeConfig.ResetProposerVMHeightIndex = v.GetBool(ResetProposerVMHeightIndexKey)
 
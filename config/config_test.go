@@ -18,6 +18,9 @@ import (
 
 	"github.com/flare-foundation/flare/chains"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/snow/networking/handler"
+	"github.com/flare-foundation/flare/utils/compression"
 )
 
 func TestGetChainConfigsFromFiles(t *testing.T) {
@@ -620,3 +623,148 @@ func setupViper(configFilePath string) *viper.Viper {
 	}
 	return v
 }
+
+func TestGetCompressionTypes(t *testing.T) {
+	tests := map[string]struct {
+		compressionEnabled bool
+		compressionType    string
+		opCompressionTypes string
+		errMessage         string
+		expected           map[message.Op]compression.Type
+	}{
+		"compression disabled": {
+			compressionType: "zstd",
+			expected:        message.CompressAllOps(compression.TypeNone),
+		},
+		"zstd": {
+			compressionEnabled: true,
+			compressionType:    "zstd",
+			expected:           message.CompressAllOps(compression.TypeZstd),
+		},
+		"per op": {
+			compressionEnabled: true,
+			compressionType:    "gzip",
+			opCompressionTypes: "ancestors=zstd, put=zstd,app_gossip=none,chits=none",
+			expected: func() map[message.Op]compression.Type {
+				m := message.CompressAllOps(compression.TypeGzip)
+				m[message.Ancestors] = compression.TypeZstd
+				m[message.Put] = compression.TypeZstd
+				m[message.AppGossip] = compression.TypeNone
+				m[message.Chits] = compression.TypeNone
+				return m
+			}(),
+		},
+		"unknown compression type": {
+			compressionEnabled: true,
+			compressionType:    "lz4",
+			errMessage:         "unknown compression type",
+		},
+		"unknown op": {
+			compressionEnabled: true,
+			compressionType:    "gzip",
+			opCompressionTypes: "blocks=zstd",
+			errMessage:         "unknown message type",
+		},
+		"uncompressible op": {
+			compressionEnabled: true,
+			compressionType:    "gzip",
+			opCompressionTypes: "chits=zstd",
+			errMessage:         "can't compress chits messages",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			v := setupViperFlags()
+			v.Set(NetworkCompressionEnabledKey, test.compressionEnabled)
+			v.Set(NetworkCompressionTypeKey, test.compressionType)
+			v.Set(NetworkOpCompressionTypesKey, test.opCompressionTypes)
+
+			compressionTypes, err := getCompressionTypes(v)
+			if len(test.errMessage) > 0 {
+				assert.Error(err)
+				if err != nil {
+					assert.Contains(err.Error(), test.errMessage)
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, compressionTypes)
+		})
+	}
+}
+
+func TestGetMessageClassWeights(t *testing.T) {
+	tests := map[string]struct {
+		classWeights string
+		errMessage   string
+		expected     map[handler.MessageClass]uint64
+	}{
+		"default": {
+			expected: handler.DefaultMessageClassWeights,
+		},
+		"partial": {
+			classWeights: "consensus=16, app_gossip=2",
+			expected: map[handler.MessageClass]uint64{
+				handler.ConsensusClass: 16,
+				handler.BootstrapClass: 1,
+				handler.AppClass:       4,
+				handler.AppGossipClass: 2,
+			},
+		},
+		"unknown class": {
+			classWeights: "control=1",
+			errMessage:   "unknown message class",
+		},
+		"invalid entry": {
+			classWeights: "consensus",
+			errMessage:   "invalid entry",
+		},
+		"zero weight": {
+			classWeights: "bootstrap=0",
+			errMessage:   "must be > 0",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			v := setupViperFlags()
+			v.Set(ConsensusMessageClassWeightsKey, test.classWeights)
+
+			classWeights, err := getMessageClassWeights(v)
+			if len(test.errMessage) > 0 {
+				assert.Error(err)
+				if err != nil {
+					assert.Contains(err.Error(), test.errMessage)
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.expected, classWeights)
+		})
+	}
+}
+
+func TestGetIPCConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	v := setupViperFlags()
+	config, err := getIPCConfig(v)
+	assert.NoError(err)
+	assert.Empty(config.IPCTLSAddress)
+
+	// Serving IPCs over TLS requires a client CA
+	v.Set(IpcsTLSAddressKey, "127.0.0.1:9653")
+	_, err = getIPCConfig(v)
+	assert.ErrorIs(err, errIPCTLSClientCARequired)
+
+	clientCAPath := filepath.Join(t.TempDir(), "client-ca.pem")
+	assert.NoError(os.WriteFile(clientCAPath, []byte("client CA"), 0o600))
+	v.Set(IpcsTLSClientCAFileKey, clientCAPath)
+	config, err = getIPCConfig(v)
+	assert.NoError(err)
+	assert.Equal("127.0.0.1:9653", config.IPCTLSAddress)
+	assert.Equal([]byte("client CA"), config.IPCTLSClientCA)
+}
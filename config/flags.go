@@ -13,10 +13,13 @@ import (
 
 	"github.com/kardianos/osext"
 
+	"github.com/flare-foundation/flare/api/health"
 	"github.com/flare-foundation/flare/database/leveldb"
 	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/pebbledb"
 	"github.com/flare-foundation/flare/database/rocksdb"
 	"github.com/flare-foundation/flare/genesis"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/ulimit"
 	"github.com/flare-foundation/flare/utils/units"
@@ -95,7 +98,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Uint64(CreateBlockchainTxFeeKey, genesis.LocalParams.CreateBlockchainTxFee, "Transaction fee, in nAVAX, for transactions that create new blockchains")
 
 	// Database
-	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s}", leveldb.Name, rocksdb.Name, memdb.Name))
+	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s, %s}", leveldb.Name, rocksdb.Name, pebbledb.Name, memdb.Name))
 	fs.String(DBPathKey, defaultDBDir, "Path to database directory")
 	fs.String(DBConfigFileKey, "", fmt.Sprintf("Path to database config file. Ignored if %s is specified", DBConfigContentKey))
 	fs.String(DBConfigContentKey, "", "Specifies base64 encoded database config content")
@@ -148,11 +151,14 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Duration(NetworkPingFrequencyKey, constants.DefaultPingFrequency, "Frequency of pinging other peers")
 
 	fs.Bool(NetworkCompressionEnabledKey, true, "If true, compress certain outbound messages. This node will be able to parse compressed inbound messages regardless of this flag's value")
+	fs.String(NetworkCompressionTypeKey, compression.TypeGzip.String(), fmt.Sprintf("Compression type of the outbound messages compressed when %s is true. Either %s or %s. Messages to peers that can't decompress %s are compressed with %s", NetworkCompressionEnabledKey, compression.TypeGzip, compression.TypeZstd, compression.TypeZstd, compression.TypeGzip))
+	fs.String(NetworkOpCompressionTypesKey, "", fmt.Sprintf("Comma separated compression types of the outbound messages of specific types, overriding %s and %s. For example, ancestors=zstd,put=zstd,app_gossip=none", NetworkCompressionEnabledKey, NetworkCompressionTypeKey))
 	fs.Duration(NetworkMaxClockDifferenceKey, time.Minute, "Max allowed clock difference value between this node and peers")
 	fs.Bool(NetworkAllowPrivateIPsKey, true, "Allows the node to initiate outbound connection attempts to peers with private IPs")
 	fs.Bool(NetworkRequireValidatorToConnectKey, false, "If true, this node will only maintain a connection with another node if this node is a validator, the other node is a validator, or the other node is a beacon")
 	fs.Uint(NetworkPeerReadBufferSizeKey, 8*units.KiB, "Size, in bytes, of the buffer that we read peer messages into (there is one buffer per peer)")
 	fs.Uint(NetworkPeerWriteBufferSizeKey, 8*units.KiB, "Size, in bytes, of the buffer that we write peer messages into (there is one buffer per peer)")
+	fs.Bool(NetworkFaultInjectionEnabledKey, false, "If true, delays, drops, bandwidth caps and partitions can be injected in the connections with the peers through the admin API. Only meant for testing")
 
 	// Benchlist
 	fs.Int(BenchlistFailThresholdKey, 10, "Number of consecutive failed queries before benchlisting a node")
@@ -161,6 +167,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 
 	// Router
 	fs.Duration(ConsensusGossipFrequencyKey, 10*time.Second, "Frequency of gossiping accepted frontiers")
+	fs.String(ConsensusMessageClassWeightsKey, "consensus=8,bootstrap=1,app=4,app_gossip=1", "Comma separated weights of the classes of inbound consensus messages. Each class is given a share of a chain's message handling time proportional to its weight")
 	fs.Duration(ConsensusShutdownTimeoutKey, 30*time.Second, "Timeout before killing an unresponsive chain")
 	fs.Uint(ConsensusGossipAcceptedFrontierSizeKey, 35, "Number of peers to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipOnAcceptSizeKey, 20, "Number of peers to gossip to each accepted container to")
@@ -204,10 +211,15 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Bool(MetricsAPIEnabledKey, true, "If true, this node exposes the Metrics API")
 	fs.Bool(HealthAPIEnabledKey, true, "If true, this node exposes the Health API")
 	fs.Bool(IpcAPIEnabledKey, false, "If true, IPCs can be opened")
+	fs.Bool(ValidatorsAPIEnabledKey, true, "If true, this node exposes the Validators API")
 
 	// Health Checks
 	fs.Duration(HealthCheckFreqKey, 30*time.Second, "Time between health checks")
 	fs.Duration(HealthCheckAveragerHalflifeKey, 10*time.Second, "Halflife of averager when calculating a running average in a health check")
+	fs.Int(HealthHistorySizeKey, health.DefaultHistorySize, "Number of past results kept for each health check")
+	fs.Int(HealthFlapThresholdKey, 4, "Number of changes between healthy and unhealthy among the kept results of a health check at which the check is flapping. If 0, flapping isn't detected")
+	fs.String(HealthWebhookURLsKey, "", "Whitespace separated URLs that are sent a POST request whenever a health check becomes healthy or unhealthy")
+	fs.Duration(HealthWebhookTimeoutKey, 5*time.Second, "Timeout of the requests sent to the health webhook URLs")
 	// Network Layer Health
 	fs.Duration(NetworkHealthMaxTimeSinceMsgSentKey, time.Minute, "Network layer returns unhealthy if haven't sent a message for at least this much time")
 	fs.Duration(NetworkHealthMaxTimeSinceMsgReceivedKey, time.Minute, "Network layer returns unhealthy if haven't received a message for at least this much time")
@@ -278,11 +290,18 @@ func addNodeFlags(fs *flag.FlagSet) {
 	// IPC
 	fs.String(IpcsChainIDsKey, "", "Comma separated list of chain ids to add to the IPC engine. Example: 11111111111111111111111111111111LpoYY,4R5p2RXDGLqaifZE4hHWH9owe34pfoBULn1DrQTWivjg8o4aH")
 	fs.String(IpcsPathKey, "", "The directory (Unix) or named pipe name prefix (Windows) for IPC sockets")
+	fs.String(IpcsTLSAddressKey, "", "If non-empty, the address (host:port) at which IPC sockets are also served over TLS, using the staking certificate")
+	fs.String(IpcsTLSClientCAFileKey, "", fmt.Sprintf("PEM file with the certificate authorities that sign the certificates of clients allowed to subscribe to IPC sockets over TLS. Required if %s is specified", IpcsTLSAddressKey))
 
 	// Indexer
 	fs.Bool(ResetProposerVMHeightIndexKey, false, "if true, proposervm height index is wiped on startup")
 	fs.Bool(IndexEnabledKey, false, "If true, index all accepted containers and transactions and expose them via an API")
 	fs.Bool(IndexAllowIncompleteKey, false, "If true, allow running the node in such a way that could cause an index to miss transactions. Ignored if index is disabled")
+	fs.String(IndexIncludedChainsKey, "", "Comma separated IDs or aliases of the chains to index. If empty, all chains are indexed. Ignored if index is disabled")
+	fs.String(IndexExcludedChainsKey, "", "Comma separated IDs or aliases of the chains not to index. Ignored if index is disabled")
+	fs.Uint64(IndexRetentionContainersKey, 0, "If non-zero, only the last [index-retention-containers] accepted containers of each index are kept. Older containers are pruned")
+	fs.Duration(IndexRetentionDurationKey, 0, "If non-zero, only the containers accepted in the last [index-retention-duration] are kept in each index. Older containers are pruned")
+	fs.String(IndexChainRetentionKey, "", `JSON object that overrides the index retention of chains by their ID or alias, e.g. {"C":{"maxContainers":100000,"maxAge":"720h"}}`)
 
 	// Config Directories
 	fs.String(ChainConfigDirKey, defaultChainConfigDir, fmt.Sprintf("Chain specific configurations parent directory. Ignored if %s is specified", ChainConfigContentKey))
@@ -297,6 +316,7 @@ func addNodeFlags(fs *flag.FlagSet) {
 	fs.Int(ProfileContinuousMaxFilesKey, 5, "Maximum number of historical profiles to keep")
 	fs.String(VMAliasesFileKey, defaultVMAliasFilePath, fmt.Sprintf("Specifies a JSON file that maps vmIDs with custom aliases. Ignored if %s is specified", VMAliasesContentKey))
 	fs.String(VMAliasesContentKey, "", "Specifies base64 encoded maps vmIDs with custom aliases")
+	fs.String(ValidatorsScheduleFileKey, "", "Specifies a JSON or YAML file that schedules the validators of the network by block height. Only used if the EVM doesn't provide the validators")
 
 	// Delays
 	fs.Duration(NetworkInitialReconnectDelayKey, time.Second, "Initial delay duration must be waited before attempting to reconnect a peer")
@@ -85,11 +85,14 @@ const (
 	NetworkPingFrequencyKey                     = "network-ping-frequency"
 	NetworkMaxReconnectDelayKey                 = "network-max-reconnect-delay"
 	NetworkCompressionEnabledKey                = "network-compression-enabled"
+	NetworkCompressionTypeKey                   = "network-compression-type"
+	NetworkOpCompressionTypesKey                = "network-op-compression-types"
 	NetworkMaxClockDifferenceKey                = "network-max-clock-difference"
 	NetworkAllowPrivateIPsKey                   = "network-allow-private-ips"
 	NetworkRequireValidatorToConnectKey         = "network-require-validator-to-connect"
 	NetworkPeerReadBufferSizeKey                = "network-peer-read-buffer-size"
 	NetworkPeerWriteBufferSizeKey               = "network-peer-write-buffer-size"
+	NetworkFaultInjectionEnabledKey             = "network-fault-injection-enabled"
 	BenchlistFailThresholdKey                   = "benchlist-fail-threshold"
 	BenchlistDurationKey                        = "benchlist-duration"
 	BenchlistMinFailingDurationKey              = "benchlist-min-failing-duration"
@@ -115,10 +118,14 @@ const (
 	MetricsAPIEnabledKey                        = "api-metrics-enabled"
 	HealthAPIEnabledKey                         = "api-health-enabled"
 	IpcAPIEnabledKey                            = "api-ipcs-enabled"
+	ValidatorsAPIEnabledKey                     = "api-validators-enabled"
 	IpcsChainIDsKey                             = "ipcs-chain-ids"
 	IpcsPathKey                                 = "ipcs-path"
+	IpcsTLSAddressKey                           = "ipcs-tls-address"
+	IpcsTLSClientCAFileKey                      = "ipcs-tls-client-ca-file"
 	MeterVMsEnabledKey                          = "meter-vms-enabled"
 	ConsensusGossipFrequencyKey                 = "consensus-gossip-frequency"
+	ConsensusMessageClassWeightsKey             = "consensus-message-class-weights"
 	ConsensusGossipAcceptedFrontierSizeKey      = "consensus-accepted-frontier-gossip-size"
 	ConsensusGossipOnAcceptSizeKey              = "consensus-on-accept-gossip-size"
 	AppGossipNonValidatorSizeKey                = "consensus-app-gossip-non-validator-size"
@@ -127,11 +134,20 @@ const (
 	FdLimitKey                                  = "fd-limit"
 	IndexEnabledKey                             = "index-enabled"
 	IndexAllowIncompleteKey                     = "index-allow-incomplete"
+	IndexIncludedChainsKey                      = "index-included-chains"
+	IndexExcludedChainsKey                      = "index-excluded-chains"
+	IndexRetentionContainersKey                 = "index-retention-containers"
+	IndexRetentionDurationKey                   = "index-retention-duration"
+	IndexChainRetentionKey                      = "index-chain-retention"
 	ResetProposerVMHeightIndexKey               = "reset-proposervm-height-index"
 	RouterHealthMaxDropRateKey                  = "router-health-max-drop-rate"
 	RouterHealthMaxOutstandingRequestsKey       = "router-health-max-outstanding-requests"
 	HealthCheckFreqKey                          = "health-check-frequency"
 	HealthCheckAveragerHalflifeKey              = "health-check-averager-halflife"
+	HealthHistorySizeKey                        = "health-history-size"
+	HealthFlapThresholdKey                      = "health-flap-threshold"
+	HealthWebhookURLsKey                        = "health-webhook-urls"
+	HealthWebhookTimeoutKey                     = "health-webhook-timeout"
 	RetryBootstrapKey                           = "bootstrap-retry-enabled"
 	RetryBootstrapWarnFrequencyKey              = "bootstrap-retry-warn-frequency"
 	PluginModeKey                               = "plugin-mode-enabled"
@@ -159,4 +175,5 @@ const (
 	UptimeMetricFreqKey                         = "uptime-metric-freq"
 	VMAliasesFileKey                            = "vm-aliases-file"
 	VMAliasesContentKey                         = "vm-aliases-file-content"
+	ValidatorsScheduleFileKey                   = "validators-schedule-file"
 )
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/spf13/viper"
+
+	"github.com/flare-foundation/flare/node"
+)
+
+var (
+	// reloadableKeys are the keys whose changes the node applies while it's
+	// running when its config is reloaded
+	reloadableKeys = map[string]bool{
+		InboundThrottlerBandwidthRefillRateKey:      true,
+		InboundThrottlerBandwidthMaxBurstSizeKey:    true,
+		InboundThrottlerMaxProcessingMsgsPerNodeKey: true,
+		BenchlistFailThresholdKey:                   true,
+		BenchlistDurationKey:                        true,
+		BenchlistMinFailingDurationKey:              true,
+		NetworkHealthMinPeersKey:                    true,
+		NetworkHealthMaxTimeSinceMsgReceivedKey:     true,
+		NetworkHealthMaxTimeSinceMsgSentKey:         true,
+		NetworkHealthMaxPortionSendQueueFillKey:     true,
+		NetworkHealthMaxSendFailRateKey:             true,
+		NetworkHealthMaxOutstandingDurationKey:      true,
+		RouterHealthMaxDropRateKey:                  true,
+		RouterHealthMaxOutstandingRequestsKey:       true,
+		HTTPAllowedOrigins:                          true,
+		ProfileDirKey:                               true,
+		ProfileContinuousEnabledKey:                 true,
+		ProfileContinuousFreqKey:                    true,
+		ProfileContinuousMaxFilesKey:                true,
+	}
+
+	// apiEnabledKeys are the keys that enable an API. The APIs disabled when
+	// the node starts aren't registered, so enabling them requires a restart.
+	apiEnabledKeys = map[string]bool{
+		AdminAPIEnabledKey:      true,
+		InfoAPIEnabledKey:       true,
+		KeystoreAPIEnabledKey:   true,
+		MetricsAPIEnabledKey:    true,
+		HealthAPIEnabledKey:     true,
+		IpcAPIEnabledKey:        true,
+		ValidatorsAPIEnabledKey: true,
+	}
+)
+
+// NewConfigReloader returns a node.ConfigReloader that parses [args], and the
+// config file they point to, again. [v] is the viper environment the node was
+// started with, which the changes are reported against.
+func NewConfigReloader(v *viper.Viper, args []string, buildDir string) node.ConfigReloader {
+	return func() (node.Config, []string, []string, error) {
+		newV, err := BuildViper(BuildFlagSet(), args)
+		if err != nil {
+			return node.Config{}, nil, nil, err
+		}
+		config, err := GetNodeConfig(newV, buildDir)
+		if err != nil {
+			return node.Config{}, nil, nil, err
+		}
+		reloaded, requireRestart := changedKeys(v, newV)
+		return config, reloaded, requireRestart, nil
+	}
+}
+
+// changedKeys returns the sorted keys whose value differs between [oldV] and
+// [newV], split between the ones that can be applied while the node is
+// running and the ones that require a restart
+func changedKeys(oldV, newV *viper.Viper) (reloaded []string, requireRestart []string) {
+	keys := make(map[string]struct{})
+	for _, key := range oldV.AllKeys() {
+		keys[key] = struct{}{}
+	}
+	for _, key := range newV.AllKeys() {
+		keys[key] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	reloaded = []string{}
+	requireRestart = []string{}
+	for _, key := range sortedKeys {
+		if reflect.DeepEqual(oldV.Get(key), newV.Get(key)) {
+			continue
+		}
+		switch {
+		case apiEnabledKeys[key] && oldV.GetBool(key):
+			reloaded = append(reloaded, key)
+		case reloadableKeys[key]:
+			reloaded = append(reloaded, key)
+		default:
+			requireRestart = append(requireRestart, key)
+		}
+	}
+	return reloaded, requireRestart
+}
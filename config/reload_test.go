@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedKeys(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	configFilePath := filepath.Join(t.TempDir(), "config.json")
+	args := []string{"--" + ConfigFileKey + "=" + configFilePath}
+
+	require.NoError(os.WriteFile(configFilePath, []byte(`{
+		"api-admin-enabled": true,
+		"api-keystore-enabled": false,
+		"benchlist-fail-threshold": 10,
+		"http-port": 9650
+	}`), 0o600))
+	oldV, err := BuildViper(BuildFlagSet(), args)
+	require.NoError(err)
+
+	// Nothing changed
+	reloaded, requireRestart := changedKeys(oldV, oldV)
+	assert.Empty(reloaded)
+	assert.Empty(requireRestart)
+
+	require.NoError(os.WriteFile(configFilePath, []byte(`{
+		"api-admin-enabled": false,
+		"api-keystore-enabled": true,
+		"benchlist-fail-threshold": 20,
+		"http-allowed-origins": "https://example.com",
+		"http-port": 9652
+	}`), 0o600))
+	newV, err := BuildViper(BuildFlagSet(), args)
+	require.NoError(err)
+
+	reloaded, requireRestart = changedKeys(oldV, newV)
+	assert.Equal([]string{AdminAPIEnabledKey, BenchlistFailThresholdKey, HTTPAllowedOrigins}, reloaded)
+	// The keystore API wasn't registered when the node started
+	assert.Equal([]string{KeystoreAPIEnabledKey, HTTPPortKey}, requireRestart)
+}
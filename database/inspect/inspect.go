@@ -0,0 +1,244 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package inspect attributes the keys of a database to the prefixes under
+// which they were written.
+package inspect
+
+import (
+	"sort"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/utils/hashing"
+)
+
+// Prefix describes the keys written through a stack of prefixdb databases.
+//
+// prefixdb hashes its prefix, so the keys of the underlying database start
+// with one hash per nesting level. A prefixdb created on top of another
+// prefixdb replaces the hash of its parent by the hash of the parent's hash
+// followed by the new prefix, while a prefixdb created on top of any other
+// database, such as a versiondb, adds a level. The zero value describes the
+// underlying database itself.
+type Prefix struct {
+	levels []hashing.Hash256
+	// prefixDB is true if the described database is a prefixdb
+	prefixDB bool
+}
+
+// New returns the prefix of prefixdb.New([prefix], db), where db is the
+// database described by [p].
+func (p Prefix) New(prefix []byte) Prefix {
+	if !p.prefixDB {
+		return p.NewNested(prefix)
+	}
+	last := p.levels[len(p.levels)-1]
+	compressed := make([]byte, 0, len(last)+len(prefix))
+	compressed = append(compressed, last[:]...)
+	compressed = append(compressed, prefix...)
+	return p.withLevel(len(p.levels)-1, compressed)
+}
+
+// NewNested returns the prefix of prefixdb.NewNested([prefix], db), where db
+// is the database described by [p].
+func (p Prefix) NewNested(prefix []byte) Prefix {
+	return p.withLevel(len(p.levels), prefix)
+}
+
+// Wrap returns the prefix of a database that wraps the database described by
+// [p] without being a prefixdb, such as a versiondb.
+func (p Prefix) Wrap() Prefix {
+	return Prefix{
+		levels:   p.levels,
+		prefixDB: false,
+	}
+}
+
+// withLevel returns the prefix whose levels are the first [level] levels of
+// [p] followed by the hash of [prefix].
+func (p Prefix) withLevel(level int, prefix []byte) Prefix {
+	levels := make([]hashing.Hash256, level+1)
+	copy(levels, p.levels[:level])
+	levels[level] = hashing.ComputeHash256Array(prefix)
+	return Prefix{
+		levels:   levels,
+		prefixDB: true,
+	}
+}
+
+// Layout names the known prefixes of a database
+type Layout struct {
+	root node
+}
+
+type node struct {
+	// label is nil if no prefix was added for this node
+	label    *Label
+	children map[hashing.Hash256]*node
+}
+
+// Label of the keys written under a prefix
+type Label struct {
+	// Chain the keys belong to, empty if they don't belong to a chain
+	Chain string
+	// Name of the prefix, e.g. "proposervm/blocks"
+	Name string
+}
+
+// NewLayout returns a layout without any known prefixes
+func NewLayout() *Layout {
+	return &Layout{}
+}
+
+// Add names the keys written under [prefix]. The keys under a prefix that is
+// longer than [prefix] are attributed to [prefix] unless they are added too.
+func (l *Layout) Add(chain, name string, prefix Prefix) {
+	n := &l.root
+	for _, level := range prefix.levels {
+		if n.children == nil {
+			n.children = make(map[hashing.Hash256]*node)
+		}
+		child, ok := n.children[level]
+		if !ok {
+			child = &node{}
+			n.children[level] = child
+		}
+		n = child
+	}
+	n.label = &Label{
+		Chain: chain,
+		Name:  name,
+	}
+}
+
+// label returns the label of the longest added prefix of [key], or nil if
+// [key] doesn't start with an added prefix.
+func (l *Layout) label(key []byte) *Label {
+	var (
+		n     = &l.root
+		label *Label
+	)
+	for len(key) >= hashing.HashLen && n.children != nil {
+		var level hashing.Hash256
+		copy(level[:], key)
+		child, ok := n.children[level]
+		if !ok {
+			break
+		}
+		n = child
+		if n.label != nil {
+			label = n.label
+		}
+		key = key[hashing.HashLen:]
+	}
+	return label
+}
+
+// Stats of a set of keys
+type Stats struct {
+	NumKeys    uint64
+	KeyBytes   uint64
+	ValueBytes uint64
+}
+
+func (s *Stats) add(key, value []byte) {
+	s.NumKeys++
+	s.KeyBytes += uint64(len(key))
+	s.ValueBytes += uint64(len(value))
+}
+
+func (s *Stats) merge(other Stats) {
+	s.NumKeys += other.NumKeys
+	s.KeyBytes += other.KeyBytes
+	s.ValueBytes += other.ValueBytes
+}
+
+// ChainStats are the stats of the keys of a chain
+type ChainStats struct {
+	Chain string
+	Stats
+}
+
+// PrefixStats are the stats of the keys written under a prefix
+type PrefixStats struct {
+	Label
+	Stats
+}
+
+// Report of the keys of a database
+type Report struct {
+	Total Stats
+	// Chains are sorted by chain
+	Chains []ChainStats
+	// Prefixes are sorted by chain and name
+	Prefixes []PrefixStats
+	// Unknown are the stats of the keys that don't start with a known prefix
+	Unknown Stats
+}
+
+// Inspect iterates over all keys of [db] and attributes them to the prefixes
+// of [layout].
+func Inspect(db database.Database, layout *Layout) (Report, error) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	report := Report{}
+	prefixes := make(map[Label]*Stats)
+	for it.Next() {
+		key := it.Key()
+		value := it.Value()
+		report.Total.add(key, value)
+
+		label := layout.label(key)
+		if label == nil {
+			report.Unknown.add(key, value)
+			continue
+		}
+		stats, ok := prefixes[*label]
+		if !ok {
+			stats = &Stats{}
+			prefixes[*label] = stats
+		}
+		stats.add(key, value)
+	}
+	if err := it.Error(); err != nil {
+		return Report{}, err
+	}
+
+	chains := make(map[string]*Stats)
+	report.Prefixes = make([]PrefixStats, 0, len(prefixes))
+	for label, stats := range prefixes {
+		report.Prefixes = append(report.Prefixes, PrefixStats{
+			Label: label,
+			Stats: *stats,
+		})
+		if label.Chain == "" {
+			continue
+		}
+		chainStats, ok := chains[label.Chain]
+		if !ok {
+			chainStats = &Stats{}
+			chains[label.Chain] = chainStats
+		}
+		chainStats.merge(*stats)
+	}
+	sort.Slice(report.Prefixes, func(i, j int) bool {
+		a, b := report.Prefixes[i], report.Prefixes[j]
+		if a.Chain != b.Chain {
+			return a.Chain < b.Chain
+		}
+		return a.Name < b.Name
+	})
+
+	report.Chains = make([]ChainStats, 0, len(chains))
+	for chain, stats := range chains {
+		report.Chains = append(report.Chains, ChainStats{
+			Chain: chain,
+			Stats: *stats,
+		})
+	}
+	sort.Slice(report.Chains, func(i, j int) bool {
+		return report.Chains[i].Chain < report.Chains[j].Chain
+	})
+	return report, nil
+}
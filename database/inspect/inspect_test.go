@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package inspect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/database/versiondb"
+)
+
+func TestInspect(t *testing.T) {
+	baseDB := memdb.New()
+
+	// A chain with a nested prefix behind a versiondb and a compressed prefix
+	chainDB := prefixdb.New([]byte("chain"), baseDB)
+	vmDB := versiondb.New(prefixdb.New([]byte("vm"), chainDB))
+	blockDB := prefixdb.New([]byte("block"), vmDB)
+	bootstrappingDB := prefixdb.New([]byte("bs"), chainDB)
+	// Prefixes that aren't in the layout are attributed to their parent
+	otherDB := prefixdb.New([]byte("other"), vmDB)
+	unknownDB := prefixdb.New([]byte("unknown"), baseDB)
+
+	require.NoError(t, blockDB.Put([]byte{1}, []byte{1, 2}))
+	require.NoError(t, blockDB.Put([]byte{2}, []byte{1, 2}))
+	require.NoError(t, vmDB.Put([]byte{3}, []byte{1}))
+	require.NoError(t, vmDB.Commit())
+	require.NoError(t, otherDB.Put([]byte{4}, nil))
+	require.NoError(t, vmDB.Commit())
+	require.NoError(t, bootstrappingDB.Put([]byte{5}, []byte{1}))
+	require.NoError(t, unknownDB.Put([]byte{6}, []byte{1}))
+	require.NoError(t, baseDB.Put([]byte{7}, []byte{1}))
+
+	chain := Prefix{}.New([]byte("chain"))
+	vm := chain.New([]byte("vm")).Wrap()
+	layout := NewLayout()
+	layout.Add("C", "vm", vm)
+	layout.Add("C", "blocks", vm.New([]byte("block")))
+	layout.Add("C", "bootstrapping", chain.New([]byte("bs")))
+
+	report, err := Inspect(baseDB, layout)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, report.Total.NumKeys)
+	assert.Equal(t, Stats{NumKeys: 2, KeyBytes: 33 + 1, ValueBytes: 2}, report.Unknown)
+	assert.Equal(t, []ChainStats{{
+		Chain: "C",
+		Stats: Stats{NumKeys: 5, KeyBytes: 2*65 + 33 + 65 + 33, ValueBytes: 6},
+	}}, report.Chains)
+	assert.Equal(t, []PrefixStats{
+		{
+			Label: Label{Chain: "C", Name: "blocks"},
+			Stats: Stats{NumKeys: 2, KeyBytes: 2 * 65, ValueBytes: 4},
+		},
+		{
+			Label: Label{Chain: "C", Name: "bootstrapping"},
+			Stats: Stats{NumKeys: 1, KeyBytes: 33, ValueBytes: 1},
+		},
+		{
+			Label: Label{Chain: "C", Name: "vm"},
+			Stats: Stats{NumKeys: 2, KeyBytes: 65 + 33, ValueBytes: 1},
+		},
+	}, report.Prefixes)
+}
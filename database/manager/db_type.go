@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/corruptabledb"
+	"github.com/flare-foundation/flare/database/leveldb"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/pebbledb"
+	"github.com/flare-foundation/flare/database/rocksdb"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/version"
+)
+
+// New creates a database manager of [dbType] databases at [dbDirPath], opening
+// the database of each version <= [currentVersion].
+func New(
+	dbType string,
+	dbDirPath string,
+	dbConfig []byte,
+	log logging.Logger,
+	currentVersion version.Version,
+) (Manager, error) {
+	if dbType == memdb.Name {
+		return NewMemDB(currentVersion), nil
+	}
+	newDB, err := newDBFunc(dbType)
+	if err != nil {
+		return nil, err
+	}
+	return new(
+		newDB,
+		versionsDir(dbType, dbDirPath),
+		dbConfig,
+		log,
+		currentVersion,
+	)
+}
+
+// NewVersionedDatabase opens the [dbType] database of version [v] at
+// [dbDirPath], creating it if it doesn't exist yet.
+func NewVersionedDatabase(
+	dbType string,
+	dbDirPath string,
+	dbConfig []byte,
+	log logging.Logger,
+	v version.Version,
+) (*VersionedDatabase, error) {
+	if dbType == memdb.Name {
+		return &VersionedDatabase{
+			Database: memdb.New(),
+			Version:  v,
+		}, nil
+	}
+	newDB, err := newDBFunc(dbType)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(versionsDir(dbType, dbDirPath), v.String())
+	db, err := newDB(path, dbConfig, log)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create db at %s: %w", path, err)
+	}
+	return &VersionedDatabase{
+		Database: corruptabledb.New(db),
+		Version:  v,
+	}, nil
+}
+
+func newDBFunc(dbType string) (func(string, []byte, logging.Logger) (database.Database, error), error) {
+	switch dbType {
+	case rocksdb.Name:
+		return rocksdb.New, nil
+	case leveldb.Name:
+		return leveldb.New, nil
+	case pebbledb.Name:
+		return pebbledb.New, nil
+	default:
+		return nil, fmt.Errorf(
+			"db-type was %q but should have been one of {%s, %s, %s, %s}",
+			dbType,
+			leveldb.Name,
+			rocksdb.Name,
+			pebbledb.Name,
+			memdb.Name,
+		)
+	}
+}
+
+// versionsDir returns the directory that holds a directory per database
+// version. Only LevelDB databases are kept directly in [dbDirPath], other
+// databases are kept in a directory of their own so that they can't be
+// confused with LevelDB databases.
+func versionsDir(dbType string, dbDirPath string) string {
+	switch dbType {
+	case rocksdb.Name, pebbledb.Name:
+		return filepath.Join(dbDirPath, dbType)
+	default:
+		return dbDirPath
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package migrate copies the versioned databases of a node from one database
+// backend to another.
+package migrate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/perms"
+	"github.com/flare-foundation/flare/utils/units"
+	"github.com/flare-foundation/flare/version"
+)
+
+const (
+	// ProgressFileName is the name of the file in the target directory that
+	// records the progress of a migration
+	ProgressFileName = "migration.json"
+
+	batchSize = 4 * units.MiB
+	// logFrequency is the number of keys after which the progress is logged
+	logFrequency = 1_000_000
+)
+
+var (
+	errNotEmpty       = errors.New("target database isn't empty")
+	errKeyMismatch    = errors.New("target database doesn't match the source database")
+	errMissingSource  = errors.New("source database has fewer keys than the target database")
+	errMissingTarget  = errors.New("target database has fewer keys than the source database")
+	errMismatchedKeys = errors.New("number of copied keys doesn't match the source database")
+)
+
+// Progress of a migration, persisted after every written batch so that an
+// interrupted migration can be resumed
+type Progress struct {
+	// Progress of each database version
+	Databases map[string]*DatabaseProgress `json:"databases"`
+}
+
+// DatabaseProgress is the progress of the migration of one database version
+type DatabaseProgress struct {
+	// Last key that was written to the target database
+	LastKey []byte `json:"lastKey"`
+	// Number of keys written to the target database
+	NumKeys uint64 `json:"numKeys"`
+	// Done is true once all keys were written
+	Done bool `json:"done"`
+}
+
+// Summary describes a database version after its migration
+type Summary struct {
+	Version version.Version
+	// Number of keys and the bytes of their keys and values, as verified in
+	// both the source and the target database
+	NumKeys  uint64
+	NumBytes uint64
+	// Checksum of the keys and values, as verified in both the source and the
+	// target database
+	Checksum ids.ID
+	// Resumed is true if the migration of this database continued a previous
+	// migration
+	Resumed bool
+}
+
+// Migrate copies every key of the databases of [from] into the databases
+// returned by [openTo] for the same versions. The progress is recorded in
+// [progressDir], from where a previous migration is resumed. After copying,
+// the source and target databases are compared key by key.
+func Migrate(
+	log logging.Logger,
+	from manager.Manager,
+	openTo func(version.Version) (database.Database, error),
+	progressDir string,
+) ([]Summary, error) {
+	progressPath := filepath.Join(progressDir, ProgressFileName)
+	progress, err := readProgress(progressPath)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]Summary, 0, len(from.GetDatabases()))
+	for _, fromDB := range from.GetDatabases() {
+		toDB, err := openTo(fromDB.Version)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't open target database %s: %w", fromDB.Version, err)
+		}
+
+		versionStr := fromDB.Version.String()
+		dbProgress, resumed := progress.Databases[versionStr]
+		if !resumed {
+			if !isEmpty(toDB) {
+				return nil, fmt.Errorf("%w: %s", errNotEmpty, versionStr)
+			}
+			// The progress is recorded before the first key is written, so
+			// that the written keys are never mistaken for unrelated data
+			dbProgress = &DatabaseProgress{}
+			progress.Databases[versionStr] = dbProgress
+			if err := writeProgress(progressPath, progress); err != nil {
+				return nil, err
+			}
+		}
+
+		if !dbProgress.Done {
+			if resumed {
+				log.Info("resuming migration of database %s after %d keys", versionStr, dbProgress.NumKeys)
+			} else {
+				log.Info("migrating database %s", versionStr)
+			}
+			err := copyDB(log, fromDB.Database, toDB, dbProgress, func() error {
+				return writeProgress(progressPath, progress)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("couldn't migrate database %s: %w", versionStr, err)
+			}
+		}
+
+		log.Info("verifying database %s", versionStr)
+		summary, err := verify(fromDB.Database, toDB)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't verify database %s: %w", versionStr, err)
+		}
+		if summary.NumKeys != dbProgress.NumKeys {
+			return nil, fmt.Errorf("%w: copied %d keys but the source has %d", errMismatchedKeys, dbProgress.NumKeys, summary.NumKeys)
+		}
+		summary.Version = fromDB.Version
+		summary.Resumed = resumed
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// copyDB copies the keys of [from] after [progress.LastKey] into [to],
+// calling [saveProgress] after every written batch.
+func copyDB(
+	log logging.Logger,
+	from database.Database,
+	to database.Database,
+	progress *DatabaseProgress,
+	saveProgress func() error,
+) error {
+	it := from.NewIteratorWithStart(progress.LastKey)
+	defer it.Release()
+
+	batch := to.NewBatch()
+	var lastKey []byte
+	numKeys := progress.NumKeys
+	writeBatch := func() error {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		progress.LastKey = lastKey
+		progress.NumKeys = numKeys
+		return saveProgress()
+	}
+
+	for it.Next() {
+		key := it.Key()
+		// The iterator starts at the last key written by the resumed migration
+		if progress.LastKey != nil && bytes.Equal(key, progress.LastKey) {
+			continue
+		}
+		if err := batch.Put(key, it.Value()); err != nil {
+			return err
+		}
+		lastKey = utils.CopyBytes(key)
+		numKeys++
+		if numKeys%logFrequency == 0 {
+			log.Info("migrated %d keys", numKeys)
+		}
+
+		if batch.Size() >= batchSize {
+			if err := writeBatch(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if lastKey == nil {
+		lastKey = progress.LastKey
+	}
+	if err := writeBatch(); err != nil {
+		return err
+	}
+
+	progress.Done = true
+	return saveProgress()
+}
+
+// verify iterates over [from] and [to] in lockstep, and returns an error if
+// they don't contain the same keys and values.
+func verify(from database.Database, to database.Database) (Summary, error) {
+	fromIt := from.NewIterator()
+	defer fromIt.Release()
+	toIt := to.NewIterator()
+	defer toIt.Release()
+
+	checksum := sha256.New()
+	summary := Summary{}
+	for fromIt.Next() {
+		if !toIt.Next() {
+			if err := toIt.Error(); err != nil {
+				return Summary{}, err
+			}
+			return Summary{}, fmt.Errorf("%w: missing key 0x%x", errMissingTarget, fromIt.Key())
+		}
+
+		key := fromIt.Key()
+		value := fromIt.Value()
+		if !bytes.Equal(key, toIt.Key()) || !bytes.Equal(value, toIt.Value()) {
+			return Summary{}, fmt.Errorf("%w: at key 0x%x", errKeyMismatch, key)
+		}
+		hashEntry(checksum, key, value)
+		summary.NumKeys++
+		summary.NumBytes += uint64(len(key) + len(value))
+	}
+	if err := fromIt.Error(); err != nil {
+		return Summary{}, err
+	}
+	if toIt.Next() {
+		return Summary{}, fmt.Errorf("%w: extra key 0x%x", errMissingSource, toIt.Key())
+	}
+	if err := toIt.Error(); err != nil {
+		return Summary{}, err
+	}
+
+	copy(summary.Checksum[:], checksum.Sum(nil))
+	return summary, nil
+}
+
+func hashEntry(h hash.Hash, key, value []byte) {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(key)))
+	h.Write(size[:])
+	h.Write(key)
+	binary.BigEndian.PutUint32(size[:], uint32(len(value)))
+	h.Write(size[:])
+	h.Write(value)
+}
+
+func isEmpty(db database.Database) bool {
+	it := db.NewIterator()
+	defer it.Release()
+	return !it.Next()
+}
+
+func readProgress(path string) (*Progress, error) {
+	progress := &Progress{}
+	b, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(b, progress); err != nil {
+			return nil, fmt.Errorf("couldn't parse migration progress %s: %w", path, err)
+		}
+	}
+	if progress.Databases == nil {
+		progress.Databases = make(map[string]*DatabaseProgress)
+	}
+	return progress, nil
+}
+
+// writeProgress replaces the progress file atomically, so that an
+// interruption never leaves a partially written file behind
+func writeProgress(path string, progress *Progress) error {
+	b, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := perms.WriteFile(tmpPath, b, perms.ReadWrite); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
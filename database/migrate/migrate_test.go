@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/units"
+	"github.com/flare-foundation/flare/version"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+func newTestManager(t *testing.T, numKeys int, valueSize int) manager.Manager {
+	current := &manager.VersionedDatabase{
+		Database: memdb.New(),
+		Version:  version.NewDefaultVersion(1, 4, 5),
+	}
+	previous := &manager.VersionedDatabase{
+		Database: memdb.New(),
+		Version:  version.NewDefaultVersion(1, 0, 0),
+	}
+	for i := 0; i < numKeys; i++ {
+		key := []byte(fmt.Sprintf("key%05d", i))
+		require.NoError(t, current.Database.Put(key, make([]byte, valueSize)))
+	}
+	require.NoError(t, previous.Database.Put([]byte("old"), []byte("value")))
+
+	m, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{current, previous})
+	require.NoError(t, err)
+	return m
+}
+
+// targets returns the target databases by version, creating them on demand
+type targets map[string]database.Database
+
+func (ts targets) open(v version.Version) (database.Database, error) {
+	db, ok := ts[v.String()]
+	if !ok {
+		db = memdb.New()
+		ts[v.String()] = db
+	}
+	return db, nil
+}
+
+func TestMigrate(t *testing.T) {
+	from := newTestManager(t, 100, 32)
+	to := targets{}
+
+	summaries, err := Migrate(logging.NoLog{}, from, to.open, t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "v1.4.5", summaries[0].Version.String())
+	assert.EqualValues(t, 100, summaries[0].NumKeys)
+	assert.False(t, summaries[0].Resumed)
+	assert.Equal(t, "v1.0.0", summaries[1].Version.String())
+	assert.EqualValues(t, 1, summaries[1].NumKeys)
+
+	value, err := to["v1.0.0"].Get([]byte("old"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestMigrateResume(t *testing.T) {
+	// Enough data for multiple batches
+	from := newTestManager(t, 200, 64*units.KiB)
+	fromDB := from.Current().Database
+	to := memdb.New()
+
+	// Interrupt the migration after the first batch
+	progress := &DatabaseProgress{}
+	err := copyDB(logging.NoLog{}, fromDB, to, progress, func() error {
+		return errInterrupted
+	})
+	require.ErrorIs(t, err, errInterrupted)
+	require.False(t, progress.Done)
+	require.NotZero(t, progress.NumKeys)
+	require.Less(t, progress.NumKeys, uint64(200))
+
+	err = copyDB(logging.NoLog{}, fromDB, to, progress, func() error { return nil })
+	require.NoError(t, err)
+	assert.True(t, progress.Done)
+	assert.EqualValues(t, 200, progress.NumKeys)
+
+	summary, err := verify(fromDB, to)
+	require.NoError(t, err)
+	assert.EqualValues(t, 200, summary.NumKeys)
+}
+
+func TestMigrateResumeFromProgressFile(t *testing.T) {
+	from := newTestManager(t, 10, 32)
+	progressDir := t.TempDir()
+	to := targets{}
+
+	_, err := Migrate(logging.NoLog{}, from, to.open, progressDir)
+	require.NoError(t, err)
+
+	// Migrating again only verifies the migrated databases
+	summaries, err := Migrate(logging.NoLog{}, from, to.open, progressDir)
+	require.NoError(t, err)
+	assert.True(t, summaries[0].Resumed)
+
+	// Changes of the target database are detected
+	require.NoError(t, to["v1.4.5"].Put([]byte("unexpected"), nil))
+	_, err = Migrate(logging.NoLog{}, from, to.open, progressDir)
+	assert.ErrorIs(t, err, errMissingSource)
+}
+
+func TestMigrateNotEmpty(t *testing.T) {
+	from := newTestManager(t, 10, 32)
+	to := targets{}
+	db, _ := to.open(version.NewDefaultVersion(1, 4, 5))
+	require.NoError(t, db.Put([]byte("existing"), nil))
+
+	_, err := Migrate(logging.NoLog{}, from, to.open, t.TempDir())
+	assert.ErrorIs(t, err, errNotEmpty)
+}
@@ -0,0 +1,523 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/units"
+)
+
+const (
+	// Name is the name of this database for database switches
+	Name = "pebbledb"
+
+	// CacheSize is the number of bytes to use for block caching in pebble.
+	CacheSize = 512 * units.MiB
+
+	// MemTableSize is the number of bytes of each memtable in pebble.
+	MemTableSize = 64 * units.MiB
+
+	// HandleCap is the number of files descriptors to cap pebble to use.
+	HandleCap = 1024
+
+	// BitsPerKey is the number of bits to add to the bloom filter per key.
+	BitsPerKey = 10
+
+	// MetricsProperty is the property for which Stat returns the metrics of
+	// pebble.
+	MetricsProperty = "pebble.metrics"
+
+	// pebbleByteOverhead is the number of bytes of constant overhead that
+	// should be added to a batch size per operation.
+	pebbleByteOverhead = 8
+)
+
+var (
+	_ database.Database = &Database{}
+	_ database.Batch    = &batch{}
+	_ database.Iterator = &iter{}
+
+	errUnknownProperty = errors.New("unknown property")
+
+	// writeOptions matches the durability of the other databases, which don't
+	// sync the write-ahead log on every write
+	writeOptions = pebble.NoSync
+)
+
+// Database is a persistent key-value store backed by pebble. Apart from basic
+// data storage functionality it also supports batch writes and iterating over
+// the keyspace in binary-alphabetical order.
+//
+// Pebble panics when it's used after being closed, so all calls are guarded
+// by [lock] and fail with [database.ErrClosed] once the database is closed.
+type Database struct {
+	lock     sync.RWMutex
+	pebbleDB *pebble.DB
+	closed   bool
+	// Iterators that haven't been released yet. Pebble refuses to close
+	// while iterators are open, so they're released when the database closes.
+	openIterators map[*iter]struct{}
+}
+
+type config struct {
+	// CacheSize is the number of bytes of the block cache.
+	//
+	// The default value is 512MiB.
+	CacheSize int64 `json:"cacheSize"`
+	// BytesPerSync syncs sstables periodically in order to smooth out writes
+	// to disk.
+	//
+	// The default value is 512KiB.
+	BytesPerSync int `json:"bytesPerSync"`
+	// WALBytesPerSync syncs the write-ahead log periodically in order to
+	// smooth out writes to disk. Zero disables the periodic syncs.
+	//
+	// The default value is 0.
+	WALBytesPerSync int `json:"walBytesPerSync"`
+	// MemTableSize is the size of a memtable before it's flushed to an
+	// sstable.
+	//
+	// The default value is 64MiB.
+	MemTableSize int `json:"memTableSize"`
+	// MemTableStopWritesThreshold is the number of queued memtables at which
+	// writes are stopped until the memtables are flushed.
+	//
+	// The default value is 8.
+	MemTableStopWritesThreshold int `json:"memTableStopWritesThreshold"`
+	// MaxOpenFiles is a soft limit on the number of open files.
+	//
+	// The default value is 1024.
+	MaxOpenFiles int `json:"maxOpenFiles"`
+	// MaxConcurrentCompactions is the maximum number of compactions that run
+	// concurrently.
+	//
+	// The default value is 1.
+	MaxConcurrentCompactions int `json:"maxConcurrentCompactions"`
+	// FilterBitsPerKey is the number of bits per key of the bloom filters of
+	// the sstables.
+	//
+	// The default value is 10.
+	FilterBitsPerKey int `json:"filterBitsPerKey"`
+}
+
+// New returns a wrapped pebble object.
+func New(file string, configBytes []byte, log logging.Logger) (database.Database, error) {
+	parsedConfig := config{
+		CacheSize:                   CacheSize,
+		BytesPerSync:                512 * units.KiB,
+		MemTableSize:                MemTableSize,
+		MemTableStopWritesThreshold: 8,
+		MaxOpenFiles:                HandleCap,
+		MaxConcurrentCompactions:    1,
+		FilterBitsPerKey:            BitsPerKey,
+	}
+	if len(configBytes) > 0 {
+		if err := json.Unmarshal(configBytes, &parsedConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse db config: %w", err)
+		}
+	}
+	configJSON, err := json.Marshal(&parsedConfig)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("pebbledb config: %s", string(configJSON))
+
+	// The database holds its own reference to the cache
+	cache := pebble.NewCache(parsedConfig.CacheSize)
+	defer cache.Unref()
+
+	opts := &pebble.Options{
+		Cache:                       cache,
+		BytesPerSync:                parsedConfig.BytesPerSync,
+		WALBytesPerSync:             parsedConfig.WALBytesPerSync,
+		MemTableSize:                parsedConfig.MemTableSize,
+		MemTableStopWritesThreshold: parsedConfig.MemTableStopWritesThreshold,
+		MaxOpenFiles:                parsedConfig.MaxOpenFiles,
+		MaxConcurrentCompactions: func() int {
+			return parsedConfig.MaxConcurrentCompactions
+		},
+		Levels: make([]pebble.LevelOptions, 7),
+	}
+	for i := range opts.Levels {
+		opts.Levels[i].FilterPolicy = bloom.FilterPolicy(parsedConfig.FilterBitsPerKey)
+	}
+	opts.EnsureDefaults()
+
+	db, err := pebble.Open(file, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{
+		pebbleDB:      db,
+		openIterators: make(map[*iter]struct{}),
+	}, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+	_, closer, err := db.pebbleDB.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, updateError(err)
+	}
+	return true, closer.Close()
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	value, closer, err := db.pebbleDB.Get(key)
+	if err != nil {
+		return nil, updateError(err)
+	}
+	// [value] is only valid until [closer] is closed
+	value = utils.CopyBytes(value)
+	return value, closer.Close()
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.pebbleDB.Set(key, value, writeOptions))
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.pebbleDB.Delete(key, writeOptions))
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		db:    db,
+		batch: db.pebbleDB.NewBatch(),
+	}
+}
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.newIter(&pebble.IterOptions{})
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.newIter(&pebble.IterOptions{
+		LowerBound: start,
+	})
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.newIter(prefixBounds(prefix))
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	opts := prefixBounds(prefix)
+	if string(start) > string(prefix) {
+		opts.LowerBound = start
+	}
+	return db.newIter(opts)
+}
+
+func (db *Database) newIter(opts *pebble.IterOptions) database.Iterator {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return &iter{
+			db:  db,
+			err: database.ErrClosed,
+		}
+	}
+	it := &iter{
+		db:   db,
+		iter: db.pebbleDB.NewIter(opts),
+	}
+	db.openIterators[it] = struct{}{}
+	return it
+}
+
+// Stat returns a particular internal stat of the database. The only supported
+// property is [MetricsProperty].
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return "", database.ErrClosed
+	}
+	if property != MetricsProperty {
+		return "", fmt.Errorf("%w: %q", errUnknownProperty, property)
+	}
+	return db.pebbleDB.Metrics().String(), nil
+}
+
+// Compact the underlying DB for the given key range.
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if limit == nil {
+		// Pebble needs an upper bound, so the key after the last key is used
+		it := db.pebbleDB.NewIter(&pebble.IterOptions{})
+		if !it.Last() {
+			// The database is empty
+			return updateError(it.Close())
+		}
+		limit = append(utils.CopyBytes(it.Key()), 0)
+		if err := it.Close(); err != nil {
+			return updateError(err)
+		}
+	}
+	if string(start) >= string(limit) {
+		return nil
+	}
+	return updateError(db.pebbleDB.Compact(start, limit, true))
+}
+
+// Close releases the open iterators and closes the database
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+
+	for it := range db.openIterators {
+		it.release()
+	}
+	db.openIterators = nil
+	return updateError(db.pebbleDB.Close())
+}
+
+// batch is a wrapper around a pebble batch to contain sizes.
+type batch struct {
+	db    *Database
+	batch *pebble.Batch
+	size  int
+	// Pebble can only write a batch once, so a batch that was written
+	// already is copied before it's written again
+	written bool
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.size += len(key) + len(value) + pebbleByteOverhead
+	return b.batch.Set(key, value, nil)
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.size += len(key) + pebbleByteOverhead
+	return b.batch.Delete(key, nil)
+}
+
+// Size retrieves the amount of data queued up for writing.
+func (b *batch) Size() int { return b.size }
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+	if b.written {
+		newBatch := b.db.pebbleDB.NewBatch()
+		if err := newBatch.Apply(b.batch, nil); err != nil {
+			return err
+		}
+		b.batch = newBatch
+	}
+	b.written = true
+	return updateError(b.batch.Commit(writeOptions))
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.batch.Reset()
+	b.written = false
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	reader := b.batch.Reader()
+	for {
+		kind, key, value, ok := reader.Next()
+		if !ok {
+			return nil
+		}
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			if err := w.Put(key, value); err != nil {
+				return err
+			}
+		case pebble.InternalKeyKindDelete:
+			if err := w.Delete(key); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected batch operation %s", kind)
+		}
+	}
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+type iter struct {
+	db   *Database
+	iter *pebble.Iterator
+
+	started  bool
+	released bool
+	key, val []byte
+	err      error
+}
+
+func (it *iter) Next() bool {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	// Short-circuit and set an error if the underlying database has been closed.
+	if it.db.closed {
+		it.key = nil
+		it.val = nil
+		it.err = database.ErrClosed
+		return false
+	}
+	if it.released || it.err != nil {
+		it.key = nil
+		it.val = nil
+		return false
+	}
+
+	var hasNext bool
+	if it.started {
+		hasNext = it.iter.Next()
+	} else {
+		hasNext = it.iter.First()
+		it.started = true
+	}
+	if hasNext {
+		it.key = utils.CopyBytes(it.iter.Key())
+		it.val = utils.CopyBytes(it.iter.Value())
+	} else {
+		it.key = nil
+		it.val = nil
+		it.err = updateError(it.iter.Error())
+	}
+	return hasNext
+}
+
+func (it *iter) Error() error { return it.err }
+
+func (it *iter) Key() []byte { return it.key }
+
+func (it *iter) Value() []byte { return it.val }
+
+func (it *iter) Release() {
+	it.db.lock.Lock()
+	defer it.db.lock.Unlock()
+
+	if it.db.closed {
+		// The database released the iterator when it was closed
+		return
+	}
+	it.release()
+	delete(it.db.openIterators, it)
+}
+
+// release assumes the database lock is held
+func (it *iter) release() {
+	if it.released || it.iter == nil {
+		it.released = true
+		return
+	}
+	it.released = true
+	if err := it.iter.Close(); err != nil && it.err == nil {
+		it.err = updateError(err)
+	}
+}
+
+// prefixBounds returns the iterator options that restrict iteration to the
+// keys starting with [prefix]
+func prefixBounds(prefix []byte) *pebble.IterOptions {
+	opts := &pebble.IterOptions{
+		LowerBound: prefix,
+	}
+	// The upper bound is the smallest key that's larger than all keys with
+	// [prefix], which doesn't exist if [prefix] consists of 0xff bytes only.
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			upperBound := utils.CopyBytes(prefix[:i+1])
+			upperBound[i]++
+			opts.UpperBound = upperBound
+			break
+		}
+	}
+	return opts
+}
+
+func updateError(err error) error {
+	switch err {
+	case pebble.ErrClosed:
+		return database.ErrClosed
+	case pebble.ErrNotFound:
+		return database.ErrNotFound
+	default:
+		return err
+	}
+}
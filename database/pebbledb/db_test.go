@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebbledb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		folder := t.TempDir()
+		db, err := New(folder, nil, logging.NoLog{})
+		if err != nil {
+			t.Fatalf("pebbledb.New(%q, logging.NoLog{}) errored with %s", folder, err)
+		}
+
+		defer db.Close()
+
+		test(t, db)
+
+		// The database may have been closed by the test, so we don't care if it
+		// errors here.
+		_ = db.Close()
+	}
+}
+
+func BenchmarkInterface(b *testing.B) {
+	for _, size := range database.BenchmarkSizes {
+		keys, values := database.SetupBenchmark(b, size[0], size[1], size[2])
+		for _, bench := range database.Benchmarks {
+			folder := b.TempDir()
+
+			db, err := New(folder, nil, logging.NoLog{})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			bench(b, db, "pebbledb", keys, values)
+
+			// The database may have been closed by the test, so we don't care if it
+			// errors here.
+			_ = db.Close()
+		}
+	}
+}
+
+func TestPrefixBounds(t *testing.T) {
+	tests := []struct {
+		prefix     []byte
+		upperBound []byte
+	}{
+		{prefix: nil, upperBound: nil},
+		{prefix: []byte{0x01}, upperBound: []byte{0x02}},
+		{prefix: []byte{0x01, 0xff}, upperBound: []byte{0x02}},
+		{prefix: []byte{0xff, 0xff}, upperBound: nil},
+	}
+	for _, test := range tests {
+		opts := prefixBounds(test.prefix)
+		if !bytes.Equal(opts.LowerBound, test.prefix) {
+			t.Fatalf("prefixBounds(0x%x) lower bound: 0x%x", test.prefix, opts.LowerBound)
+		}
+		if !bytes.Equal(opts.UpperBound, test.upperBound) {
+			t.Fatalf("prefixBounds(0x%x) upper bound: 0x%x ; Expected: 0x%x", test.prefix, opts.UpperBound, test.upperBound)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/utils/perms"
+	"github.com/flare-foundation/flare/version"
+)
+
+var errArchiveExists = errors.New("archive already exists")
+
+// WriteFile writes a snapshot of [dbs] to a new archive at [path]. The
+// archive only appears at [path] once it has been completely written.
+func WriteFile(path string, dbs []*manager.VersionedDatabase) (Summary, error) {
+	if _, err := os.Stat(path); err == nil {
+		return Summary{}, fmt.Errorf("%w: %s", errArchiveExists, path)
+	}
+
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmpFile, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return Summary{}, err
+	}
+	tmpPath := tmpFile.Name()
+	summary, err := writeFile(tmpFile, dbs)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return Summary{}, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return Summary{}, err
+	}
+	return summary, nil
+}
+
+func writeFile(f *os.File, dbs []*manager.VersionedDatabase) (Summary, error) {
+	if err := f.Chmod(perms.ReadWrite); err != nil {
+		_ = f.Close()
+		return Summary{}, err
+	}
+	summary, err := Write(f, dbs)
+	if err != nil {
+		_ = f.Close()
+		return Summary{}, err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return Summary{}, err
+	}
+	return summary, f.Close()
+}
+
+// VerifyFile checks the checksums of the archive at [path]
+func VerifyFile(path string) (Summary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer f.Close()
+	return Verify(f)
+}
+
+// RestoreFile verifies the archive at [path] and then restores it into the
+// databases returned by [open]. Nothing is written if the archive is
+// corrupted.
+func RestoreFile(path string, open func(version.Version) (database.Database, error)) (Summary, error) {
+	if _, err := VerifyFile(path); err != nil {
+		return Summary{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer f.Close()
+	return Restore(f, open)
+}
@@ -0,0 +1,419 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package snapshot writes point-in-time copies of the node's versioned
+// databases into portable archives and restores databases from them.
+//
+// An archive is laid out as follows, with all integers big endian:
+//
+//	archive  := header section* checksum
+//	header   := magic [8]byte | format uint16 | timestamp int64 | numSections uint32
+//	section  := versionLen uint16 | version | entry* | end
+//	entry    := 0x01 | keyLen uint32 | key | valueLen uint32 | value
+//	end      := 0x00 | numKeys uint64 | sectionChecksum [32]byte
+//
+// Each section holds the key/value pairs of one database version. The checksum
+// of a section is the SHA256 hash of its entries, the checksum of the archive
+// is the SHA256 hash of all bytes preceding it.
+package snapshot
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/units"
+	"github.com/flare-foundation/flare/version"
+)
+
+const (
+	// FormatVersion is the version of the archive format written by this
+	// package
+	FormatVersion uint16 = 1
+
+	entryTag byte = 1
+	endTag   byte = 0
+
+	// maxEntrySize bounds the keys and values read from an archive
+	maxEntrySize = 512 * units.MiB
+	// restoreBatchSize is the size of the batches written while restoring
+	restoreBatchSize = 4 * units.MiB
+)
+
+var (
+	magic = [8]byte{'F', 'L', 'R', 'S', 'N', 'A', 'P', 0}
+
+	errInvalidMagic    = errors.New("not a database snapshot")
+	errUnknownFormat   = errors.New("unknown snapshot format")
+	errInvalidTag      = errors.New("invalid entry tag")
+	errEntryTooLarge   = errors.New("entry exceeds maximum size")
+	errChecksum        = errors.New("checksum mismatch")
+	errNumKeysMismatch = errors.New("number of keys mismatch")
+	errNotEmpty        = errors.New("database to restore into isn't empty")
+)
+
+// Summary describes the content of an archive
+type Summary struct {
+	// Time at which the snapshot was taken
+	Timestamp time.Time
+	Databases []DatabaseSummary
+	// Checksum of the whole archive
+	Checksum ids.ID
+}
+
+// DatabaseSummary describes the content of one database version of an archive
+type DatabaseSummary struct {
+	Version  version.Version
+	NumKeys  uint64
+	NumBytes uint64
+	Checksum ids.ID
+}
+
+// Write a snapshot of [dbs] to [w].
+//
+// Each database is read with a single iterator. The iterators of the LevelDB,
+// RocksDB and in-memory databases read from an implicit snapshot of the
+// database, so the archive holds a consistent view of each database even
+// while it's written to.
+func Write(w io.Writer, dbs []*manager.VersionedDatabase) (Summary, error) {
+	bw := bufio.NewWriter(w)
+	aw := &archiveWriter{
+		w:    bw,
+		hash: sha256.New(),
+	}
+
+	summary := Summary{
+		Timestamp: time.Now().Truncate(time.Second),
+		Databases: make([]DatabaseSummary, 0, len(dbs)),
+	}
+	aw.write(magic[:])
+	aw.writeUint16(FormatVersion)
+	aw.writeUint64(uint64(summary.Timestamp.Unix()))
+	aw.writeUint32(uint32(len(dbs)))
+
+	for _, db := range dbs {
+		dbSummary, err := aw.writeSection(db)
+		if err != nil {
+			return Summary{}, fmt.Errorf("couldn't write database %s: %w", db.Version, err)
+		}
+		summary.Databases = append(summary.Databases, dbSummary)
+	}
+	if aw.err != nil {
+		return Summary{}, aw.err
+	}
+
+	copy(summary.Checksum[:], aw.hash.Sum(nil))
+	if _, err := bw.Write(summary.Checksum[:]); err != nil {
+		return Summary{}, err
+	}
+	return summary, bw.Flush()
+}
+
+// Verify reads the archive from [r] and checks its checksums without writing
+// anything.
+func Verify(r io.Reader) (Summary, error) {
+	return read(r, nil)
+}
+
+// Restore writes the content of the archive read from [r] into the databases
+// returned by [open] for each version in the archive. The databases must be
+// empty. An error is returned if the archive is corrupted, in which case the
+// databases may be partially written.
+func Restore(r io.Reader, open func(version.Version) (database.Database, error)) (Summary, error) {
+	return read(r, open)
+}
+
+// read the archive from [r]. If [open] is non-nil, the archive's entries are
+// written to the databases it returns.
+func read(r io.Reader, open func(version.Version) (database.Database, error)) (Summary, error) {
+	ar := &archiveReader{
+		r:    bufio.NewReader(r),
+		hash: sha256.New(),
+	}
+
+	var fileMagic [8]byte
+	if err := ar.read(fileMagic[:]); err != nil {
+		return Summary{}, err
+	}
+	if fileMagic != magic {
+		return Summary{}, errInvalidMagic
+	}
+	format, err := ar.readUint16()
+	if err != nil {
+		return Summary{}, err
+	}
+	if format != FormatVersion {
+		return Summary{}, fmt.Errorf("%w: %d", errUnknownFormat, format)
+	}
+	timestamp, err := ar.readUint64()
+	if err != nil {
+		return Summary{}, err
+	}
+	numSections, err := ar.readUint32()
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{
+		Timestamp: time.Unix(int64(timestamp), 0),
+	}
+	parser := version.NewDefaultParser()
+	for i := uint32(0); i < numSections; i++ {
+		versionBytes, err := ar.readBytes16()
+		if err != nil {
+			return Summary{}, err
+		}
+		dbVersion, err := parser.Parse(string(versionBytes))
+		if err != nil {
+			return Summary{}, fmt.Errorf("invalid database version: %w", err)
+		}
+
+		var db database.Database
+		if open != nil {
+			db, err = open(dbVersion)
+			if err != nil {
+				return Summary{}, fmt.Errorf("couldn't open database %s: %w", dbVersion, err)
+			}
+		}
+		dbSummary, err := ar.readSection(db)
+		if err != nil {
+			return Summary{}, fmt.Errorf("couldn't read database %s: %w", dbVersion, err)
+		}
+		dbSummary.Version = dbVersion
+		summary.Databases = append(summary.Databases, dbSummary)
+	}
+
+	copy(summary.Checksum[:], ar.hash.Sum(nil))
+	var checksum ids.ID
+	if _, err := io.ReadFull(ar.r, checksum[:]); err != nil {
+		return Summary{}, err
+	}
+	if checksum != summary.Checksum {
+		return Summary{}, fmt.Errorf("%w: archive", errChecksum)
+	}
+	return summary, nil
+}
+
+type archiveWriter struct {
+	w    io.Writer
+	hash hash.Hash
+	// hash of the section being written
+	sectionHash hash.Hash
+	err         error
+	buf         [8]byte
+}
+
+func (aw *archiveWriter) writeSection(db *manager.VersionedDatabase) (DatabaseSummary, error) {
+	summary := DatabaseSummary{Version: db.Version}
+	aw.writeBytes16([]byte(db.Version.String()))
+
+	aw.sectionHash = sha256.New()
+	it := db.Database.NewIterator()
+	defer it.Release()
+	for it.Next() && aw.err == nil {
+		key := it.Key()
+		value := it.Value()
+		aw.write([]byte{entryTag})
+		aw.writeBytes32(key)
+		aw.writeBytes32(value)
+		summary.NumKeys++
+		summary.NumBytes += uint64(len(key) + len(value))
+	}
+	if err := it.Error(); err != nil {
+		return DatabaseSummary{}, err
+	}
+	copy(summary.Checksum[:], aw.sectionHash.Sum(nil))
+	aw.sectionHash = nil
+
+	aw.write([]byte{endTag})
+	aw.writeUint64(summary.NumKeys)
+	aw.write(summary.Checksum[:])
+	return summary, aw.err
+}
+
+func (aw *archiveWriter) write(b []byte) {
+	if aw.err != nil {
+		return
+	}
+	if _, aw.err = aw.w.Write(b); aw.err != nil {
+		return
+	}
+	aw.hash.Write(b)
+	if aw.sectionHash != nil {
+		aw.sectionHash.Write(b)
+	}
+}
+
+func (aw *archiveWriter) writeUint16(v uint16) {
+	binary.BigEndian.PutUint16(aw.buf[:], v)
+	aw.write(aw.buf[:2])
+}
+
+func (aw *archiveWriter) writeUint32(v uint32) {
+	binary.BigEndian.PutUint32(aw.buf[:], v)
+	aw.write(aw.buf[:4])
+}
+
+func (aw *archiveWriter) writeUint64(v uint64) {
+	binary.BigEndian.PutUint64(aw.buf[:], v)
+	aw.write(aw.buf[:8])
+}
+
+func (aw *archiveWriter) writeBytes16(b []byte) {
+	aw.writeUint16(uint16(len(b)))
+	aw.write(b)
+}
+
+func (aw *archiveWriter) writeBytes32(b []byte) {
+	aw.writeUint32(uint32(len(b)))
+	aw.write(b)
+}
+
+type archiveReader struct {
+	r    io.Reader
+	hash hash.Hash
+	// hash of the section being read
+	sectionHash hash.Hash
+	buf         [8]byte
+}
+
+// readSection reads the entries of a section and writes them to [db], if
+// non-nil.
+func (ar *archiveReader) readSection(db database.Database) (DatabaseSummary, error) {
+	if db != nil {
+		it := db.NewIterator()
+		empty := !it.Next()
+		it.Release()
+		if !empty {
+			return DatabaseSummary{}, errNotEmpty
+		}
+	}
+
+	var batch database.Batch
+	if db != nil {
+		batch = db.NewBatch()
+	}
+
+	summary := DatabaseSummary{}
+	ar.sectionHash = sha256.New()
+	for {
+		// The end tag isn't part of the section's checksum, so the tag is only
+		// added to it once it's known to start an entry
+		tag := []byte{0}
+		sectionHash := ar.sectionHash
+		ar.sectionHash = nil
+		err := ar.read(tag)
+		ar.sectionHash = sectionHash
+		if err != nil {
+			return DatabaseSummary{}, err
+		}
+		if tag[0] == endTag {
+			break
+		}
+		if tag[0] != entryTag {
+			return DatabaseSummary{}, fmt.Errorf("%w: %d", errInvalidTag, tag[0])
+		}
+		ar.sectionHash.Write(tag)
+
+		key, err := ar.readBytes32()
+		if err != nil {
+			return DatabaseSummary{}, err
+		}
+		value, err := ar.readBytes32()
+		if err != nil {
+			return DatabaseSummary{}, err
+		}
+		summary.NumKeys++
+		summary.NumBytes += uint64(len(key) + len(value))
+
+		if batch == nil {
+			continue
+		}
+		if err := batch.Put(key, value); err != nil {
+			return DatabaseSummary{}, err
+		}
+		if batch.Size() >= restoreBatchSize {
+			if err := batch.Write(); err != nil {
+				return DatabaseSummary{}, err
+			}
+			batch.Reset()
+		}
+	}
+	copy(summary.Checksum[:], ar.sectionHash.Sum(nil))
+	ar.sectionHash = nil
+
+	numKeys, err := ar.readUint64()
+	if err != nil {
+		return DatabaseSummary{}, err
+	}
+	var checksum ids.ID
+	if err := ar.read(checksum[:]); err != nil {
+		return DatabaseSummary{}, err
+	}
+	if numKeys != summary.NumKeys {
+		return DatabaseSummary{}, fmt.Errorf("%w: expected %d but read %d", errNumKeysMismatch, numKeys, summary.NumKeys)
+	}
+	if checksum != summary.Checksum {
+		return DatabaseSummary{}, fmt.Errorf("%w: database", errChecksum)
+	}
+
+	if batch == nil {
+		return summary, nil
+	}
+	return summary, batch.Write()
+}
+
+func (ar *archiveReader) read(b []byte) error {
+	if _, err := io.ReadFull(ar.r, b); err != nil {
+		return err
+	}
+	ar.hash.Write(b)
+	if ar.sectionHash != nil {
+		ar.sectionHash.Write(b)
+	}
+	return nil
+}
+
+func (ar *archiveReader) readUint16() (uint16, error) {
+	err := ar.read(ar.buf[:2])
+	return binary.BigEndian.Uint16(ar.buf[:2]), err
+}
+
+func (ar *archiveReader) readUint32() (uint32, error) {
+	err := ar.read(ar.buf[:4])
+	return binary.BigEndian.Uint32(ar.buf[:4]), err
+}
+
+func (ar *archiveReader) readUint64() (uint64, error) {
+	err := ar.read(ar.buf[:8])
+	return binary.BigEndian.Uint64(ar.buf[:8]), err
+}
+
+func (ar *archiveReader) readBytes16() ([]byte, error) {
+	size, err := ar.readUint16()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, size)
+	return b, ar.read(b)
+}
+
+func (ar *archiveReader) readBytes32() ([]byte, error) {
+	size, err := ar.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if size > maxEntrySize {
+		return nil, fmt.Errorf("%w: %d bytes", errEntryTooLarge, size)
+	}
+	b := make([]byte, size)
+	return b, ar.read(b)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/version"
+)
+
+func newTestDBs(t *testing.T) []*manager.VersionedDatabase {
+	dbs := []*manager.VersionedDatabase{
+		{
+			Database: memdb.New(),
+			Version:  version.NewDefaultVersion(1, 4, 5),
+		},
+		{
+			Database: memdb.New(),
+			Version:  version.NewDefaultVersion(1, 0, 0),
+		},
+	}
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key%d", i))
+		require.NoError(t, dbs[0].Database.Put(key, []byte{byte(i)}))
+	}
+	require.NoError(t, dbs[1].Database.Put([]byte("old"), []byte("value")))
+	return dbs
+}
+
+func assertSameContent(t *testing.T, expected, actual database.Database) {
+	expectedIt := expected.NewIterator()
+	defer expectedIt.Release()
+	actualIt := actual.NewIterator()
+	defer actualIt.Release()
+
+	for expectedIt.Next() {
+		require.True(t, actualIt.Next())
+		assert.Equal(t, expectedIt.Key(), actualIt.Key())
+		assert.Equal(t, expectedIt.Value(), actualIt.Value())
+	}
+	assert.False(t, actualIt.Next())
+}
+
+func TestWriteRestore(t *testing.T) {
+	dbs := newTestDBs(t)
+
+	archive := &bytes.Buffer{}
+	written, err := Write(archive, dbs)
+	require.NoError(t, err)
+	require.Len(t, written.Databases, 2)
+	assert.EqualValues(t, 100, written.Databases[0].NumKeys)
+	assert.EqualValues(t, 1, written.Databases[1].NumKeys)
+
+	verified, err := Verify(bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, written.Checksum, verified.Checksum)
+
+	restored := map[string]database.Database{}
+	summary, err := Restore(bytes.NewReader(archive.Bytes()), func(v version.Version) (database.Database, error) {
+		db := memdb.New()
+		restored[v.String()] = db
+		return db, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, written.Timestamp.Unix(), summary.Timestamp.Unix())
+	for i, db := range dbs {
+		assert.Equal(t, written.Databases[i].Checksum, summary.Databases[i].Checksum)
+		assert.Equal(t, written.Databases[i].NumBytes, summary.Databases[i].NumBytes)
+		assertSameContent(t, db.Database, restored[db.Version.String()])
+	}
+}
+
+func TestVerifyCorrupted(t *testing.T) {
+	archive := &bytes.Buffer{}
+	_, err := Write(archive, newTestDBs(t))
+	require.NoError(t, err)
+
+	// Flip the last byte of the first value
+	b := archive.Bytes()
+	i := bytes.Index(b, []byte("key0")) + len("key0") + 4
+	b[i] ^= 1
+	_, err = Verify(bytes.NewReader(b))
+	assert.ErrorIs(t, err, errChecksum)
+
+	_, err = Verify(bytes.NewReader(b[:len(b)-1]))
+	assert.Error(t, err)
+
+	_, err = Verify(bytes.NewReader([]byte("not an archive")))
+	assert.ErrorIs(t, err, errInvalidMagic)
+}
+
+func TestRestoreNotEmpty(t *testing.T) {
+	archive := &bytes.Buffer{}
+	_, err := Write(archive, newTestDBs(t))
+	require.NoError(t, err)
+
+	_, err = Restore(archive, func(version.Version) (database.Database, error) {
+		db := memdb.New()
+		return db, db.Put([]byte("existing"), nil)
+	})
+	assert.ErrorIs(t, err, errNotEmpty)
+}
+
+func TestFile(t *testing.T) {
+	dbs := newTestDBs(t)
+	path := filepath.Join(t.TempDir(), "snapshot")
+
+	written, err := WriteFile(path, dbs)
+	require.NoError(t, err)
+
+	_, err = WriteFile(path, dbs)
+	assert.ErrorIs(t, err, errArchiveExists)
+
+	restored := map[string]database.Database{}
+	summary, err := RestoreFile(path, func(v version.Version) (database.Database, error) {
+		db := memdb.New()
+		restored[v.String()] = db
+		return db, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, written.Checksum, summary.Checksum)
+	for _, db := range dbs {
+		assertSameContent(t, db.Database, restored[db.Version.String()])
+	}
+}
@@ -27,6 +27,8 @@ type Client interface {
 	GetLastAccepted(context.Context, *GetLastAcceptedArgs) (Container, error)
 	// Returns 1 less than the number of containers accepted on this chain
 	GetIndex(context.Context, *GetIndexArgs) (uint64, error)
+	// Get the index of the oldest container that wasn't pruned
+	GetOldestIndex(context.Context) (uint64, error)
 	// Returns true if the given container is accepted
 	IsAccepted(context.Context, *GetIndexArgs) (bool, error)
 	// Get a container by its index
@@ -105,6 +107,12 @@ func (c *client) GetIndex(ctx context.Context, args *GetIndexArgs) (uint64, erro
 	return uint64(index.Index), err
 }
 
+func (c *client) GetOldestIndex(ctx context.Context) (uint64, error) {
+	var res GetOldestIndexResponse
+	err := c.requester.SendRequest(ctx, "getOldestIndex", struct{}{}, &res)
+	return uint64(res.OldestIndex), err
+}
+
 func (c *client) IsAccepted(ctx context.Context, args *GetIndexArgs) (bool, error) {
 	var isAccepted bool
 	err := c.requester.SendRequest(ctx, "isAccepted", args, &isAccepted)
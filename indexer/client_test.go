@@ -41,6 +41,20 @@ func TestIndexClient(t *testing.T) {
 		assert.NoError(err)
 		assert.EqualValues(5, index)
 	}
+	{
+		// Test GetOldestIndex
+		client.requester = &mockClient{
+			assert:         assert,
+			expectedMethod: "getOldestIndex",
+			onSendRequestF: func(reply interface{}) error {
+				*(reply.(*GetOldestIndexResponse)) = GetOldestIndexResponse{OldestIndex: 7}
+				return nil
+			},
+		}
+		index, err := client.GetOldestIndex(context.Background())
+		assert.NoError(err)
+		assert.EqualValues(7, index)
+	}
 	{
 		// Test GetLastAccepted
 		id := ids.GenerateTestID()
@@ -32,8 +32,15 @@ var (
 	nextAcceptedIndexKey   = []byte{0x00}
 	indexToContainerPrefix = []byte{0x01}
 	containerToIDPrefix    = []byte{0x02}
-	errNoneAccepted        = errors.New("no containers have been accepted")
-	errNumToFetchZero      = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedByRange)
+	// Maps to the byte representation of the index of the oldest container
+	// that wasn't pruned
+	firstRetainedIndexKey = []byte{0x03}
+	errNoneAccepted       = errors.New("no containers have been accepted")
+	errNumToFetchZero     = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedByRange)
+
+	// ErrPruned is returned when a requested container was removed from an
+	// index by its retention policy
+	ErrPruned = errors.New("container was pruned")
 
 	_ Index = &index{}
 )
@@ -49,6 +56,7 @@ type Index interface {
 	GetLastAccepted() (Container, error)
 	GetIndex(containerID ids.ID) (uint64, error)
 	GetContainerByID(containerID ids.ID) (Container, error)
+	GetOldestIndex() uint64
 	io.Closer
 }
 
@@ -59,6 +67,18 @@ type index struct {
 	lock  sync.RWMutex
 	// The index of the next accepted transaction
 	nextAcceptedIndex uint64
+	// The index of the oldest container that wasn't pruned
+	firstRetainedIndex uint64
+	retention          RetentionPolicy
+	// Signals the pruner that a container was accepted
+	pruneSignal chan struct{}
+	// Closed when the index is closed
+	closing   chan struct{}
+	closeOnce sync.Once
+	// Closed when the pruner stopped
+	prunerDone chan struct{}
+	// Receive a value after containers are accepted
+	subscribers map[chan struct{}]struct{}
 	// When [baseDB] is committed, writes to [baseDB]
 	vDB    *versiondb.Database
 	baseDB database.Database
@@ -72,12 +92,15 @@ type index struct {
 
 // Returns a new, thread-safe Index.
 // Closes [baseDB] on close.
+// If [retention] limits the index, the containers it doesn't retain are
+// deleted in the background.
 func newIndex(
 	baseDB database.Database,
 	log logging.Logger,
 	codec codec.Manager,
 	clock mockable.Clock,
-) (Index, error) {
+	retention RetentionPolicy,
+) (*index, error) {
 	vDB := versiondb.New(baseDB)
 	indexToContainer := prefixdb.New(indexToContainerPrefix, vDB)
 	containerToIndex := prefixdb.New(containerToIDPrefix, vDB)
@@ -90,32 +113,59 @@ func newIndex(
 		indexToContainer: indexToContainer,
 		containerToIndex: containerToIndex,
 		log:              log,
+		retention:        retention,
+		pruneSignal:      make(chan struct{}, 1),
+		closing:          make(chan struct{}),
+		prunerDone:       make(chan struct{}),
+		subscribers:      make(map[chan struct{}]struct{}),
 	}
 
 	// Get next accepted index from db
 	nextAcceptedIndex, err := database.GetUInt64(i.vDB, nextAcceptedIndexKey)
-	if err == database.ErrNotFound {
+	switch {
+	case err == database.ErrNotFound:
 		// Couldn't find it in the database. Must not have accepted any containers in previous runs.
-		i.log.Info("next accepted index %d", i.nextAcceptedIndex)
-		return i, nil
-	}
-	if err != nil {
+	case err != nil:
 		return nil, fmt.Errorf("couldn't get next accepted index from database: %w", err)
+	default:
+		i.nextAcceptedIndex = nextAcceptedIndex
 	}
-	i.nextAcceptedIndex = nextAcceptedIndex
 	i.log.Info("next accepted index %d", i.nextAcceptedIndex)
+
+	// Get the oldest retained index from db
+	firstRetainedIndex, err := database.GetUInt64(i.vDB, firstRetainedIndexKey)
+	switch {
+	case err == database.ErrNotFound:
+		// No containers have been pruned in previous runs.
+	case err != nil:
+		return nil, fmt.Errorf("couldn't get first retained index from database: %w", err)
+	default:
+		i.firstRetainedIndex = firstRetainedIndex
+		i.log.Info("first retained index %d", i.firstRetainedIndex)
+	}
+
+	if retention.Enabled() {
+		go i.runPruner()
+	} else {
+		close(i.prunerDone)
+	}
 	return i, nil
 }
 
-// Close this index
+// Close this index. Calling Close after it has been called does nothing.
 func (i *index) Close() error {
 	errs := wrappers.Errs{}
-	errs.Add(
-		i.indexToContainer.Close(),
-		i.containerToIndex.Close(),
-		i.vDB.Close(),
-		i.baseDB.Close(),
-	)
+	i.closeOnce.Do(func() {
+		close(i.closing)
+		<-i.prunerDone
+
+		errs.Add(
+			i.indexToContainer.Close(),
+			i.containerToIndex.Close(),
+			i.vDB.Close(),
+			i.baseDB.Close(),
+		)
+	})
 	return errs.Err
 }
 
@@ -166,7 +216,42 @@ func (i *index) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container
 	}
 
 	// Atomically commit [i.vDB], [i.indexToContainer], [i.containerToIndex] to [i.baseDB]
-	return i.vDB.Commit()
+	if err := i.vDB.Commit(); err != nil {
+		return err
+	}
+
+	if i.retention.Enabled() {
+		notify(i.pruneSignal)
+	}
+	for subscriber := range i.subscribers {
+		notify(subscriber)
+	}
+	return nil
+}
+
+// subscribe returns a channel that receives a value after containers are
+// accepted, and a function that stops the notifications. Notifications are
+// coalesced while the previous one wasn't received.
+func (i *index) subscribe() (<-chan struct{}, func()) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	subscriber := make(chan struct{}, 1)
+	i.subscribers[subscriber] = struct{}{}
+	return subscriber, func() {
+		i.lock.Lock()
+		defer i.lock.Unlock()
+
+		delete(i.subscribers, subscriber)
+	}
+}
+
+// notify sends a value to [c] unless it already holds one
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
 }
 
 // Returns the ID of the [index]th accepted container and the container itself.
@@ -186,6 +271,9 @@ func (i *index) getContainerByIndex(index uint64) (Container, error) {
 	if !ok || index > lastAcceptedIndex {
 		return Container{}, fmt.Errorf("no container at index %d", index)
 	}
+	if index < i.firstRetainedIndex {
+		return Container{}, fmt.Errorf("%w: index %d is before the oldest retained index %d", ErrPruned, index, i.firstRetainedIndex)
+	}
 	indexBytes := database.PackUInt64(index)
 	return i.getContainerByIndexBytes(indexBytes)
 }
@@ -225,6 +313,8 @@ func (i *index) GetContainerRange(startIndex, numToFetch uint64) ([]Container, e
 		return nil, errNoneAccepted
 	} else if startIndex > lastAcceptedIndex {
 		return nil, fmt.Errorf("start index (%d) > last accepted index (%d)", startIndex, lastAcceptedIndex)
+	} else if startIndex < i.firstRetainedIndex {
+		return nil, fmt.Errorf("%w: start index (%d) < oldest retained index (%d)", ErrPruned, startIndex, i.firstRetainedIndex)
 	}
 
 	// Calculate the last index we will fetch
@@ -245,12 +335,19 @@ func (i *index) GetContainerRange(startIndex, numToFetch uint64) ([]Container, e
 	return containers, nil
 }
 
-// Returns database.ErrNotFound if the container is not indexed as accepted
+// Returns database.ErrNotFound if the container is not indexed as accepted.
+// If containers have been pruned from this index, an unknown container may
+// have been pruned, so ErrPruned is returned instead, along with the oldest
+// retained index.
 func (i *index) GetIndex(containerID ids.ID) (uint64, error) {
 	i.lock.RLock()
 	defer i.lock.RUnlock()
 
-	return database.GetUInt64(i.containerToIndex, containerID[:])
+	index, err := database.GetUInt64(i.containerToIndex, containerID[:])
+	if err != nil {
+		return 0, i.notFoundErr(containerID, err)
+	}
+	return index, nil
 }
 
 func (i *index) GetContainerByID(containerID ids.ID) (Container, error) {
@@ -260,11 +357,30 @@ func (i *index) GetContainerByID(containerID ids.ID) (Container, error) {
 	// Read index from database
 	indexBytes, err := i.containerToIndex.Get(containerID[:])
 	if err != nil {
-		return Container{}, err
+		return Container{}, i.notFoundErr(containerID, err)
 	}
 	return i.getContainerByIndexBytes(indexBytes)
 }
 
+// notFoundErr returns the error to report when reading the index of
+// [containerID] failed with [err].
+// Assumes [i.lock] is held
+func (i *index) notFoundErr(containerID ids.ID, err error) error {
+	if err != database.ErrNotFound || i.firstRetainedIndex == 0 {
+		return err
+	}
+	return fmt.Errorf("%w: %s isn't retained, the oldest retained index is %d", ErrPruned, containerID, i.firstRetainedIndex)
+}
+
+// GetOldestIndex returns the index of the oldest retained container.
+// Returns 0 if no containers have been pruned.
+func (i *index) GetOldestIndex() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.firstRetainedIndex
+}
+
 // GetLastAccepted returns the last accepted container.
 // Returns an error if no containers have been accepted.
 func (i *index) GetLastAccepted() (Container, error) {
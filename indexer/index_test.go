@@ -4,9 +4,12 @@
 package indexer
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/flare-foundation/flare/codec"
 	"github.com/flare-foundation/flare/codec/linearcodec"
@@ -30,9 +33,8 @@ func TestIndex(t *testing.T) {
 	db := versiondb.New(baseDB)
 	ctx := snow.DefaultConsensusContextTest()
 
-	indexIntf, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{})
+	idx, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{}, RetentionPolicy{})
 	assert.NoError(err)
-	idx := indexIntf.(*index)
 
 	// Populate "containers" with random IDs/bytes
 	containers := map[ids.ID][]byte{}
@@ -84,9 +86,8 @@ func TestIndex(t *testing.T) {
 	assert.NoError(db.Commit())
 	assert.NoError(idx.Close())
 	db = versiondb.New(baseDB)
-	indexIntf, err = newIndex(db, logging.NoLog{}, codec, mockable.Clock{})
+	idx, err = newIndex(db, logging.NoLog{}, codec, mockable.Clock{}, RetentionPolicy{})
 	assert.NoError(err)
-	idx = indexIntf.(*index)
 
 	// Get all of the containers
 	containersList, err := idx.GetContainerRange(0, pageSize)
@@ -119,9 +120,8 @@ func TestIndexGetContainerByRangeMaxPageSize(t *testing.T) {
 	assert.NoError(err)
 	db := memdb.New()
 	ctx := snow.DefaultConsensusContextTest()
-	indexIntf, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{})
+	idx, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{}, RetentionPolicy{})
 	assert.NoError(err)
-	idx := indexIntf.(*index)
 
 	// Insert [MaxFetchedByRange] + 1 containers
 	for i := uint64(0); i < MaxFetchedByRange+1; i++ {
@@ -161,7 +161,7 @@ func TestDontIndexSameContainerTwice(t *testing.T) {
 	assert.NoError(err)
 	db := memdb.New()
 	ctx := snow.DefaultConsensusContextTest()
-	idx, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{})
+	idx, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{}, RetentionPolicy{})
 	assert.NoError(err)
 
 	// Accept the same container twice
@@ -174,3 +174,108 @@ func TestDontIndexSameContainerTwice(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(gotContainer.Bytes, []byte{1, 2, 3}, "should not have accepted same container twice")
 }
+
+func TestIndexRetentionMaxContainers(t *testing.T) {
+	// Setup
+	assert := assert.New(t)
+	require := require.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	require.NoError(err)
+	baseDB := memdb.New()
+	db := versiondb.New(baseDB)
+	ctx := snow.DefaultConsensusContextTest()
+	retention := RetentionPolicy{MaxContainers: 10}
+	idx, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{}, retention)
+	require.NoError(err)
+
+	// Accept more containers than are retained
+	containerIDs := make([]ids.ID, pruneBatchSize+20)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		require.NoError(idx.Accept(ctx, containerIDs[i], utils.RandomBytes(32)))
+	}
+	require.NoError(idx.prune())
+	firstRetainedIndex := uint64(len(containerIDs)) - retention.MaxContainers
+	assert.Equal(firstRetainedIndex, idx.firstRetainedIndex)
+
+	// Pruned containers aren't returned
+	_, err = idx.GetContainerByIndex(0)
+	assert.ErrorIs(err, ErrPruned)
+	_, err = idx.GetContainerRange(firstRetainedIndex-1, 2)
+	assert.ErrorIs(err, ErrPruned)
+	_, err = idx.GetIndex(containerIDs[0])
+	assert.ErrorIs(err, ErrPruned)
+	assert.Contains(err.Error(), fmt.Sprintf("oldest retained index is %d", firstRetainedIndex))
+	_, err = idx.GetContainerByID(containerIDs[firstRetainedIndex-1])
+	assert.ErrorIs(err, ErrPruned)
+
+	// Retained containers are still returned
+	containers, err := idx.GetContainerRange(firstRetainedIndex, MaxFetchedByRange)
+	require.NoError(err)
+	assert.Len(containers, int(retention.MaxContainers))
+	assert.Equal(containerIDs[firstRetainedIndex], containers[0].ID)
+	gotIndex, err := idx.GetIndex(containerIDs[firstRetainedIndex])
+	require.NoError(err)
+	assert.Equal(firstRetainedIndex, gotIndex)
+
+	// The first retained index is persisted
+	require.NoError(db.Commit())
+	require.NoError(idx.Close())
+	db = versiondb.New(baseDB)
+	idx, err = newIndex(db, logging.NoLog{}, codec, mockable.Clock{}, retention)
+	require.NoError(err)
+	assert.Equal(firstRetainedIndex, idx.firstRetainedIndex)
+	_, err = idx.GetContainerByIndex(firstRetainedIndex - 1)
+	assert.ErrorIs(err, ErrPruned)
+	require.NoError(idx.Close())
+
+	// Closing the index again does nothing
+	require.NoError(idx.Close())
+}
+
+func TestIndexRetentionMaxAge(t *testing.T) {
+	// Setup
+	assert := assert.New(t)
+	require := require.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	require.NoError(err)
+	db := memdb.New()
+	ctx := snow.DefaultConsensusContextTest()
+	retention := RetentionPolicy{MaxAge: time.Hour}
+	idx, err := newIndex(db, logging.NoLog{}, codec, mockable.Clock{}, retention)
+	require.NoError(err)
+
+	// Accept a container every 30 minutes
+	now := time.Unix(1_000_000, 0)
+	containerIDs := make([]ids.ID, 5)
+	for i := range containerIDs {
+		idx.lock.Lock()
+		idx.clock.Set(now.Add(time.Duration(i) * 30 * time.Minute))
+		idx.lock.Unlock()
+
+		containerIDs[i] = ids.GenerateTestID()
+		require.NoError(idx.Accept(ctx, containerIDs[i], utils.RandomBytes(32)))
+	}
+	require.NoError(idx.prune())
+
+	// Only the containers of the last hour are retained
+	assert.EqualValues(2, idx.firstRetainedIndex)
+	_, err = idx.GetIndex(containerIDs[1])
+	assert.ErrorIs(err, ErrPruned)
+	gotIndex, err := idx.GetIndex(containerIDs[2])
+	require.NoError(err)
+	assert.EqualValues(2, gotIndex)
+
+	// The last accepted container is retained no matter its age
+	idx.lock.Lock()
+	idx.clock.Set(now.Add(24 * time.Hour))
+	idx.lock.Unlock()
+	require.NoError(idx.prune())
+	assert.EqualValues(4, idx.firstRetainedIndex)
+	container, err := idx.GetLastAccepted()
+	require.NoError(err)
+	assert.Equal(containerIDs[4], container.ID)
+	require.NoError(idx.Close())
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"sync"
 
 	"github.com/gorilla/rpc/v2"
@@ -19,6 +20,7 @@ import (
 	"github.com/flare-foundation/flare/database"
 	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/engine/avalanche"
 	"github.com/flare-foundation/flare/snow/engine/common"
 	"github.com/flare-foundation/flare/snow/engine/snowman"
@@ -49,20 +51,33 @@ var (
 	blockPrefix             = byte(0x03)
 	isIncompletePrefix      = byte(0x04)
 	previouslyIndexedPrefix = byte(0x05)
+	subnetChainsKey         = []byte{0x06}
 	hasRunKey               = []byte{0x07}
+	chainHasRunPrefix       = byte(0x08)
 
 	_ Indexer = &indexer{}
 )
 
 // Config for an indexer
 type Config struct {
-	DB                                      database.Database
-	Log                                     logging.Logger
-	IndexingEnabled                         bool
-	AllowIncompleteIndex                    bool
+	DB                   database.Database
+	Log                  logging.Logger
+	IndexingEnabled      bool
+	AllowIncompleteIndex bool
+	// IDs or aliases of the chains to index. If empty, all chains are indexed.
+	IncludedChains []string
+	// IDs or aliases of the chains not to index
+	ExcludedChains []string
+	// Retention of the indices of the chains that aren't in [ChainRetention]
+	Retention RetentionPolicy
+	// ID or alias of a chain --> retention of the indices of that chain
+	ChainRetention                          map[string]RetentionPolicy
 	DecisionDispatcher, ConsensusDispatcher *triggers.EventDispatcher
 	APIServer                               server.PathAdder
-	ShutdownF                               func()
+	// Returns true if the origin of a websocket handshake with the stream
+	// endpoints is allowed. If nil, only same-origin handshakes are allowed.
+	CheckOrigin func(*http.Request) bool
+	ShutdownF   func()
 }
 
 // Indexer causes accepted containers for a given chain
@@ -83,12 +98,17 @@ func NewIndexer(config Config) (Indexer, error) {
 		db:                   config.DB,
 		allowIncompleteIndex: config.AllowIncompleteIndex,
 		indexingEnabled:      config.IndexingEnabled,
+		includedChains:       stringSet(config.IncludedChains),
+		excludedChains:       stringSet(config.ExcludedChains),
+		retention:            config.Retention,
+		chainRetention:       config.ChainRetention,
 		consensusDispatcher:  config.ConsensusDispatcher,
 		decisionDispatcher:   config.DecisionDispatcher,
 		txIndices:            map[ids.ID]Index{},
 		vtxIndices:           map[ids.ID]Index{},
 		blockIndices:         map[ids.ID]Index{},
 		pathAdder:            config.APIServer,
+		checkOrigin:          config.CheckOrigin,
 		shutdownF:            config.ShutdownF,
 	}
 	if err := indexer.codec.RegisterCodec(
@@ -102,6 +122,10 @@ func NewIndexer(config Config) (Indexer, error) {
 		return nil, err
 	}
 	indexer.hasRunBefore = hasRun
+	indexer.registeredSubnetChainsBefore, err = indexer.db.Has(subnetChainsKey)
+	if err != nil {
+		return nil, err
+	}
 	return indexer, indexer.markHasRun()
 }
 
@@ -118,9 +142,14 @@ type indexer struct {
 
 	// true if this is not the first run using this database
 	hasRunBefore bool
+	// true if a previous run registered a chain that isn't in the primary
+	// network. Earlier runs ignored these chains.
+	registeredSubnetChainsBefore bool
 
 	// Used to add API endpoint for new indices
 	pathAdder server.PathAdder
+	// Checks the origin of the websocket handshakes of the stream endpoints
+	checkOrigin func(*http.Request) bool
 
 	// If true, allow running in such a way that could allow the creation
 	// of an index which could be missing accepted containers.
@@ -128,6 +157,14 @@ type indexer struct {
 
 	// If false, don't create index for a chain when RegisterChain is called
 	indexingEnabled bool
+	// IDs or aliases of the chains to index. If empty, all chains are indexed.
+	includedChains map[string]struct{}
+	// IDs or aliases of the chains not to index
+	excludedChains map[string]struct{}
+	// Retention of the indices of the chains that aren't in [chainRetention]
+	retention RetentionPolicy
+	// ID or alias of a chain --> retention of the indices of that chain
+	chainRetention map[string]RetentionPolicy
 
 	// Chain ID --> index of blocks of that chain (if applicable)
 	blockIndices map[ids.ID]Index
@@ -151,9 +188,6 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 	if i.closed {
 		i.log.Debug("not registering chain %s because indexer is closed", name)
 		return
-	} else if ctx.SubnetID != constants.PrimaryNetworkID {
-		i.log.Debug("not registering chain %s because it's not in primary network", name)
-		return
 	}
 
 	chainID := ctx.ChainID
@@ -182,7 +216,31 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 		return
 	}
 
-	if !i.indexingEnabled { // Indexing is disabled
+	// See if this chain was registered in a previous run
+	hasRunBefore, err := i.chainHasRunBefore(ctx)
+	if err != nil {
+		i.log.Error("couldn't get whether chain %s has run before: %s", name, err)
+		if err := i.close(); err != nil {
+			i.log.Error("error while closing indexer: %s", err)
+		}
+		return
+	}
+
+	// Chains that aren't in the primary network were ignored by earlier runs,
+	// so they may have accepted containers without being marked incomplete.
+	if ctx.SubnetID != constants.PrimaryNetworkID && i.hasRunBefore && !i.registeredSubnetChainsBefore && !previouslyIndexed {
+		if err := i.markIncomplete(chainID); err != nil {
+			i.log.Fatal("couldn't mark chain %s as incomplete: %s", name, err)
+			if err := i.close(); err != nil {
+				i.log.Error("error while closing indexer: %s", err)
+			}
+			return
+		}
+		isIncomplete = true
+		hasRunBefore = true
+	}
+
+	if !i.shouldIndex(ctx) { // Indexing is disabled for this chain
 		if previouslyIndexed && !i.allowIncompleteIndex {
 			// We indexed this chain in a previous run but not in this run.
 			// This would create an incomplete index, which is not allowed, so exit.
@@ -194,18 +252,23 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 		}
 
 		// Creating an incomplete index is allowed. Mark index as incomplete.
-		err := i.markIncomplete(chainID)
-		if err == nil {
+		if err := i.markIncomplete(chainID); err != nil {
+			i.log.Fatal("couldn't mark chain %s as incomplete: %s", name, err)
+			if err := i.close(); err != nil {
+				i.log.Error("error while closing indexer: %s", err)
+			}
 			return
 		}
-		i.log.Fatal("couldn't mark chain %s as incomplete: %s", name, err)
-		if err := i.close(); err != nil {
-			i.log.Error("error while closing indexer: %s", err)
+		if err := i.markChainHasRun(ctx); err != nil {
+			i.log.Fatal("couldn't mark chain %s as registered: %s", name, err)
+			if err := i.close(); err != nil {
+				i.log.Error("error while closing indexer: %s", err)
+			}
 		}
 		return
 	}
 
-	if !i.allowIncompleteIndex && isIncomplete && (previouslyIndexed || i.hasRunBefore) {
+	if !i.allowIncompleteIndex && isIncomplete && (previouslyIndexed || hasRunBefore) {
 		i.log.Fatal("index %s is incomplete but incomplete indices are disabled. Shutting down", name)
 		if err := i.close(); err != nil {
 			i.log.Error("error while closing indexer: %s", err)
@@ -222,9 +285,10 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 		return
 	}
 
+	retention := i.retentionOf(ctx)
 	switch engine.(type) {
 	case snowman.Engine:
-		index, err := i.registerChainHelper(chainID, blockPrefix, name, "block", i.consensusDispatcher)
+		index, err := i.registerChainHelper(chainID, blockPrefix, name, "block", i.consensusDispatcher, retention)
 		if err != nil {
 			i.log.Fatal("couldn't create block index for %s: %s", name, err)
 			if err := i.close(); err != nil {
@@ -234,7 +298,7 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 		}
 		i.blockIndices[chainID] = index
 	case avalanche.Engine:
-		vtxIndex, err := i.registerChainHelper(chainID, vtxPrefix, name, "vtx", i.consensusDispatcher)
+		vtxIndex, err := i.registerChainHelper(chainID, vtxPrefix, name, "vtx", i.consensusDispatcher, retention)
 		if err != nil {
 			i.log.Fatal("couldn't create vertex index for %s: %s", name, err)
 			if err := i.close(); err != nil {
@@ -244,7 +308,7 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 		}
 		i.vtxIndices[chainID] = vtxIndex
 
-		txIndex, err := i.registerChainHelper(chainID, txPrefix, name, "tx", i.decisionDispatcher)
+		txIndex, err := i.registerChainHelper(chainID, txPrefix, name, "tx", i.decisionDispatcher, retention)
 		if err != nil {
 			i.log.Fatal("couldn't create tx index for %s: %s", name, err)
 			if err := i.close(); err != nil {
@@ -260,6 +324,13 @@ func (i *indexer) RegisterChain(name string, engine common.Engine) {
 		}
 		return
 	}
+
+	if err := i.markChainHasRun(ctx); err != nil {
+		i.log.Fatal("couldn't mark chain %s as registered: %s", name, err)
+		if err := i.close(); err != nil {
+			i.log.Error("error while closing indexer: %s", err)
+		}
+	}
 }
 
 func (i *indexer) registerChainHelper(
@@ -267,12 +338,10 @@ func (i *indexer) registerChainHelper(
 	prefixEnd byte,
 	name, endpoint string,
 	dispatcher *triggers.EventDispatcher,
+	retention RetentionPolicy,
 ) (Index, error) {
-	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
-	copy(prefix, chainID[:])
-	prefix[hashing.HashLen] = prefixEnd
-	indexDB := prefixdb.New(prefix, i.db)
-	index, err := newIndex(indexDB, i.log, i.codec, i.clock)
+	indexDB := prefixdb.New(indexPrefix(chainID, prefixEnd), i.db)
+	index, err := newIndex(indexDB, i.log, i.codec, i.clock, retention)
 	if err != nil {
 		_ = indexDB.Close()
 		return nil, err
@@ -298,6 +367,16 @@ func (i *indexer) registerChainHelper(
 		_ = index.Close()
 		return nil, err
 	}
+
+	// Create an endpoint that streams newly accepted containers
+	streamHandler := &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     newStreamHandler(index, i.log, i.checkOrigin),
+	}
+	if err := i.pathAdder.AddRoute(streamHandler, &sync.RWMutex{}, "index/"+name, "/"+endpoint+"/events", i.log); err != nil {
+		_ = index.Close()
+		return nil, err
+	}
 	return index, nil
 }
 
@@ -343,6 +422,51 @@ func (i *indexer) close() error {
 	return errs.Err
 }
 
+// shouldIndex returns true if the chain of [ctx] should be indexed
+func (i *indexer) shouldIndex(ctx *snow.ConsensusContext) bool {
+	if !i.indexingEnabled {
+		return false
+	}
+
+	included := len(i.includedChains) == 0
+	for _, name := range chainNames(ctx) {
+		if _, ok := i.excludedChains[name]; ok {
+			return false
+		}
+		if _, ok := i.includedChains[name]; ok {
+			included = true
+		}
+	}
+	return included
+}
+
+// retentionOf returns the retention of the indices of the chain of [ctx]
+func (i *indexer) retentionOf(ctx *snow.ConsensusContext) RetentionPolicy {
+	for _, name := range chainNames(ctx) {
+		if retention, ok := i.chainRetention[name]; ok {
+			return retention
+		}
+	}
+	return i.retention
+}
+
+// chainNames returns the ID and the aliases of the chain of [ctx]
+func chainNames(ctx *snow.ConsensusContext) []string {
+	names := []string{ctx.ChainID.String()}
+	if aliases, err := ctx.BCLookup.Aliases(ctx.ChainID); err == nil {
+		names = append(names, aliases...)
+	}
+	return names
+}
+
+func stringSet(strs []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(strs))
+	for _, str := range strs {
+		set[str] = struct{}{}
+	}
+	return set
+}
+
 func (i *indexer) markIncomplete(chainID ids.ID) error {
 	key := make([]byte, hashing.HashLen+wrappers.ByteLen)
 	copy(key, chainID[:])
@@ -373,6 +497,41 @@ func (i *indexer) previouslyIndexed(chainID ids.ID) (bool, error) {
 	return i.db.Has(key)
 }
 
+// Mark that the chain of [ctx] was registered in this run. Registering a chain
+// that isn't in the primary network also marks that subnet chains are
+// registered.
+func (i *indexer) markChainHasRun(ctx *snow.ConsensusContext) error {
+	key := make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(key, ctx.ChainID[:])
+	key[hashing.HashLen] = chainHasRunPrefix
+	if err := i.db.Put(key, nil); err != nil {
+		return err
+	}
+	if ctx.SubnetID == constants.PrimaryNetworkID {
+		return nil
+	}
+	return i.db.Put(subnetChainsKey, nil)
+}
+
+// Returns true if the chain of [ctx] was registered in a previous run
+func (i *indexer) chainHasRunBefore(ctx *snow.ConsensusContext) (bool, error) {
+	key := make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(key, ctx.ChainID[:])
+	key[hashing.HashLen] = chainHasRunPrefix
+	hasRun, err := i.db.Has(key)
+	if err != nil || hasRun {
+		return hasRun, err
+	}
+
+	// Runs before the chains were marked individually registered all the
+	// chains of the primary network. The chains of other subnets were only
+	// registered once [subnetChainsKey] was written.
+	if ctx.SubnetID == constants.PrimaryNetworkID {
+		return i.hasRunBefore, nil
+	}
+	return i.hasRunBefore && i.registeredSubnetChainsBefore, nil
+}
+
 // Mark that the node has run at least once
 func (i *indexer) markHasRun() error {
 	return i.db.Put(hasRunKey, nil)
@@ -14,6 +14,7 @@ import (
 
 	"github.com/flare-foundation/flare/api/server"
 	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/database/versiondb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/snow"
@@ -174,9 +175,10 @@ func TestIndexer(t *testing.T) {
 	assert.NoError(err)
 	assert.True(previouslyIndexed)
 	server := config.APIServer.(*apiServerMock)
-	assert.EqualValues(1, server.timesCalled)
+	assert.EqualValues(2, server.timesCalled) // block index and its stream
 	assert.EqualValues("index/chain1", server.bases[0])
 	assert.EqualValues("/block", server.endpoints[0])
+	assert.EqualValues("/block/events", server.endpoints[1])
 	assert.Len(idxr.blockIndices, 1)
 	assert.Len(idxr.txIndices, 0)
 	assert.Len(idxr.vtxIndices, 0)
@@ -284,10 +286,12 @@ func TestIndexer(t *testing.T) {
 	idxr.RegisterChain("chain2", dagEngine)
 	assert.NoError(err)
 	server = config.APIServer.(*apiServerMock)
-	assert.EqualValues(3, server.timesCalled) // block index, vtx index, tx index
+	assert.EqualValues(6, server.timesCalled) // block index, vtx index, tx index and their streams
 	assert.Contains(server.bases, "index/chain2")
 	assert.Contains(server.endpoints, "/vtx")
 	assert.Contains(server.endpoints, "/tx")
+	assert.Contains(server.endpoints, "/vtx/events")
+	assert.Contains(server.endpoints, "/tx/events")
 	assert.Len(idxr.blockIndices, 1)
 	assert.Len(idxr.txIndices, 1)
 	assert.Len(idxr.vtxIndices, 1)
@@ -508,8 +512,8 @@ func TestIncompleteIndex(t *testing.T) {
 	assert.True(ok)
 }
 
-// Ensure we only index chains in the primary network
-func TestIgnoreNonDefaultChains(t *testing.T) {
+// Ensure chains that aren't in the primary network are indexed
+func TestIndexSubnetChains(t *testing.T) {
 	assert := assert.New(t)
 	baseDB := memdb.New()
 	db := versiondb.New(baseDB)
@@ -535,11 +539,169 @@ func TestIgnoreNonDefaultChains(t *testing.T) {
 	chain1Ctx.ChainID = ids.GenerateTestID()
 	chain1Ctx.SubnetID = ids.GenerateTestID()
 
-	// RegisterChain should return without adding an index for this chain
+	// RegisterChain should add an index for this chain
 	chainVM := &smblockmocks.ChainVM{}
 	chainEngine := &smengmocks.Engine{}
 	chainEngine.On("Context").Return(chain1Ctx)
 	chainEngine.On("GetVM").Return(chainVM)
 	idxr.RegisterChain("chain1", chainEngine)
-	assert.Len(idxr.blockIndices, 0)
+	assert.False(idxr.closed)
+	assert.Len(idxr.blockIndices, 1)
+	isIncomplete, err := idxr.isIncomplete(chain1Ctx.ChainID)
+	assert.NoError(err)
+	assert.False(isIncomplete)
+}
+
+// Ensure chains of other subnets that earlier runs ignored are incomplete, and
+// that the chains registered in earlier runs are tracked per chain
+func TestSubnetChainsAfterUpgrade(t *testing.T) {
+	assert := assert.New(t)
+	baseDB := memdb.New()
+	indexerDBPrefix := []byte{0x00}
+	// Simulate a run that didn't register the chains of other subnets
+	assert.NoError(prefixdb.New(indexerDBPrefix, baseDB).Put(hasRunKey, nil))
+	config := Config{
+		IndexingEnabled:      true,
+		AllowIncompleteIndex: false,
+		Log:                  logging.NoLog{},
+		DB:                   prefixdb.New(indexerDBPrefix, baseDB),
+		ConsensusDispatcher:  triggers.New(logging.NoLog{}),
+		DecisionDispatcher:   triggers.New(logging.NoLog{}),
+		APIServer:            &apiServerMock{},
+		ShutdownF:            func() {},
+	}
+	idxrIntf, err := NewIndexer(config)
+	assert.NoError(err)
+	idxr := idxrIntf.(*indexer)
+	assert.False(idxr.registeredSubnetChainsBefore)
+
+	// Restarting before a subnet chain is registered doesn't mark that subnet
+	// chains were registered
+	idxrIntf, err = NewIndexer(config)
+	assert.NoError(err)
+	idxr = idxrIntf.(*indexer)
+	assert.False(idxr.registeredSubnetChainsBefore)
+
+	primaryCtx := snow.DefaultConsensusContextTest()
+	primaryCtx.ChainID = ids.GenerateTestID()
+	primaryEngine := &smengmocks.Engine{}
+	primaryEngine.On("Context").Return(primaryCtx)
+	primaryEngine.On("GetVM").Return(&smblockmocks.ChainVM{})
+	idxr.RegisterChain("primary", primaryEngine)
+	assert.False(idxr.closed)
+	assert.Len(idxr.blockIndices, 1)
+	registeredSubnetChains, err := idxr.db.Has(subnetChainsKey)
+	assert.NoError(err)
+	assert.False(registeredSubnetChains)
+
+	// The subnet chain may have accepted containers in an earlier run, so
+	// indexing it would create an incomplete index
+	subnetCtx := snow.DefaultConsensusContextTest()
+	subnetCtx.ChainID = ids.GenerateTestID()
+	subnetCtx.SubnetID = ids.GenerateTestID()
+	subnetEngine := &smengmocks.Engine{}
+	subnetEngine.On("Context").Return(subnetCtx)
+	subnetEngine.On("GetVM").Return(&smblockmocks.ChainVM{})
+	idxr.RegisterChain("subnet", subnetEngine)
+	assert.True(idxr.closed)
+
+	// The subnet chain is indexed if incomplete indices are allowed
+	config.DB = prefixdb.New(indexerDBPrefix, baseDB)
+	config.AllowIncompleteIndex = true
+	idxrIntf, err = NewIndexer(config)
+	assert.NoError(err)
+	idxr = idxrIntf.(*indexer)
+	assert.False(idxr.registeredSubnetChainsBefore)
+	isIncomplete, err := idxr.isIncomplete(subnetCtx.ChainID)
+	assert.NoError(err)
+	assert.True(isIncomplete)
+	idxr.RegisterChain("subnet", subnetEngine)
+	assert.False(idxr.closed)
+	assert.Len(idxr.blockIndices, 1)
+	isIncomplete, err = idxr.isIncomplete(subnetCtx.ChainID)
+	assert.NoError(err)
+	assert.True(isIncomplete)
+	registeredSubnetChains, err = idxr.db.Has(subnetChainsKey)
+	assert.NoError(err)
+	assert.True(registeredSubnetChains)
+
+	// Later runs know which chains were registered
+	assert.NoError(idxr.Close())
+	config.DB = prefixdb.New(indexerDBPrefix, baseDB)
+	config.AllowIncompleteIndex = false
+	idxrIntf, err = NewIndexer(config)
+	assert.NoError(err)
+	idxr = idxrIntf.(*indexer)
+	assert.True(idxr.registeredSubnetChainsBefore)
+	hasRunBefore, err := idxr.chainHasRunBefore(subnetCtx)
+	assert.NoError(err)
+	assert.True(hasRunBefore)
+	hasRunBefore, err = idxr.chainHasRunBefore(primaryCtx)
+	assert.NoError(err)
+	assert.True(hasRunBefore)
+
+	// A new subnet chain is complete
+	newSubnetCtx := snow.DefaultConsensusContextTest()
+	newSubnetCtx.ChainID = ids.GenerateTestID()
+	newSubnetCtx.SubnetID = subnetCtx.SubnetID
+	newSubnetEngine := &smengmocks.Engine{}
+	newSubnetEngine.On("Context").Return(newSubnetCtx)
+	newSubnetEngine.On("GetVM").Return(&smblockmocks.ChainVM{})
+	idxr.RegisterChain("new subnet", newSubnetEngine)
+	assert.False(idxr.closed)
+	assert.Len(idxr.blockIndices, 1)
+	isIncomplete, err = idxr.isIncomplete(newSubnetCtx.ChainID)
+	assert.NoError(err)
+	assert.False(isIncomplete)
+}
+
+// Ensure only the included chains that aren't excluded are indexed, and that
+// only the chains that aren't indexed are marked incomplete
+func TestIncludedAndExcludedChains(t *testing.T) {
+	assert := assert.New(t)
+	aliaser := ids.NewAliaser()
+	included := ids.GenerateTestID()
+	excluded := ids.GenerateTestID()
+	other := ids.GenerateTestID()
+	assert.NoError(aliaser.Alias(included, "C"))
+	assert.NoError(aliaser.Alias(excluded, "X"))
+
+	config := Config{
+		IndexingEnabled:      true,
+		AllowIncompleteIndex: true,
+		IncludedChains:       []string{"C", excluded.String()},
+		ExcludedChains:       []string{"X"},
+		Log:                  logging.NoLog{},
+		DB:                   versiondb.New(memdb.New()),
+		ConsensusDispatcher:  triggers.New(logging.NoLog{}),
+		DecisionDispatcher:   triggers.New(logging.NoLog{}),
+		APIServer:            &apiServerMock{},
+		ShutdownF:            func() {},
+	}
+	idxrIntf, err := NewIndexer(config)
+	assert.NoError(err)
+	idxr := idxrIntf.(*indexer)
+
+	for _, chainID := range []ids.ID{included, excluded, other} {
+		ctx := snow.DefaultConsensusContextTest()
+		ctx.ChainID = chainID
+		ctx.BCLookup = aliaser
+		engine := &smengmocks.Engine{}
+		engine.On("Context").Return(ctx)
+		engine.On("GetVM").Return(&smblockmocks.ChainVM{})
+		idxr.RegisterChain(chainID.String(), engine)
+	}
+	assert.False(idxr.closed)
+	assert.Len(idxr.blockIndices, 1)
+	assert.Contains(idxr.blockIndices, included)
+
+	for chainID, expectedIncomplete := range map[ids.ID]bool{
+		included: false,
+		excluded: true,
+		other:    true,
+	} {
+		isIncomplete, err := idxr.isIncomplete(chainID)
+		assert.NoError(err)
+		assert.Equal(expectedIncomplete, isIncomplete)
+	}
 }
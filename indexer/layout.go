@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/hashing"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+// AddLayout adds the prefixes of the indices of chain [chainID] to [layout],
+// where [db] is the prefix of the indexer's database. [dag] is true if the
+// chain runs an avalanche.DAGVM.
+func AddLayout(layout *inspect.Layout, db inspect.Prefix, chain string, chainID ids.ID, dag bool) {
+	if !dag {
+		addIndexLayout(layout, db, chain, chainID, blockPrefix, "block")
+		return
+	}
+	addIndexLayout(layout, db, chain, chainID, vtxPrefix, "vtx")
+	addIndexLayout(layout, db, chain, chainID, txPrefix, "tx")
+}
+
+func addIndexLayout(layout *inspect.Layout, db inspect.Prefix, chain string, chainID ids.ID, prefixEnd byte, endpoint string) {
+	// [newIndex] wraps its database in a versiondb
+	indexDB := db.New(indexPrefix(chainID, prefixEnd)).Wrap()
+	name := "indexer/" + endpoint
+	layout.Add(chain, name, indexDB)
+	layout.Add(chain, name+"/containers", indexDB.New(indexToContainerPrefix))
+	layout.Add(chain, name+"/container indices", indexDB.New(containerToIDPrefix))
+}
+
+// indexPrefix returns the prefix of the database of an index of chain
+// [chainID]
+func indexPrefix(chainID ids.ID, prefixEnd byte) []byte {
+	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(prefix, chainID[:])
+	prefix[hashing.HashLen] = prefixEnd
+	return prefix
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flare-foundation/flare/database"
+)
+
+const (
+	// Maximum number of containers deleted while holding the index's lock
+	pruneBatchSize = 1024
+	// How often an index checks for containers that got too old, in
+	// addition to after each accepted container
+	pruneFrequency = time.Minute
+)
+
+// RetentionPolicy limits the containers kept by an index. Containers that
+// fall outside of the policy are deleted, oldest first. The last accepted
+// container is always kept.
+type RetentionPolicy struct {
+	// If non-zero, only the last [MaxContainers] accepted containers are kept
+	MaxContainers uint64 `json:"maxContainers"`
+	// If non-zero, only the containers accepted in the last [MaxAge] are kept
+	MaxAge time.Duration `json:"maxAge"`
+}
+
+// Enabled returns true if the policy limits the containers that are kept
+func (r RetentionPolicy) Enabled() bool {
+	return r.MaxContainers != 0 || r.MaxAge != 0
+}
+
+// runPruner deletes the containers that aren't retained until the index is
+// closed.
+func (i *index) runPruner() {
+	defer close(i.prunerDone)
+
+	ticker := time.NewTicker(pruneFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-i.pruneSignal:
+		case <-ticker.C:
+		case <-i.closing:
+			return
+		}
+		if err := i.prune(); err != nil {
+			i.log.Error("couldn't prune index: %s", err)
+		}
+	}
+}
+
+// prune deletes the containers that aren't retained, in batches of at most
+// [pruneBatchSize] containers. Stops early if the index is closing.
+func (i *index) prune() error {
+	for {
+		select {
+		case <-i.closing:
+			return nil
+		default:
+		}
+
+		done, err := i.pruneBatch()
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// pruneBatch deletes up to [pruneBatchSize] of the oldest containers that
+// aren't retained. Returns true if there is nothing left to delete.
+func (i *index) pruneBatch() (bool, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	lastAcceptedIndex, ok := i.lastAcceptedIndex()
+	if !ok {
+		return true, nil
+	}
+
+	// Containers before [minRetained] exceed the maximum number of containers
+	minRetained := uint64(0)
+	if maxContainers := i.retention.MaxContainers; maxContainers != 0 && i.nextAcceptedIndex > maxContainers {
+		minRetained = i.nextAcceptedIndex - maxContainers
+	}
+	minTimestamp := i.clock.Time().Add(-i.retention.MaxAge).UnixNano()
+
+	firstRetainedIndex := i.firstRetainedIndex
+	for firstRetainedIndex < lastAcceptedIndex && firstRetainedIndex-i.firstRetainedIndex < pruneBatchSize {
+		indexBytes := database.PackUInt64(firstRetainedIndex)
+		container, err := i.getContainerByIndexBytes(indexBytes)
+		if err != nil {
+			return false, fmt.Errorf("couldn't get container at index %d: %w", firstRetainedIndex, err)
+		}
+		tooOld := i.retention.MaxAge != 0 && container.Timestamp < minTimestamp
+		if firstRetainedIndex >= minRetained && !tooOld {
+			break
+		}
+
+		if err := i.indexToContainer.Delete(indexBytes); err != nil {
+			return false, fmt.Errorf("couldn't delete container at index %d: %w", firstRetainedIndex, err)
+		}
+		if err := i.containerToIndex.Delete(container.ID[:]); err != nil {
+			return false, fmt.Errorf("couldn't delete index of container %s: %w", container.ID, err)
+		}
+		firstRetainedIndex++
+	}
+	numPruned := firstRetainedIndex - i.firstRetainedIndex
+	if numPruned == 0 {
+		return true, nil
+	}
+
+	if err := database.PutUInt64(i.vDB, firstRetainedIndexKey, firstRetainedIndex); err != nil {
+		return false, fmt.Errorf("couldn't put first retained index: %w", err)
+	}
+	// Atomically commit the deletions and the new first retained index
+	if err := i.vDB.Commit(); err != nil {
+		return false, err
+	}
+	i.log.Debug("pruned containers %d through %d", i.firstRetainedIndex, firstRetainedIndex-1)
+	i.firstRetainedIndex = firstRetainedIndex
+	return numPruned < pruneBatchSize, nil
+}
@@ -126,6 +126,17 @@ func (s *service) GetIndex(r *http.Request, args *GetIndexArgs, reply *GetIndexR
 	return err
 }
 
+type GetOldestIndexResponse struct {
+	OldestIndex json.Uint64 `json:"oldestIndex"`
+}
+
+// GetOldestIndex returns the index of the oldest container that wasn't pruned.
+// Containers before it are no longer returned by this index.
+func (s *service) GetOldestIndex(_ *http.Request, _ *struct{}, reply *GetOldestIndexResponse) error {
+	reply.OldestIndex = json.Uint64(s.Index.GetOldestIndex())
+	return nil
+}
+
 type IsAcceptedResponse struct {
 	IsAccepted bool `json:"isAccepted"`
 }
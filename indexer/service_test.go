@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/codec"
+	"github.com/flare-foundation/flare/codec/linearcodec"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/formatting"
+	"github.com/flare-foundation/flare/utils/json"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+)
+
+func TestServiceGetOldestIndex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	codec := codec.NewDefaultManager()
+	err := codec.RegisterCodec(codecVersion, linearcodec.NewDefault())
+	require.NoError(err)
+	ctx := snow.DefaultConsensusContextTest()
+	retention := RetentionPolicy{MaxContainers: 10}
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, mockable.Clock{}, retention)
+	require.NoError(err)
+	s := &service{Index: idx}
+
+	// Nothing has been pruned yet
+	reply := GetOldestIndexResponse{}
+	require.NoError(s.GetOldestIndex(nil, &struct{}{}, &reply))
+	assert.EqualValues(0, reply.OldestIndex)
+
+	// Accept more containers than are retained and prune them
+	containerIDs := make([]ids.ID, pruneBatchSize+20)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		require.NoError(idx.Accept(ctx, containerIDs[i], utils.RandomBytes(32)))
+	}
+	require.NoError(idx.prune())
+	firstRetainedIndex := uint64(len(containerIDs)) - retention.MaxContainers
+
+	require.NoError(s.GetOldestIndex(nil, &struct{}{}, &reply))
+	assert.EqualValues(firstRetainedIndex, reply.OldestIndex)
+
+	// The oldest index can be used to fetch the retained containers
+	rangeReply := GetContainerRangeResponse{}
+	err = s.GetContainerRange(nil, &GetContainerRangeArgs{
+		StartIndex: reply.OldestIndex,
+		NumToFetch: json.Uint64(MaxFetchedByRange),
+		Encoding:   formatting.Hex,
+	}, &rangeReply)
+	require.NoError(err)
+	require.Len(rangeReply.Containers, int(retention.MaxContainers))
+	assert.Equal(containerIDs[firstRetainedIndex], rangeReply.Containers[0].ID)
+	assert.Equal(reply.OldestIndex, rangeReply.Containers[0].Index)
+
+	// Pruned containers are reported as pruned
+	indexReply := GetIndexResponse{}
+	err = s.GetIndex(nil, &GetIndexArgs{ContainerID: containerIDs[0]}, &indexReply)
+	assert.ErrorIs(err, ErrPruned)
+
+	require.NoError(idx.Close())
+}
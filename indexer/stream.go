@@ -0,0 +1,236 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/flare-foundation/flare/utils/formatting"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/units"
+)
+
+const (
+	// Query parameters of a stream request
+	startIndexParam = "startIndex"
+	encodingParam   = "encoding"
+
+	// Time allowed to write a message to the client
+	streamWriteWait = 10 * time.Second
+	// Time allowed to read the next pong message from the client
+	streamPongWait = 60 * time.Second
+	// Send pings to the client with this period. Must be less than
+	// [streamPongWait].
+	streamPingPeriod = (streamPongWait * 9) / 10
+	// Maximum size of a message read from the client. Clients aren't expected
+	// to send anything but control messages.
+	streamMaxMessageSize = units.KiB
+)
+
+var errStartIndexTooHigh = errors.New("start index is after the next accepted index")
+
+type streamError struct {
+	Error string `json:"error"`
+}
+
+// streamHandler streams the containers accepted by an index over websocket
+// connections. A client may request the containers starting at
+// [startIndexParam], in which case it first receives the containers accepted
+// before it connected. Otherwise, it only receives containers accepted after
+// it connected. Containers are sent in order as FormattedContainers, so a
+// client that reconnects can resume after the index of the last container it
+// received.
+type streamHandler struct {
+	index    *index
+	log      logging.Logger
+	upgrader websocket.Upgrader
+}
+
+// newStreamHandler returns a handler that streams the containers accepted by
+// [index]. [checkOrigin] returns true if the origin of a websocket handshake
+// is allowed. If nil, only same-origin handshakes are allowed.
+func newStreamHandler(index *index, log logging.Logger, checkOrigin func(*http.Request) bool) *streamHandler {
+	return &streamHandler{
+		index: index,
+		log:   log,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  units.KiB,
+			WriteBufferSize: units.KiB,
+			CheckOrigin:     checkOrigin,
+		},
+	}
+}
+
+func (s *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var (
+		startIndex    uint64
+		hasStartIndex bool
+		encoding      = formatting.Hex
+	)
+	if startIndexStr := query.Get(startIndexParam); startIndexStr != "" {
+		var err error
+		startIndex, err = strconv.ParseUint(startIndexStr, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("couldn't parse %s: %s", startIndexParam, err), http.StatusBadRequest)
+			return
+		}
+		hasStartIndex = true
+	}
+	if encodingStr := query.Get(encodingParam); encodingStr != "" {
+		if err := encoding.UnmarshalJSON([]byte(strconv.Quote(encodingStr))); err != nil {
+			http.Error(w, fmt.Sprintf("couldn't parse %s: %s", encodingParam, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debug("couldn't upgrade stream connection: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	// Subscribe before reading the next accepted index so that no container is
+	// accepted without a notification
+	accepted, unsubscribe := s.index.subscribe()
+	defer unsubscribe()
+
+	nextIndex := s.index.nextIndex()
+	if hasStartIndex {
+		if startIndex > nextIndex {
+			s.writeError(conn, fmt.Errorf("%w: start index (%d) > next accepted index (%d)", errStartIndexTooHigh, startIndex, nextIndex))
+			return
+		}
+		nextIndex = startIndex
+	}
+
+	closed := make(chan struct{})
+	go s.readPump(conn, closed)
+
+	ticker := time.NewTicker(streamPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		// Send the containers accepted since the last sent container
+		for {
+			containers, err := s.index.containersFrom(nextIndex)
+			if err != nil {
+				s.writeError(conn, err)
+				return
+			}
+			if len(containers) == 0 {
+				break
+			}
+			for _, container := range containers {
+				fc, err := newFormattedContainer(container, nextIndex, encoding)
+				if err != nil {
+					s.writeError(conn, err)
+					return
+				}
+				if err := s.write(conn, fc); err != nil {
+					s.log.Debug("couldn't write to stream connection: %s", err)
+					return
+				}
+				nextIndex++
+			}
+		}
+
+		select {
+		case <-accepted:
+		case <-ticker.C:
+			if err := conn.SetWriteDeadline(time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-s.index.closing:
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "index closed"))
+			return
+		}
+	}
+}
+
+// readPump reads from [conn] until it fails, to handle the control messages of
+// the client, and then closes [closed].
+func (s *streamHandler) readPump(conn *websocket.Conn, closed chan struct{}) {
+	defer close(closed)
+
+	conn.SetReadLimit(streamMaxMessageSize)
+	if err := conn.SetReadDeadline(time.Now().Add(streamPongWait)); err != nil {
+		return
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	})
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				s.log.Debug("unexpected close of stream connection: %s", err)
+			}
+			return
+		}
+	}
+}
+
+func (s *streamHandler) write(conn *websocket.Conn, msg interface{}) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(streamWriteWait)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(msg)
+}
+
+// writeError sends [err] to the client before the connection is closed
+func (s *streamHandler) writeError(conn *websocket.Conn, err error) {
+	if err := s.write(conn, &streamError{Error: err.Error()}); err != nil {
+		s.log.Debug("couldn't write to stream connection: %s", err)
+	}
+}
+
+// nextIndex returns the index of the next accepted container
+func (i *index) nextIndex() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.nextAcceptedIndex
+}
+
+// containersFrom returns up to [MaxFetchedByRange] containers starting at
+// [startIndex], or none if no container has been accepted at [startIndex]
+// yet.
+func (i *index) containersFrom(startIndex uint64) ([]Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if startIndex >= i.nextAcceptedIndex {
+		return nil, nil
+	}
+	if startIndex < i.firstRetainedIndex {
+		return nil, fmt.Errorf("%w: start index (%d) < oldest retained index (%d)", ErrPruned, startIndex, i.firstRetainedIndex)
+	}
+
+	numToFetch := i.nextAcceptedIndex - startIndex
+	if numToFetch > MaxFetchedByRange {
+		numToFetch = MaxFetchedByRange
+	}
+	containers := make([]Container, numToFetch)
+	for j := range containers {
+		container, err := i.getContainerByIndex(startIndex + uint64(j))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get container at index %d: %w", startIndex+uint64(j), err)
+		}
+		containers[j] = container
+	}
+	return containers, nil
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/codec"
+	"github.com/flare-foundation/flare/codec/linearcodec"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/formatting"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+)
+
+func newTestStream(t *testing.T, retention RetentionPolicy) (*index, *httptest.Server) {
+	codec := codec.NewDefaultManager()
+	require.NoError(t, codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, mockable.Clock{}, retention)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(newStreamHandler(idx, logging.NoLog{}, nil))
+	t.Cleanup(func() {
+		server.Close()
+		_ = idx.Close()
+	})
+	return idx, server
+}
+
+func dialTestStream(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+	return conn
+}
+
+func TestStreamResume(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	idx, server := newTestStream(t, RetentionPolicy{})
+	ctx := snow.DefaultConsensusContextTest()
+
+	containerIDs := make([]ids.ID, 5)
+	containerBytes := make([][]byte, 5)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		containerBytes[i] = utils.RandomBytes(32)
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(idx.Accept(ctx, containerIDs[i], containerBytes[i]))
+	}
+
+	// The containers accepted before connecting are sent first
+	conn := dialTestStream(t, server, fmt.Sprintf("startIndex=1&encoding=%s", formatting.CB58))
+	for i := 1; i < 3; i++ {
+		var fc FormattedContainer
+		require.NoError(conn.ReadJSON(&fc))
+		assert.EqualValues(i, fc.Index)
+		assert.Equal(containerIDs[i], fc.ID)
+		assert.Equal(formatting.CB58, fc.Encoding)
+		expectedBytes, err := formatting.EncodeWithChecksum(formatting.CB58, containerBytes[i])
+		require.NoError(err)
+		assert.Equal(expectedBytes, fc.Bytes)
+	}
+
+	// Then the containers accepted while connected
+	for i := 3; i < 5; i++ {
+		require.NoError(idx.Accept(ctx, containerIDs[i], containerBytes[i]))
+	}
+	for i := 3; i < 5; i++ {
+		var fc FormattedContainer
+		require.NoError(conn.ReadJSON(&fc))
+		assert.EqualValues(i, fc.Index)
+		assert.Equal(containerIDs[i], fc.ID)
+	}
+}
+
+func TestStreamLive(t *testing.T) {
+	require := require.New(t)
+	idx, server := newTestStream(t, RetentionPolicy{})
+	ctx := snow.DefaultConsensusContextTest()
+
+	require.NoError(idx.Accept(ctx, ids.GenerateTestID(), utils.RandomBytes(32)))
+
+	// Without a start index, only newly accepted containers are sent
+	conn := dialTestStream(t, server, "")
+	containerID := ids.GenerateTestID()
+	require.NoError(idx.Accept(ctx, containerID, utils.RandomBytes(32)))
+
+	var fc FormattedContainer
+	require.NoError(conn.ReadJSON(&fc))
+	require.EqualValues(1, fc.Index)
+	require.Equal(containerID, fc.ID)
+}
+
+func TestStreamInvalidStartIndex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	idx, server := newTestStream(t, RetentionPolicy{MaxContainers: 1})
+	ctx := snow.DefaultConsensusContextTest()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(idx.Accept(ctx, ids.GenerateTestID(), utils.RandomBytes(32)))
+	}
+	require.NoError(idx.prune())
+
+	// Pruned containers can't be streamed
+	conn := dialTestStream(t, server, "startIndex=0")
+	var msg streamError
+	require.NoError(conn.ReadJSON(&msg))
+	assert.Contains(msg.Error, ErrPruned.Error())
+
+	// Containers that weren't accepted yet can't be skipped
+	conn = dialTestStream(t, server, "startIndex=4")
+	require.NoError(conn.ReadJSON(&msg))
+	assert.Contains(msg.Error, errStartIndexTooHigh.Error())
+}
+
+func TestStreamCheckOrigin(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	codec := codec.NewDefaultManager()
+	require.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, codec, mockable.Clock{}, RetentionPolicy{})
+	require.NoError(err)
+	server := httptest.NewServer(newStreamHandler(idx, logging.NoLog{}, func(r *http.Request) bool {
+		return r.Header.Get("Origin") == "https://allowed.example"
+	}))
+	defer func() {
+		server.Close()
+		_ = idx.Close()
+	}()
+
+	dial := func(origin string) error {
+		url := "ws" + strings.TrimPrefix(server.URL, "http")
+		conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{origin}})
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err
+	}
+	assert.NoError(dial("https://allowed.example"))
+	assert.ErrorIs(dial("https://other.example"), websocket.ErrBadHandshake)
+}
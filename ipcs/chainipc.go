@@ -4,10 +4,16 @@
 package ipcs
 
 import (
+	"crypto/tls"
 	"fmt"
 	"path/filepath"
+	"sync"
 
+	"github.com/flare-foundation/flare/chains"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/ipcs/socket"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
 	"github.com/flare-foundation/flare/snow/triggers"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/wrappers"
@@ -22,10 +28,15 @@ const (
 	ipcDecisionsIdentifier = "decisions"
 )
 
+var _ chains.Registrant = &ChainIPCs{}
+
 type context struct {
 	log       logging.Logger
 	networkID uint32
 	path      string
+	// Serves the event sockets over TLS, nil if disabled
+	tlsServer *socket.TLSServer
+	heights   *blockHeights
 }
 
 // ChainIPCs maintains IPCs for a set of chains
@@ -37,18 +48,39 @@ type ChainIPCs struct {
 }
 
 // NewChainIPCs creates a new *ChainIPCs that writes consensus and decision
-// events to IPC sockets
-func NewChainIPCs(log logging.Logger, path string, networkID uint32, consensusEvents *triggers.EventDispatcher, decisionEvents *triggers.EventDispatcher, defaultChainIDs []ids.ID) (*ChainIPCs, error) {
+// events to IPC sockets. If [tlsAddress] isn't empty, the events are also
+// served over TLS at [tlsAddress], using [tlsConfig].
+func NewChainIPCs(
+	log logging.Logger,
+	path string,
+	networkID uint32,
+	consensusEvents *triggers.EventDispatcher,
+	decisionEvents *triggers.EventDispatcher,
+	defaultChainIDs []ids.ID,
+	tlsAddress string,
+	tlsConfig *tls.Config,
+) (*ChainIPCs, error) {
 	cipcs := &ChainIPCs{
 		context: context{
 			log:       log,
 			networkID: networkID,
 			path:      path,
+			heights: &blockHeights{
+				vms: make(map[ids.ID]block.ChainVM),
+			},
 		},
 		chains:          make(map[ids.ID]*EventSockets),
 		consensusEvents: consensusEvents,
 		decisionEvents:  decisionEvents,
 	}
+	if tlsAddress != "" {
+		tlsServer, err := socket.NewTLSServer(tlsAddress, tlsConfig, log)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't serve IPCs over TLS: %w", err)
+		}
+		cipcs.tlsServer = tlsServer
+		log.Info("serving IPCs over TLS at %s", tlsServer.Addr())
+	}
 	for _, chainID := range defaultChainIDs {
 		if _, err := cipcs.Publish(chainID); err != nil {
 			return nil, err
@@ -86,6 +118,25 @@ func (cipcs *ChainIPCs) Unpublish(chainID ids.ID) (bool, error) {
 	return true, chainIPCs.stop()
 }
 
+// TLSAddress returns the address the IPCs are served at over TLS, or an empty
+// string if they aren't
+func (cipcs *ChainIPCs) TLSAddress() string {
+	if cipcs.tlsServer == nil {
+		return ""
+	}
+	return cipcs.tlsServer.Addr().String()
+}
+
+// RegisterChain allows the events of chain [engine] to report the heights of
+// its blocks
+func (cipcs *ChainIPCs) RegisterChain(_ string, engine common.Engine) {
+	vm, ok := engine.GetVM().(block.ChainVM)
+	if !ok {
+		return
+	}
+	cipcs.heights.register(engine.Context().ChainID, vm)
+}
+
 // GetPublishedBlockchains returns the chains that are currently being published
 func (cipcs *ChainIPCs) GetPublishedBlockchains() []ids.ID {
 	chainIds := make([]ids.ID, 0, len(cipcs.chains))
@@ -104,9 +155,48 @@ func (cipcs *ChainIPCs) Shutdown() error {
 	for _, ch := range cipcs.chains {
 		errs.Add(ch.stop())
 	}
+	if cipcs.tlsServer != nil {
+		errs.Add(cipcs.tlsServer.Close())
+	}
 	return errs.Err
 }
 
 func ipcURL(ctx context, chainID ids.ID, eventType string) string {
-	return filepath.Join(ctx.path, fmt.Sprintf("%d-%s-%s", ctx.networkID, chainID.String(), eventType))
+	return filepath.Join(ctx.path, socketName(ctx, chainID, eventType))
+}
+
+// socketName returns the name of the socket of the [eventType] events of chain
+// [chainID]. It's used to subscribe to the socket over TLS.
+func socketName(ctx context, chainID ids.ID, eventType string) string {
+	return fmt.Sprintf("%d-%s-%s", ctx.networkID, chainID.String(), eventType)
+}
+
+// blockHeights looks up the heights of the blocks of linear chains
+type blockHeights struct {
+	lock sync.RWMutex
+	vms  map[ids.ID]block.ChainVM
+}
+
+func (h *blockHeights) register(chainID ids.ID, vm block.ChainVM) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.vms[chainID] = vm
+}
+
+// height returns the height of block [blkID] of chain [chainID], if it's
+// known. Assumes the context lock of the chain is held.
+func (h *blockHeights) height(chainID ids.ID, blkID ids.ID) (uint64, bool) {
+	h.lock.RLock()
+	vm, ok := h.vms[chainID]
+	h.lock.RUnlock()
+	if !ok {
+		return 0, false
+	}
+
+	blk, err := vm.GetBlock(blkID)
+	if err != nil {
+		return 0, false
+	}
+	return blk.Height(), true
 }
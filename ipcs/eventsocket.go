@@ -6,17 +6,32 @@ package ipcs
 import (
 	"errors"
 	"os"
+	"sync/atomic"
 	"syscall"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/flare-foundation/flare/api/proto/ipcproto"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/ipcs/socket"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/triggers"
 	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
 	"github.com/flare-foundation/flare/utils/wrappers"
 )
 
-var _ snow.Acceptor = &EventSockets{}
+// EnvelopeVersion is the version of the ipcproto.Envelope messages written to
+// the event sockets
+const EnvelopeVersion = 1
+
+var (
+	_ snow.Acceptor          = &EventSockets{}
+	_ snow.Acceptor          = &eventSocket{}
+	_ snow.Rejector          = &eventSocket{}
+	_ snow.Issuer            = &eventSocket{}
+	_ socket.ControlMessages = &eventSocket{}
+)
 
 // EventSockets is a set of named eventSockets
 type EventSockets struct {
@@ -84,12 +99,31 @@ func (ipcs *EventSockets) DecisionsURL() string {
 	return ipcs.decisionsSocket.URL()
 }
 
+// ConsensusName returns the name of the socket receiving consensus events
+func (ipcs *EventSockets) ConsensusName() string {
+	return ipcs.consensusSocket.name
+}
+
+// DecisionsName returns the name of the socket receiving decisions events
+func (ipcs *EventSockets) DecisionsName() string {
+	return ipcs.decisionsSocket.name
+}
+
 // eventSocket is a single IPC socket for a single chain
 type eventSocket struct {
 	url          string
+	name         string
+	stream       string
 	log          logging.Logger
 	socket       *socket.Socket
 	unregisterFn func() error
+
+	networkID uint32
+	chainID   ids.ID
+	heights   *blockHeights
+	clock     mockable.Clock
+	// Sequence number of the last sent event
+	sequence uint64
 }
 
 // newEventIPCSocket creates a *eventSocket for the given chain and
@@ -108,11 +142,16 @@ func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *trigger
 	eis := &eventSocket{
 		log:    ctx.log,
 		url:    url,
-		socket: socket.NewSocket(url, ctx.log),
+		name:   socketName(ctx, chainID, name),
+		stream: name,
 		unregisterFn: func() error {
 			return events.DeregisterChain(chainID, ipcName)
 		},
+		networkID: ctx.networkID,
+		chainID:   chainID,
+		heights:   ctx.heights,
 	}
+	eis.socket = socket.NewSocket(url, ctx.log, eis)
 
 	if err := eis.socket.Listen(); err != nil {
 		if err := eis.socket.Close(); err != nil {
@@ -121,6 +160,15 @@ func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *trigger
 		return nil, err
 	}
 
+	if ctx.tlsServer != nil {
+		ctx.tlsServer.Register(eis.name, eis.socket)
+		unregister := eis.unregisterFn
+		eis.unregisterFn = func() error {
+			ctx.tlsServer.Deregister(eis.name)
+			return unregister()
+		}
+	}
+
 	if err := events.RegisterChain(chainID, ipcName, eis, false); err != nil {
 		if err := eis.stop(); err != nil {
 			return nil, err
@@ -131,12 +179,78 @@ func newEventIPCSocket(ctx context, chainID ids.ID, name string, events *trigger
 	return eis, nil
 }
 
-// Accept delivers a message to the eventSocket
-func (eis *eventSocket) Accept(_ *snow.ConsensusContext, _ ids.ID, container []byte) error {
-	eis.socket.Send(container)
+// Accept delivers an accept event to the eventSocket
+func (eis *eventSocket) Accept(_ *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	return eis.send(ipcproto.EventType_EVENT_TYPE_ACCEPT, containerID, container)
+}
+
+// Reject delivers a reject event to the eventSocket
+func (eis *eventSocket) Reject(_ *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	return eis.send(ipcproto.EventType_EVENT_TYPE_REJECT, containerID, container)
+}
+
+// Issue delivers an issue event to the eventSocket
+func (eis *eventSocket) Issue(_ *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	return eis.send(ipcproto.EventType_EVENT_TYPE_ISSUE, containerID, container)
+}
+
+func (eis *eventSocket) send(eventType ipcproto.EventType, containerID ids.ID, container []byte) error {
+	event := &ipcproto.Event{
+		Type:        eventType,
+		ChainId:     eis.chainID[:],
+		ContainerId: containerID[:],
+		Timestamp:   eis.clock.Time().UnixNano(),
+		Container:   container,
+		Sequence:    atomic.AddUint64(&eis.sequence, 1),
+	}
+	if height, ok := eis.heights.height(eis.chainID, containerID); ok {
+		event.Height = &height
+	}
+	msg, err := marshalEnvelope(&ipcproto.Envelope{
+		Message: &ipcproto.Envelope_Event{Event: event},
+	})
+	if err != nil {
+		return err
+	}
+	eis.socket.Send(msg)
 	return nil
 }
 
+// Subscribed returns the metadata of the events of the eventSocket
+func (eis *eventSocket) Subscribed() []byte {
+	msg, err := marshalEnvelope(&ipcproto.Envelope{
+		Message: &ipcproto.Envelope_Metadata{Metadata: &ipcproto.Metadata{
+			NetworkId: eis.networkID,
+			ChainId:   eis.chainID[:],
+			Stream:    eis.stream,
+		}},
+	})
+	if err != nil {
+		eis.log.Error("couldn't marshal IPC metadata: %s", err)
+		return nil
+	}
+	return msg
+}
+
+// Dropped returns the notification that [numDropped] events were dropped
+func (eis *eventSocket) Dropped(numDropped uint64) []byte {
+	msg, err := marshalEnvelope(&ipcproto.Envelope{
+		Message: &ipcproto.Envelope_Dropped{Dropped: &ipcproto.Dropped{
+			Count: numDropped,
+		}},
+	})
+	if err != nil {
+		eis.log.Error("couldn't marshal IPC drop notification: %s", err)
+		return nil
+	}
+	return msg
+}
+
+func marshalEnvelope(envelope *ipcproto.Envelope) ([]byte, error) {
+	envelope.Version = EnvelopeVersion
+	return proto.Marshal(envelope)
+}
+
 // stop unregisters the event handler and closes the eventSocket
 func (eis *eventSocket) stop() error {
 	eis.log.Info("closing Chain IPC")
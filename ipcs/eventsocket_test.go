@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/flare-foundation/flare/api/proto/ipcproto"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/ipcs/socket"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/triggers"
+	"github.com/flare-foundation/flare/staking"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+func recvEnvelope(t *testing.T, client *socket.Client) *ipcproto.Envelope {
+	msg, err := client.Recv()
+	require.NoError(t, err)
+	envelope := &ipcproto.Envelope{}
+	require.NoError(t, proto.Unmarshal(msg, envelope))
+	require.EqualValues(t, EnvelopeVersion, envelope.Version)
+	return envelope
+}
+
+func TestEventSockets(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	serverCert, err := staking.NewTLSCert()
+	require.NoError(err)
+	clientCert, err := staking.NewTLSCert()
+	require.NoError(err)
+	clientLeaf, err := x509.ParseCertificate(clientCert.Certificate[0])
+	require.NoError(err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientLeaf)
+	consensusEvents := triggers.New(logging.NoLog{})
+	decisionEvents := triggers.New(logging.NoLog{})
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+
+	cipcs, err := NewChainIPCs(
+		logging.NoLog{},
+		t.TempDir(),
+		12345,
+		consensusEvents,
+		decisionEvents,
+		[]ids.ID{ctx.ChainID},
+		"127.0.0.1:0",
+		&tls.Config{
+			Certificates: []tls.Certificate{*serverCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			MinVersion:   tls.VersionTLS13,
+		},
+	)
+	require.NoError(err)
+	defer func() {
+		assert.NoError(cipcs.Shutdown())
+	}()
+	es, err := cipcs.Publish(ctx.ChainID)
+	require.NoError(err)
+
+	consensusClient, err := socket.Dial(es.ConsensusURL())
+	require.NoError(err)
+	defer consensusClient.Close()
+	decisionsClient, err := socket.DialTLS(cipcs.TLSAddress(), es.DecisionsName(), &tls.Config{
+		Certificates:       []tls.Certificate{*clientCert},
+		InsecureSkipVerify: true, // #nosec G402
+		MinVersion:         tls.VersionTLS13,
+	})
+	require.NoError(err)
+	defer decisionsClient.Close()
+
+	// Subscribers first receive the metadata of their stream
+	for stream, client := range map[string]*socket.Client{
+		ipcConsensusIdentifier: consensusClient,
+		ipcDecisionsIdentifier: decisionsClient,
+	} {
+		metadata := recvEnvelope(t, client).GetMetadata()
+		require.NotNil(metadata)
+		assert.EqualValues(12345, metadata.NetworkId)
+		assert.Equal(ctx.ChainID[:], metadata.ChainId)
+		assert.Equal(stream, metadata.Stream)
+	}
+
+	// Then the events of their stream, in order
+	issuedID := ids.GenerateTestID()
+	acceptedID := ids.GenerateTestID()
+	require.NoError(consensusEvents.Issue(ctx, issuedID, []byte{1}))
+	require.NoError(consensusEvents.Accept(ctx, acceptedID, []byte{2}))
+	require.NoError(decisionEvents.Reject(ctx, issuedID, []byte{1}))
+
+	event := recvEnvelope(t, consensusClient).GetEvent()
+	require.NotNil(event)
+	assert.Equal(ipcproto.EventType_EVENT_TYPE_ISSUE, event.Type)
+	assert.Equal(issuedID[:], event.ContainerId)
+	assert.Equal([]byte{1}, event.Container)
+	assert.EqualValues(1, event.Sequence)
+	assert.Nil(event.Height)
+
+	event = recvEnvelope(t, consensusClient).GetEvent()
+	require.NotNil(event)
+	assert.Equal(ipcproto.EventType_EVENT_TYPE_ACCEPT, event.Type)
+	assert.Equal(acceptedID[:], event.ContainerId)
+	assert.Equal(ctx.ChainID[:], event.ChainId)
+	assert.EqualValues(2, event.Sequence)
+
+	event = recvEnvelope(t, decisionsClient).GetEvent()
+	require.NotNil(event)
+	assert.Equal(ipcproto.EventType_EVENT_TYPE_REJECT, event.Type)
+	assert.Equal(issuedID[:], event.ContainerId)
+	assert.EqualValues(1, event.Sequence)
+}
@@ -18,6 +18,16 @@ import (
 	"github.com/flare-foundation/flare/utils/wrappers"
 )
 
+const (
+	// Maximum number of messages buffered for a subscriber. Messages sent
+	// while the buffer of a subscriber is full are dropped for that
+	// subscriber.
+	maxPendingMessages = 1024
+
+	// Length of the prefix of each message
+	lenPrefixLen = 8
+)
+
 var (
 	// ErrMessageTooLarge is returned when reading a message that is larger than
 	// our max size
@@ -26,27 +36,53 @@ var (
 	_ error = errReadTimeout{}
 )
 
+// ControlMessages creates the messages that a socket sends to a subscriber in
+// addition to the messages passed to Send
+type ControlMessages interface {
+	// Subscribed returns the first message sent to a new subscriber, or nil
+	// if none should be sent
+	Subscribed() []byte
+	// Dropped returns the message sent to a subscriber before the next
+	// message after [numDropped] messages couldn't be buffered for it, or nil
+	// if none should be sent
+	Dropped(numDropped uint64) []byte
+}
+
+// SubscriberStats describes a subscriber of a socket
+type SubscriberStats struct {
+	RemoteAddr string
+	// Number of messages buffered for the subscriber
+	Pending int
+	// Number of messages written to the subscriber
+	Sent uint64
+	// Number of messages that were dropped because the buffer of the
+	// subscriber was full
+	Dropped uint64
+}
+
 // Socket manages sending messages over a socket to many subscribed clients
 type Socket struct {
 	log      logging.Logger
 	addr     string
 	accept   acceptFn
+	control  ControlMessages
 	connLock *sync.RWMutex
-	conns    map[net.Conn]struct{}
+	conns    map[net.Conn]*subscriber
 	quitCh   chan struct{}
 	doneCh   chan struct{}
 	listener net.Listener // the current listener
 }
 
 // NewSocket creates a new socket object for the given address. It does not open
-// the socket until Listen is called.
-func NewSocket(addr string, log logging.Logger) *Socket {
+// the socket until Listen is called. [control] may be nil.
+func NewSocket(addr string, log logging.Logger, control ControlMessages) *Socket {
 	return &Socket{
 		log:      log,
 		addr:     addr,
 		accept:   accept,
+		control:  control,
 		connLock: &sync.RWMutex{},
-		conns:    map[net.Conn]struct{}{},
+		conns:    map[net.Conn]*subscriber{},
 		quitCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
 	}
@@ -76,38 +112,58 @@ func (s *Socket) Listen() error {
 	return nil
 }
 
-// Send writes the given message to all connection clients
+// Send buffers the given message for all connected clients. The message is
+// dropped for the clients whose buffer is full.
 func (s *Socket) Send(msg []byte) {
-	var conns []net.Conn
-
-	// Get a copy of connections
 	s.connLock.RLock()
-	if len(s.conns) > 0 {
-		conns = make([]net.Conn, len(s.conns))
-		i := 0
-		for conn := range s.conns {
-			conns[i] = conn
-			i++
+	defer s.connLock.RUnlock()
+
+	for _, sub := range s.conns {
+		select {
+		case sub.send <- msg:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+			atomic.AddUint64(&sub.unreported, 1)
 		}
 	}
-	s.connLock.RUnlock()
+}
 
-	// Write to each connection
-	if len(conns) == 0 {
-		return
+// Subscribers returns the stats of the connected clients
+func (s *Socket) Subscribers() []SubscriberStats {
+	s.connLock.RLock()
+	defer s.connLock.RUnlock()
+
+	stats := make([]SubscriberStats, 0, len(s.conns))
+	for conn, sub := range s.conns {
+		stats = append(stats, SubscriberStats{
+			RemoteAddr: conn.RemoteAddr().String(),
+			Pending:    len(sub.send),
+			Sent:       atomic.LoadUint64(&sub.sent),
+			Dropped:    atomic.LoadUint64(&sub.dropped),
+		})
 	}
+	return stats
+}
 
-	// Prefix the message with an 8 byte length
-	lenBytes := [8]byte{}
-	binary.BigEndian.PutUint64(lenBytes[:], uint64(len(msg)))
-	for _, conn := range conns {
-		for _, byteSlice := range [][]byte{lenBytes[:], msg} {
-			if _, err := conn.Write(byteSlice); err != nil {
-				s.removeConn(conn)
-				s.log.Debug("failed to write message to %s: %s", conn.RemoteAddr(), err)
-			}
-		}
+// AddConn subscribes [conn] to the messages sent over the socket. [conn] is
+// closed when the socket is closed.
+func (s *Socket) AddConn(conn net.Conn) {
+	sub := &subscriber{
+		conn:   conn,
+		send:   make(chan []byte, maxPendingMessages),
+		closed: make(chan struct{}),
+	}
+
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+
+	if s.conns == nil {
+		// The socket is closed
+		_ = conn.Close()
+		return
 	}
+	s.conns[conn] = sub
+	go s.writeLoop(sub)
 }
 
 // Close closes the socket by cutting off new connections, closing all
@@ -120,9 +176,11 @@ func (s *Socket) Close() error {
 	s.listener = nil
 
 	// close the listener to break the loop
-	err := listener.Close()
-
-	<-s.doneCh
+	var err error
+	if listener != nil {
+		err = listener.Close()
+		<-s.doneCh
+	}
 
 	// Zero out the connection pool but save a reference so we can close them all
 	s.connLock.Lock()
@@ -132,10 +190,9 @@ func (s *Socket) Close() error {
 
 	// Close all connections that were open at the time of shutdown
 	errs := wrappers.Errs{Err: err}
-	for conn := range conns {
-		if conn != nil {
-			errs.Add(conn.Close())
-		}
+	for conn, sub := range conns {
+		close(sub.closed)
+		errs.Add(conn.Close())
 	}
 	return errs.Err
 }
@@ -146,8 +203,78 @@ func (s *Socket) Running() bool {
 
 func (s *Socket) removeConn(c net.Conn) {
 	s.connLock.Lock()
-	delete(s.conns, c)
-	s.connLock.Unlock()
+	defer s.connLock.Unlock()
+
+	if sub, ok := s.conns[c]; ok {
+		close(sub.closed)
+		delete(s.conns, c)
+	}
+}
+
+// subscriber is a connection to a client with its buffered messages
+type subscriber struct {
+	conn net.Conn
+	// Buffered messages
+	send chan []byte
+	// Closed when the connection is removed from the socket
+	closed chan struct{}
+
+	sent    uint64
+	dropped uint64
+	// Number of dropped messages that weren't reported to the client yet
+	unreported uint64
+}
+
+// writeLoop writes the messages buffered for [sub] until [sub] is removed or
+// a write fails
+func (s *Socket) writeLoop(sub *subscriber) {
+	if s.control != nil {
+		if msg := s.control.Subscribed(); msg != nil {
+			if err := writeMsg(sub.conn, msg); err != nil {
+				s.closeSubscriber(sub, err)
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case msg := <-sub.send:
+			if numDropped := atomic.SwapUint64(&sub.unreported, 0); numDropped > 0 {
+				s.log.Debug("dropped %d messages to %s", numDropped, sub.conn.RemoteAddr())
+				if s.control != nil {
+					if droppedMsg := s.control.Dropped(numDropped); droppedMsg != nil {
+						if err := writeMsg(sub.conn, droppedMsg); err != nil {
+							s.closeSubscriber(sub, err)
+							return
+						}
+					}
+				}
+			}
+			if err := writeMsg(sub.conn, msg); err != nil {
+				s.closeSubscriber(sub, err)
+				return
+			}
+			atomic.AddUint64(&sub.sent, 1)
+		case <-sub.closed:
+			return
+		}
+	}
+}
+
+func (s *Socket) closeSubscriber(sub *subscriber, err error) {
+	s.log.Debug("failed to write message to %s: %s", sub.conn.RemoteAddr(), err)
+	s.removeConn(sub.conn)
+	_ = sub.conn.Close()
+}
+
+// writeMsg writes [msg] to [conn], prefixed with an 8 byte length
+func writeMsg(conn net.Conn, msg []byte) error {
+	lenBytes := [lenPrefixLen]byte{}
+	binary.BigEndian.PutUint64(lenBytes[:], uint64(len(msg)))
+	buffers := net.Buffers{lenBytes[:], msg}
+	_, err := buffers.WriteTo(conn)
+	return err
 }
 
 // Client is a read-only connection to a socket
@@ -215,6 +342,7 @@ func accept(s *Socket, l net.Listener) {
 			return
 		}
 		s.log.Error("socket accept error: %s", err.Error())
+		return
 	}
 	if conn, ok := conn.(*net.TCPConn); ok {
 		if err := conn.SetLinger(0); err != nil {
@@ -224,9 +352,7 @@ func accept(s *Socket, l net.Listener) {
 			s.log.Warn("failed to set socket nodelay due to: %s", err)
 		}
 	}
-	s.connLock.Lock()
-	s.conns[conn] = struct{}{}
-	s.connLock.Unlock()
+	s.AddConn(conn)
 }
 
 // isTimeoutError checks if an error is a timeout as per the net.Error interface
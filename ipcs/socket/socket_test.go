@@ -4,8 +4,16 @@
 package socket
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/staking"
+	"github.com/flare-foundation/flare/utils/logging"
 )
 
 func TestSocketSendAndReceive(t *testing.T) {
@@ -17,7 +25,7 @@ func TestSocketSendAndReceive(t *testing.T) {
 	)
 
 	// Create socket and client; wait for client to connect
-	socket := NewSocket(socketName, nil)
+	socket := NewSocket(socketName, logging.NoLog{}, nil)
 	socket.accept, connCh = newTestAcceptFn()
 	if err := socket.Listen(); err != nil {
 		t.Fatal("Failed to listen on socket:", err.Error())
@@ -65,12 +73,127 @@ func newTestAcceptFn() (acceptFn, chan net.Conn) {
 		conn, err := l.Accept()
 		if err != nil {
 			s.log.Error("socket accept error: %s", err.Error())
+			return
 		}
 
-		s.connLock.Lock()
-		s.conns[conn] = struct{}{}
-		s.connLock.Unlock()
-
+		s.AddConn(conn)
 		connCh <- conn
 	}, connCh
 }
+
+// testControlMessages marks the first message sent to a subscriber and
+// reports dropped messages as their count
+type testControlMessages struct{}
+
+func (testControlMessages) Subscribed() []byte { return []byte("subscribed") }
+
+func (testControlMessages) Dropped(numDropped uint64) []byte { return []byte{byte(numDropped)} }
+
+func TestSocketDropsMessagesOfSlowSubscribers(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	socketName := "/tmp/pipe-test-drops.sock"
+
+	socket := NewSocket(socketName, logging.NoLog{}, testControlMessages{})
+	var connCh chan net.Conn
+	socket.accept, connCh = newTestAcceptFn()
+	require.NoError(socket.Listen())
+	defer socket.Close()
+
+	client, err := Dial(socketName)
+	require.NoError(err)
+	defer client.Close()
+	<-connCh
+
+	// The client doesn't read, so the socket buffers up to
+	// [maxPendingMessages] messages for it and drops the others. Large
+	// messages fill the kernel buffers with few messages.
+	msg := make([]byte, 64*1024)
+	for i := 0; i < 4*maxPendingMessages; i++ {
+		socket.Send(msg)
+	}
+	stats := socket.Subscribers()
+	require.Len(stats, 1)
+	assert.NotZero(stats[0].Dropped)
+	assert.LessOrEqual(stats[0].Pending, maxPendingMessages)
+
+	received, err := client.Recv()
+	require.NoError(err)
+	assert.Equal([]byte("subscribed"), received)
+
+	// The drops are reported before the next message written to the client
+	dropped := stats[0].Dropped
+	for {
+		received, err = client.Recv()
+		require.NoError(err)
+		if len(received) != len(msg) {
+			break
+		}
+	}
+	assert.Equal([]byte{byte(dropped)}, received)
+}
+
+func TestTLSServer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	serverCert, err := staking.NewTLSCert()
+	require.NoError(err)
+	clientCert, err := staking.NewTLSCert()
+	require.NoError(err)
+	clientLeaf, err := x509.ParseCertificate(clientCert.Certificate[0])
+	require.NoError(err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientLeaf)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{*serverCert},
+		MinVersion:   tls.VersionTLS13,
+	}
+	_, err = NewTLSServer("127.0.0.1:0", serverConfig, logging.NoLog{})
+	assert.ErrorIs(err, errClientAuthRequired)
+
+	serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	serverConfig.ClientCAs = clientCAs
+	server, err := NewTLSServer("127.0.0.1:0", serverConfig, logging.NoLog{})
+	require.NoError(err)
+	defer server.Close()
+
+	socket := NewSocket("", logging.NoLog{}, testControlMessages{})
+	defer socket.Close()
+	server.Register("test", socket)
+
+	client, err := DialTLS(server.Addr().String(), "test", &tls.Config{
+		Certificates:       []tls.Certificate{*clientCert},
+		InsecureSkipVerify: true, // #nosec G402
+		MinVersion:         tls.VersionTLS13,
+	})
+	require.NoError(err)
+	defer client.Close()
+
+	received, err := client.Recv()
+	require.NoError(err)
+	assert.Equal([]byte("subscribed"), received)
+
+	socket.Send([]byte("message"))
+	received, err = client.Recv()
+	require.NoError(err)
+	assert.Equal([]byte("message"), received)
+
+	// Clients without a certificate signed by a client CA are rejected
+	unknownCert, err := staking.NewTLSCert()
+	require.NoError(err)
+	for _, certs := range [][]tls.Certificate{nil, {*unknownCert}} {
+		client, err := DialTLS(server.Addr().String(), "test", &tls.Config{
+			Certificates:       certs,
+			InsecureSkipVerify: true, // #nosec G402
+			MinVersion:         tls.VersionTLS13,
+		})
+		if err != nil {
+			continue
+		}
+		_, err = client.Recv()
+		assert.Error(err)
+		_ = client.Close()
+	}
+}
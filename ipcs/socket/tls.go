@@ -0,0 +1,180 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package socket
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+const (
+	// Time allowed for a client to complete the TLS handshake and to send the
+	// name of the socket it subscribes to
+	subscribeTimeout = 10 * time.Second
+
+	// Maximum length of the name of a socket
+	maxNameLen = 256
+)
+
+var (
+	errNameTooLong        = errors.New("socket name too long")
+	errClientAuthRequired = errors.New("TLS config must require and verify client certificates")
+)
+
+// TLSServer accepts TLS connections over TCP and subscribes each of them to
+// the socket it requests. After the TLS handshake, a client sends the name of
+// a registered socket, prefixed with its length as an 8 byte big endian
+// integer, and then receives the messages sent over that socket. Only clients
+// with a certificate signed by one of the configured client CAs can connect.
+type TLSServer struct {
+	log      logging.Logger
+	listener net.Listener
+	doneCh   chan struct{}
+
+	lock    sync.RWMutex
+	sockets map[string]*Socket
+}
+
+// NewTLSServer starts accepting TLS connections at [addr]. [config] must
+// require and verify client certificates against its ClientCAs.
+func NewTLSServer(addr string, config *tls.Config, log logging.Logger) (*TLSServer, error) {
+	if config.ClientAuth != tls.RequireAndVerifyClientCert || config.ClientCAs == nil {
+		return nil, errClientAuthRequired
+	}
+	listener, err := tls.Listen("tcp", addr, config)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &TLSServer{
+		log:      log,
+		listener: listener,
+		doneCh:   make(chan struct{}),
+		sockets:  make(map[string]*Socket),
+	}
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Addr returns the address the server listens on
+func (s *TLSServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Register allows clients to subscribe to [socket] by [name]
+func (s *TLSServer) Register(name string, socket *Socket) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sockets[name] = socket
+}
+
+// Deregister stops clients from subscribing to the socket registered as
+// [name]. Existing subscribers are closed along with the socket.
+func (s *TLSServer) Deregister(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.sockets, name)
+}
+
+// Close stops accepting connections
+func (s *TLSServer) Close() error {
+	err := s.listener.Close()
+	<-s.doneCh
+	return err
+}
+
+func (s *TLSServer) acceptLoop() {
+	defer close(s.doneCh)
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				s.log.Debug("temporary TLS socket accept error: %s", err)
+				continue
+			}
+			return
+		}
+		go s.subscribe(conn)
+	}
+}
+
+// subscribe reads the name of the socket that [conn] subscribes to and adds
+// [conn] to it
+func (s *TLSServer) subscribe(conn net.Conn) {
+	if err := conn.SetReadDeadline(time.Now().Add(subscribeTimeout)); err != nil {
+		_ = conn.Close()
+		return
+	}
+	name, err := readName(conn)
+	if err != nil {
+		s.log.Debug("couldn't read subscription of %s: %s", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	s.lock.RLock()
+	socket, ok := s.sockets[name]
+	s.lock.RUnlock()
+	if !ok {
+		s.log.Debug("%s subscribed to unknown socket %q", conn.RemoteAddr(), name)
+		_ = conn.Close()
+		return
+	}
+	socket.AddConn(conn)
+}
+
+func readName(r io.Reader) (string, error) {
+	var nameLen uint64
+	if err := binary.Read(r, binary.BigEndian, &nameLen); err != nil {
+		return "", err
+	}
+	if nameLen > maxNameLen {
+		return "", fmt.Errorf("%w: %d bytes", errNameTooLong, nameLen)
+	}
+	name := make([]byte, nameLen)
+	if _, err := io.ReadFull(r, name); err != nil {
+		return "", err
+	}
+	return string(name), nil
+}
+
+// DialTLS creates a new *Client subscribed to the socket registered as [name]
+// on the TLSServer at [addr]
+func DialTLS(addr, name string, config *tls.Config) (*Client, error) {
+	if len(name) > maxNameLen {
+		return nil, fmt.Errorf("%w: %d bytes", errNameTooLong, len(name))
+	}
+
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, err
+	}
+
+	p := wrappers.Packer{Bytes: make([]byte, lenPrefixLen+len(name))}
+	p.PackLong(uint64(len(name)))
+	p.PackFixedBytes([]byte(name))
+	if _, err := conn.Write(p.Bytes); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &Client{Conn: conn, maxMessageSize: int64(constants.DefaultMaxMessageSize)}, nil
+}
@@ -0,0 +1,329 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/flare-foundation/flare/config"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/database/manager"
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/database/migrate"
+	"github.com/flare-foundation/flare/database/snapshot"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/node"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/perms"
+	"github.com/flare-foundation/flare/version"
+)
+
+const (
+	// dbCommand is the first argument that runs the database tooling instead
+	// of the node, e.g.:
+	//
+	//	flare db snapshot --archive=/backups/flare.snap --db-dir=/data/db
+	dbCommand = "db"
+
+	archiveKey = "archive"
+
+	fromTypeKey       = "from-type"
+	fromDirKey        = "from-dir"
+	fromConfigFileKey = "from-config-file"
+	toTypeKey         = "to-type"
+	toDirKey          = "to-dir"
+	toConfigFileKey   = "to-config-file"
+)
+
+var (
+	errNoDBCommand       = errors.New("expected one of the db commands: snapshot, restore, verify, migrate, inspect")
+	errNoArchive         = fmt.Errorf("--%s must be set", archiveKey)
+	errNoDatabase        = errors.New("database directory doesn't exist")
+	errInMemoryDatabase  = errors.New("in-memory databases can't be used offline")
+	errDatabaseDirExists = errors.New("database directory already exists")
+	errSameDatabase      = errors.New("source and target database must differ")
+)
+
+// runDB runs the database command given by [args]. The node's config flags
+// select the database the command operates on, so the node must be stopped.
+func runDB(args []string) error {
+	if len(args) == 0 {
+		return errNoDBCommand
+	}
+	command, args := args[0], args[1:]
+	if command == "migrate" {
+		return migrateDB(args)
+	}
+
+	fs := config.BuildFlagSet()
+	fs.String(archiveKey, "", "Path of the database snapshot archive")
+	v, err := config.BuildViper(fs, args)
+	if err != nil {
+		return err
+	}
+	if command == "inspect" {
+		return inspectDB(v)
+	}
+	archive := v.GetString(archiveKey)
+	if archive == "" {
+		return errNoArchive
+	}
+
+	if command == "verify" {
+		summary, err := snapshot.VerifyFile(archive)
+		if err != nil {
+			return err
+		}
+		printSnapshotSummary("verified", archive, summary)
+		return nil
+	}
+
+	dbConfig, err := config.GetDatabaseConfig(v)
+	if err != nil {
+		return err
+	}
+	if dbConfig.Name == memdb.Name {
+		return errInMemoryDatabase
+	}
+
+	switch command {
+	case "snapshot":
+		return snapshotDB(dbConfig, archive)
+	case "restore":
+		return restoreDB(dbConfig, archive)
+	default:
+		return fmt.Errorf("%w but got %q", errNoDBCommand, command)
+	}
+}
+
+func snapshotDB(dbConfig node.DatabaseConfig, archive string) error {
+	if _, err := os.Stat(dbConfig.Path); err != nil {
+		return fmt.Errorf("%w: %s", errNoDatabase, dbConfig.Path)
+	}
+	dbManager, err := manager.New(dbConfig.Name, dbConfig.Path, dbConfig.Config, logging.NoLog{}, version.CurrentDatabase)
+	if err != nil {
+		return err
+	}
+	defer dbManager.Close()
+
+	summary, err := snapshot.WriteFile(archive, dbManager.GetDatabases())
+	if err != nil {
+		return err
+	}
+	printSnapshotSummary("wrote", archive, summary)
+	return nil
+}
+
+func restoreDB(dbConfig node.DatabaseConfig, archive string) error {
+	if _, err := os.Stat(dbConfig.Path); err == nil {
+		return fmt.Errorf("%w: %s", errDatabaseDirExists, dbConfig.Path)
+	}
+
+	var dbs []*manager.VersionedDatabase
+	defer func() {
+		for _, db := range dbs {
+			_ = db.Close()
+		}
+	}()
+	summary, err := snapshot.RestoreFile(archive, func(v version.Version) (database.Database, error) {
+		db, err := manager.NewVersionedDatabase(dbConfig.Name, dbConfig.Path, dbConfig.Config, logging.NoLog{}, v)
+		if err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, db)
+		return db.Database, nil
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't restore %s into %s: %w", archive, dbConfig.Path, err)
+	}
+	printSnapshotSummary("restored", archive, summary)
+	return nil
+}
+
+// migrateDB copies the databases of every version from one database backend
+// to another, e.g.:
+//
+//	flare db migrate --from-type=rocksdb --from-dir=/data/db/flare --to-type=leveldb --to-dir=/data/leveldb/flare
+//
+// The directories are the network's database directories, as used by the node
+// for --db-dir. An interrupted migration is resumed by running the same
+// command again.
+func migrateDB(args []string) error {
+	fs := pflag.NewFlagSet("migrate", pflag.ContinueOnError)
+	fs.String(fromTypeKey, "", "Database type to migrate from")
+	fs.String(fromDirKey, "", "Database directory to migrate from")
+	fs.String(fromConfigFileKey, "", "Config file of the database to migrate from")
+	fs.String(toTypeKey, "", "Database type to migrate to")
+	fs.String(toDirKey, "", "Database directory to migrate to")
+	fs.String(toConfigFileKey, "", "Config file of the database to migrate to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	from, err := getMigrateDBConfig(fs, fromTypeKey, fromDirKey, fromConfigFileKey)
+	if err != nil {
+		return err
+	}
+	to, err := getMigrateDBConfig(fs, toTypeKey, toDirKey, toConfigFileKey)
+	if err != nil {
+		return err
+	}
+	if from.Name == to.Name && filepath.Clean(from.Path) == filepath.Clean(to.Path) {
+		return errSameDatabase
+	}
+	if _, err := os.Stat(from.Path); err != nil {
+		return fmt.Errorf("%w: %s", errNoDatabase, from.Path)
+	}
+	if err := os.MkdirAll(to.Path, perms.ReadWriteExecute); err != nil {
+		return err
+	}
+
+	logFactory := logging.NewFactory(logging.DefaultConfig)
+	defer logFactory.Close()
+	log, err := logFactory.Make("db-migrate")
+	if err != nil {
+		return err
+	}
+
+	fromManager, err := manager.New(from.Name, from.Path, from.Config, log, version.CurrentDatabase)
+	if err != nil {
+		return err
+	}
+	defer fromManager.Close()
+
+	var dbs []*manager.VersionedDatabase
+	defer func() {
+		for _, db := range dbs {
+			_ = db.Close()
+		}
+	}()
+	summaries, err := migrate.Migrate(log, fromManager, func(v version.Version) (database.Database, error) {
+		db, err := manager.NewVersionedDatabase(to.Name, to.Path, to.Config, log, v)
+		if err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, db)
+		return db.Database, nil
+	}, to.Path)
+	if err != nil {
+		return fmt.Errorf("couldn't migrate %s into %s: %w", from.Path, to.Path, err)
+	}
+
+	fmt.Printf("migrated %s database %s into %s database %s\n", from.Name, from.Path, to.Name, to.Path)
+	for _, summary := range summaries {
+		fmt.Printf("  database %s: %d keys, %d bytes (checksum %s)", summary.Version, summary.NumKeys, summary.NumBytes, summary.Checksum)
+		if summary.Resumed {
+			fmt.Print(", resumed")
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func getMigrateDBConfig(fs *pflag.FlagSet, typeKey, dirKey, configFileKey string) (node.DatabaseConfig, error) {
+	dbConfig := node.DatabaseConfig{}
+	dbConfig.Name, _ = fs.GetString(typeKey)
+	dbConfig.Path, _ = fs.GetString(dirKey)
+	switch {
+	case dbConfig.Name == "":
+		return node.DatabaseConfig{}, fmt.Errorf("--%s must be set", typeKey)
+	case dbConfig.Name == memdb.Name:
+		return node.DatabaseConfig{}, errInMemoryDatabase
+	case dbConfig.Path == "":
+		return node.DatabaseConfig{}, fmt.Errorf("--%s must be set", dirKey)
+	}
+	dbConfig.Path = os.ExpandEnv(dbConfig.Path)
+
+	configFile, _ := fs.GetString(configFileKey)
+	if configFile == "" {
+		return dbConfig, nil
+	}
+	configBytes, err := os.ReadFile(os.ExpandEnv(configFile))
+	if err != nil {
+		return node.DatabaseConfig{}, err
+	}
+	dbConfig.Config = configBytes
+	return dbConfig, nil
+}
+
+// inspectDB reports the number and size of the keys of the node's databases by
+// chain and by the prefix they were written under. Only the chains created in
+// the genesis are known offline.
+func inspectDB(v *viper.Viper) error {
+	dbConfig, err := config.GetDatabaseConfig(v)
+	if err != nil {
+		return err
+	}
+	if dbConfig.Name == memdb.Name {
+		return errInMemoryDatabase
+	}
+	if _, err := os.Stat(dbConfig.Path); err != nil {
+		return fmt.Errorf("%w: %s", errNoDatabase, dbConfig.Path)
+	}
+	genesisBytes, err := config.GetGenesisBytes(v)
+	if err != nil {
+		return err
+	}
+	chainInfos, chainNames, err := node.GenesisChains(genesisBytes)
+	if err != nil {
+		return err
+	}
+
+	dbManager, err := manager.New(dbConfig.Name, dbConfig.Path, dbConfig.Config, logging.NoLog{}, version.CurrentDatabase)
+	if err != nil {
+		return err
+	}
+	defer dbManager.Close()
+
+	for _, db := range dbManager.GetDatabases() {
+		layout, err := node.DatabaseLayout(db.Database, chainInfos, func(chainID ids.ID) string {
+			return chainNames[chainID]
+		})
+		if err != nil {
+			return err
+		}
+		report, err := inspect.Inspect(db.Database, layout)
+		if err != nil {
+			return fmt.Errorf("couldn't inspect database %s: %w", db.Version, err)
+		}
+		printInspectReport(db.Version, report)
+	}
+	return nil
+}
+
+func printInspectReport(v version.Version, report inspect.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "database %s\tkeys\tkey bytes\tvalue bytes\t\n", v)
+	printStats := func(name string, stats inspect.Stats) {
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t\n", name, stats.NumKeys, stats.KeyBytes, stats.ValueBytes)
+	}
+	printStats("total", report.Total)
+	for _, chain := range report.Chains {
+		printStats("chain "+chain.Chain, chain.Stats)
+	}
+	for _, prefix := range report.Prefixes {
+		name := prefix.Name
+		if prefix.Chain != "" {
+			name = prefix.Chain + ": " + name
+		}
+		printStats(name, prefix.Stats)
+	}
+	printStats("unknown", report.Unknown)
+	_ = w.Flush()
+}
+
+func printSnapshotSummary(action string, archive string, summary snapshot.Summary) {
+	fmt.Printf("%s snapshot %s taken at %s (checksum %s)\n", action, archive, summary.Timestamp, summary.Checksum)
+	for _, db := range summary.Databases {
+		fmt.Printf("  database %s: %d keys, %d bytes (checksum %s)\n", db.Version, db.NumKeys, db.NumBytes, db.Checksum)
+	}
+}
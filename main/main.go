@@ -16,6 +16,18 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == dbCommand {
+		err := runDB(os.Args[2:])
+		if errors.Is(err, pflag.ErrHelp) {
+			os.Exit(0)
+		}
+		if err != nil {
+			fmt.Printf("couldn't run db command: %s\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	fs := config.BuildFlagSet()
 	v, err := config.BuildViper(fs, os.Args[1:])
 
@@ -44,6 +56,7 @@ func main() {
 		fmt.Printf("couldn't load node config: %s\n", err)
 		os.Exit(1)
 	}
+	nodeConfig.ConfigReloader = config.NewConfigReloader(v, os.Args[1:], runnerConfig.BuildDir)
 
 	runner.Run(runnerConfig, nodeConfig)
 }
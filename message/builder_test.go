@@ -13,6 +13,7 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/units"
 	"github.com/flare-foundation/flare/version"
 )
@@ -355,3 +356,122 @@ func TestBuildAppGossipMsg(t *testing.T) {
 		assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
 	}
 }
+
+func TestBuildGetStateSummaryFrontier(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+
+	msg, err := UncompressingBuilder.GetStateSummaryFrontier(chainID, requestID, time.Duration(deadline))
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetStateSummaryFrontier, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetStateSummaryFrontier, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+}
+
+func TestBuildStateSummaryFrontier(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	summary := make([]byte, 1024)
+	summary[0] = 1
+	summary[len(summary)-1] = 1
+
+	for _, compress := range []bool{false, true} {
+		builder := NewOutboundBuilder(TestCodec, compress)
+		msg, err := builder.StateSummaryFrontier(chainID, requestID, summary)
+		assert.NoError(t, err)
+		assert.NotNil(t, msg)
+		assert.Equal(t, StateSummaryFrontier, msg.Op())
+
+		parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+		assert.NoError(t, err)
+		assert.NotNil(t, parsedMsg)
+		assert.Equal(t, StateSummaryFrontier, parsedMsg.Op())
+		assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+		assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+		assert.Equal(t, summary, parsedMsg.Get(SummaryBytes))
+	}
+}
+
+func TestBuildGetAcceptedStateSummary(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+	heights := []uint64{1000, 2000}
+
+	msg, err := UncompressingBuilder.GetAcceptedStateSummary(chainID, requestID, time.Duration(deadline), heights)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetAcceptedStateSummary, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetAcceptedStateSummary, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+	assert.Equal(t, heights, parsedMsg.Get(SummaryHeights))
+}
+
+func TestBuildAcceptedStateSummary(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	summaryID := ids.Empty.Prefix(1)
+	summaryIDs := [][]byte{summaryID[:]}
+
+	msg, err := UncompressingBuilder.AcceptedStateSummary(chainID, requestID, []ids.ID{summaryID})
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, AcceptedStateSummary, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, AcceptedStateSummary, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, summaryIDs, parsedMsg.Get(SummaryIDs))
+}
+
+func TestBuildCompressions(t *testing.T) {
+	msg, err := UncompressingBuilder.Compressions(compression.DecompressibleTypes)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, Compressions, msg.Op())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, Compressions, parsedMsg.Op())
+	assert.Equal(t, []byte{byte(compression.TypeGzip), byte(compression.TypeZstd)}, parsedMsg.Get(CompressionTypes))
+}
+
+func TestBuildWithCompressionPerOp(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	containerID := ids.Empty.Prefix(1)
+	container := []byte{2}
+
+	builder := NewOutboundBuilderWithCompression(TestCodec, map[Op]compression.Type{
+		Put: compression.TypeZstd,
+	})
+	msg, err := builder.Put(chainID, requestID, containerID, container)
+	assert.NoError(t, err)
+	assert.Equal(t, compression.TypeZstd, msg.CompressionType())
+
+	parsedMsg, err := TestCodec.Parse(msg.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+	assert.NoError(t, err)
+	assert.Equal(t, container, parsedMsg.Get(ContainerBytes))
+
+	msg, err = builder.PushQuery(chainID, requestID, time.Second, containerID, container)
+	assert.NoError(t, err)
+	assert.Equal(t, compression.TypeNone, msg.CompressionType())
+}
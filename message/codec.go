@@ -21,8 +21,10 @@ import (
 )
 
 var (
-	errMissingField = errors.New("message missing field")
-	errBadOp        = errors.New("input field has invalid operation")
+	errMissingField           = errors.New("message missing field")
+	errBadOp                  = errors.New("input field has invalid operation")
+	errUncompressibleOp       = errors.New("messages of this type can't be compressed")
+	errUnknownCompressionType = errors.New("unknown compression type")
 
 	_ Codec = &codec{}
 )
@@ -31,9 +33,14 @@ type Packer interface {
 	Pack(
 		op Op,
 		fieldValues map[Field]interface{},
-		compress bool,
+		compressionType compression.Type,
 		bypassThrottling bool,
 	) (OutboundMessage, error)
+
+	// Recompress returns the bytes of [msg] with its payload compressed with
+	// [compressionType] instead. It's used to send messages to peers that
+	// can't decompress the compression type they were packed with.
+	Recompress(msg OutboundMessage, compressionType compression.Type) ([]byte, error)
 }
 
 type Parser interface {
@@ -60,11 +67,16 @@ type codec struct {
 
 	compressTimeMetrics   map[Op]metric.Averager
 	decompressTimeMetrics map[Op]metric.Averager
-	compressor            compression.Compressor
-	maxMessageTimeout     time.Duration
+	// compressors of each compression type, except [compression.TypeNone]
+	compressors       map[compression.Type]compression.Compressor
+	maxMessageTimeout time.Duration
 }
 
 func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, maxMessageSize int64, maxMessageTimeout time.Duration) (Codec, error) {
+	zstdCompressor, err := compression.NewZstdCompressor(maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
 	c := &codec{
 		byteSlicePool: sync.Pool{
 			New: func() interface{} {
@@ -73,8 +85,11 @@ func NewCodecWithMemoryPool(namespace string, metrics prometheus.Registerer, max
 		},
 		compressTimeMetrics:   make(map[Op]metric.Averager, len(ExternalOps)),
 		decompressTimeMetrics: make(map[Op]metric.Averager, len(ExternalOps)),
-		compressor:            compression.NewGzipCompressor(maxMessageSize),
-		maxMessageTimeout:     maxMessageTimeout,
+		compressors: map[compression.Type]compression.Compressor{
+			compression.TypeGzip: compression.NewGzipCompressor(maxMessageSize),
+			compression.TypeZstd: zstdCompressor,
+		},
+		maxMessageTimeout: maxMessageTimeout,
 	}
 
 	errs := wrappers.Errs{}
@@ -110,18 +125,21 @@ func (c *codec) SetTime(t time.Time) {
 // Uses [buffer] to hold the message's byte repr.
 // [buffer]'s contents may be overwritten by this method.
 // [buffer] may be nil.
-// If [compress], compress the payload.
+// The payload is compressed with [compressionType].
 // If [bypassThrottling], mark the message to avoid outbound throttling checks.
 func (c *codec) Pack(
 	op Op,
 	fieldValues map[Field]interface{},
-	compress bool,
+	compressionType compression.Type,
 	bypassThrottling bool,
 ) (OutboundMessage, error) {
 	msgFields, ok := messages[op]
 	if !ok {
 		return nil, errBadOp
 	}
+	if compressionType != compression.TypeNone && !op.Compressible() {
+		return nil, fmt.Errorf("%w: %s", errUncompressibleOp, op)
+	}
 
 	buffer := c.byteSlicePool.Get().([]byte)
 	p := wrappers.Packer{
@@ -131,9 +149,12 @@ func (c *codec) Pack(
 	// Pack the op code (message type)
 	p.PackByte(byte(op))
 
-	// Optionally, pack whether the payload is compressed
+	// Optionally, pack how the payload is compressed. Before other compression
+	// types were introduced, this was a bool flagging gzip compression, which
+	// is still compatible with [compression.TypeNone] and
+	// [compression.TypeGzip].
 	if op.Compressible() {
-		p.PackBool(compress)
+		p.PackByte(byte(compressionType))
 	}
 
 	// Pack the uncompressed payload
@@ -150,32 +171,96 @@ func (c *codec) Pack(
 	msg := &outboundMessage{
 		op:               op,
 		bytes:            p.Bytes,
+		compressionType:  compressionType,
 		refs:             1,
 		c:                c,
 		bypassThrottling: bypassThrottling,
 	}
-	if !compress {
+	if compressionType == compression.TypeNone {
 		return msg, nil
 	}
 
-	// If [compress], compress the payload (not the op code, not isCompressed).
+	// Compress the payload (not the op code, not the compression type).
 	// The slice below is guaranteed to be in-bounds because [p.Err] == nil
 	// implies that len(msg.bytes) >= 2
-	payloadBytes := msg.bytes[wrappers.BoolLen+wrappers.ByteLen:]
-	startTime := time.Now()
-	compressedPayloadBytes, err := c.compressor.Compress(payloadBytes)
+	payloadBytes := msg.bytes[2*wrappers.ByteLen:]
+	compressedPayloadBytes, err := c.compress(op, compressionType, payloadBytes)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't compress payload of %s message: %w", op, err)
+		return nil, err
 	}
-	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
 	msg.bytesSavedCompression = len(payloadBytes) - len(compressedPayloadBytes) // may be negative
-	// Remove the uncompressed payload (keep just the message type and isCompressed)
-	msg.bytes = msg.bytes[:wrappers.BoolLen+wrappers.ByteLen]
+	// Remove the uncompressed payload (keep just the message type and the
+	// compression type)
+	msg.bytes = msg.bytes[:2*wrappers.ByteLen]
 	// Attach the compressed payload
 	msg.bytes = append(msg.bytes, compressedPayloadBytes...)
 	return msg, nil
 }
 
+func (c *codec) Recompress(msg OutboundMessage, compressionType compression.Type) ([]byte, error) {
+	op := msg.Op()
+	if !op.Compressible() {
+		if compressionType != compression.TypeNone {
+			return nil, fmt.Errorf("%w: %s", errUncompressibleOp, op)
+		}
+		return msg.Bytes(), nil
+	}
+
+	msgBytes := msg.Bytes()
+	if len(msgBytes) < 2*wrappers.ByteLen {
+		return nil, fmt.Errorf("%s message is too short", op)
+	}
+	payloadBytes, err := c.decompress(op, compression.Type(msgBytes[wrappers.ByteLen]), msgBytes[2*wrappers.ByteLen:])
+	if err != nil {
+		return nil, err
+	}
+	if compressionType != compression.TypeNone {
+		payloadBytes, err = c.compress(op, compressionType, payloadBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recompressedBytes := make([]byte, 2*wrappers.ByteLen, 2*wrappers.ByteLen+len(payloadBytes))
+	recompressedBytes[0] = byte(op)
+	recompressedBytes[1] = byte(compressionType)
+	return append(recompressedBytes, payloadBytes...), nil
+}
+
+// compress [payloadBytes] of an [op] message with [compressionType]
+func (c *codec) compress(op Op, compressionType compression.Type, payloadBytes []byte) ([]byte, error) {
+	compressor, ok := c.compressors[compressionType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errUnknownCompressionType, compressionType)
+	}
+	startTime := time.Now()
+	compressedPayloadBytes, err := compressor.Compress(payloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't compress payload of %s message: %w", op, err)
+	}
+	c.compressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	return compressedPayloadBytes, nil
+}
+
+// decompress [payloadBytes] of an [op] message that was compressed with
+// [compressionType]
+func (c *codec) decompress(op Op, compressionType compression.Type, payloadBytes []byte) ([]byte, error) {
+	if compressionType == compression.TypeNone {
+		return payloadBytes, nil
+	}
+	compressor, ok := c.compressors[compressionType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errUnknownCompressionType, compressionType)
+	}
+	startTime := time.Now()
+	decompressedPayloadBytes, err := compressor.Decompress(payloadBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
+	}
+	c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
+	return decompressedPayloadBytes, nil
+}
+
 // Parse attempts to convert bytes into a message.
 // The first byte of the message is the opcode of the message.
 // Overrides client specified deadline in a message to maxDeadlineDuration
@@ -191,9 +276,9 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 	}
 
 	// See if messages of this type may be compressed
-	compressed := false
+	compressionType := compression.TypeNone
 	if op.Compressible() {
-		compressed = p.UnpackBool()
+		compressionType = compression.Type(p.UnpackByte())
 	}
 	if p.Err != nil {
 		return nil, p.Err
@@ -202,21 +287,20 @@ func (c *codec) Parse(bytes []byte, nodeID ids.ShortID, onFinishedHandling func(
 	bytesSaved := 0
 
 	// If the payload is compressed, decompress it
-	if compressed {
+	if compressionType != compression.TypeNone {
 		// The slice below is guaranteed to be in-bounds because [p.Err] == nil
-		compressedPayloadBytes := p.Bytes[wrappers.ByteLen+wrappers.BoolLen:]
-		startTime := time.Now()
-		payloadBytes, err := c.compressor.Decompress(compressedPayloadBytes)
+		compressedPayloadBytes := p.Bytes[2*wrappers.ByteLen:]
+		payloadBytes, err := c.decompress(op, compressionType, compressedPayloadBytes)
 		if err != nil {
-			return nil, fmt.Errorf("couldn't decompress payload of %s message: %w", op, err)
+			return nil, err
 		}
-		c.decompressTimeMetrics[op].Observe(float64(time.Since(startTime)))
 		// Replace the compressed payload with the decompressed payload.
-		// Remove the compressed payload and isCompressed; keep just the message type
+		// Remove the compressed payload and the compression type; keep just
+		// the message type
 		p.Bytes = p.Bytes[:wrappers.ByteLen]
-		// Rewind offset by 1 because we removed the bool flag
+		// Rewind offset by 1 because we removed the compression type
 		// since the data now is uncompressed
-		p.Offset -= wrappers.BoolLen
+		p.Offset -= wrappers.ByteLen
 		// Attach the decompressed payload.
 		p.Bytes = append(p.Bytes, payloadBytes...)
 		bytesSaved = len(payloadBytes) - len(compressedPayloadBytes)
@@ -15,6 +15,7 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/staking"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/units"
 )
 
@@ -22,10 +23,10 @@ func TestCodecPackInvalidOp(t *testing.T) {
 	codec, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
 	assert.NoError(t, err)
 
-	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), false, false)
+	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), compression.TypeNone, false)
 	assert.Error(t, err)
 
-	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), true, false)
+	_, err = codec.Pack(math.MaxUint8, make(map[Field]interface{}), compression.TypeGzip, false)
 	assert.Error(t, err)
 }
 
@@ -33,10 +34,10 @@ func TestCodecPackMissingField(t *testing.T) {
 	codec, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
 	assert.NoError(t, err)
 
-	_, err = codec.Pack(Get, make(map[Field]interface{}), false, false)
+	_, err = codec.Pack(Get, make(map[Field]interface{}), compression.TypeNone, false)
 	assert.Error(t, err)
 
-	_, err = codec.Pack(Get, make(map[Field]interface{}), true, false)
+	_, err = codec.Pack(Get, make(map[Field]interface{}), compression.TypeGzip, false)
 	assert.Error(t, err)
 }
 
@@ -75,7 +76,7 @@ func TestDeadlineOverride(t *testing.T) {
 		},
 	}
 
-	packedIntf, err := c.Pack(m.op, m.fields, m.op.Compressible(), false)
+	packedIntf, err := c.Pack(m.op, m.fields, compression.TypeGzip, false)
 	assert.NoError(t, err, "failed to pack on operation %s", m.op)
 
 	unpackedIntf, err := c.Parse(packedIntf.Bytes(), dummyNodeID, dummyOnFinishedHandling)
@@ -220,16 +221,105 @@ func TestCodecPackParseGzip(t *testing.T) {
 				ContainerIDs: [][]byte{id[:]},
 			},
 		},
+		{
+			op: GetStateSummaryFrontier,
+			fields: map[Field]interface{}{
+				ChainID:   id[:],
+				RequestID: uint32(1337),
+				Deadline:  uint64(time.Now().Unix()),
+			},
+		},
+		{
+			op: StateSummaryFrontier,
+			fields: map[Field]interface{}{
+				ChainID:      id[:],
+				RequestID:    uint32(1337),
+				SummaryBytes: make([]byte, 1024),
+			},
+		},
+		{
+			op: GetAcceptedStateSummary,
+			fields: map[Field]interface{}{
+				ChainID:        id[:],
+				RequestID:      uint32(1337),
+				Deadline:       uint64(time.Now().Unix()),
+				SummaryHeights: []uint64{1337},
+			},
+		},
+		{
+			op: AcceptedStateSummary,
+			fields: map[Field]interface{}{
+				ChainID:    id[:],
+				RequestID:  uint32(1337),
+				SummaryIDs: [][]byte{id[:]},
+			},
+		},
+		{
+			op: Compressions,
+			fields: map[Field]interface{}{
+				CompressionTypes: []byte{byte(compression.TypeGzip), byte(compression.TypeZstd)},
+			},
+		},
 	}
 	for _, m := range msgs {
-		packedIntf, err := c.Pack(m.op, m.fields, m.op.Compressible(), false)
-		assert.NoError(t, err, "failed to pack on operation %s", m.op)
+		compressionTypes := []compression.Type{compression.TypeNone}
+		if m.op.Compressible() {
+			compressionTypes = append(compressionTypes, compression.TypeGzip, compression.TypeZstd)
+		}
+		for _, compressionType := range compressionTypes {
+			packedIntf, err := c.Pack(m.op, m.fields, compressionType, false)
+			assert.NoError(t, err, "failed to pack on operation %s with %s", m.op, compressionType)
+
+			unpackedIntf, err := c.Parse(packedIntf.Bytes(), dummyNodeID, dummyOnFinishedHandling)
+			assert.NoError(t, err, "failed to parse w/ %s on operation %s", compressionType, m.op)
+
+			unpacked := unpackedIntf.(*inboundMessage)
+
+			assert.EqualValues(t, len(m.fields), len(unpacked.fields))
+		}
+	}
+}
+
+func TestCodecPackUncompressibleOp(t *testing.T) {
+	codec, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	assert.NoError(t, err)
 
-		unpackedIntf, err := c.Parse(packedIntf.Bytes(), dummyNodeID, dummyOnFinishedHandling)
-		assert.NoError(t, err, "failed to parse w/ compression on operation %s", m.op)
+	_, err = codec.Pack(Ping, nil, compression.TypeZstd, false)
+	assert.ErrorIs(t, err, errUncompressibleOp)
+}
+
+func TestCodecParseUnknownCompressionType(t *testing.T) {
+	codec, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	assert.NoError(t, err)
+
+	_, err = codec.Parse([]byte{byte(Put), math.MaxUint8, 0x00}, dummyNodeID, dummyOnFinishedHandling)
+	assert.ErrorIs(t, err, errUnknownCompressionType)
+}
+
+func TestCodecRecompress(t *testing.T) {
+	assert := assert.New(t)
+	c, err := NewCodecWithMemoryPool("", prometheus.NewRegistry(), 2*units.MiB, 10*time.Second)
+	assert.NoError(err)
+
+	id := ids.GenerateTestID()
+	fields := map[Field]interface{}{
+		ChainID:        id[:],
+		RequestID:      uint32(1337),
+		ContainerID:    id[:],
+		ContainerBytes: make([]byte, 1024),
+	}
+	msg, err := c.Pack(Put, fields, compression.TypeZstd, false)
+	assert.NoError(err)
+	assert.Equal(compression.TypeZstd, msg.CompressionType())
 
-		unpacked := unpackedIntf.(*inboundMessage)
+	for _, compressionType := range []compression.Type{compression.TypeNone, compression.TypeGzip} {
+		msgBytes, err := c.Recompress(msg, compressionType)
+		assert.NoError(err)
+		assert.EqualValues(compressionType, msgBytes[1])
 
-		assert.EqualValues(t, len(m.fields), len(unpacked.fields))
+		parsedMsg, err := c.Parse(msgBytes, dummyNodeID, dummyOnFinishedHandling)
+		assert.NoError(err)
+		assert.Equal(Put, parsedMsg.Op())
+		assert.Equal(fields[ContainerBytes], parsedMsg.Get(ContainerBytes))
 	}
 }
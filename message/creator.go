@@ -9,6 +9,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 )
 
@@ -27,13 +28,23 @@ type creator struct {
 }
 
 func NewCreator(metrics prometheus.Registerer, compressionEnabled bool, parentNamespace string, maxInboundMessageTimeout time.Duration) (Creator, error) {
+	compressionType := compression.TypeNone
+	if compressionEnabled {
+		compressionType = compression.TypeGzip
+	}
+	return NewCreatorWithCompression(metrics, CompressAllOps(compressionType), parentNamespace, maxInboundMessageTimeout)
+}
+
+// NewCreatorWithCompression returns a Creator that compresses the outbound
+// messages of each op in [compressionTypes] with its compression type.
+func NewCreatorWithCompression(metrics prometheus.Registerer, compressionTypes map[Op]compression.Type, parentNamespace string, maxInboundMessageTimeout time.Duration) (Creator, error) {
 	namespace := fmt.Sprintf("%s_codec", parentNamespace)
 	codec, err := NewCodecWithMemoryPool(namespace, metrics, int64(constants.DefaultMaxMessageSize), maxInboundMessageTimeout)
 	if err != nil {
 		return nil, err
 	}
 	return &creator{
-		OutboundMsgBuilder: NewOutboundBuilder(codec, compressionEnabled),
+		OutboundMsgBuilder: NewOutboundBuilderWithCompression(codec, compressionTypes),
 		InboundMsgBuilder:  NewInboundBuilder(codec),
 		InternalMsgBuilder: NewInternalBuilder(),
 	}, nil
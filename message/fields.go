@@ -32,6 +32,10 @@ const (
 	VMMessage                        // Used internally
 	Uptime                           // Used for Pong
 	VersionStruct                    // Used internally
+	SummaryBytes                     // Used for state sync
+	SummaryHeights                   // Used for state sync
+	SummaryIDs                       // Used for state sync
+	CompressionTypes                 // Used for compression negotiation
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -73,6 +77,14 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackHashes
 	case Uptime:
 		return wrappers.TryPackByte
+	case SummaryBytes:
+		return wrappers.TryPackBytes
+	case SummaryHeights:
+		return wrappers.TryPackLongs
+	case SummaryIDs:
+		return wrappers.TryPackHashes
+	case CompressionTypes:
+		return wrappers.TryPackBytes
 	default:
 		return nil
 	}
@@ -117,6 +129,14 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackHashes
 	case Uptime:
 		return wrappers.TryUnpackByte
+	case SummaryBytes:
+		return wrappers.TryUnpackBytes
+	case SummaryHeights:
+		return wrappers.TryUnpackLongs
+	case SummaryIDs:
+		return wrappers.TryUnpackHashes
+	case CompressionTypes:
+		return wrappers.TryUnpackBytes
 	default:
 		return nil
 	}
@@ -164,6 +184,14 @@ func (f Field) String() string {
 		return "Uptime"
 	case VersionStruct:
 		return "VersionStruct"
+	case SummaryBytes:
+		return "SummaryBytes"
+	case SummaryHeights:
+		return "SummaryHeights"
+	case SummaryIDs:
+		return "SummaryIDs"
+	case CompressionTypes:
+		return "CompressionTypes"
 	default:
 		return "Unknown Field"
 	}
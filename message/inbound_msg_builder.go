@@ -105,6 +105,35 @@ type InboundMsgBuilder interface {
 		container []byte,
 		nodeID ids.ShortID,
 	) InboundMessage // used in UTs only
+
+	InboundGetStateSummaryFrontier(
+		chainID ids.ID,
+		requestID uint32,
+		deadline time.Duration,
+		nodeID ids.ShortID,
+	) InboundMessage
+
+	InboundStateSummaryFrontier(
+		chainID ids.ID,
+		requestID uint32,
+		summary []byte,
+		nodeID ids.ShortID,
+	) InboundMessage
+
+	InboundGetAcceptedStateSummary(
+		chainID ids.ID,
+		requestID uint32,
+		deadline time.Duration,
+		heights []uint64,
+		nodeID ids.ShortID,
+	) InboundMessage
+
+	InboundAcceptedStateSummary(
+		chainID ids.ID,
+		requestID uint32,
+		summaryIDs []ids.ID,
+		nodeID ids.ShortID,
+	) InboundMessage
 }
 
 type inMsgBuilder struct {
@@ -361,6 +390,82 @@ func (b *inMsgBuilder) InboundAncestors(
 	}
 }
 
+func (b *inMsgBuilder) InboundGetStateSummaryFrontier(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	nodeID ids.ShortID,
+) InboundMessage {
+	received := b.clock.Time()
+	return &inboundMessage{
+		op: GetStateSummaryFrontier,
+		fields: map[Field]interface{}{
+			ChainID:   chainID[:],
+			RequestID: requestID,
+			Deadline:  uint64(deadline),
+		},
+		nodeID:         nodeID,
+		expirationTime: received.Add(deadline),
+	}
+}
+
+func (b *inMsgBuilder) InboundStateSummaryFrontier(
+	chainID ids.ID,
+	requestID uint32,
+	summary []byte,
+	nodeID ids.ShortID,
+) InboundMessage {
+	return &inboundMessage{
+		op: StateSummaryFrontier,
+		fields: map[Field]interface{}{
+			ChainID:      chainID[:],
+			RequestID:    requestID,
+			SummaryBytes: summary,
+		},
+		nodeID: nodeID,
+	}
+}
+
+func (b *inMsgBuilder) InboundGetAcceptedStateSummary(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	heights []uint64,
+	nodeID ids.ShortID,
+) InboundMessage {
+	received := b.clock.Time()
+	return &inboundMessage{
+		op: GetAcceptedStateSummary,
+		fields: map[Field]interface{}{
+			ChainID:        chainID[:],
+			RequestID:      requestID,
+			Deadline:       uint64(deadline),
+			SummaryHeights: heights,
+		},
+		nodeID:         nodeID,
+		expirationTime: received.Add(deadline),
+	}
+}
+
+func (b *inMsgBuilder) InboundAcceptedStateSummary(
+	chainID ids.ID,
+	requestID uint32,
+	summaryIDs []ids.ID,
+	nodeID ids.ShortID,
+) InboundMessage {
+	summaryIDBytes := make([][]byte, len(summaryIDs))
+	encodeContainerIDs(summaryIDs, summaryIDBytes)
+	return &inboundMessage{
+		op: AcceptedStateSummary,
+		fields: map[Field]interface{}{
+			ChainID:    chainID[:],
+			RequestID:  requestID,
+			SummaryIDs: summaryIDBytes,
+		},
+		nodeID: nodeID,
+	}
+}
+
 func encodeContainerIDs(containerIDs []ids.ID, result [][]byte) {
 	for i, containerID := range containerIDs {
 		copy := containerID
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 )
 
@@ -82,6 +83,12 @@ func (inMsg *inboundMessage) String() string {
 		sb.WriteString(fmt.Sprintf(", ContainerID: 0x%x)", inMsg.fields[ContainerID].([]byte)))
 	case Ancestors:
 		sb.WriteString(fmt.Sprintf(", NumContainers: %d)", len(inMsg.fields[MultiContainerBytes].([][]byte))))
+	case StateSummaryFrontier:
+		sb.WriteString(fmt.Sprintf(", len(Summary): %d)", len(inMsg.fields[SummaryBytes].([]byte))))
+	case GetAcceptedStateSummary:
+		sb.WriteString(fmt.Sprintf(", NumHeights: %d)", len(inMsg.fields[SummaryHeights].([]uint64))))
+	case AcceptedStateSummary:
+		sb.WriteString(fmt.Sprintf(", NumSummaryIDs: %d)", len(inMsg.fields[SummaryIDs].([][]byte))))
 	case Notify:
 		sb.WriteString(fmt.Sprintf(", Notification: %d)", inMsg.fields[VMMessage].(uint32)))
 	case AppRequest, AppResponse, AppGossip:
@@ -97,6 +104,7 @@ func (inMsg *inboundMessage) String() string {
 // be serialized into a byte stream
 type OutboundMessage interface {
 	BytesSavedCompression() int
+	CompressionType() compression.Type
 	Bytes() []byte
 	Op() Op
 	BypassThrottling() bool
@@ -108,6 +116,7 @@ type OutboundMessage interface {
 type outboundMessage struct {
 	bytes                 []byte
 	bytesSavedCompression int
+	compressionType       compression.Type
 	op                    Op
 	bypassThrottling      bool
 
@@ -128,6 +137,9 @@ func (outMsg *outboundMessage) Bytes() []byte { return outMsg.bytes }
 // compressed.
 func (outMsg *outboundMessage) BytesSavedCompression() int { return outMsg.bytesSavedCompression }
 
+// CompressionType returns how the payload of this message is compressed
+func (outMsg *outboundMessage) CompressionType() compression.Type { return outMsg.compressionType }
+
 func (outMsg *outboundMessage) AddRef() {
 	outMsg.refLock.Lock()
 	defer outMsg.refLock.Unlock()
@@ -164,10 +176,11 @@ func NewTestMsg(op Op, bytes []byte, bypassThrottling bool) *TestMsg {
 	}
 }
 
-func (m *TestMsg) Op() Op                   { return m.op }
-func (*TestMsg) Get(Field) interface{}      { return nil }
-func (m *TestMsg) Bytes() []byte            { return m.bytes }
-func (*TestMsg) BytesSavedCompression() int { return 0 }
-func (*TestMsg) AddRef()                    {}
-func (*TestMsg) DecRef()                    {}
-func (m *TestMsg) BypassThrottling() bool   { return m.bypassThrottling }
+func (m *TestMsg) Op() Op                          { return m.op }
+func (*TestMsg) Get(Field) interface{}             { return nil }
+func (m *TestMsg) Bytes() []byte                   { return m.bytes }
+func (*TestMsg) BytesSavedCompression() int        { return 0 }
+func (*TestMsg) CompressionType() compression.Type { return compression.TypeNone }
+func (*TestMsg) AddRef()                           {}
+func (*TestMsg) DecRef()                           {}
+func (m *TestMsg) BypassThrottling() bool          { return m.bypassThrottling }
@@ -42,6 +42,13 @@ const (
 	AppRequest
 	AppResponse
 	AppGossip
+	// State sync:
+	GetStateSummaryFrontier
+	StateSummaryFrontier
+	GetAcceptedStateSummary
+	AcceptedStateSummary
+	// Compression negotiation:
+	Compressions
 
 	// Internal messages (External messages should be added above these):
 	GetAcceptedFrontierFailed
@@ -50,6 +57,8 @@ const (
 	QueryFailed
 	GetAncestorsFailed
 	AppRequestFailed
+	GetStateSummaryFrontierFailed
+	GetAcceptedStateSummaryFailed
 	Timeout
 	Connected
 	Disconnected
@@ -63,6 +72,7 @@ var (
 		PeerList,
 		Ping,
 		Pong,
+		Compressions,
 	}
 
 	// List of all consensus request message types
@@ -74,6 +84,8 @@ var (
 		PushQuery,
 		PullQuery,
 		AppRequest,
+		GetStateSummaryFrontier,
+		GetAcceptedStateSummary,
 	}
 	ConsensusResponseOps = []Op{
 		AcceptedFrontier,
@@ -82,6 +94,8 @@ var (
 		Put,
 		Chits,
 		AppResponse,
+		StateSummaryFrontier,
+		AcceptedStateSummary,
 	}
 	// AppGossip is the only message that is sent unrequested without the
 	// expectation of a response
@@ -99,6 +113,8 @@ var (
 		QueryFailed,
 		GetAncestorsFailed,
 		AppRequestFailed,
+		GetStateSummaryFrontierFailed,
+		GetAcceptedStateSummaryFailed,
 		Timeout,
 		Connected,
 		Disconnected,
@@ -121,11 +137,17 @@ var (
 		PushQuery,
 		PullQuery,
 		Chits,
+		GetStateSummaryFrontier,
+		StateSummaryFrontier,
+		GetAcceptedStateSummary,
+		AcceptedStateSummary,
 		GetAcceptedFrontierFailed,
 		GetAcceptedFailed,
 		GetFailed,
 		QueryFailed,
 		GetAncestorsFailed,
+		GetStateSummaryFrontierFailed,
+		GetAcceptedStateSummaryFailed,
 		Connected,
 		Disconnected,
 	}
@@ -145,6 +167,9 @@ var (
 		PushQuery:           Chits,
 		PullQuery:           Chits,
 		AppRequest:          AppResponse,
+
+		GetStateSummaryFrontier: StateSummaryFrontier,
+		GetAcceptedStateSummary: AcceptedStateSummary,
 	}
 	ResponseToFailedOps = map[Op]Op{
 		AcceptedFrontier: GetAcceptedFrontierFailed,
@@ -153,6 +178,9 @@ var (
 		Put:              GetFailed,
 		Chits:            QueryFailed,
 		AppResponse:      AppRequestFailed,
+
+		StateSummaryFrontier: GetStateSummaryFrontierFailed,
+		AcceptedStateSummary: GetAcceptedStateSummaryFailed,
 	}
 	FailedToResponseOps = map[Op]Op{
 		GetAcceptedFrontierFailed: AcceptedFrontier,
@@ -161,6 +189,9 @@ var (
 		GetFailed:                 Put,
 		QueryFailed:               Chits,
 		AppRequestFailed:          AppResponse,
+
+		GetStateSummaryFrontierFailed: StateSummaryFrontier,
+		GetAcceptedStateSummaryFailed: AcceptedStateSummary,
 	}
 	UnrequestedOps = map[Op]struct{}{
 		GetAcceptedFrontier: {},
@@ -171,6 +202,9 @@ var (
 		PullQuery:           {},
 		AppRequest:          {},
 		AppGossip:           {},
+
+		GetStateSummaryFrontier: {},
+		GetAcceptedStateSummary: {},
 	}
 
 	// Defines the messages that can be sent/received with this network
@@ -198,18 +232,32 @@ var (
 		AppRequest:  {ChainID, RequestID, Deadline, AppBytes},
 		AppResponse: {ChainID, RequestID, AppBytes},
 		AppGossip:   {ChainID, AppBytes},
+		// State sync:
+		GetStateSummaryFrontier: {ChainID, RequestID, Deadline},
+		StateSummaryFrontier:    {ChainID, RequestID, SummaryBytes},
+		GetAcceptedStateSummary: {ChainID, RequestID, Deadline, SummaryHeights},
+		AcceptedStateSummary:    {ChainID, RequestID, SummaryIDs},
+		// Compression negotiation:
+		Compressions: {CompressionTypes},
 	}
 )
 
 func (op Op) Compressible() bool {
 	switch op {
-	case PeerList, Put, Ancestors, PushQuery, AppRequest, AppResponse, AppGossip:
+	case PeerList, Put, Ancestors, PushQuery, AppRequest, AppResponse, AppGossip,
+		StateSummaryFrontier:
 		return true
 	default:
 		return false
 	}
 }
 
+// MarshalText returns the name of the op, so that it can be used as a JSON
+// map key
+func (op Op) MarshalText() ([]byte, error) {
+	return []byte(op.String()), nil
+}
+
 func (op Op) String() string {
 	switch op {
 	case Version:
@@ -248,6 +296,16 @@ func (op Op) String() string {
 		return "app_response"
 	case AppGossip:
 		return "app_gossip"
+	case GetStateSummaryFrontier:
+		return "get_state_summary_frontier"
+	case StateSummaryFrontier:
+		return "state_summary_frontier"
+	case GetAcceptedStateSummary:
+		return "get_accepted_state_summary"
+	case AcceptedStateSummary:
+		return "accepted_state_summary"
+	case Compressions:
+		return "compressions"
 
 	case GetAcceptedFrontierFailed:
 		return "get_accepted_frontier_failed"
@@ -261,6 +319,10 @@ func (op Op) String() string {
 		return "get_ancestors_failed"
 	case AppRequestFailed:
 		return "app_request_failed"
+	case GetStateSummaryFrontierFailed:
+		return "get_state_summary_frontier_failed"
+	case GetAcceptedStateSummaryFailed:
+		return "get_accepted_state_summary_failed"
 	case Timeout:
 		return "timeout"
 	case Connected:
@@ -8,6 +8,7 @@ import (
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 )
 
 var _ OutboundMsgBuilder = &outMsgBuilder{}
@@ -125,17 +126,74 @@ type OutboundMsgBuilder interface {
 		chainID ids.ID,
 		msg []byte,
 	) (OutboundMessage, error)
+
+	GetStateSummaryFrontier(
+		chainID ids.ID,
+		requestID uint32,
+		deadline time.Duration,
+	) (OutboundMessage, error)
+
+	StateSummaryFrontier(
+		chainID ids.ID,
+		requestID uint32,
+		summary []byte,
+	) (OutboundMessage, error)
+
+	GetAcceptedStateSummary(
+		chainID ids.ID,
+		requestID uint32,
+		deadline time.Duration,
+		heights []uint64,
+	) (OutboundMessage, error)
+
+	AcceptedStateSummary(
+		chainID ids.ID,
+		requestID uint32,
+		summaryIDs []ids.ID,
+	) (OutboundMessage, error)
+
+	Compressions(compressionTypes []compression.Type) (OutboundMessage, error)
+
+	// Recompress returns the bytes of [msg] with its payload compressed with
+	// [compressionType] instead.
+	Recompress(msg OutboundMessage, compressionType compression.Type) ([]byte, error)
 }
 
 type outMsgBuilder struct {
-	c        Codec
-	compress bool
+	c Codec
+	// compressionTypes is the compression type of each op. Ops that aren't
+	// in the map aren't compressed.
+	compressionTypes map[Op]compression.Type
 }
 
+// CompressAllOps returns the compression type of each compressible op if
+// they are all compressed with [compressionType]
+func CompressAllOps(compressionType compression.Type) map[Op]compression.Type {
+	compressionTypes := make(map[Op]compression.Type)
+	for _, op := range ExternalOps {
+		if op.Compressible() {
+			compressionTypes[op] = compressionType
+		}
+	}
+	return compressionTypes
+}
+
+// NewOutboundBuilder returns a builder that compresses all the compressible
+// messages with gzip if [enableCompression]
 func NewOutboundBuilder(c Codec, enableCompression bool) OutboundMsgBuilder {
+	compressionType := compression.TypeNone
+	if enableCompression {
+		compressionType = compression.TypeGzip
+	}
+	return NewOutboundBuilderWithCompression(c, CompressAllOps(compressionType))
+}
+
+// NewOutboundBuilderWithCompression returns a builder that compresses the
+// messages of each op in [compressionTypes] with its compression type
+func NewOutboundBuilderWithCompression(c Codec, compressionTypes map[Op]compression.Type) OutboundMsgBuilder {
 	return &outMsgBuilder{
-		c:        c,
-		compress: enableCompression,
+		c:                c,
+		compressionTypes: compressionTypes,
 	}
 }
 
@@ -165,7 +223,7 @@ func (b *outMsgBuilder) Version(
 			SigBytes:       sig,
 			TrackedSubnets: subnetIDBytes,
 		},
-		Version.Compressible(), // Version Messages can't be compressed
+		compression.TypeNone, // Version Messages can't be compressed
 		true,
 	)
 }
@@ -176,7 +234,7 @@ func (b *outMsgBuilder) PeerList(peers []utils.IPCertDesc, bypassThrottling bool
 		map[Field]interface{}{
 			Peers: peers,
 		},
-		b.compress && PeerList.Compressible(), // PeerList messages may be compressed
+		b.compressionTypes[PeerList], // PeerList messages may be compressed
 		bypassThrottling,
 	)
 }
@@ -185,7 +243,7 @@ func (b *outMsgBuilder) Ping() (OutboundMessage, error) {
 	return b.c.Pack(
 		Ping,
 		nil,
-		Ping.Compressible(), // Ping messages can't be compressed
+		compression.TypeNone, // Ping messages can't be compressed
 		false,
 	)
 }
@@ -196,7 +254,7 @@ func (b *outMsgBuilder) Pong(uptimePercentage uint8) (OutboundMessage, error) {
 		map[Field]interface{}{
 			Uptime: uptimePercentage,
 		},
-		Pong.Compressible(), // Pong messages can't be compressed
+		compression.TypeNone, // Pong messages can't be compressed
 		false,
 	)
 }
@@ -213,7 +271,7 @@ func (b *outMsgBuilder) GetAcceptedFrontier(
 			RequestID: requestID,
 			Deadline:  uint64(deadline),
 		},
-		GetAcceptedFrontier.Compressible(), // GetAcceptedFrontier messages can't be compressed
+		compression.TypeNone, // GetAcceptedFrontier messages can't be compressed
 		false,
 	)
 }
@@ -235,7 +293,7 @@ func (b *outMsgBuilder) AcceptedFrontier(
 			RequestID:    requestID,
 			ContainerIDs: containerIDBytes,
 		},
-		AcceptedFrontier.Compressible(), // AcceptedFrontier messages can't be compressed
+		compression.TypeNone, // AcceptedFrontier messages can't be compressed
 		false,
 	)
 }
@@ -259,7 +317,7 @@ func (b *outMsgBuilder) GetAccepted(
 			Deadline:     uint64(deadline),
 			ContainerIDs: containerIDBytes,
 		},
-		GetAccepted.Compressible(), // GetAccepted messages can't be compressed
+		compression.TypeNone, // GetAccepted messages can't be compressed
 		false,
 	)
 }
@@ -281,7 +339,7 @@ func (b *outMsgBuilder) Accepted(
 			RequestID:    requestID,
 			ContainerIDs: containerIDBytes,
 		},
-		Accepted.Compressible(), // Accepted messages can't be compressed
+		compression.TypeNone, // Accepted messages can't be compressed
 		false,
 	)
 }
@@ -300,7 +358,7 @@ func (b *outMsgBuilder) GetAncestors(
 			Deadline:    uint64(deadline),
 			ContainerID: containerID[:],
 		},
-		GetAncestors.Compressible(), // GetAncestors messages can't be compressed
+		compression.TypeNone, // GetAncestors messages can't be compressed
 		false,
 	)
 }
@@ -317,7 +375,7 @@ func (b *outMsgBuilder) Ancestors(
 			RequestID:           requestID,
 			MultiContainerBytes: containers,
 		},
-		b.compress && Ancestors.Compressible(), // Ancestors messages may be compressed
+		b.compressionTypes[Ancestors], // Ancestors messages may be compressed
 		false,
 	)
 }
@@ -336,7 +394,7 @@ func (b *outMsgBuilder) Get(
 			Deadline:    uint64(deadline),
 			ContainerID: containerID[:],
 		},
-		Get.Compressible(), // Get messages can't be compressed
+		compression.TypeNone, // Get messages can't be compressed
 		false,
 	)
 }
@@ -355,7 +413,7 @@ func (b *outMsgBuilder) Put(
 			ContainerID:    containerID[:],
 			ContainerBytes: container,
 		},
-		b.compress && Put.Compressible(), // Put messages may be compressed
+		b.compressionTypes[Put], // Put messages may be compressed
 		false,
 	)
 }
@@ -376,7 +434,7 @@ func (b *outMsgBuilder) PushQuery(
 			ContainerID:    containerID[:],
 			ContainerBytes: container,
 		},
-		b.compress && PushQuery.Compressible(), // PushQuery messages may be compressed
+		b.compressionTypes[PushQuery], // PushQuery messages may be compressed
 		false,
 	)
 }
@@ -395,7 +453,7 @@ func (b *outMsgBuilder) PullQuery(
 			Deadline:    uint64(deadline),
 			ContainerID: containerID[:],
 		},
-		PullQuery.Compressible(), // PullQuery messages can't be compressed
+		compression.TypeNone, // PullQuery messages can't be compressed
 		false,
 	)
 }
@@ -417,7 +475,7 @@ func (b *outMsgBuilder) Chits(
 			RequestID:    requestID,
 			ContainerIDs: containerIDBytes,
 		},
-		Chits.Compressible(), // Chits messages can't be compressed
+		compression.TypeNone, // Chits messages can't be compressed
 		false,
 	)
 }
@@ -437,7 +495,7 @@ func (b *outMsgBuilder) AppRequest(
 			Deadline:  uint64(deadline),
 			AppBytes:  msg,
 		},
-		b.compress && AppRequest.Compressible(), // App messages may be compressed
+		b.compressionTypes[AppRequest], // App messages may be compressed
 		false,
 	)
 }
@@ -451,7 +509,7 @@ func (b *outMsgBuilder) AppResponse(chainID ids.ID, requestID uint32, msg []byte
 			RequestID: requestID,
 			AppBytes:  msg,
 		},
-		b.compress && AppResponse.Compressible(), // App messages may be compressed
+		b.compressionTypes[AppResponse], // App messages may be compressed
 		false,
 	)
 }
@@ -464,7 +522,98 @@ func (b *outMsgBuilder) AppGossip(chainID ids.ID, msg []byte) (OutboundMessage,
 			ChainID:  chainID[:],
 			AppBytes: msg,
 		},
-		b.compress && AppGossip.Compressible(), // App messages may be compressed
+		b.compressionTypes[AppGossip], // App messages may be compressed
+		false,
+	)
+}
+
+func (b *outMsgBuilder) GetStateSummaryFrontier(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		GetStateSummaryFrontier,
+		map[Field]interface{}{
+			ChainID:   chainID[:],
+			RequestID: requestID,
+			Deadline:  uint64(deadline),
+		},
+		compression.TypeNone, // GetStateSummaryFrontier messages can't be compressed
+		false,
+	)
+}
+
+func (b *outMsgBuilder) StateSummaryFrontier(
+	chainID ids.ID,
+	requestID uint32,
+	summary []byte,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		StateSummaryFrontier,
+		map[Field]interface{}{
+			ChainID:      chainID[:],
+			RequestID:    requestID,
+			SummaryBytes: summary,
+		},
+		b.compressionTypes[StateSummaryFrontier], // StateSummaryFrontier messages may be compressed
 		false,
 	)
 }
+
+func (b *outMsgBuilder) GetAcceptedStateSummary(
+	chainID ids.ID,
+	requestID uint32,
+	deadline time.Duration,
+	heights []uint64,
+) (OutboundMessage, error) {
+	return b.c.Pack(
+		GetAcceptedStateSummary,
+		map[Field]interface{}{
+			ChainID:        chainID[:],
+			RequestID:      requestID,
+			Deadline:       uint64(deadline),
+			SummaryHeights: heights,
+		},
+		compression.TypeNone, // GetAcceptedStateSummary messages can't be compressed
+		false,
+	)
+}
+
+func (b *outMsgBuilder) AcceptedStateSummary(
+	chainID ids.ID,
+	requestID uint32,
+	summaryIDs []ids.ID,
+) (OutboundMessage, error) {
+	summaryIDBytes := make([][]byte, len(summaryIDs))
+	encodeContainerIDs(summaryIDs, summaryIDBytes)
+	return b.c.Pack(
+		AcceptedStateSummary,
+		map[Field]interface{}{
+			ChainID:    chainID[:],
+			RequestID:  requestID,
+			SummaryIDs: summaryIDBytes,
+		},
+		compression.TypeNone, // AcceptedStateSummary messages can't be compressed
+		false,
+	)
+}
+
+func (b *outMsgBuilder) Compressions(compressionTypes []compression.Type) (OutboundMessage, error) {
+	compressionTypeBytes := make([]byte, len(compressionTypes))
+	for i, compressionType := range compressionTypes {
+		compressionTypeBytes[i] = byte(compressionType)
+	}
+	return b.c.Pack(
+		Compressions,
+		map[Field]interface{}{
+			CompressionTypes: compressionTypeBytes,
+		},
+		compression.TypeNone, // Compressions messages can't be compressed
+		true,
+	)
+}
+
+func (b *outMsgBuilder) Recompress(msg OutboundMessage, compressionType compression.Type) ([]byte, error) {
+	return b.c.Recompress(msg, compressionType)
+}
@@ -0,0 +1,245 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+const (
+	nodeIDBanPrefix byte = iota
+	ipBanPrefix
+
+	// Maximum length of the reason of a ban
+	maxBanReasonLen = 1024
+)
+
+var (
+	errNoBanTarget        = errors.New("a ban must target either a node ID or an IP")
+	errAmbiguousBanTarget = errors.New("a ban can't target both a node ID and an IP")
+	errBanReasonTooLong   = errors.New("ban reason is too long")
+	errInvalidBanKey      = errors.New("invalid ban key")
+)
+
+// Ban prevents this node from connecting to a node ID, or to any peer at an
+// IP, until it expires.
+type Ban struct {
+	// NodeID is the banned node ID, or empty if an IP is banned
+	NodeID ids.ShortID
+	// IP is the banned IP, or nil if a node ID is banned
+	IP net.IP
+	// Expiry is when the ban is lifted. The zero time means never.
+	Expiry time.Time
+	// Reason is a description of why the ban was added
+	Reason string
+}
+
+// Verify returns nil iff the ban targets exactly one node ID or IP
+func (b *Ban) Verify() error {
+	switch {
+	case b.NodeID == ids.ShortEmpty && b.IP == nil:
+		return errNoBanTarget
+	case b.NodeID != ids.ShortEmpty && b.IP != nil:
+		return errAmbiguousBanTarget
+	case len(b.Reason) > maxBanReasonLen:
+		return fmt.Errorf("%w: %d > %d", errBanReasonTooLong, len(b.Reason), maxBanReasonLen)
+	default:
+		return nil
+	}
+}
+
+func (b *Ban) expired(now time.Time) bool {
+	return !b.Expiry.IsZero() && !now.Before(b.Expiry)
+}
+
+func (b *Ban) key() []byte {
+	if b.IP != nil {
+		return append([]byte{ipBanPrefix}, b.IP.To16()...)
+	}
+	return append([]byte{nodeIDBanPrefix}, b.NodeID[:]...)
+}
+
+func (b *Ban) value() []byte {
+	p := wrappers.Packer{MaxSize: wrappers.LongLen + wrappers.ShortLen + len(b.Reason)}
+	expiry := uint64(0)
+	if !b.Expiry.IsZero() {
+		expiry = uint64(b.Expiry.Unix())
+	}
+	p.PackLong(expiry)
+	p.PackStr(b.Reason)
+	return p.Bytes
+}
+
+func parseBan(key, value []byte) (*Ban, error) {
+	ban := &Ban{}
+	switch {
+	case len(key) == 1+len(ids.ShortEmpty) && key[0] == nodeIDBanPrefix:
+		copy(ban.NodeID[:], key[1:])
+	case len(key) == 1+net.IPv6len && key[0] == ipBanPrefix:
+		ban.IP = net.IP(utils.CopyBytes(key[1:]))
+	default:
+		return nil, fmt.Errorf("%w: 0x%x", errInvalidBanKey, key)
+	}
+
+	p := wrappers.Packer{Bytes: value}
+	if expiry := p.UnpackLong(); expiry != 0 {
+		ban.Expiry = time.Unix(int64(expiry), 0)
+	}
+	ban.Reason = p.UnpackStr()
+	return ban, p.Err
+}
+
+// banList is the set of bans of the network. Bans are written to [db], so that
+// they are kept across restarts.
+type banList struct {
+	db database.Database
+
+	lock    sync.RWMutex
+	nodeIDs map[ids.ShortID]*Ban
+	// Keyed by the 16 byte representation of the banned IP
+	ips map[string]*Ban
+}
+
+// newBanList returns the bans stored in [db] that didn't expire before [now].
+// The expired bans are deleted.
+func newBanList(db database.Database, now time.Time) (*banList, error) {
+	b := &banList{
+		db:      db,
+		nodeIDs: make(map[ids.ShortID]*Ban),
+		ips:     make(map[string]*Ban),
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	var expired [][]byte
+	for it.Next() {
+		key := it.Key()
+		ban, err := parseBan(key, it.Value())
+		if err != nil {
+			return nil, err
+		}
+		if ban.expired(now) {
+			expired = append(expired, utils.CopyBytes(key))
+			continue
+		}
+		b.set(ban)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	for _, key := range expired {
+		if err := db.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (b *banList) set(ban *Ban) {
+	if ban.IP != nil {
+		b.ips[string(ban.IP.To16())] = ban
+	} else {
+		b.nodeIDs[ban.NodeID] = ban
+	}
+}
+
+// add bans the target of [ban], replacing its previous ban if any
+func (b *banList) add(ban *Ban) error {
+	if err := ban.Verify(); err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.db.Put(ban.key(), ban.value()); err != nil {
+		return err
+	}
+	b.set(ban)
+	return nil
+}
+
+// remove lifts the ban of [nodeID], or of [ip] if it is non-nil. Returns false
+// if it wasn't banned.
+func (b *banList) remove(nodeID ids.ShortID, ip net.IP) (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	ban, ok := b.nodeIDs[nodeID]
+	if ip != nil {
+		ban, ok = b.ips[string(ip.To16())]
+	}
+	if !ok {
+		return false, nil
+	}
+	if err := b.db.Delete(ban.key()); err != nil {
+		return false, err
+	}
+	if ip != nil {
+		delete(b.ips, string(ip.To16()))
+	} else {
+		delete(b.nodeIDs, nodeID)
+	}
+	return true, nil
+}
+
+func (b *banList) nodeIDBanned(nodeID ids.ShortID, now time.Time) bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	ban, ok := b.nodeIDs[nodeID]
+	return ok && !ban.expired(now)
+}
+
+func (b *banList) ipBanned(ip net.IP, now time.Time) bool {
+	if ip == nil {
+		return false
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	ban, ok := b.ips[string(ip.To16())]
+	return ok && !ban.expired(now)
+}
+
+// list returns the bans that didn't expire before [now], node IDs first
+func (b *banList) list(now time.Time) []Ban {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	bans := make([]Ban, 0, len(b.nodeIDs)+len(b.ips))
+	for _, ban := range b.nodeIDs {
+		if !ban.expired(now) {
+			bans = append(bans, *ban)
+		}
+	}
+	numNodeIDs := len(bans)
+	for _, ban := range b.ips {
+		if !ban.expired(now) {
+			bans = append(bans, *ban)
+		}
+	}
+	nodeIDBans := bans[:numNodeIDs]
+	sort.Slice(nodeIDBans, func(i, j int) bool {
+		return bytes.Compare(nodeIDBans[i].NodeID[:], nodeIDBans[j].NodeID[:]) < 0
+	})
+	ipBans := bans[numNodeIDs:]
+	sort.Slice(ipBans, func(i, j int) bool {
+		return bytes.Compare(ipBans[i].IP.To16(), ipBans[j].IP.To16()) < 0
+	})
+	return bans
+}
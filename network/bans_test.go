@@ -0,0 +1,90 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+)
+
+func TestBanVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.ErrorIs((&Ban{}).Verify(), errNoBanTarget)
+	assert.ErrorIs((&Ban{
+		NodeID: ids.GenerateTestShortID(),
+		IP:     net.IPv4(1, 2, 3, 4),
+	}).Verify(), errAmbiguousBanTarget)
+	assert.ErrorIs((&Ban{
+		NodeID: ids.GenerateTestShortID(),
+		Reason: string(make([]byte, maxBanReasonLen+1)),
+	}).Verify(), errBanReasonTooLong)
+	assert.NoError((&Ban{IP: net.IPv4(1, 2, 3, 4)}).Verify())
+}
+
+func TestBanList(t *testing.T) {
+	assert := assert.New(t)
+
+	db := memdb.New()
+	now := time.Unix(1000, 0)
+	bans, err := newBanList(db, now)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	ip := net.IPv4(1, 2, 3, 4)
+	assert.NoError(bans.add(&Ban{
+		NodeID: nodeID,
+		Expiry: now.Add(time.Hour),
+		Reason: "spam",
+	}))
+	assert.NoError(bans.add(&Ban{IP: ip}))
+
+	assert.True(bans.nodeIDBanned(nodeID, now))
+	assert.False(bans.nodeIDBanned(ids.GenerateTestShortID(), now))
+	assert.True(bans.ipBanned(ip.To16(), now))
+	assert.True(bans.ipBanned(ip.To4(), now))
+	assert.False(bans.ipBanned(net.IPv4(1, 2, 3, 5), now))
+	assert.False(bans.ipBanned(nil, now))
+
+	// Bans are lifted once they expire
+	assert.False(bans.nodeIDBanned(nodeID, now.Add(time.Hour)))
+	assert.Len(bans.list(now.Add(time.Hour)), 1)
+
+	// Bans are kept across restarts
+	bans, err = newBanList(db, now)
+	assert.NoError(err)
+	list := bans.list(now)
+	assert.Len(list, 2)
+	assert.Equal(nodeID, list[0].NodeID)
+	assert.Nil(list[0].IP)
+	assert.Equal(now.Add(time.Hour), list[0].Expiry)
+	assert.Equal("spam", list[0].Reason)
+	assert.True(ip.Equal(list[1].IP))
+	assert.True(list[1].Expiry.IsZero())
+
+	// Expired bans are deleted on restart
+	bans, err = newBanList(db, now.Add(time.Hour))
+	assert.NoError(err)
+	assert.Len(bans.list(now), 1)
+	has, err := db.Has((&Ban{NodeID: nodeID}).key())
+	assert.NoError(err)
+	assert.False(has)
+
+	removed, err := bans.remove(ids.ShortEmpty, ip)
+	assert.NoError(err)
+	assert.True(removed)
+	removed, err = bans.remove(ids.ShortEmpty, ip)
+	assert.NoError(err)
+	assert.False(removed)
+	assert.False(bans.ipBanned(ip, now))
+	has, err = db.Has((&Ban{IP: ip}).key())
+	assert.NoError(err)
+	assert.False(has)
+}
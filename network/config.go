@@ -8,12 +8,16 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/flare-foundation/flare/database"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/network/fault"
 	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow/uptime"
 	"github.com/flare-foundation/flare/snow/validation"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 )
 
 // HealthConfig describes parameters for network layer health checks.
@@ -102,6 +106,14 @@ type Config struct {
 	DialerConfig dialer.Config `json:"dialerConfig"`
 	TLSConfig    *tls.Config   `json:"-"`
 
+	// FaultInjectionEnabled is true if faults can be injected in the
+	// connections with the peers through the admin API. It is only meant for
+	// tests.
+	FaultInjectionEnabled bool `json:"faultInjectionEnabled"`
+	// FaultInjector injects faults in the connections with the peers. If nil,
+	// no faults are injected.
+	FaultInjector fault.Injector `json:"-"`
+
 	Namespace          string              `json:"namespace"`
 	MyNodeID           ids.ShortID         `json:"myNodeID"`
 	MyIP               utils.DynamicIPDesc `json:"myIP"`
@@ -110,9 +122,10 @@ type Config struct {
 	PingFrequency      time.Duration       `json:"pingFrequency"`
 	AllowPrivateIPs    bool                `json:"allowPrivateIPs"`
 
-	// CompressionEnabled will compress available outbound messages when set to
-	// true.
-	CompressionEnabled bool `json:"compressionEnabled"`
+	// CompressionTypes is the compression type of the outbound messages of
+	// each op. The messages of the ops that aren't in the map aren't
+	// compressed.
+	CompressionTypes map[message.Op]compression.Type `json:"compressionTypes"`
 
 	// TLSKey is this node's TLS key that is used to sign IPs.
 	TLSKey crypto.Signer `json:"-"`
@@ -145,6 +158,10 @@ type Config struct {
 	// will be reset to this value.
 	MaximumInboundMessageTimeout time.Duration `json:"maximumInboundMessageTimeout"`
 
+	// BanDB is where the banned node IDs and IPs are kept across restarts. If
+	// nil, bans are only kept in memory.
+	BanDB database.Database `json:"-"`
+
 	// Size, in bytes, of the buffer that we read peer messages into
 	// (there is one buffer per peer)
 	PeerReadBufferSize int `json:"peerReadBufferSize"`
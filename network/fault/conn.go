@@ -0,0 +1,175 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fault
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+// Maximum number of messages in flight in each direction of a connection.
+// Once reached, the sender blocks until a message is delivered.
+const maxInFlightMsgs = 1024
+
+// faultConn is one end of a pipe, used in place of the wrapped connection. A
+// link forwards the messages between the other end of the pipe and the
+// wrapped connection.
+type faultConn struct {
+	net.Conn
+	localAddr, remoteAddr net.Addr
+}
+
+func (c *faultConn) LocalAddr() net.Addr { return c.localAddr }
+
+func (c *faultConn) RemoteAddr() net.Addr { return c.remoteAddr }
+
+// link forwards the messages between the wrapped connection [conn] and the
+// pipe end [pipe], with the faults of [nodeID] injected
+type link struct {
+	injector *injector
+	nodeID   ids.ShortID
+	conn     net.Conn
+	pipe     net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+type inFlightMsg struct {
+	msg       []byte
+	deliverAt time.Time
+}
+
+func (i *injector) wrapConn(nodeID ids.ShortID, conn net.Conn) net.Conn {
+	localPipe, remotePipe := net.Pipe()
+	l := &link{
+		injector: i,
+		nodeID:   nodeID,
+		conn:     conn,
+		pipe:     remotePipe,
+		closed:   make(chan struct{}),
+	}
+	go l.forward(remotePipe, conn)
+	go l.forward(conn, remotePipe)
+	return &faultConn{
+		Conn:       localPipe,
+		localAddr:  conn.LocalAddr(),
+		remoteAddr: conn.RemoteAddr(),
+	}
+}
+
+// forward reads the messages of [src] and forwards them to [dst] once their
+// delay elapsed, unless they are dropped. Closes the link when [src] can't be
+// read.
+func (l *link) forward(src io.Reader, dst io.Writer) {
+	defer l.close()
+
+	msgs := make(chan inFlightMsg, maxInFlightMsgs)
+	go l.deliver(msgs, dst)
+
+	reader := bufio.NewReader(src)
+	// Time the previous message is sent and delivered at. The messages are
+	// sent one after the other at the bandwidth of the link, and delivered in
+	// order.
+	var sentAt, deliveredAt time.Time
+	for {
+		msg, err := readMsg(reader)
+		if err != nil {
+			l.injector.log.Verbo("stopped forwarding messages of %s%s: %s",
+				constants.NodeIDPrefix, l.nodeID,
+				err,
+			)
+			return
+		}
+
+		fault := l.injector.fault(l.nodeID)
+		if fault.drop() {
+			continue
+		}
+
+		now := time.Now()
+		if sentAt.Before(now) {
+			sentAt = now
+		}
+		sentAt = sentAt.Add(fault.transmitTime(len(msg)))
+		deliverAt := sentAt.Add(fault.delay())
+		if deliverAt.Before(deliveredAt) {
+			deliverAt = deliveredAt
+		}
+		deliveredAt = deliverAt
+
+		select {
+		case msgs <- inFlightMsg{
+			msg:       msg,
+			deliverAt: deliverAt,
+		}:
+		case <-l.closed:
+			return
+		}
+	}
+}
+
+// deliver writes [msgs] to [dst] at their delivery time. Closes the link when
+// [dst] can't be written.
+func (l *link) deliver(msgs <-chan inFlightMsg, dst io.Writer) {
+	defer l.close()
+
+	for {
+		var msg inFlightMsg
+		select {
+		case msg = <-msgs:
+		case <-l.closed:
+			return
+		}
+
+		if delay := time.Until(msg.deliverAt); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-l.closed:
+				timer.Stop()
+				return
+			}
+		}
+
+		if _, err := dst.Write(msg.msg); err != nil {
+			return
+		}
+	}
+}
+
+func (l *link) close() {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		_ = l.conn.Close()
+		_ = l.pipe.Close()
+	})
+}
+
+// readMsg reads a message, and its length prefix, from [reader]
+func readMsg(reader io.Reader) ([]byte, error) {
+	msgLenBytes := make([]byte, wrappers.IntLen)
+	if _, err := io.ReadFull(reader, msgLenBytes); err != nil {
+		return nil, err
+	}
+	msgLen := binary.BigEndian.Uint32(msgLenBytes)
+	if msgLen > constants.DefaultMaxMessageSize {
+		return nil, errMessageTooLarge
+	}
+
+	msg := make([]byte, wrappers.IntLen+int(msgLen))
+	copy(msg, msgLenBytes)
+	if _, err := io.ReadFull(reader, msg[wrappers.IntLen:]); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
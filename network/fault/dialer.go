@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fault
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/utils"
+)
+
+var _ dialer.Dialer = &faultDialer{}
+
+type faultDialer struct {
+	dialer   dialer.Dialer
+	injector *injector
+}
+
+func (d *faultDialer) Dial(ctx context.Context, ip utils.IPDesc) (net.Conn, error) {
+	nodeID, ok := d.injector.nodeID(ip.String())
+	if !ok {
+		return d.dialer.Dial(ctx, ip)
+	}
+
+	fault := d.injector.fault(nodeID)
+	if fault.Partitioned {
+		return nil, fmt.Errorf("error while dialing %s: %w", ip, errPartitioned)
+	}
+
+	// Establishing a connection takes a round trip, so the message delay is
+	// applied both ways
+	timer := time.NewTimer(fault.delay() + fault.delay())
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+		return nil, ctx.Err()
+	}
+	return d.dialer.Dial(ctx, ip)
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fault
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+var (
+	errNegativeDelay   = errors.New("delay must be >= 0")
+	errNegativeJitter  = errors.New("jitter must be >= 0")
+	errInvalidDropRate = errors.New("drop rate must be in [0, 1]")
+	errPartitioned     = errors.New("partitioned from the node")
+	errMessageTooLarge = errors.New("message too large")
+)
+
+// Fault describes the faults injected in the traffic with a node. They apply
+// to each message sent to, or received from, the node.
+type Fault struct {
+	// Delay of each message
+	Delay time.Duration
+	// Jitter is the maximum random delay added to [Delay]. The messages are
+	// still delivered in order.
+	Jitter time.Duration
+	// DropRate is the probability, in [0, 1], that a message is dropped
+	DropRate float64
+	// Bandwidth is the maximum number of bytes sent per second in each
+	// direction. 0 means unlimited.
+	Bandwidth uint64
+	// Partitioned drops all the messages, and fails the dials to the node
+	Partitioned bool
+}
+
+// Verify returns an error if the fault is invalid
+func (f *Fault) Verify() error {
+	switch {
+	case f.Delay < 0:
+		return errNegativeDelay
+	case f.Jitter < 0:
+		return errNegativeJitter
+	case f.DropRate < 0 || f.DropRate > 1:
+		return errInvalidDropRate
+	default:
+		return nil
+	}
+}
+
+// delay returns the delay of a message: [Delay] plus a random jitter
+func (f *Fault) delay() time.Duration {
+	if f.Jitter <= 0 {
+		return f.Delay
+	}
+	// Faults are only injected in tests. This doesn't require
+	// cryptographically secure random number generation.
+	return f.Delay + time.Duration(rand.Int63n(int64(f.Jitter)+1)) // #nosec G404
+}
+
+// drop returns true if a message should be dropped
+func (f *Fault) drop() bool {
+	return f.Partitioned || (f.DropRate > 0 && rand.Float64() < f.DropRate) // #nosec G404
+}
+
+// transmitTime returns the time it takes to send [numBytes] at [Bandwidth]
+func (f *Fault) transmitTime(numBytes int) time.Duration {
+	if f.Bandwidth == 0 {
+		return 0
+	}
+	return time.Duration(uint64(numBytes) * uint64(time.Second) / f.Bandwidth)
+}
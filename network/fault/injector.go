@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fault
+
+import (
+	"net"
+	"sync"
+
+	"github.com/flare-foundation/flare/cache"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/utils/logging"
+)
+
+// Number of remote addresses whose node ID is remembered to fail the dials to
+// partitioned nodes
+const addrCacheSize = 1024
+
+var _ Injector = &injector{}
+
+// Injector injects faults in the connections with other nodes, to reproduce a
+// degraded network on a single machine. It is only meant for tests.
+//
+// The faults of a node can be changed at any time, and apply to the messages
+// handled after the change.
+type Injector interface {
+	// SetFault replaces the faults injected in the traffic with [nodeID]
+	SetFault(nodeID ids.ShortID, fault Fault) error
+
+	// RemoveFault stops injecting faults in the traffic with [nodeID]
+	RemoveFault(nodeID ids.ShortID)
+
+	// Partition drops all the traffic with [nodeIDs], in both directions,
+	// until they are healed. Their other faults are kept.
+	Partition(nodeIDs ...ids.ShortID)
+
+	// Heal ends the partition from [nodeIDs]
+	Heal(nodeIDs ...ids.ShortID)
+
+	// Faults returns the faults injected in the traffic with each node
+	Faults() map[ids.ShortID]Fault
+
+	// WrapConn returns [conn] with the faults of [nodeID] injected. [conn]
+	// must carry the messages of a peer, each prefixed with its 4 byte length.
+	WrapConn(nodeID ids.ShortID, conn net.Conn) net.Conn
+
+	// WrapDialer returns [dialer] with the dials delayed by a round trip of
+	// the faults of the dialed node, and failed right away if it is
+	// partitioned. The node is only known once a connection whose remote
+	// address is the dialed IP and port was wrapped, which is usually an
+	// earlier connection dialed to the node. Until then, the dials to the IP
+	// aren't affected.
+	WrapDialer(dialer dialer.Dialer) dialer.Dialer
+}
+
+type injector struct {
+	log logging.Logger
+
+	lock sync.RWMutex
+	// Node ID --> Faults injected in the traffic with the node
+	faults map[ids.ShortID]Fault
+	// Remote address --> Node ID of the wrapped connections
+	addrs cache.LRU
+}
+
+// NewInjector returns an Injector that doesn't inject any fault until they are
+// set
+func NewInjector(log logging.Logger) Injector {
+	return &injector{
+		log:    log,
+		faults: make(map[ids.ShortID]Fault),
+		addrs:  cache.LRU{Size: addrCacheSize},
+	}
+}
+
+func (i *injector) SetFault(nodeID ids.ShortID, fault Fault) error {
+	if err := fault.Verify(); err != nil {
+		return err
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.setFault(nodeID, fault)
+	return nil
+}
+
+func (i *injector) RemoveFault(nodeID ids.ShortID) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	delete(i.faults, nodeID)
+}
+
+func (i *injector) Partition(nodeIDs ...ids.ShortID) {
+	i.setPartitioned(nodeIDs, true)
+}
+
+func (i *injector) Heal(nodeIDs ...ids.ShortID) {
+	i.setPartitioned(nodeIDs, false)
+}
+
+func (i *injector) Faults() map[ids.ShortID]Fault {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	faults := make(map[ids.ShortID]Fault, len(i.faults))
+	for nodeID, fault := range i.faults {
+		faults[nodeID] = fault
+	}
+	return faults
+}
+
+func (i *injector) WrapConn(nodeID ids.ShortID, conn net.Conn) net.Conn {
+	i.addrs.Put(conn.RemoteAddr().String(), nodeID)
+
+	return i.wrapConn(nodeID, conn)
+}
+
+func (i *injector) WrapDialer(dialer dialer.Dialer) dialer.Dialer {
+	return &faultDialer{
+		dialer:   dialer,
+		injector: i,
+	}
+}
+
+// fault returns the faults injected in the traffic with [nodeID]
+func (i *injector) fault(nodeID ids.ShortID) Fault {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.faults[nodeID]
+}
+
+// nodeID returns the node ID of the wrapped connections with [addr]
+func (i *injector) nodeID(addr string) (ids.ShortID, bool) {
+	nodeIDIntf, ok := i.addrs.Get(addr)
+	if !ok {
+		return ids.ShortID{}, false
+	}
+	return nodeIDIntf.(ids.ShortID), true
+}
+
+func (i *injector) setPartitioned(nodeIDs []ids.ShortID, partitioned bool) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for _, nodeID := range nodeIDs {
+		fault := i.faults[nodeID]
+		fault.Partitioned = partitioned
+		i.setFault(nodeID, fault)
+	}
+}
+
+// setFault assumes [i.lock] is held
+func (i *injector) setFault(nodeID ids.ShortID, fault Fault) {
+	if fault == (Fault{}) {
+		delete(i.faults, nodeID)
+		return
+	}
+	i.faults[nodeID] = fault
+}
@@ -0,0 +1,272 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fault
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+var _ net.Conn = &testConn{}
+
+type testConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c *testConn) RemoteAddr() net.Addr { return c.remoteAddr }
+
+type testDialer struct {
+	dialed []utils.IPDesc
+}
+
+func (d *testDialer) Dial(_ context.Context, ip utils.IPDesc) (net.Conn, error) {
+	d.dialed = append(d.dialed, ip)
+	conn, _ := net.Pipe()
+	return conn, nil
+}
+
+func newMsg(payload string) []byte {
+	msg := make([]byte, wrappers.IntLen+len(payload))
+	binary.BigEndian.PutUint32(msg, uint32(len(payload)))
+	copy(msg[wrappers.IntLen:], payload)
+	return msg
+}
+
+// assertNoMsg asserts that no message is read from [conn] for a while
+func assertNoMsg(t *testing.T, conn net.Conn) {
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err := readMsg(conn)
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	assert.NoError(t, conn.SetReadDeadline(time.Time{}))
+}
+
+// newWrappedConn returns a connection to [nodeID] wrapped by [injector], and
+// the remote end of the connection
+func newWrappedConn(injector Injector, nodeID ids.ShortID) (net.Conn, net.Conn) {
+	conn, remoteConn := net.Pipe()
+	return injector.WrapConn(nodeID, conn), remoteConn
+}
+
+func TestFaultVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&Fault{Delay: time.Second, Jitter: time.Second, DropRate: 1}).Verify())
+	assert.ErrorIs((&Fault{Delay: -1}).Verify(), errNegativeDelay)
+	assert.ErrorIs((&Fault{Jitter: -1}).Verify(), errNegativeJitter)
+	assert.ErrorIs((&Fault{DropRate: 1.5}).Verify(), errInvalidDropRate)
+}
+
+func TestWrapConnForwardsMessages(t *testing.T) {
+	assert := assert.New(t)
+
+	injector := NewInjector(logging.NoLog{})
+	conn, remoteConn := newWrappedConn(injector, ids.GenerateTestShortID())
+
+	_, err := conn.Write(newMsg("ping"))
+	assert.NoError(err)
+	msg, err := readMsg(remoteConn)
+	assert.NoError(err)
+	assert.Equal(newMsg("ping"), msg)
+
+	_, err = remoteConn.Write(newMsg("pong"))
+	assert.NoError(err)
+	msg, err = readMsg(conn)
+	assert.NoError(err)
+	assert.Equal(newMsg("pong"), msg)
+
+	// Closing the wrapped connection closes the connection
+	assert.NoError(conn.Close())
+	_, err = readMsg(remoteConn)
+	assert.Error(err)
+}
+
+func TestWrapConnDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	injector := NewInjector(logging.NoLog{})
+	nodeID := ids.GenerateTestShortID()
+	conn, remoteConn := newWrappedConn(injector, nodeID)
+	assert.NoError(injector.SetFault(nodeID, Fault{
+		Delay:  100 * time.Millisecond,
+		Jitter: 10 * time.Millisecond,
+	}))
+
+	start := time.Now()
+	_, err := conn.Write(newMsg("ping"))
+	assert.NoError(err)
+	msg, err := readMsg(remoteConn)
+	assert.NoError(err)
+	assert.Equal(newMsg("ping"), msg)
+	assert.GreaterOrEqual(int64(time.Since(start)), int64(100*time.Millisecond))
+
+	// Received messages are delayed as well
+	start = time.Now()
+	go func() {
+		_, _ = remoteConn.Write(newMsg("pong"))
+	}()
+	msg, err = readMsg(conn)
+	assert.NoError(err)
+	assert.Equal(newMsg("pong"), msg)
+	assert.GreaterOrEqual(int64(time.Since(start)), int64(100*time.Millisecond))
+}
+
+func TestWrapConnBandwidth(t *testing.T) {
+	assert := assert.New(t)
+
+	injector := NewInjector(logging.NoLog{})
+	nodeID := ids.GenerateTestShortID()
+	conn, remoteConn := newWrappedConn(injector, nodeID)
+	assert.NoError(injector.SetFault(nodeID, Fault{
+		Bandwidth: 10 * 1024,
+	}))
+
+	// Sending 2 messages of 1 KiB at 10 KiB/s takes at least 200ms
+	payload := string(make([]byte, 1024))
+	start := time.Now()
+	go func() {
+		_, _ = conn.Write(newMsg(payload))
+		_, _ = conn.Write(newMsg(payload))
+	}()
+	for i := 0; i < 2; i++ {
+		msg, err := readMsg(remoteConn)
+		assert.NoError(err)
+		assert.Equal(newMsg(payload), msg)
+	}
+	assert.GreaterOrEqual(int64(time.Since(start)), int64(200*time.Millisecond))
+}
+
+func TestWrapConnDrop(t *testing.T) {
+	assert := assert.New(t)
+
+	injector := NewInjector(logging.NoLog{})
+	nodeID := ids.GenerateTestShortID()
+	conn, remoteConn := newWrappedConn(injector, nodeID)
+	assert.NoError(injector.SetFault(nodeID, Fault{
+		DropRate: 1,
+	}))
+
+	_, err := conn.Write(newMsg("dropped"))
+	assert.NoError(err)
+	assertNoMsg(t, remoteConn)
+
+	injector.RemoveFault(nodeID)
+	assert.Empty(injector.Faults())
+
+	_, err = conn.Write(newMsg("delivered"))
+	assert.NoError(err)
+	msg, err := readMsg(remoteConn)
+	assert.NoError(err)
+	assert.Equal(newMsg("delivered"), msg)
+}
+
+func TestPartition(t *testing.T) {
+	assert := assert.New(t)
+
+	injector := NewInjector(logging.NoLog{})
+	nodeID := ids.GenerateTestShortID()
+	conn, remoteConn := newWrappedConn(injector, nodeID)
+	fault := Fault{
+		Delay: time.Millisecond,
+	}
+	assert.NoError(injector.SetFault(nodeID, fault))
+
+	injector.Partition(nodeID)
+	assert.True(injector.Faults()[nodeID].Partitioned)
+
+	// The messages are dropped in both directions
+	_, err := conn.Write(newMsg("dropped"))
+	assert.NoError(err)
+	_, err = remoteConn.Write(newMsg("dropped"))
+	assert.NoError(err)
+	assertNoMsg(t, remoteConn)
+	assertNoMsg(t, conn)
+
+	// Healing keeps the other faults
+	injector.Heal(nodeID)
+	assert.Equal(map[ids.ShortID]Fault{nodeID: fault}, injector.Faults())
+
+	_, err = conn.Write(newMsg("ping"))
+	assert.NoError(err)
+	msg, err := readMsg(remoteConn)
+	assert.NoError(err)
+	assert.Equal(newMsg("ping"), msg)
+
+	go func() {
+		_, _ = remoteConn.Write(newMsg("pong"))
+	}()
+	msg, err = readMsg(conn)
+	assert.NoError(err)
+	assert.Equal(newMsg("pong"), msg)
+
+	// Healing a node without other faults removes it
+	injector.RemoveFault(nodeID)
+	injector.Partition(nodeID)
+	injector.Heal(nodeID)
+	assert.Empty(injector.Faults())
+}
+
+func TestWrapDialer(t *testing.T) {
+	assert := assert.New(t)
+
+	injector := NewInjector(logging.NoLog{})
+	dialer := &testDialer{}
+	faultDialer := injector.WrapDialer(dialer)
+
+	nodeID := ids.GenerateTestShortID()
+	ip := utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 9651,
+	}
+	injector.Partition(nodeID)
+
+	// The node ID of the IP isn't known yet
+	_, err := faultDialer.Dial(context.Background(), ip)
+	assert.NoError(err)
+	assert.Len(dialer.dialed, 1)
+
+	conn, _ := net.Pipe()
+	injector.WrapConn(nodeID, &testConn{
+		Conn: conn,
+		remoteAddr: &net.TCPAddr{
+			IP:   ip.IP,
+			Port: int(ip.Port),
+		},
+	})
+
+	_, err = faultDialer.Dial(context.Background(), ip)
+	assert.ErrorIs(err, errPartitioned)
+	assert.Len(dialer.dialed, 1)
+
+	injector.Heal(nodeID)
+	_, err = faultDialer.Dial(context.Background(), ip)
+	assert.NoError(err)
+	assert.Len(dialer.dialed, 2)
+
+	// The dials to a partitioned node fail without being delayed
+	assert.NoError(injector.SetFault(nodeID, Fault{Delay: time.Hour, Partitioned: true}))
+	_, err = faultDialer.Dial(context.Background(), ip)
+	assert.ErrorIs(err, errPartitioned)
+	assert.Len(dialer.dialed, 2)
+
+	// The dials take a round trip
+	delay := 50 * time.Millisecond
+	assert.NoError(injector.SetFault(nodeID, Fault{Delay: delay}))
+	start := time.Now()
+	_, err = faultDialer.Dial(context.Background(), ip)
+	assert.NoError(err)
+	assert.GreaterOrEqual(int64(time.Since(start)), int64(2*delay))
+	assert.Len(dialer.dialed, 3)
+}
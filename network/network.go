@@ -17,6 +17,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/flare-foundation/flare/api/health"
+	"github.com/flare-foundation/flare/database/memdb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network/dialer"
@@ -70,9 +71,36 @@ type Network interface {
 	WantsConnection(ids.ShortID) bool
 
 	// Attempt to connect to this IP. The network will never stop attempting to
-	// connect to this IP.
+	// connect to this IP, unless [nodeID] or the IP gets banned.
 	ManuallyTrack(nodeID ids.ShortID, ip utils.IPDesc)
 
+	// Disconnect closes the connection to [nodeID]. Returns false if this node
+	// isn't connected, or connecting, to [nodeID]. Note that if this node
+	// wants a connection to [nodeID], it will attempt to reconnect.
+	Disconnect(nodeID ids.ShortID) bool
+
+	// Ban prevents connections to the node ID or IP targeted by [ban] and
+	// closes the existing ones. If [duration] is positive, the ban expires
+	// after [duration], otherwise it never expires. The expiry of [ban] is
+	// ignored. Bans are kept across restarts.
+	Ban(ban Ban, duration time.Duration) error
+
+	// Unban lifts the ban of [nodeID], or of [ip] if it is non-nil. Returns
+	// false if it wasn't banned.
+	Unban(nodeID ids.ShortID, ip net.IP) (bool, error)
+
+	// Bans returns the bans that haven't expired
+	Bans() []Ban
+
+	// SetHealthConfig changes the thresholds of the health check. The send
+	// fail rate halflife can't be changed.
+	SetHealthConfig(config HealthConfig)
+
+	// SetInboundMsgThrottlerConfig changes the inbound bandwidth limits and
+	// the max number of messages processed at a time from a peer. The byte
+	// allocations can't be changed.
+	SetInboundMsgThrottlerConfig(config throttling.InboundMsgThrottlerConfig)
+
 	// PeerInfo returns information about peers. If [nodeIDs] is empty, returns
 	// info about all peers that have finished the handshake. Otherwise, returns
 	// info about the peers in [nodeIDs] that have finished the handshake.
@@ -114,6 +142,18 @@ type network struct {
 
 	sendFailRateCalculator math.Averager
 
+	healthConfigLock sync.RWMutex
+	// Thresholds of the health check, initially [config.HealthConfig]
+	healthConfig HealthConfig
+
+	// Node IDs and IPs that this node refuses to connect to
+	bans *banList
+
+	manuallyTrackedIDsLock sync.RWMutex
+	// Node IDs that this node was asked to connect to with [ManuallyTrack].
+	// This node wants a connection to them even if they aren't validators.
+	manuallyTrackedIDs ids.ShortSet
+
 	peersLock sync.RWMutex
 	// trackedIPs contains the set of IPs that we are currently attempting to
 	// connect to. An entry is added to this set when we first start attempting
@@ -203,6 +243,15 @@ func NewNetwork(
 		PongTimeout:          config.PingPongTimeout,
 		MaxClockDifference:   config.MaxClockDifference,
 	}
+	banDB := config.BanDB
+	if banDB == nil {
+		banDB = memdb.New()
+	}
+	bans, err := newBanList(banDB, peerConfig.Clock.Time())
+	if err != nil {
+		return nil, fmt.Errorf("initializing bans failed with: %w", err)
+	}
+
 	onCloseCtx, cancel := context.WithCancel(context.Background())
 	n := &network{
 		config:     config,
@@ -210,6 +259,8 @@ func NewNetwork(
 		metrics:    metrics,
 		ipSigner:   newIPSigner(&config.MyIP, &peerConfig.Clock, config.TLSKey),
 
+		healthConfig: config.HealthConfig,
+
 		inboundConnUpgradeThrottler: throttling.NewInboundConnUpgradeThrottler(log, config.ThrottlerConfig.InboundConnUpgradeThrottlerConfig),
 		listener:                    listener,
 		dialer:                      dialer,
@@ -224,6 +275,7 @@ func NewNetwork(
 			config.SendFailRateHalflife,
 			time.Now(),
 		)),
+		bans: bans,
 
 		trackedIPs:      make(map[ids.ShortID]*trackedIP),
 		connectingPeers: peer.NewSet(),
@@ -263,8 +315,12 @@ func (n *network) HealthCheck() (interface{}, error) {
 
 	sendFailRate := n.sendFailRateCalculator.Read()
 
+	n.healthConfigLock.RLock()
+	healthConfig := n.healthConfig
+	n.healthConfigLock.RUnlock()
+
 	// Make sure we're connected to at least the minimum number of peers
-	isConnected := connectedTo >= int(n.config.HealthConfig.MinConnectedPeers)
+	isConnected := connectedTo >= int(healthConfig.MinConnectedPeers)
 	healthy := isConnected
 	details := map[string]interface{}{
 		ConnectedPeersKey: connectedTo,
@@ -275,7 +331,7 @@ func (n *network) HealthCheck() (interface{}, error) {
 
 	lastMsgReceivedAt := time.Unix(atomic.LoadInt64(&n.peerConfig.LastReceived), 0)
 	timeSinceLastMsgReceived := now.Sub(lastMsgReceivedAt)
-	wasMsgReceivedRecently := timeSinceLastMsgReceived <= n.config.HealthConfig.MaxTimeSinceMsgReceived
+	wasMsgReceivedRecently := timeSinceLastMsgReceived <= healthConfig.MaxTimeSinceMsgReceived
 	healthy = healthy && wasMsgReceivedRecently
 	details[TimeSinceLastMsgReceivedKey] = timeSinceLastMsgReceived.String()
 	n.metrics.timeSinceLastMsgReceived.Set(float64(timeSinceLastMsgReceived))
@@ -283,13 +339,13 @@ func (n *network) HealthCheck() (interface{}, error) {
 	// Make sure we've sent an outgoing message within the threshold
 	lastMsgSentAt := time.Unix(atomic.LoadInt64(&n.peerConfig.LastSent), 0)
 	timeSinceLastMsgSent := now.Sub(lastMsgSentAt)
-	wasMsgSentRecently := timeSinceLastMsgSent <= n.config.HealthConfig.MaxTimeSinceMsgSent
+	wasMsgSentRecently := timeSinceLastMsgSent <= healthConfig.MaxTimeSinceMsgSent
 	healthy = healthy && wasMsgSentRecently
 	details[TimeSinceLastMsgSentKey] = timeSinceLastMsgSent.String()
 	n.metrics.timeSinceLastMsgSent.Set(float64(timeSinceLastMsgSent))
 
 	// Make sure the message send failed rate isn't too high
-	isMsgFailRate := sendFailRate <= n.config.HealthConfig.MaxSendFailRate
+	isMsgFailRate := sendFailRate <= healthConfig.MaxSendFailRate
 	healthy = healthy && isMsgFailRate
 	details[SendFailRateKey] = sendFailRate
 	n.metrics.sendFailRate.Set(sendFailRate)
@@ -298,16 +354,16 @@ func (n *network) HealthCheck() (interface{}, error) {
 	if !healthy {
 		var errorReasons []string
 		if !isConnected {
-			errorReasons = append(errorReasons, fmt.Sprintf("not connected to a minimum of %d peer(s) only %d", n.config.HealthConfig.MinConnectedPeers, connectedTo))
+			errorReasons = append(errorReasons, fmt.Sprintf("not connected to a minimum of %d peer(s) only %d", healthConfig.MinConnectedPeers, connectedTo))
 		}
 		if !wasMsgReceivedRecently {
-			errorReasons = append(errorReasons, fmt.Sprintf("no messages from network received in %s > %s", timeSinceLastMsgReceived, n.config.HealthConfig.MaxTimeSinceMsgReceived))
+			errorReasons = append(errorReasons, fmt.Sprintf("no messages from network received in %s > %s", timeSinceLastMsgReceived, healthConfig.MaxTimeSinceMsgReceived))
 		}
 		if !wasMsgSentRecently {
-			errorReasons = append(errorReasons, fmt.Sprintf("no messages from network sent in %s > %s", timeSinceLastMsgSent, n.config.HealthConfig.MaxTimeSinceMsgSent))
+			errorReasons = append(errorReasons, fmt.Sprintf("no messages from network sent in %s > %s", timeSinceLastMsgSent, healthConfig.MaxTimeSinceMsgSent))
 		}
 		if !isMsgFailRate {
-			errorReasons = append(errorReasons, fmt.Sprintf("messages failure send rate %g > %g", sendFailRate, n.config.HealthConfig.MaxSendFailRate))
+			errorReasons = append(errorReasons, fmt.Sprintf("messages failure send rate %g > %g", sendFailRate, healthConfig.MaxSendFailRate))
 		}
 
 		return details, fmt.Errorf("network layer is unhealthy reason: %s", strings.Join(errorReasons, ", "))
@@ -348,8 +404,11 @@ func (n *network) Connected(nodeID ids.ShortID) {
 // AllowConnection returns true if this node should have a connection to the
 // provided nodeID. If the node is attempting to connect to the minimum number
 // of peers, then it should only connect if this node is a validator, or the
-// peer is a validator/beacon.
+// peer is a validator/beacon. Connections to banned node IDs are never allowed.
 func (n *network) AllowConnection(nodeID ids.ShortID) bool {
+	if n.nodeIDBanned(nodeID) {
+		return false
+	}
 	return !n.config.RequireValidatorToConnect ||
 		n.config.Validators.Contains(n.config.MyNodeID) ||
 		n.WantsConnection(nodeID)
@@ -499,6 +558,15 @@ func (n *network) Dispatch() error {
 			break
 		}
 
+		if n.ipBanned(ip.IP) {
+			n.peerConfig.Log.Verbo(
+				"dropping connection from %s because the ip is banned",
+				ip,
+			)
+			_ = conn.Close()
+			continue
+		}
+
 		if !n.inboundConnUpgradeThrottler.ShouldUpgrade(ip) {
 			n.peerConfig.Log.Debug(
 				"not upgrading connection to %s due to rate-limiting",
@@ -531,11 +599,24 @@ func (n *network) Dispatch() error {
 }
 
 func (n *network) WantsConnection(nodeID ids.ShortID) bool {
-	return n.config.Validators.Contains(nodeID) ||
-		n.config.Beacons.Contains(nodeID)
+	if n.nodeIDBanned(nodeID) {
+		return false
+	}
+	if n.config.Validators.Contains(nodeID) || n.config.Beacons.Contains(nodeID) {
+		return true
+	}
+
+	n.manuallyTrackedIDsLock.RLock()
+	defer n.manuallyTrackedIDsLock.RUnlock()
+
+	return n.manuallyTrackedIDs.Contains(nodeID)
 }
 
 func (n *network) ManuallyTrack(nodeID ids.ShortID, ip utils.IPDesc) {
+	n.manuallyTrackedIDsLock.Lock()
+	n.manuallyTrackedIDs.Add(nodeID)
+	n.manuallyTrackedIDsLock.Unlock()
+
 	n.peersLock.Lock()
 	defer n.peersLock.Unlock()
 
@@ -558,6 +639,102 @@ func (n *network) ManuallyTrack(nodeID ids.ShortID, ip utils.IPDesc) {
 	}
 }
 
+func (n *network) Disconnect(nodeID ids.ShortID) bool {
+	n.peersLock.RLock()
+	defer n.peersLock.RUnlock()
+
+	peer, ok := n.connectingPeers.GetByID(nodeID)
+	if !ok {
+		peer, ok = n.connectedPeers.GetByID(nodeID)
+	}
+	if ok {
+		peer.StartClose()
+	}
+	return ok
+}
+
+func (n *network) Ban(ban Ban, duration time.Duration) error {
+	ban.Expiry = time.Time{}
+	if duration > 0 {
+		ban.Expiry = n.peerConfig.Clock.Time().Add(duration)
+	}
+	if err := n.bans.add(&ban); err != nil {
+		return err
+	}
+	n.peerConfig.Log.Info("banned %s", banTarget(&ban))
+
+	n.peersLock.Lock()
+	defer n.peersLock.Unlock()
+
+	if ban.IP == nil {
+		if tracked, ok := n.trackedIPs[ban.NodeID]; ok {
+			tracked.stopTracking()
+			delete(n.trackedIPs, ban.NodeID)
+		}
+		if peer, ok := n.connectingPeers.GetByID(ban.NodeID); ok {
+			peer.StartClose()
+		}
+		if peer, ok := n.connectedPeers.GetByID(ban.NodeID); ok {
+			peer.StartClose()
+		}
+		return nil
+	}
+
+	for nodeID, tracked := range n.trackedIPs {
+		if tracked.ip.IP.IP.Equal(ban.IP) {
+			tracked.stopTracking()
+			delete(n.trackedIPs, nodeID)
+		}
+	}
+	// The remote address of a peer is only known once it finished the
+	// handshake. Peers that are still connecting were already checked against
+	// the bans when their connection was accepted or dialed.
+	for i := 0; i < n.connectedPeers.Len(); i++ {
+		peer, _ := n.connectedPeers.GetByIndex(i)
+		ip, err := utils.ToIPDesc(peer.Info().IP)
+		if err == nil && ip.IP.Equal(ban.IP) {
+			peer.StartClose()
+		}
+	}
+	return nil
+}
+
+func (n *network) Unban(nodeID ids.ShortID, ip net.IP) (bool, error) {
+	return n.bans.remove(nodeID, ip)
+}
+
+func (n *network) Bans() []Ban {
+	return n.bans.list(n.peerConfig.Clock.Time())
+}
+
+func (n *network) SetHealthConfig(config HealthConfig) {
+	n.healthConfigLock.Lock()
+	defer n.healthConfigLock.Unlock()
+
+	config.SendFailRateHalflife = n.healthConfig.SendFailRateHalflife
+	n.healthConfig = config
+}
+
+func (n *network) SetInboundMsgThrottlerConfig(config throttling.InboundMsgThrottlerConfig) {
+	n.peerConfig.InboundMsgThrottler.SetLimits(config.BandwidthThrottlerConfig)
+	n.peerConfig.InboundMsgThrottler.SetMaxProcessingMsgsPerNode(config.MaxProcessingMsgsPerNode)
+}
+
+func (n *network) nodeIDBanned(nodeID ids.ShortID) bool {
+	return n.bans.nodeIDBanned(nodeID, n.peerConfig.Clock.Time())
+}
+
+func (n *network) ipBanned(ip net.IP) bool {
+	return n.bans.ipBanned(ip, n.peerConfig.Clock.Time())
+}
+
+func banTarget(ban *Ban) string {
+	if ban.IP != nil {
+		return fmt.Sprintf("ip %s", ban.IP)
+	}
+	return fmt.Sprintf("%s%s", constants.NodeIDPrefix, ban.NodeID)
+}
+
 func (n *network) sampleValidatorIPs() []utils.IPCertDesc {
 	n.peersLock.RLock()
 	peers := n.connectedPeers.Sample(
@@ -728,6 +905,15 @@ func (n *network) shouldTrack(nodeID ids.ShortID, ip utils.IPCertDesc) bool {
 		return false
 	}
 
+	if n.nodeIDBanned(nodeID) || n.ipBanned(ip.IPDesc.IP) {
+		n.peerConfig.Log.Verbo(
+			"dropping suggested connected to %s%s because the node or the ip (%s) is banned",
+			constants.NodeIDPrefix, nodeID,
+			ip.IPDesc,
+		)
+		return false
+	}
+
 	n.peersLock.RLock()
 	defer n.peersLock.RUnlock()
 
@@ -781,7 +967,7 @@ func (n *network) dial(ctx context.Context, nodeID ids.ShortID, ip *trackedIP) {
 			}
 
 			n.peersLock.Lock()
-			if !n.WantsConnection(nodeID) {
+			if !n.WantsConnection(nodeID) || n.ipBanned(ip.ip.IP.IP) {
 				// Typically [n.trackedIPs[nodeID]] will already equal [ip], but
 				// the reference to [ip] is refreshed to avoid any potential
 				// race conditions before removing the entry.
@@ -931,6 +1117,10 @@ func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
 		constants.NodeIDPrefix, nodeID,
 	)
 
+	if n.config.FaultInjector != nil {
+		tlsConn = n.config.FaultInjector.WrapConn(nodeID, tlsConn)
+	}
+
 	peer := peer.Start(n.peerConfig, tlsConn, cert, nodeID)
 	n.connectingPeers.Add(peer)
 	return nil
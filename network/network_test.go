@@ -15,11 +15,13 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/network/fault"
 	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/snow/uptime"
 	"github.com/flare-foundation/flare/snow/validation"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/units"
@@ -92,7 +94,7 @@ var (
 		PingFrequency:      constants.DefaultPingFrequency,
 		AllowPrivateIPs:    true,
 
-		CompressionEnabled: true,
+		CompressionTypes: message.CompressAllOps(compression.TypeGzip),
 
 		UptimeCalculator:  uptime.NewManager(uptime.NewTestState()),
 		UptimeMetricFreq:  30 * time.Second,
@@ -140,10 +142,18 @@ func newMessageCreator(t *testing.T) message.Creator {
 	return mc
 }
 
-func newFullyConnectedTestNetwork(t *testing.T, handlers []router.InboundHandler) ([]ids.ShortID, []Network, *sync.WaitGroup) {
+// newFullyConnectedTestNetwork returns a network of len([handlers]) nodes once
+// they are all connected. [configure] is applied to the config of each node
+// before it's created.
+func newFullyConnectedTestNetwork(t *testing.T, handlers []router.InboundHandler, configure ...func(*Config)) ([]ids.ShortID, []Network, *sync.WaitGroup) {
 	assert := assert.New(t)
 
 	dialer, listeners, nodeIDs, configs := newTestNetwork(t, len(handlers))
+	for _, config := range configs {
+		for _, f := range configure {
+			f(config)
+		}
+	}
 
 	beacons := validation.NewSet()
 	err := beacons.AddWeight(nodeIDs[0], 1)
@@ -283,3 +293,100 @@ func TestSend(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestFaultInjection(t *testing.T) {
+	assert := assert.New(t)
+
+	received := make(chan message.InboundMessage, 1)
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(
+		t,
+		[]router.InboundHandler{
+			router.InboundHandlerFunc(func(message.InboundMessage) {
+				t.Fatal("unexpected message received")
+			}),
+			router.InboundHandlerFunc(func(msg message.InboundMessage) {
+				received <- msg
+			}),
+		},
+		func(config *Config) {
+			config.FaultInjector = fault.NewInjector(logging.NoLog{})
+		},
+	)
+
+	net0 := networks[0]
+	injector := net0.(*network).config.FaultInjector
+
+	mc := newMessageCreator(t)
+	toSend := ids.ShortSet{}
+	toSend.Add(nodeIDs[1])
+	send := func(requestID uint32) {
+		outboundGetMsg, err := mc.Get(ids.Empty, requestID, time.Second, ids.Empty)
+		assert.NoError(err)
+		sentTo := net0.Send(outboundGetMsg, toSend, false)
+		assert.EqualValues(toSend, sentTo)
+	}
+
+	// The messages are delayed
+	delay := 100 * time.Millisecond
+	assert.NoError(injector.SetFault(nodeIDs[1], fault.Fault{Delay: delay}))
+	start := time.Now()
+	send(1)
+	inboundGetMsg := <-received
+	assert.Equal(message.Get, inboundGetMsg.Op())
+	assert.GreaterOrEqual(int64(time.Since(start)), int64(delay))
+	injector.RemoveFault(nodeIDs[1])
+
+	// The messages sent during a partition are lost
+	injector.Partition(nodeIDs[1])
+	send(2)
+	select {
+	case <-received:
+		t.Fatal("message received during a partition")
+	case <-time.After(delay):
+	}
+
+	injector.Heal(nodeIDs[1])
+	send(3)
+	inboundGetMsg = <-received
+	assert.EqualValues(3, inboundGetMsg.Get(message.RequestID))
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestBan(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil, nil})
+
+	net0 := networks[0]
+	assert.True(net0.AllowConnection(nodeIDs[1]))
+
+	assert.NoError(net0.Ban(Ban{NodeID: nodeIDs[1]}, 0))
+	assert.False(net0.AllowConnection(nodeIDs[1]))
+	assert.False(net0.WantsConnection(nodeIDs[1]))
+	assert.Equal([]Ban{{NodeID: nodeIDs[1]}}, net0.Bans())
+
+	unbanned, err := net0.Unban(nodeIDs[1], nil)
+	assert.NoError(err)
+	assert.True(unbanned)
+	assert.True(net0.AllowConnection(nodeIDs[1]))
+	assert.Empty(net0.Bans())
+
+	// The expiry is set from the duration of the ban
+	before := time.Now()
+	assert.NoError(net0.Ban(Ban{NodeID: nodeIDs[1], Expiry: before}, time.Hour))
+	after := time.Now()
+	bans := net0.Bans()
+	if assert.Len(bans, 1) {
+		assert.False(bans[0].Expiry.Before(before.Add(time.Hour)))
+		assert.False(bans[0].Expiry.After(after.Add(time.Hour)))
+	}
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
@@ -7,16 +7,19 @@ import (
 	"time"
 
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/json"
 )
 
 type Info struct {
-	IP             string     `json:"ip"`
-	PublicIP       string     `json:"publicIP,omitempty"`
-	ID             string     `json:"nodeID"`
-	Version        string     `json:"version"`
-	LastSent       time.Time  `json:"lastSent"`
-	LastReceived   time.Time  `json:"lastReceived"`
-	ObservedUptime json.Uint8 `json:"observedUptime"`
-	TrackedSubnets []ids.ID   `json:"trackedSubnets"`
+	IP               string             `json:"ip"`
+	PublicIP         string             `json:"publicIP,omitempty"`
+	ID               string             `json:"nodeID"`
+	Version          string             `json:"version"`
+	LastSent         time.Time          `json:"lastSent"`
+	LastReceived     time.Time          `json:"lastReceived"`
+	ObservedUptime   json.Uint8         `json:"observedUptime"`
+	TrackedSubnets   []ids.ID           `json:"trackedSubnets"`
+	CompressionTypes []compression.Type `json:"compressionTypes"`
+	Stats            Stats              `json:"stats"`
 }
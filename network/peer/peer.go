@@ -19,6 +19,7 @@ import (
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/formatting"
 	"github.com/flare-foundation/flare/utils/json"
@@ -61,6 +62,9 @@ type Peer interface {
 	// called after [Ready] returns true.
 	Info() Info
 
+	// Stats returns the traffic with this peer since the connection was made.
+	Stats() Stats
+
 	// IP returns the claimed IP and signature provided by this peer during the
 	// handshake. It should only be called after [Ready] returns true.
 	IP() *SignedIP
@@ -122,6 +126,12 @@ type peer struct {
 	// [observedUptimeLock] must be held while accessing [observedUptime]
 	observedUptime uint8
 
+	compressionTypesLock sync.RWMutex
+	// compressionTypes are the compressed types the peer can decompress. Peers
+	// that don't send a Compressions message can only decompress gzip.
+	// [compressionTypesLock] must be held while accessing [compressionTypes]
+	compressionTypes []compression.Type
+
 	// True if this peer has sent us a valid Version message and
 	// is running a compatible version.
 	// Only modified on the connection's reader routine.
@@ -165,6 +175,9 @@ type peer struct {
 	// Unix time of the last message sent and received respectively
 	// Must only be accessed atomically
 	lastSent, lastReceived int64
+
+	// Traffic with this peer, reported in [Info]
+	stats *stats
 }
 
 func Start(
@@ -184,6 +197,8 @@ func Start(
 		onClosed:          make(chan struct{}),
 		sendQueueCond:     sync.NewCond(&sync.Mutex{}),
 		canSend:           true,
+		compressionTypes:  []compression.Type{compression.TypeGzip},
+		stats:             newStats(),
 	}
 
 	p.trackedSubnets.Add(constants.PrimaryNetworkID)
@@ -193,6 +208,13 @@ func Start(
 	p.Log.AssertNoError(err)
 	p.Send(msg)
 
+	// The compression types are negotiated with a separate message, rather than
+	// in the Version message, because peers running older versions reject
+	// Version messages with unknown fields but drop unknown messages.
+	msg, err = p.MessageCreator.Compressions(compression.DecompressibleTypes)
+	p.Log.AssertNoError(err)
+	p.Send(msg)
+
 	go p.readMessages()
 	go p.writeMessages()
 	go p.sendPings()
@@ -237,17 +259,33 @@ func (p *peer) Info() Info {
 		publicIPStr = p.ip.IP.IP.String()
 	}
 	return Info{
-		IP:             p.conn.RemoteAddr().String(),
-		PublicIP:       publicIPStr,
-		ID:             p.id.PrefixedString(constants.NodeIDPrefix),
-		Version:        p.version.String(),
-		LastSent:       time.Unix(atomic.LoadInt64(&p.lastSent), 0),
-		LastReceived:   time.Unix(atomic.LoadInt64(&p.lastReceived), 0),
-		ObservedUptime: json.Uint8(p.ObservedUptime()),
-		TrackedSubnets: p.trackedSubnets.List(),
+		IP:               p.conn.RemoteAddr().String(),
+		PublicIP:         publicIPStr,
+		ID:               p.id.PrefixedString(constants.NodeIDPrefix),
+		Version:          p.version.String(),
+		LastSent:         time.Unix(atomic.LoadInt64(&p.lastSent), 0),
+		LastReceived:     time.Unix(atomic.LoadInt64(&p.lastReceived), 0),
+		ObservedUptime:   json.Uint8(p.ObservedUptime()),
+		TrackedSubnets:   p.trackedSubnets.List(),
+		CompressionTypes: p.getCompressionTypes(),
+		Stats:            p.Stats(),
 	}
 }
 
+// Stats returns the traffic with this peer since the connection was made
+func (p *peer) Stats() Stats {
+	stats := p.stats.get()
+
+	p.sendQueueCond.L.Lock()
+	defer p.sendQueueCond.L.Unlock()
+
+	stats.SendQueueMessages = json.Uint64(len(p.sendQueue))
+	for _, msg := range p.sendQueue {
+		stats.SendQueueBytes += json.Uint64(len(msg.Bytes()))
+	}
+	return stats
+}
+
 func (p *peer) IP() *SignedIP { return p.ip }
 
 func (p *peer) Version() version.Application { return p.version }
@@ -261,6 +299,27 @@ func (p *peer) ObservedUptime() uint8 {
 	return uptime
 }
 
+// getCompressionTypes returns the compressed types the peer can decompress
+func (p *peer) getCompressionTypes() []compression.Type {
+	p.compressionTypesLock.RLock()
+	defer p.compressionTypesLock.RUnlock()
+
+	return p.compressionTypes
+}
+
+// canDecompress returns true if the peer can decompress [compressionType]
+func (p *peer) canDecompress(compressionType compression.Type) bool {
+	if compressionType == compression.TypeNone {
+		return true
+	}
+	for _, peerCompressionType := range p.getCompressionTypes() {
+		if peerCompressionType == compressionType {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *peer) Send(msg message.OutboundMessage) bool {
 	// Acquire space on the outbound message queue, or drop [msg] if we can't.
 	if !p.OutboundMsgThrottler.Acquire(msg, p.id) {
@@ -270,6 +329,7 @@ func (p *peer) Send(msg message.OutboundMessage) bool {
 			constants.NodeIDPrefix, p.id,
 		)
 		p.Metrics.SendFailed(msg)
+		p.stats.outboundDropped()
 		return false
 	}
 
@@ -401,7 +461,9 @@ func (p *peer) readMessages() {
 		// Note that when we are done handling this message, or give up
 		// trying to read it, we must call [p.InboundMsgThrottler.Release]
 		// to give back the bytes used by this message.
+		startedWaiting := p.Clock.Time()
 		p.InboundMsgThrottler.Acquire(uint64(msgLen), p.id)
+		p.stats.throttled(p.Clock.Time().Sub(startedWaiting))
 
 		// Invariant: When done processing this message, onFinishedHandling() is
 		// called exactly once. If this is not honored, the message throttler
@@ -459,6 +521,7 @@ func (p *peer) readMessages() {
 		atomic.StoreInt64(&p.Config.LastReceived, now)
 		atomic.StoreInt64(&p.lastReceived, now)
 		p.Metrics.Received(msg, msgLen)
+		p.stats.received(msg.Op(), msgLen)
 
 		// Handle the message. Note that when we are done handling this message,
 		// we must call [msg.OnFinishedHandling()].
@@ -505,6 +568,24 @@ func (p *peer) writeMessages() {
 		}
 
 		msgBytes := msg.Bytes()
+		if compressionType := msg.CompressionType(); !p.canDecompress(compressionType) {
+			// Every peer can decompress gzip
+			recompressedBytes, err := p.MessageCreator.Recompress(msg, compression.TypeGzip)
+			if err != nil {
+				p.Log.Error(
+					"failed to recompress %s message from %s to %s for %s%s: %s",
+					msg.Op(),
+					compressionType,
+					compression.TypeGzip,
+					constants.NodeIDPrefix, p.id,
+					err,
+				)
+				p.OutboundMsgThrottler.Release(msg, p.id)
+				p.Metrics.SendFailed(msg)
+				continue
+			}
+			msgBytes = recompressedBytes
+		}
 		p.Log.Verbo(
 			"sending message to %s%s:\n%s",
 			constants.NodeIDPrefix, p.id,
@@ -545,6 +626,7 @@ func (p *peer) writeMessages() {
 		atomic.StoreInt64(&p.Config.LastSent, now)
 		atomic.StoreInt64(&p.lastSent, now)
 		p.Metrics.Sent(msg)
+		p.stats.sent(msg.Op(), len(msgBytes))
 	}
 }
 
@@ -623,7 +705,9 @@ func (p *peer) sendPings() {
 
 			msg, err := p.MessageCreator.Ping()
 			p.Log.AssertNoError(err)
-			p.Send(msg)
+			if p.Send(msg) {
+				p.stats.pingSent(p.Clock.Time())
+			}
 		case <-p.onClosing:
 			return
 		}
@@ -649,6 +733,10 @@ func (p *peer) handle(msg message.InboundMessage) {
 		p.handlePeerList(msg)
 		msg.OnFinishedHandling()
 		return
+	case message.Compressions:
+		p.handleCompressions(msg)
+		msg.OnFinishedHandling()
+		return
 	}
 	if !p.finishedHandshake.GetValue() {
 		p.Log.Debug(
@@ -671,6 +759,8 @@ func (p *peer) handlePing(_ message.InboundMessage) {
 }
 
 func (p *peer) handlePong(msg message.InboundMessage) {
+	p.stats.pongReceived(p.Clock.Time())
+
 	uptime := msg.Get(message.Uptime).(uint8)
 	if uptime > 100 {
 		return
@@ -837,6 +927,18 @@ func (p *peer) handlePeerList(msg message.InboundMessage) {
 	}
 }
 
+func (p *peer) handleCompressions(msg message.InboundMessage) {
+	compressionTypeBytes := msg.Get(message.CompressionTypes).([]byte)
+	compressionTypes := make([]compression.Type, len(compressionTypeBytes))
+	for i, compressionTypeByte := range compressionTypeBytes {
+		compressionTypes[i] = compression.Type(compressionTypeByte)
+	}
+
+	p.compressionTypesLock.Lock()
+	p.compressionTypes = compressionTypes
+	p.compressionTypesLock.Unlock()
+}
+
 func (p *peer) nextTimeout() time.Time {
 	return p.Clock.Time().Add(p.PongTimeout)
 }
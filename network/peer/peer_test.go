@@ -21,7 +21,9 @@ import (
 	"github.com/flare-foundation/flare/snow/validation"
 	"github.com/flare-foundation/flare/staking"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/compression"
 	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/json"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/version"
 )
@@ -251,3 +253,101 @@ func TestSend(t *testing.T) {
 	err = peer1.AwaitClosed(context.Background())
 	assert.NoError(err)
 }
+
+func TestStats(t *testing.T) {
+	assert := assert.New(t)
+
+	peer0, peer1 := makeReadyTestPeers(t)
+	mc := newMessageCreator(t)
+
+	outboundGetMsg, err := mc.Get(ids.Empty, 1, time.Second, ids.Empty)
+	assert.NoError(err)
+	numBytes := len(outboundGetMsg.Bytes())
+
+	sent := peer0.Send(outboundGetMsg)
+	assert.True(sent)
+
+	inboundGetMsg := <-peer1.inboundMsgChan
+	assert.Equal(message.Get, inboundGetMsg.Op())
+
+	getStats := func(stats Stats) OpStats {
+		for _, opStats := range stats.Ops {
+			if opStats.Op == message.Get.String() {
+				return opStats
+			}
+		}
+		return OpStats{}
+	}
+	stats0 := peer0.Stats()
+	assert.Equal(OpStats{
+		Op:           message.Get.String(),
+		MessagesSent: 1,
+		BytesSent:    json.Uint64(numBytes),
+	}, getStats(stats0))
+	assert.GreaterOrEqual(uint64(stats0.BytesSent), uint64(numBytes))
+
+	stats1 := peer1.Stats()
+	assert.Equal(OpStats{
+		Op:               message.Get.String(),
+		MessagesReceived: 1,
+		BytesReceived:    json.Uint64(numBytes),
+	}, getStats(stats1))
+	assert.GreaterOrEqual(uint64(stats1.MessagesReceived), uint64(1))
+
+	peer1.StartClose()
+	err = peer0.AwaitClosed(context.Background())
+	assert.NoError(err)
+	err = peer1.AwaitClosed(context.Background())
+	assert.NoError(err)
+}
+
+func TestCompressionNegotiation(t *testing.T) {
+	assert := assert.New(t)
+
+	peer0, peer1 := makeReadyTestPeers(t)
+	assert.Equal(compression.DecompressibleTypes, peer0.Info().CompressionTypes)
+	assert.Equal(compression.DecompressibleTypes, peer1.Info().CompressionTypes)
+
+	mc, err := message.NewCreatorWithCompression(
+		prometheus.NewRegistry(),
+		message.CompressAllOps(compression.TypeZstd),
+		"",
+		10*time.Second,
+	)
+	assert.NoError(err)
+
+	container := make([]byte, 1024)
+	outboundPutMsg, err := mc.Put(ids.Empty, 1, ids.Empty, container)
+	assert.NoError(err)
+	assert.Equal(compression.TypeZstd, outboundPutMsg.CompressionType())
+
+	sent := peer0.Send(outboundPutMsg)
+	assert.True(sent)
+
+	inboundPutMsg := <-peer1.inboundMsgChan
+	assert.Equal(message.Put, inboundPutMsg.Op())
+	assert.Equal(container, inboundPutMsg.Get(message.ContainerBytes))
+
+	// Peers that didn't send the compression types they support are sent gzip
+	rawPeer0 := peer0.Peer.(*peer)
+	rawPeer0.compressionTypesLock.Lock()
+	rawPeer0.compressionTypes = []compression.Type{compression.TypeGzip}
+	rawPeer0.compressionTypesLock.Unlock()
+
+	outboundPutMsg, err = mc.Put(ids.Empty, 2, ids.Empty, container)
+	assert.NoError(err)
+
+	sent = peer0.Send(outboundPutMsg)
+	assert.True(sent)
+
+	inboundPutMsg = <-peer1.inboundMsgChan
+	assert.Equal(message.Put, inboundPutMsg.Op())
+	assert.Equal(uint32(2), inboundPutMsg.Get(message.RequestID))
+	assert.Equal(container, inboundPutMsg.Get(message.ContainerBytes))
+
+	peer1.StartClose()
+	err = peer0.AwaitClosed(context.Background())
+	assert.NoError(err)
+	err = peer1.AwaitClosed(context.Background())
+	assert.NoError(err)
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/utils/json"
+)
+
+// OpStats are the number of messages of one op, and their size in bytes, sent
+// to and received from a peer
+type OpStats struct {
+	Op               string      `json:"op"`
+	MessagesSent     json.Uint64 `json:"messagesSent"`
+	BytesSent        json.Uint64 `json:"bytesSent"`
+	MessagesReceived json.Uint64 `json:"messagesReceived"`
+	BytesReceived    json.Uint64 `json:"bytesReceived"`
+}
+
+// Stats describe the traffic with a peer since the connection was made
+type Stats struct {
+	MessagesSent     json.Uint64 `json:"messagesSent"`
+	BytesSent        json.Uint64 `json:"bytesSent"`
+	MessagesReceived json.Uint64 `json:"messagesReceived"`
+	BytesReceived    json.Uint64 `json:"bytesReceived"`
+	// Ops are the stats of each op that was sent or received, ordered by op
+	Ops []OpStats `json:"ops"`
+
+	// Number of messages waiting to be sent, and their size in bytes
+	SendQueueMessages json.Uint64 `json:"sendQueueMessages"`
+	SendQueueBytes    json.Uint64 `json:"sendQueueBytes"`
+
+	// PingRTT is the time between the last ping sent to the peer and the pong
+	// it replied with. Zero if the peer didn't reply to a ping yet.
+	PingRTT time.Duration `json:"pingRTT"`
+
+	// InboundThrottleWait is the total time spent waiting for the inbound
+	// message throttler before reading messages from the peer, and
+	// LastInboundThrottleWait is the time spent waiting for the last message.
+	InboundThrottleWait     time.Duration `json:"inboundThrottleWait"`
+	LastInboundThrottleWait time.Duration `json:"lastInboundThrottleWait"`
+
+	// OutboundThrottled is the number of messages to the peer that were
+	// dropped by the outbound message throttler
+	OutboundThrottled json.Uint64 `json:"outboundThrottled"`
+}
+
+type opStats struct {
+	messagesSent, bytesSent, messagesReceived, bytesReceived uint64
+}
+
+// stats are gathered by a peer as it sends and receives messages
+type stats struct {
+	lock sync.Mutex
+	ops  map[message.Op]*opStats
+
+	lastPingSent            time.Time
+	pingRTT                 time.Duration
+	inboundThrottleWait     time.Duration
+	lastInboundThrottleWait time.Duration
+	outboundThrottled       uint64
+}
+
+func newStats() *stats {
+	return &stats{ops: make(map[message.Op]*opStats)}
+}
+
+func (s *stats) op(op message.Op) *opStats {
+	stats, ok := s.ops[op]
+	if !ok {
+		stats = &opStats{}
+		s.ops[op] = stats
+	}
+	return stats
+}
+
+func (s *stats) sent(op message.Op, numBytes int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.op(op)
+	stats.messagesSent++
+	stats.bytesSent += uint64(numBytes)
+}
+
+func (s *stats) received(op message.Op, numBytes uint32) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.op(op)
+	stats.messagesReceived++
+	stats.bytesReceived += uint64(numBytes)
+}
+
+func (s *stats) pingSent(now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.lastPingSent = now
+}
+
+func (s *stats) pongReceived(now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.lastPingSent.IsZero() {
+		s.pingRTT = now.Sub(s.lastPingSent)
+	}
+}
+
+func (s *stats) throttled(wait time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.inboundThrottleWait += wait
+	s.lastInboundThrottleWait = wait
+}
+
+func (s *stats) outboundDropped() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.outboundThrottled++
+}
+
+// get returns the stats gathered so far, except for the send queue
+func (s *stats) get() Stats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := Stats{
+		Ops:                     make([]OpStats, 0, len(s.ops)),
+		PingRTT:                 s.pingRTT,
+		InboundThrottleWait:     s.inboundThrottleWait,
+		LastInboundThrottleWait: s.lastInboundThrottleWait,
+		OutboundThrottled:       json.Uint64(s.outboundThrottled),
+	}
+	ops := make([]message.Op, 0, len(s.ops))
+	for op := range s.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i] < ops[j] })
+	for _, op := range ops {
+		opStats := s.ops[op]
+		stats.MessagesSent += json.Uint64(opStats.messagesSent)
+		stats.BytesSent += json.Uint64(opStats.bytesSent)
+		stats.MessagesReceived += json.Uint64(opStats.messagesReceived)
+		stats.BytesReceived += json.Uint64(opStats.bytesReceived)
+		stats.Ops = append(stats.Ops, OpStats{
+			Op:               op.String(),
+			MessagesSent:     json.Uint64(opStats.messagesSent),
+			BytesSent:        json.Uint64(opStats.bytesSent),
+			MessagesReceived: json.Uint64(opStats.messagesReceived),
+			BytesReceived:    json.Uint64(opStats.bytesReceived),
+		})
+	}
+	return stats
+}
@@ -47,6 +47,11 @@ type BandwidthThrottler interface {
 	// Must be called when we stop reading messages from [nodeID].
 	// It's safe for multiple goroutines to concurrently call RemoveNode.
 	RemoveNode(nodeID ids.ShortID)
+
+	// SetLimits changes the refill rate and the max burst size of the
+	// bandwidth allocation of every node, including the nodes already added.
+	// It's safe for multiple goroutines to concurrently call SetLimits.
+	SetLimits(config BandwidthThrottlerConfig)
 }
 
 type BandwidthThrottlerConfig struct {
@@ -146,3 +151,15 @@ func (t *bandwidthThrottler) RemoveNode(nodeID ids.ShortID) {
 	}
 	delete(t.limiters, nodeID)
 }
+
+// See BandwidthThrottler.
+func (t *bandwidthThrottler) SetLimits(config BandwidthThrottlerConfig) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.BandwidthThrottlerConfig = config
+	for _, limiter := range t.limiters {
+		limiter.SetLimit(rate.Limit(config.RefillRate))
+		limiter.SetBurst(int(config.MaxBurstSize))
+	}
+}
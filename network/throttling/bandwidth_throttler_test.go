@@ -58,3 +58,34 @@ func TestBandwidthThrottler(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestBandwidthThrottlerSetLimits(t *testing.T) {
+	assert := assert.New(t)
+	throttlerIntf, err := NewBandwidthThrottler(logging.NoLog{}, "", prometheus.NewRegistry(), BandwidthThrottlerConfig{
+		RefillRate:   8,
+		MaxBurstSize: 10,
+	})
+	assert.NoError(err)
+	throttler, ok := throttlerIntf.(*bandwidthThrottler)
+	assert.True(ok)
+
+	nodeID1 := ids.GenerateTestShortID()
+	throttler.AddNode(nodeID1)
+
+	config := BandwidthThrottlerConfig{
+		RefillRate:   16,
+		MaxBurstSize: 20,
+	}
+	throttler.SetLimits(config)
+	assert.Equal(config, throttler.BandwidthThrottlerConfig)
+
+	// Nodes already added get the new limits
+	assert.EqualValues(16, throttler.limiters[nodeID1].Limit())
+	assert.Equal(20, throttler.limiters[nodeID1].Burst())
+
+	// And so do the nodes added afterwards
+	nodeID2 := ids.GenerateTestShortID()
+	throttler.AddNode(nodeID2)
+	assert.EqualValues(16, throttler.limiters[nodeID2].Limit())
+	assert.Equal(20, throttler.limiters[nodeID2].Burst())
+}
@@ -106,6 +106,10 @@ func (t *inboundMsgBufferThrottler) Release(nodeID ids.ShortID) {
 		// We're not waiting to acquire for any messages from [nodeID]
 		return
 	}
+	if t.nodeToNumProcessingMsgs[nodeID] >= t.maxProcessingMsgsPerNode {
+		// The max was lowered while we were processing messages from [nodeID]
+		return
+	}
 	if len(waiting) > 0 {
 		waitingLongest := waiting[0]
 		t.nodeToNumProcessingMsgs[nodeID]++
@@ -120,6 +124,29 @@ func (t *inboundMsgBufferThrottler) Release(nodeID ids.ShortID) {
 	}
 }
 
+// SetMaxProcessingMsgsPerNode changes the max number of messages from a given
+// node that we process at a time. If it's raised, the messages waiting for
+// space on the inbound message buffer are let through, up to the new max.
+func (t *inboundMsgBufferThrottler) SetMaxProcessingMsgsPerNode(maxProcessingMsgsPerNode uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.maxProcessingMsgsPerNode = maxProcessingMsgsPerNode
+	for nodeID, waiting := range t.awaitingAcquire {
+		for len(waiting) > 0 && t.nodeToNumProcessingMsgs[nodeID] < maxProcessingMsgsPerNode {
+			t.nodeToNumProcessingMsgs[nodeID]++
+			close(waiting[0])
+			waiting[0] = nil
+			waiting = waiting[1:]
+		}
+		if len(waiting) == 0 {
+			delete(t.awaitingAcquire, nodeID)
+		} else {
+			t.awaitingAcquire[nodeID] = waiting
+		}
+	}
+}
+
 type inboundMsgBufferThrottlerMetrics struct {
 	acquireLatency  metric.Averager
 	awaitingAcquire prometheus.Gauge
@@ -91,3 +91,49 @@ func TestMsgBufferThrottler(t *testing.T) {
 	throttler.Release(nodeID1)
 	assert.Len(throttler.nodeToNumProcessingMsgs, 0)
 }
+
+// Test raising the max number of processing messages of an
+// inboundMsgBufferThrottler lets the waiting messages through
+func TestMsgBufferThrottlerSetMaxProcessingMsgsPerNode(t *testing.T) {
+	assert := assert.New(t)
+	throttler, err := newInboundMsgBufferThrottler("", prometheus.NewRegistry(), 1)
+	assert.NoError(err)
+
+	nodeID := ids.GenerateTestShortID()
+	throttler.Acquire(nodeID)
+
+	done := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			throttler.Acquire(nodeID)
+			done <- struct{}{}
+		}()
+	}
+	select {
+	case <-done:
+		t.Fatal("should block on acquiring")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Only one more message can be processed
+	throttler.SetMaxProcessingMsgsPerNode(2)
+	<-done
+	select {
+	case <-done:
+		t.Fatal("should be blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.EqualValues(2, throttler.nodeToNumProcessingMsgs[nodeID])
+	assert.Len(throttler.awaitingAcquire[nodeID], 1)
+
+	// Lowering the max doesn't affect the messages being processed
+	throttler.SetMaxProcessingMsgsPerNode(1)
+	throttler.Release(nodeID)
+	select {
+	case <-done:
+		t.Fatal("should be blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.EqualValues(1, throttler.nodeToNumProcessingMsgs[nodeID])
+	assert.Len(throttler.awaitingAcquire[nodeID], 1)
+}
@@ -26,6 +26,10 @@ type InboundMsgThrottler interface {
 	// Mark that we're done processing a message of size [msgSize]
 	// from [nodeID].
 	Release(msgSize uint64, nodeID ids.ShortID)
+
+	// SetMaxProcessingMsgsPerNode changes the max number of messages from a
+	// given node that we process at a time
+	SetMaxProcessingMsgsPerNode(maxProcessingMsgsPerNode uint64)
 }
 
 type InboundMsgThrottlerConfig struct {
@@ -129,3 +133,13 @@ func (t *inboundMsgThrottler) AddNode(nodeID ids.ShortID) {
 func (t *inboundMsgThrottler) RemoveNode(nodeID ids.ShortID) {
 	t.bandwidthThrottler.RemoveNode(nodeID)
 }
+
+// See BandwidthThrottler.
+func (t *inboundMsgThrottler) SetLimits(config BandwidthThrottlerConfig) {
+	t.bandwidthThrottler.SetLimits(config)
+}
+
+// See InboundMsgThrottler.
+func (t *inboundMsgThrottler) SetMaxProcessingMsgsPerNode(maxProcessingMsgsPerNode uint64) {
+	t.bufferThrottler.SetMaxProcessingMsgsPerNode(maxProcessingMsgsPerNode)
+}
@@ -24,3 +24,7 @@ func (*noInboundMsgThrottler) Release(uint64, ids.ShortID) {}
 func (*noInboundMsgThrottler) AddNode(ids.ShortID) {}
 
 func (*noInboundMsgThrottler) RemoveNode(ids.ShortID) {}
+
+func (*noInboundMsgThrottler) SetLimits(BandwidthThrottlerConfig) {}
+
+func (*noInboundMsgThrottler) SetMaxProcessingMsgsPerNode(uint64) {}
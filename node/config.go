@@ -7,15 +7,19 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/flare-foundation/flare/api/health"
 	"github.com/flare-foundation/flare/chains"
 	"github.com/flare-foundation/flare/genesis"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/indexer"
 	"github.com/flare-foundation/flare/nat"
 	"github.com/flare-foundation/flare/network"
 	"github.com/flare-foundation/flare/snow/consensus/avalanche"
 	"github.com/flare-foundation/flare/snow/networking/benchlist"
+	"github.com/flare-foundation/flare/snow/networking/handler"
 	"github.com/flare-foundation/flare/snow/networking/router"
 	"github.com/flare-foundation/flare/snow/networking/sender"
+	"github.com/flare-foundation/flare/snow/validation"
 	"github.com/flare-foundation/flare/utils"
 	"github.com/flare-foundation/flare/utils/dynamicip"
 	"github.com/flare-foundation/flare/utils/logging"
@@ -28,6 +32,11 @@ type IPCConfig struct {
 	IPCAPIEnabled      bool     `json:"ipcAPIEnabled"`
 	IPCPath            string   `json:"ipcPath"`
 	IPCDefaultChainIDs []string `json:"ipcDefaultChainIDs"`
+	// If non-empty, the address at which IPCs are also served over TLS
+	IPCTLSAddress string `json:"ipcTLSAddress"`
+	// PEM encoded certificate authorities that sign the certificates of the
+	// clients allowed to subscribe to IPCs over TLS
+	IPCTLSClientCA []byte `json:"-"`
 }
 
 type APIAuthConfig struct {
@@ -38,6 +47,14 @@ type APIAuthConfig struct {
 type APIIndexerConfig struct {
 	IndexAPIEnabled      bool `json:"indexAPIEnabled"`
 	IndexAllowIncomplete bool `json:"indexAllowIncomplete"`
+	// IDs or aliases of the chains to index. If empty, all chains are indexed.
+	IndexIncludedChains []string `json:"indexIncludedChains"`
+	// IDs or aliases of the chains not to index
+	IndexExcludedChains []string `json:"indexExcludedChains"`
+	// Retention of the indices of the chains that aren't in [IndexChainRetention]
+	IndexRetention indexer.RetentionPolicy `json:"indexRetention"`
+	// ID or alias of a chain --> retention of the indices of that chain
+	IndexChainRetention map[string]indexer.RetentionPolicy `json:"indexChainRetention"`
 }
 
 type HTTPConfig struct {
@@ -61,11 +78,12 @@ type APIConfig struct {
 	IPCConfig        `json:"ipcConfig"`
 
 	// Enable/Disable APIs
-	AdminAPIEnabled    bool `json:"adminAPIEnabled"`
-	InfoAPIEnabled     bool `json:"infoAPIEnabled"`
-	KeystoreAPIEnabled bool `json:"keystoreAPIEnabled"`
-	MetricsAPIEnabled  bool `json:"metricsAPIEnabled"`
-	HealthAPIEnabled   bool `json:"healthAPIEnabled"`
+	AdminAPIEnabled      bool `json:"adminAPIEnabled"`
+	InfoAPIEnabled       bool `json:"infoAPIEnabled"`
+	KeystoreAPIEnabled   bool `json:"keystoreAPIEnabled"`
+	MetricsAPIEnabled    bool `json:"metricsAPIEnabled"`
+	HealthAPIEnabled     bool `json:"healthAPIEnabled"`
+	ValidatorsAPIEnabled bool `json:"validatorsAPIEnabled"`
 }
 
 type IPConfig struct {
@@ -149,6 +167,7 @@ type Config struct {
 
 	// Health
 	HealthCheckFreq time.Duration `json:"healthCheckFreq"`
+	HealthConfig    health.Config `json:"healthConfig"`
 
 	// Network configuration
 	NetworkConfig network.Config `json:"networkConfig"`
@@ -184,6 +203,8 @@ type Config struct {
 	ConsensusShutdownTimeout time.Duration       `json:"consensusShutdownTimeout"`
 	// Gossip a container in the accepted frontier every [ConsensusGossipFrequency]
 	ConsensusGossipFrequency time.Duration `json:"consensusGossipFreq"`
+	// Share of the handlers' time given to each class of consensus messages
+	ConsensusMessageClassWeights map[handler.MessageClass]uint64 `json:"consensusMessageClassWeights"`
 
 	// Subnet Whitelist
 	WhitelistedSubnets ids.Set `json:"whitelistedSubnets"`
@@ -197,6 +218,18 @@ type Config struct {
 	// VM management
 	VMManager vms.Manager `json:"-"`
 
+	// Validators of the network if the EVM doesn't provide them
+	ValidatorsSchedule *validation.Schedule `json:"-"`
+
 	// Reset proposerVM height index
 	ResetProposerVMHeightIndex bool `json:"resetProposerVMHeightIndex"`
+
+	// ConfigReloader re-reads the config when it's reloaded. Nil if the config
+	// can't be reloaded.
+	ConfigReloader ConfigReloader `json:"-"`
 }
+
+// ConfigReloader returns the config the node would be started with now. Also
+// returns the keys that changed since the node started, split between the ones
+// the node can apply while it's running and the ones that require a restart.
+type ConfigReloader func() (config Config, reloaded []string, requireRestart []string, err error)
@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"github.com/flare-foundation/flare/api/keystore"
+	"github.com/flare-foundation/flare/chains"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/genesis"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/indexer"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/vms/platformvm"
+	"github.com/flare-foundation/flare/vms/proposervm"
+)
+
+// evmDBPrefixes are the prefixes of the databases of the EVM by name. The EVM
+// wraps its database in a versiondb.
+var evmDBPrefixes = []struct {
+	name   string
+	prefix []byte
+}{
+	{name: "evm/ethdb", prefix: []byte("ethdb")},
+	{name: "evm/accepted blocks", prefix: []byte("snowman_accepted")},
+	{name: "evm/metadata", prefix: []byte("metadata")},
+}
+
+// DatabaseLayout returns the layout of the node's database [db], which holds
+// the databases of [chainInfos]. The keys of a chain are reported under the
+// name returned by [chainName].
+func DatabaseLayout(
+	db database.Database,
+	chainInfos []chains.ChainInfo,
+	chainName func(ids.ID) string,
+) (*inspect.Layout, error) {
+	layout := inspect.NewLayout()
+	root := inspect.Prefix{}
+
+	indexerDB := root.New(indexerDBPrefix)
+	layout.Add("", "indexer", indexerDB)
+	layout.Add("", "shared memory", root.New(sharedMemoryDBPrefix))
+	layout.Add("", "validator journal", root.New(validatorJournalDBPrefix))
+	layout.Add("", "network bans", root.New(networkBansDBPrefix))
+	if err := keystore.AddLayout(layout, root.New(keystoreDBPrefix), prefixdb.New(keystoreDBPrefix, db)); err != nil {
+		return nil, err
+	}
+
+	for _, info := range chainInfos {
+		chain := chainName(info.ID)
+		indexer.AddLayout(layout, indexerDB, chain, info.ID, info.DAG)
+		vmDB := chains.AddLayout(layout, root, info, chain)
+		if info.DAG {
+			continue
+		}
+
+		// Every linear chain runs in a proposervm, which gives its database to
+		// the inner VM as is
+		proposervm.AddLayout(layout, chain, vmDB)
+		switch info.VMID {
+		case constants.PlatformVMID:
+			platformvm.AddLayout(layout, chain, vmDB)
+		case constants.EVMID:
+			evmDB := vmDB.Wrap()
+			for _, evmDBPrefix := range evmDBPrefixes {
+				layout.Add(chain, evmDBPrefix.name, evmDB.New(evmDBPrefix.prefix))
+			}
+		}
+	}
+	return layout, nil
+}
+
+// GenesisChains returns the chains created by [genesisBytes], including the
+// P-Chain, and their primary aliases.
+func GenesisChains(genesisBytes []byte) ([]chains.ChainInfo, map[ids.ID]string, error) {
+	gen := &platformvm.Genesis{}
+	if _, err := platformvm.GenesisCodec.Unmarshal(genesisBytes, gen); err != nil {
+		return nil, nil, err
+	}
+	if err := gen.Initialize(); err != nil {
+		return nil, nil, err
+	}
+	_, chainAliases, err := genesis.Aliases(genesisBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chainInfos := []chains.ChainInfo{{
+		ID:       constants.PlatformChainID,
+		SubnetID: constants.PrimaryNetworkID,
+		VMID:     constants.PlatformVMID,
+	}}
+	for _, chain := range gen.Chains {
+		tx := chain.UnsignedTx.(*platformvm.UnsignedCreateChainTx)
+		chainInfos = append(chainInfos, chains.ChainInfo{
+			ID:       chain.ID(),
+			SubnetID: tx.SubnetID,
+			VMID:     tx.VMID,
+			DAG:      tx.VMID == constants.AVMID,
+		})
+	}
+
+	names := make(map[ids.ID]string, len(chainInfos))
+	for _, info := range chainInfos {
+		names[info.ID] = info.ID.String()
+		if aliases := chainAliases[info.ID]; len(aliases) > 0 {
+			names[info.ID] = aliases[0]
+		}
+	}
+	return chainInfos, names, nil
+}
@@ -5,6 +5,8 @@ package node
 
 import (
 	"crypto"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,6 +31,7 @@ import (
 	"github.com/flare-foundation/flare/chains"
 	"github.com/flare-foundation/flare/chains/atomic"
 	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/inspect"
 	"github.com/flare-foundation/flare/database/manager"
 	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/genesis"
@@ -38,6 +41,7 @@ import (
 	"github.com/flare-foundation/flare/message"
 	"github.com/flare-foundation/flare/network"
 	"github.com/flare-foundation/flare/network/dialer"
+	"github.com/flare-foundation/flare/network/fault"
 	"github.com/flare-foundation/flare/network/peer"
 	"github.com/flare-foundation/flare/network/throttling"
 	"github.com/flare-foundation/flare/snow/engine/common"
@@ -65,18 +69,25 @@ import (
 	"github.com/flare-foundation/flare/vms/secp256k1fx"
 
 	ipcsapi "github.com/flare-foundation/flare/api/ipcs"
+	validatorsapi "github.com/flare-foundation/flare/api/validators"
 )
 
 var (
-	genesisHashKey  = []byte("genesisID")
-	indexerDBPrefix = []byte{0x00}
+	genesisHashKey           = []byte("genesisID")
+	indexerDBPrefix          = []byte{0x00}
+	sharedMemoryDBPrefix     = []byte("shared memory")
+	validatorJournalDBPrefix = []byte("validator journal")
+	keystoreDBPrefix         = []byte("keystore")
+	networkBansDBPrefix      = []byte("network bans")
 
 	errInvalidTLSKey   = errors.New("invalid TLS key")
+	errInvalidClientCA = errors.New("invalid IPC TLS client CA")
 	errPNotCreated     = errors.New("P-Chain not created")
 	errXNotCreated     = errors.New("X-Chain not created")
 	errCNotCreated     = errors.New("C-Chain not created")
 	errNotBootstrapped = errors.New("primary subnet has not finished bootstrapping")
 	errShuttingDown    = errors.New("server shutting down")
+	errNotReloadable   = errors.New("config can't be reloaded")
 )
 
 // Node is an instance of an Avalanche node.
@@ -93,8 +104,18 @@ type Node struct {
 	DBManager manager.Manager
 	DB        database.Database
 
+	// [profilerLock] must be held when accessing [profiler], [profilerDone]
+	// and [profilerConfig]
+	profilerLock sync.Mutex
 	// Profiles the process. Nil if continuous profiling is disabled.
 	profiler profiler.ContinuousProfiler
+	// Closed when [profiler] stops
+	profilerDone chan struct{}
+	// Config [profiler] was started with
+	profilerConfig profiler.Config
+
+	// Held while the config is reloaded
+	reloadLock sync.Mutex
 
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
@@ -135,6 +156,9 @@ type Node struct {
 	// current validators of the network
 	validators validation.Set
 
+	// Records every change of [validators]
+	validatorJournal validation.Journal
+
 	// Handles HTTP API calls
 	APIServer server.Server
 
@@ -262,6 +286,14 @@ func (n *Node) initNetworking() error {
 	n.Config.NetworkConfig.WhitelistedSubnets = n.Config.WhitelistedSubnets
 	n.Config.NetworkConfig.UptimeCalculator = n.uptimeCalculator
 	n.Config.NetworkConfig.UptimeRequirement = n.Config.UptimeRequirement
+	n.Config.NetworkConfig.BanDB = prefixdb.New(networkBansDBPrefix, n.DB)
+
+	netDialer := dialer.NewDialer(constants.NetworkType, n.Config.NetworkConfig.DialerConfig, n.Log)
+	if n.Config.NetworkConfig.FaultInjectionEnabled {
+		n.Log.Warn("fault injection is enabled. This should only be used for testing")
+		n.Config.NetworkConfig.FaultInjector = fault.NewInjector(n.Log)
+		netDialer = n.Config.NetworkConfig.FaultInjector.WrapDialer(netDialer)
+	}
 
 	n.Net, err = network.NewNetwork(
 		&n.Config.NetworkConfig,
@@ -269,7 +301,7 @@ func (n *Node) initNetworking() error {
 		n.MetricsRegisterer,
 		n.Log,
 		listener,
-		dialer.NewDialer(constants.NetworkType, n.Config.NetworkConfig.DialerConfig, n.Log),
+		netDialer,
 		consensusRouter,
 		n.benchlistManager,
 	)
@@ -441,9 +473,38 @@ func (n *Node) initIPCs() error {
 		chainIDs[i] = id
 	}
 
+	var tlsConfig *tls.Config
+	if n.Config.IPCTLSAddress != "" {
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(n.Config.IPCTLSClientCA) {
+			return errInvalidClientCA
+		}
+		tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{n.Config.StakingTLSCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			MinVersion:   tls.VersionTLS13,
+		}
+	}
+
 	var err error
-	n.IPCs, err = ipcs.NewChainIPCs(n.Log, n.Config.IPCPath, n.Config.NetworkID, n.ConsensusDispatcher, n.DecisionDispatcher, chainIDs)
-	return err
+	n.IPCs, err = ipcs.NewChainIPCs(
+		n.Log,
+		n.Config.IPCPath,
+		n.Config.NetworkID,
+		n.ConsensusDispatcher,
+		n.DecisionDispatcher,
+		chainIDs,
+		n.Config.IPCTLSAddress,
+		tlsConfig,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Chain manager will notify IPCs when a chain is created
+	n.chainManager.AddRegistrant(n.IPCs)
+	return nil
 }
 
 // Initialize [n.indexer].
@@ -455,11 +516,16 @@ func (n *Node) initIndexer() error {
 	n.indexer, err = indexer.NewIndexer(indexer.Config{
 		IndexingEnabled:      n.Config.IndexAPIEnabled,
 		AllowIncompleteIndex: n.Config.IndexAllowIncomplete,
+		IncludedChains:       n.Config.IndexIncludedChains,
+		ExcludedChains:       n.Config.IndexExcludedChains,
+		Retention:            n.Config.IndexRetention,
+		ChainRetention:       n.Config.IndexChainRetention,
 		DB:                   txIndexerDB,
 		Log:                  n.Log,
 		DecisionDispatcher:   n.DecisionDispatcher,
 		ConsensusDispatcher:  n.ConsensusDispatcher,
 		APIServer:            n.APIServer,
+		CheckOrigin:          n.APIServer.CheckOrigin,
 		ShutdownF:            func() { n.Shutdown(0) }, // TODO put exit code here
 	})
 	if err != nil {
@@ -616,6 +682,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		Net:                                     n.Net,
 		ConsensusParams:                         n.Config.ConsensusParams,
 		Validators:                              n.validators,
+		ValidatorJournal:                        n.validatorJournal,
 		NodeID:                                  n.ID,
 		NetworkID:                               n.Config.NetworkID,
 		Server:                                  n.APIServer,
@@ -635,6 +702,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		SubnetConfigs:                           n.Config.SubnetConfigs,
 		ChainConfigs:                            n.Config.ChainConfigs,
 		ConsensusGossipFrequency:                n.Config.ConsensusGossipFrequency,
+		ConsensusMessageClassWeights:            n.Config.ConsensusMessageClassWeights,
 		GossipConfig:                            n.Config.GossipConfig,
 		BootstrapMaxTimeGetAncestors:            n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapAncestorsMaxContainersSent:     n.Config.BootstrapAncestorsMaxContainersSent,
@@ -642,6 +710,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		ApricotPhase4Time:                       version.GetApricotPhase4Time(n.Config.NetworkID),
 		ApricotPhase4MinPChainHeight:            version.GetApricotPhase4MinPChainHeight(n.Config.NetworkID),
 		ResetProposerVMHeightIndex:              n.Config.ResetProposerVMHeightIndex,
+		ValidatorsSchedule:                      n.Config.ValidatorsSchedule,
 	})
 
 	// Notify the API server when new chains are created
@@ -727,15 +796,24 @@ func (n *Node) initVMs() error {
 // initSharedMemory initializes the shared memory for cross chain interation
 func (n *Node) initSharedMemory() error {
 	n.Log.Info("initializing SharedMemory")
-	sharedMemoryDB := prefixdb.New([]byte("shared memory"), n.DB)
+	sharedMemoryDB := prefixdb.New(sharedMemoryDBPrefix, n.DB)
 	return n.sharedMemory.Initialize(n.Log, sharedMemoryDB)
 }
 
+// initValidatorJournal initializes the journal of validator set changes.
+// Assumes n.DBManager and n.MetricsRegisterer are already set
+func (n *Node) initValidatorJournal() error {
+	journalDB := n.DBManager.NewPrefixDBManager(validatorJournalDBPrefix)
+	var err error
+	n.validatorJournal, err = validation.NewJournal(journalDB.Current().Database, "validators", n.MetricsRegisterer)
+	return err
+}
+
 // initKeystoreAPI initializes the keystore service, which is an on-node wallet.
 // Assumes n.APIServer is already set
 func (n *Node) initKeystoreAPI() error {
 	n.Log.Info("initializing keystore")
-	keystoreDB := n.DBManager.NewPrefixDBManager([]byte("keystore"))
+	keystoreDB := n.DBManager.NewPrefixDBManager(keystoreDBPrefix)
 	n.keystore = keystore.New(n.Log, keystoreDB)
 	keystoreHandler, err := n.keystore.CreateHandler()
 	if err != nil {
@@ -807,14 +885,21 @@ func (n *Node) initAdminAPI() error {
 	n.Log.Info("initializing admin API")
 	service, err := admin.NewService(
 		admin.Config{
-			Log:          n.Log,
-			ChainManager: n.chainManager,
-			HTTPServer:   n.APIServer,
-			ProfileDir:   n.Config.ProfilerConfig.Dir,
-			LogFactory:   n.LogFactory,
-			NodeConfig:   n.Config,
-			VMManager:    n.Config.VMManager,
-			VMRegistry:   n.VMRegistry,
+			Log:           n.Log,
+			ChainManager:  n.chainManager,
+			HTTPServer:    n.APIServer,
+			ProfileDir:    n.Config.ProfilerConfig.Dir,
+			LogFactory:    n.LogFactory,
+			NodeConfig:    n.Config,
+			VMManager:     n.Config.VMManager,
+			VMRegistry:    n.VMRegistry,
+			DBManager:     n.DBManager,
+			Network:       n.Net,
+			FaultInjector: n.Config.NetworkConfig.FaultInjector,
+			DatabaseLayout: func(db database.Database) (*inspect.Layout, error) {
+				return DatabaseLayout(db, n.chainManager.Chains(), n.chainManager.PrimaryAliasOrDefault)
+			},
+			ReloadConfig: n.ReloadConfig,
 		},
 	)
 	if err != nil {
@@ -825,26 +910,77 @@ func (n *Node) initAdminAPI() error {
 
 // initProfiler initializes the continuous profiling
 func (n *Node) initProfiler() {
+	n.profilerLock.Lock()
+	defer n.profilerLock.Unlock()
+
+	n.profilerConfig = n.Config.ProfilerConfig
 	if !n.Config.ProfilerConfig.Enabled {
 		n.Log.Info("skipping profiler initialization because it has been disabled")
 		return
 	}
 
 	n.Log.Info("initializing continuous profiler")
-	n.profiler = profiler.NewContinuous(
-		filepath.Join(n.Config.ProfilerConfig.Dir, "continuous"),
-		n.Config.ProfilerConfig.Freq,
-		n.Config.ProfilerConfig.MaxNumFiles,
+	n.startProfiler()
+}
+
+// startProfiler starts continuous profiling with [n.profilerConfig]
+// Assumes [n.profilerLock] is held
+func (n *Node) startProfiler() {
+	p := profiler.NewContinuous(
+		filepath.Join(n.profilerConfig.Dir, "continuous"),
+		n.profilerConfig.Freq,
+		n.profilerConfig.MaxNumFiles,
 	)
+	done := make(chan struct{})
+	n.profiler = p
+	n.profilerDone = done
 	go n.Log.RecoverAndPanic(func() {
-		err := n.profiler.Dispatch()
+		err := p.Dispatch()
+		close(done)
 		if err != nil {
 			n.Log.Fatal("continuous profiler failed with %s", err)
+			n.Shutdown(1)
+			return
+		}
+
+		n.profilerLock.Lock()
+		stopped := n.profiler != p
+		n.profilerLock.Unlock()
+		if !stopped {
+			n.Shutdown(1)
 		}
-		n.Shutdown(1)
 	})
 }
 
+// stopProfiler stops continuous profiling and waits for the profiles to be
+// written
+// Assumes [n.profilerLock] is held
+func (n *Node) stopProfiler() {
+	if n.profiler == nil {
+		return
+	}
+	n.profiler.Shutdown()
+	<-n.profilerDone
+	n.profiler = nil
+	n.profilerDone = nil
+}
+
+// setProfilerConfig restarts continuous profiling with [config] if it changed
+func (n *Node) setProfilerConfig(config profiler.Config) {
+	n.profilerLock.Lock()
+	defer n.profilerLock.Unlock()
+
+	if config == n.profilerConfig {
+		return
+	}
+	n.stopProfiler()
+	n.profilerConfig = config
+	if config.Enabled {
+		n.Log.Info("restarting continuous profiler")
+		n.startProfiler()
+	}
+}
+
 func (n *Node) initInfoAPI() error {
 	if !n.Config.InfoAPIEnabled {
 		n.Log.Info("skipping info API initialization because it has been disabled")
@@ -879,10 +1015,29 @@ func (n *Node) initInfoAPI() error {
 	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "info", "", n.HTTPLog)
 }
 
+// initValidatorsAPI initializes the Validators API service
+// Assumes n.Log and n.chainManager already initialized
+func (n *Node) initValidatorsAPI() error {
+	if !n.Config.ValidatorsAPIEnabled {
+		n.Log.Info("skipping validators API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing validators API")
+	service, err := validatorsapi.NewService(validatorsapi.Config{
+		Log:          n.Log,
+		ChainManager: n.chainManager,
+		Journal:      n.validatorJournal,
+	})
+	if err != nil {
+		return err
+	}
+	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "validators", "", n.HTTPLog)
+}
+
 // initHealthAPI initializes the Health API service
 // Assumes n.Log, n.Net, n.APIServer, n.HTTPLog already initialized
 func (n *Node) initHealthAPI() error {
-	healthChecker, err := health.New(n.MetricsRegisterer)
+	healthChecker, err := health.New(n.Config.HealthConfig, n.Log, n.MetricsRegisterer)
 	if err != nil {
 		return err
 	}
@@ -1100,6 +1255,9 @@ func (n *Node) Initialize(
 	if err := n.initKeystoreAPI(); err != nil { // Start the Keystore API
 		return fmt.Errorf("couldn't initialize keystore API: %w", err)
 	}
+	if err := n.initValidatorJournal(); err != nil { // Record validator set changes
+		return fmt.Errorf("couldn't initialize validator journal: %w", err)
+	}
 
 	if err := n.initSharedMemory(); err != nil { // Initialize shared memory
 		return fmt.Errorf("problem initializing shared memory: %w", err)
@@ -1110,8 +1268,8 @@ func (n *Node) Initialize(
 	// and the engine (initChains) but after the metrics (initMetricsAPI)
 	// message.Creator currently record metrics under network namespace
 	n.networkNamespace = "network"
-	n.msgCreator, err = message.NewCreator(n.MetricsRegisterer,
-		n.Config.NetworkConfig.CompressionEnabled,
+	n.msgCreator, err = message.NewCreatorWithCompression(n.MetricsRegisterer,
+		n.Config.NetworkConfig.CompressionTypes,
 		n.networkNamespace,
 		n.Config.NetworkConfig.MaximumInboundMessageTimeout,
 	)
@@ -1146,6 +1304,9 @@ func (n *Node) Initialize(
 	if err := n.initInfoAPI(); err != nil { // Start the Info API
 		return fmt.Errorf("couldn't initialize info API: %w", err)
 	}
+	if err := n.initValidatorsAPI(); err != nil { // Start the Validators API
+		return fmt.Errorf("couldn't initialize validators API: %w", err)
+	}
 	if err := n.initIPCs(); err != nil { // Start the IPCs
 		return fmt.Errorf("couldn't initialize IPCs: %w", err)
 	}
@@ -1170,6 +1331,53 @@ func (n *Node) Initialize(
 	return nil
 }
 
+// ReloadConfig reloads the config with [n.Config.ConfigReloader] and applies
+// the changes that can be made while the node is running: the inbound
+// throttler limits, the benchlist parameters, the network and router health
+// thresholds, the APIs that are enabled, the HTTP allowed origins and the
+// continuous profiler. Returns the keys changed since the node started,
+// split between the ones that were applied and the ones that require a
+// restart. The node config isn't updated.
+func (n *Node) ReloadConfig() ([]string, []string, error) {
+	if n.Config.ConfigReloader == nil {
+		return nil, nil, errNotReloadable
+	}
+
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	config, reloaded, requireRestart, err := n.Config.ConfigReloader()
+	if err != nil {
+		n.Log.Warn("couldn't reload config: %s", err)
+		return nil, nil, fmt.Errorf("couldn't reload config: %w", err)
+	}
+
+	n.Net.SetInboundMsgThrottlerConfig(config.NetworkConfig.ThrottlerConfig.InboundMsgThrottlerConfig)
+	n.Net.SetHealthConfig(config.NetworkConfig.HealthConfig)
+	n.Config.ConsensusRouter.SetHealthConfig(config.RouterHealthConfig)
+	n.benchlistManager.SetParameters(
+		config.BenchlistConfig.Threshold,
+		config.BenchlistConfig.MinimumFailingDuration,
+		config.BenchlistConfig.Duration,
+	)
+	n.APIServer.SetAllowedOrigins(config.APIAllowedOrigins)
+	for base, enabled := range map[string]bool{
+		"admin":      config.AdminAPIEnabled,
+		"info":       config.InfoAPIEnabled,
+		"keystore":   config.KeystoreAPIEnabled,
+		"metrics":    config.MetricsAPIEnabled,
+		"health":     config.HealthAPIEnabled,
+		"ipcs":       config.IPCAPIEnabled,
+		"validators": config.ValidatorsAPIEnabled,
+	} {
+		n.APIServer.SetRouteEnabled(base, enabled)
+	}
+	n.setProfilerConfig(config.ProfilerConfig)
+
+	n.Log.Info("reloaded config. Applied changes to %v. Changes to %v require a restart", reloaded, requireRestart)
+	return reloaded, requireRestart, nil
+}
+
 // Shutdown this node
 // May be called multiple times
 func (n *Node) Shutdown(exitCode int) {
@@ -1207,9 +1415,9 @@ func (n *Node) shutdown() {
 	if n.chainManager != nil {
 		n.chainManager.Shutdown()
 	}
-	if n.profiler != nil {
-		n.profiler.Shutdown()
-	}
+	n.profilerLock.Lock()
+	n.stopProfiler()
+	n.profilerLock.Unlock()
 	if n.Net != nil {
 		n.Net.StartClose()
 	}
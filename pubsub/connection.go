@@ -20,11 +20,16 @@ var (
 	ErrAddressLimit                = errors.New("address limit exceeded")
 	ErrInvalidFilterParam          = errors.New("invalid bloom filter params")
 	ErrInvalidCommand              = errors.New("invalid command")
+	ErrFilterValueLimit            = errors.New("filter value limit exceeded")
+	ErrRemoveFromBloom             = errors.New("can't remove addresses from a bloom filter")
 	_                       Filter = &connection{}
 )
 
 type Filter interface {
+	// Check returns true if [addr] passes the address filter
 	Check(addr []byte) bool
+	// Match returns true if [fields] pass the configured filters
+	Match(fields *Fields) bool
 }
 
 // connection is a representation of the websocket connection.
@@ -46,6 +51,10 @@ func (c *connection) Check(addr []byte) bool {
 	return c.fp.Check(addr)
 }
 
+func (c *connection) Match(fields *Fields) bool {
+	return c.fp.Match(fields)
+}
+
 func (c *connection) isActive() bool {
 	active := atomic.LoadUint32(&c.active)
 	return active != 0
@@ -165,6 +174,22 @@ func (c *connection) readMessage() error {
 		c.handleNewSet(cmd.NewSet)
 	case cmd.AddAddresses != nil:
 		err = c.handleAddAddresses(cmd.AddAddresses)
+	case cmd.RemoveAddresses != nil:
+		err = c.handleRemoveAddresses(cmd.RemoveAddresses)
+	case cmd.AddAssetIDs != nil:
+		err = c.handleAddAssetIDs(cmd.AddAssetIDs)
+	case cmd.RemoveAssetIDs != nil:
+		c.fp.RemoveAssetIDs(cmd.RemoveAssetIDs.AssetIDs...)
+	case cmd.AddTxTypes != nil:
+		err = c.handleAddTxTypes(cmd.AddTxTypes)
+	case cmd.RemoveTxTypes != nil:
+		c.fp.RemoveTxTypes(cmd.RemoveTxTypes.TxTypes...)
+	case cmd.AddUTXOIDs != nil:
+		err = c.handleAddUTXOIDs(cmd.AddUTXOIDs)
+	case cmd.RemoveUTXOIDs != nil:
+		c.fp.RemoveUTXOIDs(cmd.RemoveUTXOIDs.UTXOIDs...)
+	case cmd.ListFilters != nil:
+		err = c.handleListFilters()
 	default:
 		err = ErrInvalidCommand
 	}
@@ -203,3 +228,48 @@ func (c *connection) handleAddAddresses(cmd *AddAddresses) error {
 	c.s.subscribedConnections.Add(c)
 	return nil
 }
+
+func (c *connection) handleRemoveAddresses(cmd *RemoveAddresses) error {
+	if err := cmd.parseAddresses(); err != nil {
+		return fmt.Errorf("address parse failed %w", err)
+	}
+	if err := c.fp.Remove(cmd.addressIds...); err != nil {
+		return fmt.Errorf("address removal failed %w", err)
+	}
+	return nil
+}
+
+func (c *connection) handleAddAssetIDs(cmd *AddAssetIDs) error {
+	if err := c.fp.AddAssetIDs(cmd.AssetIDs...); err != nil {
+		return fmt.Errorf("asset ID append failed %w", err)
+	}
+	c.s.subscribedConnections.Add(c)
+	return nil
+}
+
+func (c *connection) handleAddTxTypes(cmd *AddTxTypes) error {
+	if err := c.fp.AddTxTypes(cmd.TxTypes...); err != nil {
+		return fmt.Errorf("tx type append failed %w", err)
+	}
+	c.s.subscribedConnections.Add(c)
+	return nil
+}
+
+func (c *connection) handleAddUTXOIDs(cmd *AddUTXOIDs) error {
+	if err := c.fp.AddUTXOIDs(cmd.UTXOIDs...); err != nil {
+		return fmt.Errorf("UTXO ID append failed %w", err)
+	}
+	c.s.subscribedConnections.Add(c)
+	return nil
+}
+
+func (c *connection) handleListFilters() error {
+	filters, err := c.fp.Filters(c.s.hrp)
+	if err != nil {
+		return fmt.Errorf("listing filters failed %w", err)
+	}
+	if !c.Send(&filtersMsg{Filters: filters}) {
+		c.s.log.Verbo("dropping filters reply due to too many pending messages")
+	}
+	return nil
+}
@@ -4,20 +4,32 @@
 package pubsub
 
 import (
+	"sort"
 	"sync"
 
+	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/bloom"
+	"github.com/flare-foundation/flare/utils/formatting"
 )
 
+var _ Filter = &FilterParam{}
+
 type FilterParam struct {
 	lock   sync.RWMutex
 	set    map[string]struct{}
 	filter bloom.Filter
+
+	assetIDs ids.Set
+	txTypes  map[string]struct{}
+	utxoIDs  ids.Set
 }
 
 func NewFilterParam() *FilterParam {
 	return &FilterParam{
-		set: make(map[string]struct{}),
+		set:      make(map[string]struct{}),
+		assetIDs: ids.Set{},
+		txTypes:  make(map[string]struct{}),
+		utxoIDs:  ids.Set{},
 	}
 }
 
@@ -49,6 +61,10 @@ func (f *FilterParam) Check(addr []byte) bool {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 
+	return f.check(addr)
+}
+
+func (f *FilterParam) check(addr []byte) bool {
 	if f.filter != nil && f.filter.Check(addr) {
 		return true
 	}
@@ -56,6 +72,58 @@ func (f *FilterParam) Check(addr []byte) bool {
 	return ok
 }
 
+// Match returns true if [fields] pass every filter that was configured. The
+// address filter passes if any of the addresses passes it, and similarly for
+// the asset ID and UTXO ID filters. If no filter was configured, nothing
+// matches.
+func (f *FilterParam) Match(fields *Fields) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	filtered := false
+	if f.filter != nil || len(f.set) > 0 {
+		filtered = true
+		matched := false
+		for _, addr := range fields.Addresses {
+			if f.check(addr) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.assetIDs.Len() > 0 {
+		filtered = true
+		if !containsAny(f.assetIDs, fields.AssetIDs) {
+			return false
+		}
+	}
+	if len(f.txTypes) > 0 {
+		filtered = true
+		if _, ok := f.txTypes[fields.TxType]; !ok {
+			return false
+		}
+	}
+	if f.utxoIDs.Len() > 0 {
+		filtered = true
+		if !containsAny(f.utxoIDs, fields.UTXOIDs) {
+			return false
+		}
+	}
+	return filtered
+}
+
+func containsAny(set ids.Set, values []ids.ID) bool {
+	for _, value := range values {
+		if set.Contains(value) {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *FilterParam) Add(bl ...[]byte) error {
 	filter := f.Filter()
 	if filter != nil {
@@ -79,9 +147,110 @@ func (f *FilterParam) Add(bl ...[]byte) error {
 	return nil
 }
 
+// Remove the addresses [bl] from the set. Addresses can't be removed from a
+// bloom filter.
+func (f *FilterParam) Remove(bl ...[]byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.filter != nil {
+		return ErrRemoveFromBloom
+	}
+	for _, b := range bl {
+		delete(f.set, string(b))
+	}
+	return nil
+}
+
 func (f *FilterParam) Len() int {
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 
 	return len(f.set)
 }
+
+func (f *FilterParam) AddAssetIDs(assetIDs ...ids.ID) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.assetIDs.Len()+len(assetIDs) > MaxFilterValues {
+		return ErrFilterValueLimit
+	}
+	f.assetIDs.Add(assetIDs...)
+	return nil
+}
+
+func (f *FilterParam) RemoveAssetIDs(assetIDs ...ids.ID) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.assetIDs.Remove(assetIDs...)
+}
+
+func (f *FilterParam) AddTxTypes(txTypes ...string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if len(f.txTypes)+len(txTypes) > MaxFilterValues {
+		return ErrFilterValueLimit
+	}
+	for _, txType := range txTypes {
+		f.txTypes[txType] = struct{}{}
+	}
+	return nil
+}
+
+func (f *FilterParam) RemoveTxTypes(txTypes ...string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, txType := range txTypes {
+		delete(f.txTypes, txType)
+	}
+}
+
+func (f *FilterParam) AddUTXOIDs(utxoIDs ...ids.ID) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.utxoIDs.Len()+len(utxoIDs) > MaxFilterValues {
+		return ErrFilterValueLimit
+	}
+	f.utxoIDs.Add(utxoIDs...)
+	return nil
+}
+
+func (f *FilterParam) RemoveUTXOIDs(utxoIDs ...ids.ID) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.utxoIDs.Remove(utxoIDs...)
+}
+
+// Filters returns the filters that are configured. The addresses of a bloom
+// filter can't be listed.
+func (f *FilterParam) Filters(hrp string) (*Filters, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	filters := &Filters{
+		Bloom:     f.filter != nil,
+		Addresses: make([]string, 0, len(f.set)),
+		AssetIDs:  f.assetIDs.SortedList(),
+		TxTypes:   make([]string, 0, len(f.txTypes)),
+		UTXOIDs:   f.utxoIDs.SortedList(),
+	}
+	for addr := range f.set {
+		addrStr, err := formatting.FormatBech32(hrp, []byte(addr))
+		if err != nil {
+			return nil, err
+		}
+		filters.Addresses = append(filters.Addresses, addrStr)
+	}
+	for txType := range f.txTypes {
+		filters.TxTypes = append(filters.TxTypes, txType)
+	}
+	sort.Strings(filters.Addresses)
+	sort.Strings(filters.TxTypes)
+	return filters, nil
+}
@@ -92,3 +92,81 @@ func TestNewBloom(t *testing.T) {
 		t.Fatalf("new filter check failed")
 	}
 }
+
+func TestFilterParamMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	addr := ids.GenerateTestShortID()
+	assetID := ids.GenerateTestID()
+	utxoID := ids.GenerateTestID()
+	fields := &Fields{
+		Addresses: [][]byte{addr[:]},
+		AssetIDs:  []ids.ID{assetID},
+		TxType:    "BaseTx",
+		UTXOIDs:   []ids.ID{utxoID},
+	}
+
+	fp := NewFilterParam()
+	assert.False(fp.Match(fields), "nothing should match without filters")
+
+	assert.NoError(fp.AddTxTypes("BaseTx"))
+	assert.True(fp.Match(fields))
+
+	assert.NoError(fp.AddAssetIDs(ids.GenerateTestID()))
+	assert.False(fp.Match(fields), "every filter should have to match")
+	assert.NoError(fp.AddAssetIDs(assetID))
+	assert.True(fp.Match(fields))
+
+	assert.NoError(fp.AddUTXOIDs(utxoID))
+	assert.NoError(fp.Add(addr[:]))
+	assert.True(fp.Match(fields))
+
+	assert.NoError(fp.Remove(addr[:]))
+	fp.RemoveTxTypes("BaseTx")
+	fp.RemoveAssetIDs(assetID)
+	assert.False(fp.Match(fields))
+	fp.RemoveUTXOIDs(utxoID)
+	assert.False(fp.Match(fields), "nothing should match once the filters are removed")
+}
+
+func TestFilterParamRemoveFromBloom(t *testing.T) {
+	fp := NewFilterParam()
+	fp.SetFilter(bloom.NewMap())
+
+	addr := ids.GenerateTestShortID()
+	assert.NoError(t, fp.Add(addr[:]))
+	assert.ErrorIs(t, fp.Remove(addr[:]), ErrRemoveFromBloom)
+}
+
+func TestFilterParamFilters(t *testing.T) {
+	assert := assert.New(t)
+
+	hrp := constants.GetHRP(5)
+	addr := ids.GenerateTestShortID()
+	addrStr, err := formatting.FormatBech32(hrp, addr[:])
+	assert.NoError(err)
+	assetID := ids.GenerateTestID()
+
+	fp := NewFilterParam()
+	assert.NoError(fp.Add(addr[:]))
+	assert.NoError(fp.AddAssetIDs(assetID))
+	assert.NoError(fp.AddTxTypes("ImportTx", "ExportTx"))
+
+	filters, err := fp.Filters(hrp)
+	assert.NoError(err)
+	assert.Equal(&Filters{
+		Addresses: []string{addrStr},
+		AssetIDs:  []ids.ID{assetID},
+		TxTypes:   []string{"ExportTx", "ImportTx"},
+		UTXOIDs:   []ids.ID{},
+	}, filters)
+}
+
+func TestTypeName(t *testing.T) {
+	type UnsignedAddValidatorTx struct{}
+	type BaseTx struct{}
+
+	assert.Equal(t, "AddValidatorTx", TypeName(&UnsignedAddValidatorTx{}))
+	assert.Equal(t, "BaseTx", TypeName(BaseTx{}))
+	assert.Equal(t, "", TypeName(nil))
+}
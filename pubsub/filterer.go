@@ -3,6 +3,64 @@
 
 package pubsub
 
+import (
+	"reflect"
+	"strings"
+
+	"github.com/flare-foundation/flare/ids"
+)
+
+var _ Filterer = &filterer{}
+
 type Filterer interface {
 	Filter(connections []Filter) ([]bool, interface{})
 }
+
+// Fields are the values of a published message that connections filter on
+type Fields struct {
+	// Addresses referenced by the message, such as the owners of its outputs
+	Addresses [][]byte
+	// AssetIDs of the assets the message consumes or produces
+	AssetIDs []ids.ID
+	// TxType is the type of the message, such as the type of a transaction or
+	// of a block
+	TxType string
+	// UTXOIDs of the UTXOs the message consumes or produces
+	UTXOIDs []ids.ID
+}
+
+type filterer struct {
+	fields Fields
+	msg    interface{}
+}
+
+// NewFilterer returns a Filterer that publishes [msg] to the connections whose
+// filters match [fields]
+func NewFilterer(fields Fields, msg interface{}) Filterer {
+	return &filterer{
+		fields: fields,
+		msg:    msg,
+	}
+}
+
+func (f *filterer) Filter(connections []Filter) ([]bool, interface{}) {
+	resp := make([]bool, len(connections))
+	for i, c := range connections {
+		resp[i] = c.Match(&f.fields)
+	}
+	return resp, f.msg
+}
+
+// TypeName returns the name of the type of [v], dereferencing pointers and
+// dropping the "Unsigned" prefix of unsigned transactions. For example, the
+// name of a *UnsignedAddValidatorTx is "AddValidatorTx".
+func TypeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return strings.TrimPrefix(t.Name(), "Unsigned")
+}
@@ -5,6 +5,7 @@ package pubsub
 
 import (
 	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/formatting"
 	"github.com/flare-foundation/flare/utils/json"
 )
@@ -28,11 +29,75 @@ type AddAddresses struct {
 	addressIds [][]byte
 }
 
+// RemoveAddresses command to remove addresses from a map set
+type RemoveAddresses struct {
+	api.JSONAddresses
+
+	// addressIds array of addresses, kept as a [][]byte for use in the map set
+	addressIds [][]byte
+}
+
+// AddAssetIDs command to add asset IDs
+type AddAssetIDs struct {
+	AssetIDs []ids.ID `json:"assetIDs"`
+}
+
+// RemoveAssetIDs command to remove asset IDs
+type RemoveAssetIDs struct {
+	AssetIDs []ids.ID `json:"assetIDs"`
+}
+
+// AddTxTypes command to add tx types, such as "BaseTx" or "AddValidatorTx"
+type AddTxTypes struct {
+	TxTypes []string `json:"txTypes"`
+}
+
+// RemoveTxTypes command to remove tx types
+type RemoveTxTypes struct {
+	TxTypes []string `json:"txTypes"`
+}
+
+// AddUTXOIDs command to add UTXO IDs, as returned by avax.UTXOID.InputID
+type AddUTXOIDs struct {
+	UTXOIDs []ids.ID `json:"utxoIDs"`
+}
+
+// RemoveUTXOIDs command to remove UTXO IDs
+type RemoveUTXOIDs struct {
+	UTXOIDs []ids.ID `json:"utxoIDs"`
+}
+
+// ListFilters command to list the filters of the connection
+type ListFilters struct{}
+
+// Filters of a connection, sent in reply to a ListFilters command
+type Filters struct {
+	// Bloom is true if addresses are filtered by a bloom filter, whose
+	// addresses aren't listed
+	Bloom     bool     `json:"bloom"`
+	Addresses []string `json:"addresses"`
+	AssetIDs  []ids.ID `json:"assetIDs"`
+	TxTypes   []string `json:"txTypes"`
+	UTXOIDs   []ids.ID `json:"utxoIDs"`
+}
+
+type filtersMsg struct {
+	Filters *Filters `json:"filters"`
+}
+
 // Command execution command
 type Command struct {
-	NewBloom     *NewBloom     `json:"newBloom,omitempty"`
-	NewSet       *NewSet       `json:"newSet,omitempty"`
-	AddAddresses *AddAddresses `json:"addAddresses,omitempty"`
+	NewBloom        *NewBloom        `json:"newBloom,omitempty"`
+	NewSet          *NewSet          `json:"newSet,omitempty"`
+	AddAddresses    *AddAddresses    `json:"addAddresses,omitempty"`
+	RemoveAddresses *RemoveAddresses `json:"removeAddresses,omitempty"`
+	AddAssetIDs     *AddAssetIDs     `json:"addAssetIDs,omitempty"`
+	RemoveAssetIDs  *RemoveAssetIDs  `json:"removeAssetIDs,omitempty"`
+	AddTxTypes      *AddTxTypes      `json:"addTxTypes,omitempty"`
+	RemoveTxTypes   *RemoveTxTypes   `json:"removeTxTypes,omitempty"`
+	AddUTXOIDs      *AddUTXOIDs      `json:"addUTXOIDs,omitempty"`
+	RemoveUTXOIDs   *RemoveUTXOIDs   `json:"removeUTXOIDs,omitempty"`
+	ListFilters     *ListFilters     `json:"listFilters,omitempty"`
 }
 
 func (c *Command) String() string {
@@ -43,6 +108,22 @@ func (c *Command) String() string {
 		return "newSet"
 	case c.AddAddresses != nil:
 		return "addAddresses"
+	case c.RemoveAddresses != nil:
+		return "removeAddresses"
+	case c.AddAssetIDs != nil:
+		return "addAssetIDs"
+	case c.RemoveAssetIDs != nil:
+		return "removeAssetIDs"
+	case c.AddTxTypes != nil:
+		return "addTxTypes"
+	case c.RemoveTxTypes != nil:
+		return "removeTxTypes"
+	case c.AddUTXOIDs != nil:
+		return "addUTXOIDs"
+	case c.RemoveUTXOIDs != nil:
+		return "removeUTXOIDs"
+	case c.ListFilters != nil:
+		return "listFilters"
 	default:
 		return "unknown"
 	}
@@ -55,15 +136,26 @@ func (c *NewBloom) IsParamsValid() bool {
 
 // parseAddresses converts the bech32 addresses to their byte format.
 func (c *AddAddresses) parseAddresses() error {
-	if c.addressIds == nil {
-		c.addressIds = make([][]byte, len(c.Addresses))
-	}
-	for i, addrStr := range c.Addresses {
+	addressIds, err := parseAddresses(c.Addresses)
+	c.addressIds = addressIds
+	return err
+}
+
+// parseAddresses converts the bech32 addresses to their byte format.
+func (c *RemoveAddresses) parseAddresses() error {
+	addressIds, err := parseAddresses(c.Addresses)
+	c.addressIds = addressIds
+	return err
+}
+
+func parseAddresses(addrStrs []string) ([][]byte, error) {
+	addressIds := make([][]byte, len(addrStrs))
+	for i, addrStr := range addrStrs {
 		_, _, addrBytes, err := formatting.ParseAddress(addrStr)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		c.addressIds[i] = addrBytes
+		addressIds[i] = addrBytes
 	}
-	return nil
+	return addressIds, nil
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gorilla/websocket"
 
+	"github.com/flare-foundation/flare/utils/constants"
 	"github.com/flare-foundation/flare/utils/logging"
 	"github.com/flare-foundation/flare/utils/units"
 )
@@ -41,6 +42,10 @@ const (
 
 	// MaxAddresses the max number of addresses allowed
 	MaxAddresses = 10000
+
+	// MaxFilterValues the max number of asset IDs, tx types, or UTXO IDs
+	// allowed, per filter
+	MaxFilterValues = 10000
 )
 
 type errorMsg struct {
@@ -55,7 +60,9 @@ var upgrader = websocket.Upgrader{
 
 // Server maintains the set of active clients and sends messages to the clients.
 type Server struct {
-	log  logging.Logger
+	log logging.Logger
+	// hrp the human readable part of the addresses listed to connections
+	hrp  string
 	lock sync.RWMutex
 	// conns a list of all our connections
 	conns map[*connection]struct{}
@@ -66,6 +73,7 @@ type Server struct {
 func New(networkID uint32, log logging.Logger) *Server {
 	return &Server{
 		log:                   log,
+		hrp:                   constants.GetHRP(networkID),
 		conns:                 make(map[*connection]struct{}),
 		subscribedConnections: newConnections(),
 	}
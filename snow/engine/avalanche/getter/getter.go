@@ -119,3 +119,14 @@ func (gh *getter) Get(validatorID ids.ShortID, requestID uint32, vtxID ids.ID) e
 	}
 	return nil
 }
+
+// State sync isn't supported by DAG based chains, the requests are dropped.
+func (gh *getter) GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32) error {
+	gh.log.Debug("GetStateSummaryFrontier(%s, %d) unhandled by this gear. Dropped.", validatorID, requestID)
+	return nil
+}
+
+func (gh *getter) GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, heights []uint64) error {
+	gh.log.Debug("GetAcceptedStateSummary(%s, %d) unhandled by this gear. Dropped.", validatorID, requestID)
+	return nil
+}
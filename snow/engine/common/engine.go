@@ -39,6 +39,8 @@ type Handler interface {
 	QueryHandler
 	ChitsHandler
 	AppHandler
+	StateSummaryFrontierHandler
+	AcceptedStateSummaryHandler
 
 	InternalHandler
 }
@@ -48,6 +50,8 @@ type AllGetsServer interface {
 	GetAcceptedHandler
 	GetAncestorsHandler
 	GetHandler
+	GetStateSummaryFrontierHandler
+	GetAcceptedStateSummaryHandler
 }
 
 // GetAcceptedFrontierHandler defines how a consensus engine reacts to a get
@@ -366,6 +370,89 @@ type AppHandler interface {
 	AppGossip(nodeID ids.ShortID, msg []byte) error
 }
 
+// GetStateSummaryFrontierHandler defines how a consensus engine reacts to a
+// get state summary frontier message from another validator. Functions only
+// return fatal errors.
+type GetStateSummaryFrontierHandler interface {
+	// Notify this engine of a request for the frontier of state summaries.
+	//
+	// The frontier is the last state summary available locally.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID.
+	//
+	// This engine should respond with a StateSummaryFrontier message with the
+	// same requestID, and the engine's current state summary frontier. If the
+	// VM doesn't support state sync, the message can be safely dropped.
+	GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32) error
+}
+
+// StateSummaryFrontierHandler defines how a consensus engine reacts to a state
+// summary frontier message from other validators. Functions only return fatal
+// errors.
+type StateSummaryFrontierHandler interface {
+	// Notify this engine of a state summary frontier.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is in response to a GetStateSummaryFrontier message, is
+	// utilizing a unique requestID, or that the summary bytes are from a valid
+	// state summary.
+	StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) error
+
+	// Notify this engine that a get state summary frontier request it issued
+	// has failed.
+	//
+	// This function will be called if the engine sent a GetStateSummaryFrontier
+	// message that is not anticipated to be responded to. This could be because
+	// the recipient of the message is unknown or if the message request has
+	// timed out.
+	//
+	// The validatorID and requestID are assumed to be the same as those sent in
+	// the GetStateSummaryFrontier message.
+	GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) error
+}
+
+// GetAcceptedStateSummaryHandler defines how a consensus engine reacts to a get
+// accepted state summary message from another validator. Functions only return
+// fatal errors.
+type GetAcceptedStateSummaryHandler interface {
+	// Notify this engine of a request to return the IDs of the state summaries
+	// it has accepted at the given heights.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID.
+	//
+	// This engine should respond with an AcceptedStateSummary message with the
+	// same requestID, and the IDs of the state summaries this node has at
+	// [heights]. Heights that have no state summary are skipped.
+	GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, heights []uint64) error
+}
+
+// AcceptedStateSummaryHandler defines how a consensus engine reacts to an
+// accepted state summary message from other validators. Functions only return
+// fatal errors.
+type AcceptedStateSummaryHandler interface {
+	// Notify this engine of a set of accepted state summaries.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is in response to a GetAcceptedStateSummary message, is
+	// utilizing a unique requestID, or that the summaryIDs are of summaries
+	// that were requested.
+	AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error
+
+	// Notify this engine that a get accepted state summary request it issued
+	// has failed.
+	//
+	// This function will be called if the engine sent a GetAcceptedStateSummary
+	// message that is not anticipated to be responded to. This could be because
+	// the recipient of the message is unknown or if the message request has
+	// timed out.
+	//
+	// The validatorID and requestID are assumed to be the same as those sent in
+	// the GetAcceptedStateSummary message.
+	GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error
+}
+
 // InternalHandler defines how this consensus engine reacts to messages from
 // other components of this validator. Functions only return fatal errors if
 // they occur.
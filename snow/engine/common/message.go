@@ -19,6 +19,9 @@ const (
 	PendingTxs Message = iota
 	// StopVertex notifies a consensus that it has a pending stop vertex
 	StopVertex
+	// StateSyncDone notifies the state syncer that its VM finished syncing the
+	// state summary it accepted
+	StateSyncDone
 )
 
 func (msg Message) String() string {
@@ -27,6 +30,8 @@ func (msg Message) String() string {
 		return "Pending Transactions"
 	case StopVertex:
 		return "Pending Stop Vertex"
+	case StateSyncDone:
+		return "State Sync Done"
 	default:
 		return fmt.Sprintf("Unknown Message: %d", msg)
 	}
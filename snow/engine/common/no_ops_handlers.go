@@ -19,6 +19,9 @@ var (
 	_ QueryHandler            = &noOpQueryHandler{}
 	_ ChitsHandler            = &noOpChitsHandler{}
 	_ AppHandler              = &noOpAppHandler{}
+
+	_ StateSummaryFrontierHandler = &noOpStateSummaryFrontierHandler{}
+	_ AcceptedStateSummaryHandler = &noOpAcceptedStateSummaryHandler{}
 )
 
 type noOpAcceptedFrontierHandler struct {
@@ -160,3 +163,39 @@ func (nop *noOpAppHandler) AppGossip(nodeID ids.ShortID, msg []byte) error {
 	nop.log.Debug("AppGossip(%s) unhandled by this gear. Dropped.", nodeID)
 	return nil
 }
+
+type noOpStateSummaryFrontierHandler struct {
+	log logging.Logger
+}
+
+func NewNoOpStateSummaryFrontierHandler(log logging.Logger) StateSummaryFrontierHandler {
+	return &noOpStateSummaryFrontierHandler{log: log}
+}
+
+func (nop *noOpStateSummaryFrontierHandler) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summary []byte) error {
+	nop.log.Debug("StateSummaryFrontier(%s, %d) unhandled by this gear. Dropped.", validatorID, requestID)
+	return nil
+}
+
+func (nop *noOpStateSummaryFrontierHandler) GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) error {
+	nop.log.Debug("GetStateSummaryFrontierFailed(%s, %d) unhandled by this gear. Dropped.", validatorID, requestID)
+	return nil
+}
+
+type noOpAcceptedStateSummaryHandler struct {
+	log logging.Logger
+}
+
+func NewNoOpAcceptedStateSummaryHandler(log logging.Logger) AcceptedStateSummaryHandler {
+	return &noOpAcceptedStateSummaryHandler{log: log}
+}
+
+func (nop *noOpAcceptedStateSummaryHandler) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error {
+	nop.log.Debug("AcceptedStateSummary(%s, %d) unhandled by this gear. Dropped.", validatorID, requestID)
+	return nil
+}
+
+func (nop *noOpAcceptedStateSummaryHandler) GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error {
+	nop.log.Debug("GetAcceptedStateSummaryFailed(%s, %d) unhandled by this gear. Dropped.", validatorID, requestID)
+	return nil
+}
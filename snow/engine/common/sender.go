@@ -16,6 +16,8 @@ type Sender interface {
 	QuerySender
 	Gossiper
 	AppSender
+	StateSummarySender
+	AcceptedStateSummarySender
 }
 
 // FrontierSender defines how a consensus engine sends frontier messages to
@@ -34,6 +36,32 @@ type FrontierSender interface {
 	)
 }
 
+// StateSummarySender defines how a consensus engine sends state sync messages
+// to other nodes.
+type StateSummarySender interface {
+	// SendGetStateSummaryFrontier requests that every node in [nodeIDs] sends
+	// a StateSummaryFrontier message.
+	SendGetStateSummaryFrontier(nodeIDs ids.ShortSet, requestID uint32)
+
+	// SendStateSummaryFrontier responds to a GetStateSummaryFrontier message
+	// with this engine's last state summary.
+	SendStateSummaryFrontier(nodeID ids.ShortID, requestID uint32, summary []byte)
+}
+
+// AcceptedStateSummarySender defines how a consensus engine sends messages
+// pertaining to accepted state summaries
+type AcceptedStateSummarySender interface {
+	// SendGetAcceptedStateSummary requests that every node in [nodeIDs] sends
+	// an AcceptedStateSummary message with the IDs of the state summaries it
+	// has at [heights].
+	SendGetAcceptedStateSummary(nodeIDs ids.ShortSet, requestID uint32, heights []uint64)
+
+	// SendAcceptedStateSummary responds to a GetAcceptedStateSummary message
+	// with the IDs of the state summaries this node has at the requested
+	// heights.
+	SendAcceptedStateSummary(nodeID ids.ShortID, requestID uint32, summaryIDs []ids.ID)
+}
+
 // AcceptedSender defines how a consensus engine sends messages pertaining to
 // accepted containers
 type AcceptedSender interface {
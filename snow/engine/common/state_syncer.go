@@ -0,0 +1,20 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// MaxStateSummaryHeights is the maximum number of heights requested in a
+// GetAcceptedStateSummary message. Nodes ignore the heights past this limit.
+const MaxStateSummaryHeights = 128
+
+// StateSyncer selects the state summary a VM syncs to before the chain is
+// bootstrapped. It collects the latest state summaries of a sample of the
+// beacons and only syncs to a summary that enough of the beacons' stake
+// accepted.
+type StateSyncer interface {
+	Engine
+
+	// IsEnabled returns true if the VM supports state sync and is willing to
+	// sync. Any returned error will be considered fatal.
+	IsEnabled() (bool, error)
+}
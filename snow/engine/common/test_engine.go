@@ -35,6 +35,13 @@ var (
 	errChits                     = errors.New("unexpectedly called Chits")
 	errStart                     = errors.New("unexpectedly called Start")
 
+	errGetStateSummaryFrontier       = errors.New("unexpectedly called GetStateSummaryFrontier")
+	errStateSummaryFrontier          = errors.New("unexpectedly called StateSummaryFrontier")
+	errGetStateSummaryFrontierFailed = errors.New("unexpectedly called GetStateSummaryFrontierFailed")
+	errGetAcceptedStateSummary       = errors.New("unexpectedly called GetAcceptedStateSummary")
+	errAcceptedStateSummary          = errors.New("unexpectedly called AcceptedStateSummary")
+	errGetAcceptedStateSummaryFailed = errors.New("unexpectedly called GetAcceptedStateSummaryFailed")
+
 	_ Engine = &EngineTest{}
 )
 
@@ -84,6 +91,13 @@ type EngineTest struct {
 	CantAppGossip,
 	CantAppRequestFailed,
 
+	CantGetStateSummaryFrontier,
+	CantStateSummaryFrontier,
+	CantGetStateSummaryFrontierFailed,
+	CantGetAcceptedStateSummary,
+	CantAcceptedStateSummary,
+	CantGetAcceptedStateSummaryFailed,
+
 	CantGetVM bool
 
 	StartF                                             func(startReqID uint32) error
@@ -104,6 +118,11 @@ type EngineTest struct {
 	GetVMF                    func() VM
 	AppRequestF, AppResponseF func(nodeID ids.ShortID, requestID uint32, msg []byte) error
 	AppGossipF                func(nodeID ids.ShortID, msg []byte) error
+
+	GetStateSummaryFrontierF, GetStateSummaryFrontierFailedF, GetAcceptedStateSummaryFailedF func(nodeID ids.ShortID, requestID uint32) error
+	StateSummaryFrontierF                                                                    func(nodeID ids.ShortID, requestID uint32, summary []byte) error
+	GetAcceptedStateSummaryF                                                                 func(nodeID ids.ShortID, requestID uint32, heights []uint64) error
+	AcceptedStateSummaryF                                                                    func(nodeID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error
 }
 
 func (e *EngineTest) Default(cant bool) {
@@ -138,6 +157,12 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantAppRequestFailed = cant
 	e.CantAppResponse = cant
 	e.CantAppGossip = cant
+	e.CantGetStateSummaryFrontier = cant
+	e.CantStateSummaryFrontier = cant
+	e.CantGetStateSummaryFrontierFailed = cant
+	e.CantGetAcceptedStateSummary = cant
+	e.CantAcceptedStateSummary = cant
+	e.CantGetAcceptedStateSummaryFailed = cant
 	e.CantGetVM = cant
 }
 
@@ -526,3 +551,81 @@ func (e *EngineTest) GetVM() VM {
 	}
 	return nil
 }
+
+func (e *EngineTest) GetStateSummaryFrontier(nodeID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryFrontierF != nil {
+		return e.GetStateSummaryFrontierF(nodeID, requestID)
+	}
+	if !e.CantGetStateSummaryFrontier {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatal(errGetStateSummaryFrontier)
+	}
+	return errGetStateSummaryFrontier
+}
+
+func (e *EngineTest) StateSummaryFrontier(nodeID ids.ShortID, requestID uint32, summary []byte) error {
+	if e.StateSummaryFrontierF != nil {
+		return e.StateSummaryFrontierF(nodeID, requestID, summary)
+	}
+	if !e.CantStateSummaryFrontier {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatal(errStateSummaryFrontier)
+	}
+	return errStateSummaryFrontier
+}
+
+func (e *EngineTest) GetStateSummaryFrontierFailed(nodeID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryFrontierFailedF != nil {
+		return e.GetStateSummaryFrontierFailedF(nodeID, requestID)
+	}
+	if !e.CantGetStateSummaryFrontierFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatal(errGetStateSummaryFrontierFailed)
+	}
+	return errGetStateSummaryFrontierFailed
+}
+
+func (e *EngineTest) GetAcceptedStateSummary(nodeID ids.ShortID, requestID uint32, heights []uint64) error {
+	if e.GetAcceptedStateSummaryF != nil {
+		return e.GetAcceptedStateSummaryF(nodeID, requestID, heights)
+	}
+	if !e.CantGetAcceptedStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatal(errGetAcceptedStateSummary)
+	}
+	return errGetAcceptedStateSummary
+}
+
+func (e *EngineTest) AcceptedStateSummary(nodeID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error {
+	if e.AcceptedStateSummaryF != nil {
+		return e.AcceptedStateSummaryF(nodeID, requestID, summaryIDs)
+	}
+	if !e.CantAcceptedStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatal(errAcceptedStateSummary)
+	}
+	return errAcceptedStateSummary
+}
+
+func (e *EngineTest) GetAcceptedStateSummaryFailed(nodeID ids.ShortID, requestID uint32) error {
+	if e.GetAcceptedStateSummaryFailedF != nil {
+		return e.GetAcceptedStateSummaryFailedF(nodeID, requestID)
+	}
+	if !e.CantGetAcceptedStateSummaryFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatal(errGetAcceptedStateSummaryFailed)
+	}
+	return errGetAcceptedStateSummaryFailed
+}
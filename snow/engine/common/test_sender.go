@@ -26,7 +26,9 @@ type SenderTest struct {
 	CantSendGet, CantSendGetAncestors, CantSendPut, CantSendAncestors,
 	CantSendPullQuery, CantSendPushQuery, CantSendChits,
 	CantSendGossip,
-	CantSendAppRequest, CantSendAppResponse, CantSendAppGossip, CantSendAppGossipSpecific bool
+	CantSendAppRequest, CantSendAppResponse, CantSendAppGossip, CantSendAppGossipSpecific,
+	CantSendGetStateSummaryFrontier, CantSendStateSummaryFrontier,
+	CantSendGetAcceptedStateSummary, CantSendAcceptedStateSummary bool
 
 	SendGetAcceptedFrontierF func(ids.ShortSet, uint32)
 	SendAcceptedFrontierF    func(ids.ShortID, uint32, []ids.ID)
@@ -44,6 +46,11 @@ type SenderTest struct {
 	SendAppResponseF         func(ids.ShortID, uint32, []byte) error
 	SendAppGossipF           func([]byte) error
 	SendAppGossipSpecificF   func(ids.ShortSet, []byte) error
+
+	SendGetStateSummaryFrontierF func(ids.ShortSet, uint32)
+	SendStateSummaryFrontierF    func(ids.ShortID, uint32, []byte)
+	SendGetAcceptedStateSummaryF func(ids.ShortSet, uint32, []uint64)
+	SendAcceptedStateSummaryF    func(ids.ShortID, uint32, []ids.ID)
 }
 
 // Default set the default callable value to [cant]
@@ -64,6 +71,10 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantSendAppResponse = cant
 	s.CantSendAppGossip = cant
 	s.CantSendAppGossipSpecific = cant
+	s.CantSendGetStateSummaryFrontier = cant
+	s.CantSendStateSummaryFrontier = cant
+	s.CantSendGetAcceptedStateSummary = cant
+	s.CantSendAcceptedStateSummary = cant
 }
 
 // SendGetAcceptedFrontier calls SendGetAcceptedFrontierF if it was initialized.
@@ -249,3 +260,47 @@ func (s *SenderTest) SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes
 	}
 	return errSendAppGossipSpecific
 }
+
+// SendGetStateSummaryFrontier calls SendGetStateSummaryFrontierF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *SenderTest) SendGetStateSummaryFrontier(nodeIDs ids.ShortSet, requestID uint32) {
+	if s.SendGetStateSummaryFrontierF != nil {
+		s.SendGetStateSummaryFrontierF(nodeIDs, requestID)
+	} else if s.CantSendGetStateSummaryFrontier && s.T != nil {
+		s.T.Fatalf("Unexpectedly called SendGetStateSummaryFrontier")
+	}
+}
+
+// SendStateSummaryFrontier calls SendStateSummaryFrontierF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *SenderTest) SendStateSummaryFrontier(nodeID ids.ShortID, requestID uint32, summary []byte) {
+	if s.SendStateSummaryFrontierF != nil {
+		s.SendStateSummaryFrontierF(nodeID, requestID, summary)
+	} else if s.CantSendStateSummaryFrontier && s.T != nil {
+		s.T.Fatalf("Unexpectedly called SendStateSummaryFrontier")
+	}
+}
+
+// SendGetAcceptedStateSummary calls SendGetAcceptedStateSummaryF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *SenderTest) SendGetAcceptedStateSummary(nodeIDs ids.ShortSet, requestID uint32, heights []uint64) {
+	if s.SendGetAcceptedStateSummaryF != nil {
+		s.SendGetAcceptedStateSummaryF(nodeIDs, requestID, heights)
+	} else if s.CantSendGetAcceptedStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called SendGetAcceptedStateSummary")
+	}
+}
+
+// SendAcceptedStateSummary calls SendAcceptedStateSummaryF if it was
+// initialized. If it wasn't initialized and this function shouldn't be called
+// and testing was initialized, then testing will fail.
+func (s *SenderTest) SendAcceptedStateSummary(nodeID ids.ShortID, requestID uint32, summaryIDs []ids.ID) {
+	if s.SendAcceptedStateSummaryF != nil {
+		s.SendAcceptedStateSummaryF(nodeID, requestID, summaryIDs)
+	} else if s.CantSendAcceptedStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called SendAcceptedStateSummary")
+	}
+}
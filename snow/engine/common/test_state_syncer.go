@@ -0,0 +1,37 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+)
+
+var (
+	_ StateSyncer = &StateSyncerTest{}
+
+	errIsEnabled = errors.New("unexpectedly called IsEnabled")
+)
+
+// StateSyncerTest is a test state syncer
+type StateSyncerTest struct {
+	EngineTest
+
+	CantIsEnabled bool
+	IsEnabledF    func() (bool, error)
+}
+
+func (s *StateSyncerTest) Default(cant bool) {
+	s.EngineTest.Default(cant)
+	s.CantIsEnabled = cant
+}
+
+func (s *StateSyncerTest) IsEnabled() (bool, error) {
+	if s.IsEnabledF != nil {
+		return s.IsEnabledF()
+	}
+	if s.CantIsEnabled && s.T != nil {
+		s.T.Fatal(errIsEnabled)
+	}
+	return false, errIsEnabled
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+
+	"github.com/flare-foundation/flare/ids"
+)
+
+var (
+	ErrStateSyncableVMNotImplemented = errors.New("vm does not implement StateSyncableVM interface")
+	ErrStateSummaryNotFound          = errors.New("state summary not found")
+)
+
+// StateSummary describes the state of a VM at a given height. A node can sync
+// its VM to the state a summary describes instead of executing every block up
+// to its height.
+type StateSummary interface {
+	// ID uniquely identifies the summary. It must be derived from Bytes.
+	ID() ids.ID
+
+	// Height is the height of the block whose state the summary describes.
+	Height() uint64
+
+	// Bytes is the representation of the summary sent to other nodes.
+	Bytes() []byte
+
+	// Accept notifies the VM that the summary was selected to be synced to.
+	// It returns true if the VM syncs in the background, in which case it
+	// sends common.StateSyncDone to the engine once it's done. It returns false
+	// if the VM is done syncing, or skipped the summary, when Accept returns.
+	Accept() (bool, error)
+}
+
+// StateSyncableVM extends ChainVM to allow a node to sync the state of the
+// chain from other nodes rather than executing its whole history.
+type StateSyncableVM interface {
+	// StateSyncEnabled returns true if the VM wants to sync its state before
+	// being bootstrapped. VMs that return false may still serve their state
+	// summaries to other nodes.
+	StateSyncEnabled() (bool, error)
+
+	// GetOngoingSyncStateSummary returns the summary the VM was syncing to
+	// when the node was last stopped, so that syncing can resume.
+	// ErrStateSummaryNotFound is returned if there is no such summary.
+	GetOngoingSyncStateSummary() (StateSummary, error)
+
+	// GetLastStateSummary returns the latest summary the VM can serve.
+	// ErrStateSummaryNotFound is returned if there is no such summary.
+	GetLastStateSummary() (StateSummary, error)
+
+	// ParseStateSummary parses a summary received from another node.
+	ParseStateSummary(summaryBytes []byte) (StateSummary, error)
+
+	// GetStateSummary returns the summary of the state at [height].
+	// ErrStateSummaryNotFound is returned if there is no summary at [height].
+	GetStateSummary(height uint64) (StateSummary, error)
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+	"testing"
+)
+
+var (
+	errStateSyncEnabled           = errors.New("unexpectedly called StateSyncEnabled")
+	errGetOngoingSyncStateSummary = errors.New("unexpectedly called GetOngoingSyncStateSummary")
+	errGetLastStateSummary        = errors.New("unexpectedly called GetLastStateSummary")
+	errParseStateSummary          = errors.New("unexpectedly called ParseStateSummary")
+	errGetStateSummary            = errors.New("unexpectedly called GetStateSummary")
+
+	_ StateSyncableVM = &TestStateSyncableVM{}
+)
+
+// TestStateSyncableVM is a StateSyncableVM that is useful for testing.
+type TestStateSyncableVM struct {
+	T *testing.T
+
+	CantStateSyncEnabled,
+	CantGetOngoingSyncStateSummary,
+	CantGetLastStateSummary,
+	CantParseStateSummary,
+	CantGetStateSummary bool
+
+	StateSyncEnabledF           func() (bool, error)
+	GetOngoingSyncStateSummaryF func() (StateSummary, error)
+	GetLastStateSummaryF        func() (StateSummary, error)
+	ParseStateSummaryF          func(summaryBytes []byte) (StateSummary, error)
+	GetStateSummaryF            func(height uint64) (StateSummary, error)
+}
+
+func (vm *TestStateSyncableVM) StateSyncEnabled() (bool, error) {
+	if vm.StateSyncEnabledF != nil {
+		return vm.StateSyncEnabledF()
+	}
+	if vm.CantStateSyncEnabled && vm.T != nil {
+		vm.T.Fatal(errStateSyncEnabled)
+	}
+	return false, errStateSyncEnabled
+}
+
+func (vm *TestStateSyncableVM) GetOngoingSyncStateSummary() (StateSummary, error) {
+	if vm.GetOngoingSyncStateSummaryF != nil {
+		return vm.GetOngoingSyncStateSummaryF()
+	}
+	if vm.CantGetOngoingSyncStateSummary && vm.T != nil {
+		vm.T.Fatal(errGetOngoingSyncStateSummary)
+	}
+	return nil, errGetOngoingSyncStateSummary
+}
+
+func (vm *TestStateSyncableVM) GetLastStateSummary() (StateSummary, error) {
+	if vm.GetLastStateSummaryF != nil {
+		return vm.GetLastStateSummaryF()
+	}
+	if vm.CantGetLastStateSummary && vm.T != nil {
+		vm.T.Fatal(errGetLastStateSummary)
+	}
+	return nil, errGetLastStateSummary
+}
+
+func (vm *TestStateSyncableVM) ParseStateSummary(summaryBytes []byte) (StateSummary, error) {
+	if vm.ParseStateSummaryF != nil {
+		return vm.ParseStateSummaryF(summaryBytes)
+	}
+	if vm.CantParseStateSummary && vm.T != nil {
+		vm.T.Fatal(errParseStateSummary)
+	}
+	return nil, errParseStateSummary
+}
+
+func (vm *TestStateSyncableVM) GetStateSummary(height uint64) (StateSummary, error) {
+	if vm.GetStateSummaryF != nil {
+		return vm.GetStateSummaryF(height)
+	}
+	if vm.CantGetStateSummary && vm.T != nil {
+		vm.T.Fatal(errGetStateSummary)
+	}
+	return nil, errGetStateSummary
+}
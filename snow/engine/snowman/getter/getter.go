@@ -23,6 +23,7 @@ func New(vm block.ChainVM, commonCfg common.Config) (common.AllGetsServer, error
 		cfg:    commonCfg,
 		log:    commonCfg.Ctx.Log,
 	}
+	gh.ssVM, _ = vm.(block.StateSyncableVM)
 
 	var err error
 	gh.getAncestorsBlks, err = metric.NewAverager(
@@ -36,6 +37,7 @@ func New(vm block.ChainVM, commonCfg common.Config) (common.AllGetsServer, error
 
 type getter struct {
 	vm     block.ChainVM
+	ssVM   block.StateSyncableVM // nil if the VM doesn't support state sync
 	sender common.Sender
 	cfg    common.Config
 
@@ -96,3 +98,49 @@ func (gh *getter) Get(validatorID ids.ShortID, requestID uint32, blkID ids.ID) e
 	gh.sender.SendPut(validatorID, requestID, blkID, blk.Bytes())
 	return nil
 }
+
+func (gh *getter) GetStateSummaryFrontier(validatorID ids.ShortID, requestID uint32) error {
+	// The summaries are served whether or not this node state synced itself.
+	if gh.ssVM == nil {
+		gh.log.Debug("state sync not supported. Dropping GetStateSummaryFrontier(%s, %d)", validatorID, requestID)
+		return nil
+	}
+
+	summary, err := gh.ssVM.GetLastStateSummary()
+	if err != nil {
+		gh.log.Debug("couldn't get last state summary with %s. Dropping GetStateSummaryFrontier(%s, %d)",
+			err, validatorID, requestID)
+		return nil
+	}
+	gh.sender.SendStateSummaryFrontier(validatorID, requestID, summary.Bytes())
+	return nil
+}
+
+func (gh *getter) GetAcceptedStateSummary(validatorID ids.ShortID, requestID uint32, heights []uint64) error {
+	if gh.ssVM == nil {
+		gh.log.Debug("state sync not supported. Dropping GetAcceptedStateSummary(%s, %d)", validatorID, requestID)
+		return nil
+	}
+
+	if len(heights) > common.MaxStateSummaryHeights {
+		gh.log.Debug("GetAcceptedStateSummary(%s, %d) requested %d heights, only serving the first %d",
+			validatorID, requestID, len(heights), common.MaxStateSummaryHeights)
+		heights = heights[:common.MaxStateSummaryHeights]
+	}
+
+	summaryIDs := make([]ids.ID, 0, len(heights))
+	for _, height := range heights {
+		summary, err := gh.ssVM.GetStateSummary(height)
+		if err == block.ErrStateSyncableVMNotImplemented {
+			gh.log.Debug("state sync not supported. Dropping GetAcceptedStateSummary(%s, %d)", validatorID, requestID)
+			return nil
+		}
+		if err != nil {
+			gh.log.Debug("couldn't get state summary at height %d with %s", height, err)
+			continue
+		}
+		summaryIDs = append(summaryIDs, summary.ID())
+	}
+	gh.sender.SendAcceptedStateSummary(validatorID, requestID, summaryIDs)
+	return nil
+}
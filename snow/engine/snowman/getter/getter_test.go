@@ -181,3 +181,71 @@ func TestFilterAccepted(t *testing.T) {
 		t.Fatalf("Blk shouldn't be accepted")
 	}
 }
+
+type stateSyncableTestVM struct {
+	*block.TestVM
+	*block.TestStateSyncableVM
+}
+
+func TestStateSummaries(t *testing.T) {
+	vm, sender, config := testSetup(t)
+	ssVM := &block.TestStateSyncableVM{T: t}
+	ssVM.CantGetLastStateSummary = true
+	ssVM.CantGetStateSummary = true
+
+	summary := &block.TestStateSummary{
+		IDV:     ids.GenerateTestID(),
+		HeightV: 100,
+		BytesV:  []byte{1, 2, 3},
+	}
+	ssVM.GetLastStateSummaryF = func() (block.StateSummary, error) { return summary, nil }
+	lookups := 0
+	ssVM.GetStateSummaryF = func(height uint64) (block.StateSummary, error) {
+		lookups++
+		if height == summary.Height() {
+			return summary, nil
+		}
+		return nil, block.ErrStateSummaryNotFound
+	}
+
+	bs, err := New(stateSyncableTestVM{TestVM: vm, TestStateSyncableVM: ssVM}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frontier []byte
+	sender.SendStateSummaryFrontierF = func(_ ids.ShortID, _ uint32, summary []byte) {
+		frontier = summary
+	}
+	assert.NoError(t, bs.GetStateSummaryFrontier(ids.ShortEmpty, 0))
+	assert.Equal(t, summary.Bytes(), frontier)
+
+	var accepted []ids.ID
+	sender.SendAcceptedStateSummaryF = func(_ ids.ShortID, _ uint32, summaryIDs []ids.ID) {
+		accepted = summaryIDs
+	}
+	assert.NoError(t, bs.GetAcceptedStateSummary(ids.ShortEmpty, 0, []uint64{summary.Height(), summary.Height() + 1}))
+	assert.Equal(t, []ids.ID{summary.ID()}, accepted)
+
+	// Only the first heights of a request are looked up
+	heights := make([]uint64, common.MaxStateSummaryHeights+10)
+	for i := range heights {
+		heights[i] = summary.Height() + uint64(i)
+	}
+	lookups = 0
+	assert.NoError(t, bs.GetAcceptedStateSummary(ids.ShortEmpty, 0, heights))
+	assert.Equal(t, []ids.ID{summary.ID()}, accepted)
+	assert.Equal(t, common.MaxStateSummaryHeights, lookups)
+}
+
+func TestStateSummariesNotSupported(t *testing.T) {
+	vm, _, config := testSetup(t)
+
+	// The sender fails the test if a state summary is sent
+	bs, err := New(vm, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, bs.GetStateSummaryFrontier(ids.ShortEmpty, 0))
+	assert.NoError(t, bs.GetAcceptedStateSummary(ids.ShortEmpty, 0, []uint64{100}))
+}
@@ -0,0 +1,20 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncer
+
+import (
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/common/tracker"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+)
+
+type Config struct {
+	common.Config
+	common.AllGetsServer
+
+	VM block.ChainVM
+	// WeightTracker must not be shared with the bootstrapper, which starts
+	// once state syncing is done
+	WeightTracker tracker.WeightTracker
+}
@@ -0,0 +1,492 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncer
+
+import (
+	"fmt"
+	stdmath "math"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/snow/validation"
+	"github.com/flare-foundation/flare/utils/math"
+	"github.com/flare-foundation/flare/version"
+)
+
+// maxStateSyncAttempts is the number of times state syncing is attempted when
+// not enough beacons respond, after which the chain is bootstrapped from its
+// last accepted block instead
+const maxStateSyncAttempts = 5
+
+var _ common.StateSyncer = &stateSyncer{}
+
+// weightedSummary is a state summary along with the stake of the beacons that
+// accepted it
+type weightedSummary struct {
+	summary block.StateSummary
+	weight  uint64
+}
+
+type stateSyncer struct {
+	Config
+	common.Halter
+
+	// list of NoOpsHandler for messages dropped by the state syncer
+	common.AcceptedFrontierHandler
+	common.AcceptedHandler
+	common.AncestorsHandler
+	common.PutHandler
+	common.QueryHandler
+	common.ChitsHandler
+	common.AppHandler
+
+	// onDoneStateSyncing is called, with the last request ID used, once state
+	// syncing is done or skipped. It starts bootstrapping.
+	onDoneStateSyncing func(lastReqID uint32) error
+
+	// ssVM is nil if the VM doesn't implement state sync
+	ssVM block.StateSyncableVM
+
+	started bool
+	// number of times state syncing has been attempted
+	attempts int
+	// true if the VM is syncing to the accepted summary in the background
+	waitingForVM bool
+
+	// Holds the beacons that were sampled for the state summary frontier
+	sampledBeacons validation.Set
+	// IDs of beacons we should request a state summary frontier from
+	pendingSendStateSummaryFrontier ids.ShortSet
+	// IDs of beacons we requested a state summary frontier from but haven't
+	// received a reply yet
+	pendingReceiveStateSummaryFrontier ids.ShortSet
+	// IDs of beacons that failed to respond with their state summary frontier
+	failedStateSummaryFrontier ids.ShortSet
+
+	// IDs of beacons we should ask to vote on the summaries
+	pendingSendAcceptedStateSummary ids.ShortSet
+	// IDs of beacons we asked to vote on the summaries but haven't received a
+	// reply yet
+	pendingReceiveAcceptedStateSummary ids.ShortSet
+	// IDs of beacons that failed to vote on the summaries
+	failedAcceptedStateSummary ids.ShortSet
+
+	// Summaries received in the state summary frontiers, and the summary the
+	// VM was syncing to when the node was stopped, by ID
+	weightedSummaries map[ids.ID]*weightedSummary
+	// ID of the summary the VM was syncing to when the node was stopped, if
+	// any
+	ongoingSummaryID ids.ID
+}
+
+func New(config Config, onDoneStateSyncing func(lastReqID uint32) error) common.StateSyncer {
+	ssVM, _ := config.VM.(block.StateSyncableVM)
+	return &stateSyncer{
+		Config: config,
+
+		AcceptedFrontierHandler: common.NewNoOpAcceptedFrontierHandler(config.Ctx.Log),
+		AcceptedHandler:         common.NewNoOpAcceptedHandler(config.Ctx.Log),
+		AncestorsHandler:        common.NewNoOpAncestorsHandler(config.Ctx.Log),
+		PutHandler:              common.NewNoOpPutHandler(config.Ctx.Log),
+		QueryHandler:            common.NewNoOpQueryHandler(config.Ctx.Log),
+		ChitsHandler:            common.NewNoOpChitsHandler(config.Ctx.Log),
+		AppHandler:              common.NewNoOpAppHandler(config.Ctx.Log),
+
+		onDoneStateSyncing: onDoneStateSyncing,
+		ssVM:               ssVM,
+	}
+}
+
+func (ss *stateSyncer) IsEnabled() (bool, error) {
+	if ss.ssVM == nil {
+		return false, nil
+	}
+	enabled, err := ss.ssVM.StateSyncEnabled()
+	if err == block.ErrStateSyncableVMNotImplemented {
+		return false, nil
+	}
+	return enabled, err
+}
+
+func (ss *stateSyncer) Start(startReqID uint32) error {
+	ss.Config.SharedCfg.RequestID = startReqID
+
+	enabled, err := ss.IsEnabled()
+	if err != nil {
+		return fmt.Errorf("couldn't check if state sync is enabled: %w", err)
+	}
+	if !enabled {
+		ss.Ctx.Log.Debug("state sync isn't enabled by the VM")
+		return ss.onDoneStateSyncing(startReqID)
+	}
+	if ss.Beacons.Len() == 0 {
+		ss.Ctx.Log.Info("State sync skipped due to no provided beacons")
+		return ss.onDoneStateSyncing(startReqID)
+	}
+
+	ss.Ctx.Log.Info("Starting state sync...")
+	ss.Ctx.SetState(snow.StateSyncing)
+
+	if !ss.WeightTracker.EnoughConnectedWeight() {
+		// Wait for enough beacons to be connected
+		return nil
+	}
+	ss.started = true
+	return ss.startup()
+}
+
+func (ss *stateSyncer) startup() error {
+	beacons, err := ss.Beacons.Sample(ss.Config.SampleK)
+	if err != nil {
+		return err
+	}
+
+	ss.sampledBeacons = validation.NewSet()
+	if err := ss.sampledBeacons.Set(beacons); err != nil {
+		return err
+	}
+
+	ss.pendingSendStateSummaryFrontier.Clear()
+	for _, beacon := range beacons {
+		ss.pendingSendStateSummaryFrontier.Add(beacon.ID())
+	}
+	ss.pendingReceiveStateSummaryFrontier.Clear()
+	ss.failedStateSummaryFrontier.Clear()
+
+	ss.pendingSendAcceptedStateSummary.Clear()
+	for _, beacon := range ss.Beacons.List() {
+		ss.pendingSendAcceptedStateSummary.Add(beacon.ID())
+	}
+	ss.pendingReceiveAcceptedStateSummary.Clear()
+	ss.failedAcceptedStateSummary.Clear()
+
+	ss.weightedSummaries = make(map[ids.ID]*weightedSummary)
+	ss.ongoingSummaryID = ids.Empty
+
+	// Resume syncing to the summary the VM was syncing to, if enough beacons
+	// still accept it
+	switch ongoingSummary, err := ss.ssVM.GetOngoingSyncStateSummary(); err {
+	case nil:
+		ss.ongoingSummaryID = ongoingSummary.ID()
+		ss.weightedSummaries[ss.ongoingSummaryID] = &weightedSummary{summary: ongoingSummary}
+	case block.ErrStateSummaryNotFound:
+	default:
+		return fmt.Errorf("couldn't get the ongoing state summary: %w", err)
+	}
+
+	ss.attempts++
+	ss.Config.SharedCfg.RequestID++
+	ss.sendGetStateSummaryFrontiers()
+	return nil
+}
+
+func (ss *stateSyncer) restart() error {
+	if ss.attempts >= maxStateSyncAttempts {
+		ss.Ctx.Log.Warn("giving up state syncing after %d attempts, bootstrapping instead", ss.attempts)
+		return ss.onDoneStateSyncing(ss.Config.SharedCfg.RequestID)
+	}
+	return ss.startup()
+}
+
+func (ss *stateSyncer) StateSummaryFrontier(validatorID ids.ShortID, requestID uint32, summaryBytes []byte) error {
+	// ignores any late responses
+	if requestID != ss.Config.SharedCfg.RequestID {
+		ss.Ctx.Log.Debug("Received an Out-of-Sync StateSummaryFrontier - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			ss.Config.SharedCfg.RequestID,
+			requestID)
+		return nil
+	}
+
+	if !ss.pendingReceiveStateSummaryFrontier.Contains(validatorID) {
+		ss.Ctx.Log.Debug("Received a StateSummaryFrontier message from %s unexpectedly", validatorID)
+		return nil
+	}
+
+	// Mark that we received a response from [validatorID]
+	ss.pendingReceiveStateSummaryFrontier.Remove(validatorID)
+
+	if len(summaryBytes) != 0 {
+		summary, err := ss.ssVM.ParseStateSummary(summaryBytes)
+		if err != nil {
+			ss.Ctx.Log.Debug("couldn't parse the state summary from %s: %s", validatorID, err)
+			ss.failedStateSummaryFrontier.Add(validatorID)
+		} else if _, exists := ss.weightedSummaries[summary.ID()]; !exists {
+			ss.weightedSummaries[summary.ID()] = &weightedSummary{summary: summary}
+		}
+	}
+
+	ss.sendGetStateSummaryFrontiers()
+
+	// still waiting on requests
+	if ss.pendingReceiveStateSummaryFrontier.Len() != 0 {
+		return nil
+	}
+
+	// Keep the proportion of ss.Alpha in the weight of the sampled beacons
+	frontierAlpha := float64(ss.sampledBeacons.Weight()*ss.Alpha) / float64(ss.Beacons.Weight())
+	failedBeaconWeight, err := ss.Beacons.SubsetWeight(ss.failedStateSummaryFrontier)
+	if err != nil {
+		return err
+	}
+	if float64(ss.sampledBeacons.Weight())-frontierAlpha < float64(failedBeaconWeight) {
+		if ss.Config.RetryBootstrap {
+			ss.Ctx.Log.Debug("Not enough state summary frontiers received, restarting state sync... - Beacons: %d - Failed beacons: %d "+
+				"- state sync attempt: %d", ss.Beacons.Len(), ss.failedStateSummaryFrontier.Len(), ss.attempts)
+			return ss.restart()
+		}
+
+		ss.Ctx.Log.Debug("Didn't receive enough state summary frontiers - failed beacons: %d, "+
+			"state sync attempt: %d", ss.failedStateSummaryFrontier.Len(), ss.attempts)
+	}
+
+	if len(ss.weightedSummaries) == 0 {
+		ss.Ctx.Log.Info("No state summary was received, skipping state sync")
+		return ss.onDoneStateSyncing(ss.Config.SharedCfg.RequestID)
+	}
+
+	ss.Config.SharedCfg.RequestID++
+	ss.sendGetAcceptedStateSummaries()
+	return nil
+}
+
+func (ss *stateSyncer) GetStateSummaryFrontierFailed(validatorID ids.ShortID, requestID uint32) error {
+	// ignores any late responses
+	if requestID != ss.Config.SharedCfg.RequestID {
+		ss.Ctx.Log.Debug("Received an Out-of-Sync GetStateSummaryFrontierFailed - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			ss.Config.SharedCfg.RequestID,
+			requestID)
+		return nil
+	}
+
+	// If we can't get a response from [validatorID], act as though they sent
+	// no summary and add the validator to the failed list
+	ss.failedStateSummaryFrontier.Add(validatorID)
+	return ss.StateSummaryFrontier(validatorID, requestID, nil)
+}
+
+func (ss *stateSyncer) AcceptedStateSummary(validatorID ids.ShortID, requestID uint32, summaryIDs []ids.ID) error {
+	// ignores any late responses
+	if requestID != ss.Config.SharedCfg.RequestID {
+		ss.Ctx.Log.Debug("Received an Out-of-Sync AcceptedStateSummary - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			ss.Config.SharedCfg.RequestID,
+			requestID)
+		return nil
+	}
+
+	if !ss.pendingReceiveAcceptedStateSummary.Contains(validatorID) {
+		ss.Ctx.Log.Debug("Received an AcceptedStateSummary message from %s unexpectedly", validatorID)
+		return nil
+	}
+	// Mark that we received a response from [validatorID]
+	ss.pendingReceiveAcceptedStateSummary.Remove(validatorID)
+
+	weight, _ := ss.Beacons.GetWeight(validatorID)
+	for _, summaryID := range summaryIDs {
+		ws, ok := ss.weightedSummaries[summaryID]
+		if !ok {
+			ss.Ctx.Log.Debug("Received a vote from %s for unknown summary %s", validatorID, summaryID)
+			continue
+		}
+		newWeight, err := math.Add64(weight, ws.weight)
+		if err != nil {
+			ss.Ctx.Log.Error("Error calculating the AcceptedStateSummary votes - weight: %v, previousWeight: %v", weight, ws.weight)
+			newWeight = stdmath.MaxUint64
+		}
+		ws.weight = newWeight
+	}
+
+	ss.sendGetAcceptedStateSummaries()
+
+	// wait on pending responses
+	if ss.pendingReceiveAcceptedStateSummary.Len() != 0 {
+		return nil
+	}
+
+	// We've received the votes of every beacon. Sync to the highest summary
+	// that has a sufficient weight behind it.
+	var selected block.StateSummary
+	for _, ws := range ss.weightedSummaries {
+		if ws.weight < ss.Alpha {
+			continue
+		}
+		if selected == nil || ws.summary.Height() > selected.Height() {
+			selected = ws.summary
+		}
+	}
+
+	if selected == nil {
+		failedBeaconWeight, err := ss.Beacons.SubsetWeight(ss.failedAcceptedStateSummary)
+		if err != nil {
+			return err
+		}
+		if ss.Config.RetryBootstrap && ss.Beacons.Weight()-ss.Alpha < failedBeaconWeight {
+			ss.Ctx.Log.Debug("Not enough votes received, restarting state sync... - Beacons: %d - Failed beacons: %d "+
+				"- state sync attempt: %d", ss.Beacons.Len(), ss.failedAcceptedStateSummary.Len(), ss.attempts)
+			return ss.restart()
+		}
+
+		ss.Ctx.Log.Info("No state summary was accepted by enough stake, skipping state sync")
+		return ss.onDoneStateSyncing(ss.Config.SharedCfg.RequestID)
+	}
+	return ss.acceptSummary(selected)
+}
+
+func (ss *stateSyncer) GetAcceptedStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error {
+	// ignores any late responses
+	if requestID != ss.Config.SharedCfg.RequestID {
+		ss.Ctx.Log.Debug("Received an Out-of-Sync GetAcceptedStateSummaryFailed - validator: %v - expectedRequestID: %v, requestID: %v",
+			validatorID,
+			ss.Config.SharedCfg.RequestID,
+			requestID)
+		return nil
+	}
+
+	// If we can't get a response from [validatorID], act as though they said
+	// that none of the summaries are accepted
+	ss.failedAcceptedStateSummary.Add(validatorID)
+	return ss.AcceptedStateSummary(validatorID, requestID, nil)
+}
+
+// acceptSummary hands [summary] to the VM, unless the VM is already past it
+func (ss *stateSyncer) acceptSummary(summary block.StateSummary) error {
+	if summary.ID() != ss.ongoingSummaryID {
+		lastAcceptedID, err := ss.VM.LastAccepted()
+		if err != nil {
+			return fmt.Errorf("couldn't get last accepted ID: %w", err)
+		}
+		lastAccepted, err := ss.VM.GetBlock(lastAcceptedID)
+		if err != nil {
+			return fmt.Errorf("couldn't get last accepted block: %w", err)
+		}
+		if lastAcceptedHeight := lastAccepted.Height(); summary.Height() <= lastAcceptedHeight {
+			ss.Ctx.Log.Info("Skipping state sync to height %d as the last accepted block is at height %d",
+				summary.Height(), lastAcceptedHeight)
+			return ss.onDoneStateSyncing(ss.Config.SharedCfg.RequestID)
+		}
+	}
+
+	ss.Ctx.Log.Info("State syncing to summary %s at height %d", summary.ID(), summary.Height())
+	syncing, err := summary.Accept()
+	if err != nil {
+		return fmt.Errorf("couldn't accept state summary %s: %w", summary.ID(), err)
+	}
+	if syncing {
+		ss.Ctx.Log.Info("Waiting for the VM to finish state syncing")
+		ss.waitingForVM = true
+		return nil
+	}
+	return ss.onDoneStateSyncing(ss.Config.SharedCfg.RequestID)
+}
+
+// Ask up to [common.MaxOutstandingBootstrapRequests] sampled beacons to send
+// their state summary frontier
+func (ss *stateSyncer) sendGetStateSummaryFrontiers() {
+	validators := ids.NewShortSet(1)
+	for ss.pendingSendStateSummaryFrontier.Len() > 0 && ss.pendingReceiveStateSummaryFrontier.Len() < common.MaxOutstandingBootstrapRequests {
+		validator, _ := ss.pendingSendStateSummaryFrontier.Pop()
+		validators.Add(validator)
+		ss.pendingReceiveStateSummaryFrontier.Add(validator)
+	}
+
+	if validators.Len() > 0 {
+		ss.Sender.SendGetStateSummaryFrontier(validators, ss.Config.SharedCfg.RequestID)
+	}
+}
+
+// Ask up to [common.MaxOutstandingBootstrapRequests] beacons which of the
+// received summaries they accepted
+func (ss *stateSyncer) sendGetAcceptedStateSummaries() {
+	validators := ids.NewShortSet(1)
+	for ss.pendingSendAcceptedStateSummary.Len() > 0 && ss.pendingReceiveAcceptedStateSummary.Len() < common.MaxOutstandingBootstrapRequests {
+		validator, _ := ss.pendingSendAcceptedStateSummary.Pop()
+		validators.Add(validator)
+		ss.pendingReceiveAcceptedStateSummary.Add(validator)
+	}
+
+	if validators.Len() == 0 {
+		return
+	}
+
+	heightsSet := make(map[uint64]struct{}, len(ss.weightedSummaries))
+	heights := make([]uint64, 0, len(ss.weightedSummaries))
+	for _, ws := range ss.weightedSummaries {
+		height := ws.summary.Height()
+		if _, exists := heightsSet[height]; exists {
+			continue
+		}
+		heightsSet[height] = struct{}{}
+		heights = append(heights, height)
+		if len(heights) == common.MaxStateSummaryHeights {
+			break
+		}
+	}
+
+	ss.Ctx.Log.Debug("sent %d more GetAcceptedStateSummary messages with %d more to send",
+		validators.Len(),
+		ss.pendingSendAcceptedStateSummary.Len(),
+	)
+	ss.Sender.SendGetAcceptedStateSummary(validators, ss.Config.SharedCfg.RequestID, heights)
+}
+
+func (ss *stateSyncer) Connected(nodeID ids.ShortID, nodeVersion version.Application) error {
+	if err := ss.VM.Connected(nodeID, nodeVersion); err != nil {
+		return err
+	}
+
+	if err := ss.WeightTracker.AddWeightForNode(nodeID); err != nil {
+		return err
+	}
+
+	if ss.WeightTracker.EnoughConnectedWeight() && !ss.started {
+		ss.started = true
+		return ss.startup()
+	}
+	return nil
+}
+
+func (ss *stateSyncer) Disconnected(nodeID ids.ShortID) error {
+	if err := ss.VM.Disconnected(nodeID); err != nil {
+		return err
+	}
+
+	return ss.WeightTracker.RemoveWeightForNode(nodeID)
+}
+
+func (ss *stateSyncer) Notify(msg common.Message) error {
+	if msg != common.StateSyncDone {
+		ss.Ctx.Log.Debug("dropping message %s from the VM while state syncing", msg)
+		return nil
+	}
+	if !ss.waitingForVM {
+		ss.Ctx.Log.Debug("dropping unexpected %s message", msg)
+		return nil
+	}
+	ss.waitingForVM = false
+	ss.Ctx.Log.Info("VM finished state syncing")
+	return ss.onDoneStateSyncing(ss.Config.SharedCfg.RequestID)
+}
+
+func (ss *stateSyncer) Timeout() error { return nil }
+
+func (ss *stateSyncer) Gossip() error { return nil }
+
+func (ss *stateSyncer) Shutdown() error { return nil }
+
+func (ss *stateSyncer) Context() *snow.ConsensusContext { return ss.Config.Ctx }
+
+func (ss *stateSyncer) HealthCheck() (interface{}, error) {
+	vmIntf, vmErr := ss.VM.HealthCheck()
+	intf := map[string]interface{}{
+		"consensus": struct{}{},
+		"vm":        vmIntf,
+	}
+	return intf, vmErr
+}
+
+func (ss *stateSyncer) GetVM() common.VM { return ss.VM }
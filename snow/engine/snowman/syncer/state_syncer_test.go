@@ -0,0 +1,274 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/engine/common"
+	"github.com/flare-foundation/flare/snow/engine/common/tracker"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/snow/validation"
+	"github.com/flare-foundation/flare/version"
+)
+
+var errUnknownSummary = errors.New("unknown summary")
+
+type stateSyncableTestVM struct {
+	*block.TestVM
+	*block.TestStateSyncableVM
+}
+
+type testEnv struct {
+	config     Config
+	sender     *common.SenderTest
+	vm         *block.TestVM
+	ssVM       *block.TestStateSyncableVM
+	beacons    []ids.ShortID
+	lastBlock  *snowman.TestBlock
+	doneReqID  uint32
+	doneCalled bool
+}
+
+// newTestEnv returns a state syncer config with [numBeacons] beacons of
+// weight 1 and alpha set to a majority of them
+func newTestEnv(t *testing.T, numBeacons int) *testEnv {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.SetState(snow.Bootstrapping)
+
+	beacons := validation.NewSet()
+	env := &testEnv{
+		sender: &common.SenderTest{T: t},
+		vm:     &block.TestVM{},
+		ssVM:   &block.TestStateSyncableVM{T: t},
+	}
+	env.sender.Default(true)
+	env.vm.T = t
+	env.vm.Default(true)
+	env.vm.CantConnected = false
+
+	for i := 0; i < numBeacons; i++ {
+		beaconID := ids.GenerateTestShortID()
+		if err := beacons.AddWeight(beaconID, 1); err != nil {
+			t.Fatal(err)
+		}
+		env.beacons = append(env.beacons, beaconID)
+	}
+
+	env.lastBlock = &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	env.vm.LastAcceptedF = func() (ids.ID, error) { return env.lastBlock.ID(), nil }
+	env.vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID != env.lastBlock.ID() {
+			return nil, errors.New("unknown block")
+		}
+		return env.lastBlock, nil
+	}
+	env.ssVM.StateSyncEnabledF = func() (bool, error) { return true, nil }
+	env.ssVM.GetOngoingSyncStateSummaryF = func() (block.StateSummary, error) {
+		return nil, block.ErrStateSummaryNotFound
+	}
+
+	commonConfig := common.Config{
+		Ctx:          ctx,
+		Validators:   beacons,
+		Beacons:      beacons,
+		SampleK:      beacons.Len(),
+		StartupAlpha: beacons.Weight()/2 + 1,
+		Alpha:        beacons.Weight()/2 + 1,
+		Sender:       env.sender,
+		SharedCfg:    &common.SharedConfig{},
+	}
+	env.config = Config{
+		Config:        commonConfig,
+		VM:            stateSyncableTestVM{TestVM: env.vm, TestStateSyncableVM: env.ssVM},
+		WeightTracker: tracker.NewWeightTracker(commonConfig.Beacons, commonConfig.StartupAlpha),
+	}
+	return env
+}
+
+func (env *testEnv) newStateSyncer() common.StateSyncer {
+	return New(env.config, func(lastReqID uint32) error {
+		env.doneCalled = true
+		env.doneReqID = lastReqID
+		return nil
+	})
+}
+
+// serve makes the state syncer parse the bytes of [summaries]
+func (env *testEnv) serve(summaries ...*block.TestStateSummary) {
+	env.ssVM.ParseStateSummaryF = func(summaryBytes []byte) (block.StateSummary, error) {
+		for _, summary := range summaries {
+			if bytes.Equal(summary.Bytes(), summaryBytes) {
+				return summary, nil
+			}
+		}
+		return nil, errUnknownSummary
+	}
+}
+
+func TestStateSyncNotEnabled(t *testing.T) {
+	assert := assert.New(t)
+	env := newTestEnv(t, 1)
+	env.config.VM = env.vm
+
+	syncer := env.newStateSyncer()
+	enabled, err := syncer.IsEnabled()
+	assert.NoError(err)
+	assert.False(enabled)
+
+	assert.NoError(syncer.Start(5))
+	assert.True(env.doneCalled)
+	assert.EqualValues(5, env.doneReqID)
+	assert.EqualValues(snow.Bootstrapping, env.config.Ctx.GetState())
+}
+
+func TestStateSyncWaitsForBeacons(t *testing.T) {
+	assert := assert.New(t)
+	env := newTestEnv(t, 3)
+
+	frontierRequested := ids.ShortSet{}
+	env.sender.SendGetStateSummaryFrontierF = func(nodeIDs ids.ShortSet, _ uint32) {
+		frontierRequested.Union(nodeIDs)
+	}
+
+	syncer := env.newStateSyncer()
+	assert.NoError(syncer.Start(0))
+	assert.EqualValues(snow.StateSyncing, env.config.Ctx.GetState())
+	assert.Zero(frontierRequested.Len())
+
+	assert.NoError(syncer.Connected(env.beacons[0], version.CurrentApp))
+	assert.Zero(frontierRequested.Len())
+
+	assert.NoError(syncer.Connected(env.beacons[1], version.CurrentApp))
+	assert.Equal(3, frontierRequested.Len())
+	assert.False(env.doneCalled)
+}
+
+func TestStateSyncToHighestAcceptedSummary(t *testing.T) {
+	assert := assert.New(t)
+	env := newTestEnv(t, 3)
+
+	lowSummary := &block.TestStateSummary{IDV: ids.GenerateTestID(), HeightV: 10, BytesV: []byte{10}, T: t}
+	highSummary := &block.TestStateSummary{IDV: ids.GenerateTestID(), HeightV: 20, BytesV: []byte{20}, T: t, CantAccept: true}
+	env.serve(lowSummary, highSummary)
+
+	var frontierReqID uint32
+	env.sender.SendGetStateSummaryFrontierF = func(_ ids.ShortSet, requestID uint32) { frontierReqID = requestID }
+	var (
+		acceptedReqID    uint32
+		requestedHeights []uint64
+	)
+	env.sender.SendGetAcceptedStateSummaryF = func(_ ids.ShortSet, requestID uint32, heights []uint64) {
+		acceptedReqID = requestID
+		requestedHeights = heights
+	}
+
+	syncer := env.newStateSyncer()
+	assert.NoError(syncer.Start(0))
+	for _, beaconID := range env.beacons {
+		assert.NoError(syncer.Connected(beaconID, version.CurrentApp))
+	}
+
+	assert.NoError(syncer.StateSummaryFrontier(env.beacons[0], frontierReqID, lowSummary.Bytes()))
+	assert.NoError(syncer.StateSummaryFrontier(env.beacons[1], frontierReqID, highSummary.Bytes()))
+	assert.NoError(syncer.GetStateSummaryFrontierFailed(env.beacons[2], frontierReqID))
+	assert.ElementsMatch([]uint64{10, 20}, requestedHeights)
+
+	// Only a single beacon accepted the highest summary
+	assert.NoError(syncer.AcceptedStateSummary(env.beacons[0], acceptedReqID, []ids.ID{lowSummary.ID()}))
+	assert.NoError(syncer.AcceptedStateSummary(env.beacons[1], acceptedReqID, []ids.ID{lowSummary.ID(), highSummary.ID()}))
+
+	lowSummary.AcceptF = func() (bool, error) { return true, nil }
+	assert.NoError(syncer.GetAcceptedStateSummaryFailed(env.beacons[2], acceptedReqID))
+	assert.False(env.doneCalled)
+
+	// The VM syncs in the background
+	assert.NoError(syncer.Notify(common.PendingTxs))
+	assert.False(env.doneCalled)
+	assert.NoError(syncer.Notify(common.StateSyncDone))
+	assert.True(env.doneCalled)
+	assert.Equal(acceptedReqID, env.doneReqID)
+}
+
+func TestStateSyncSkippedWithoutAcceptedSummary(t *testing.T) {
+	assert := assert.New(t)
+	env := newTestEnv(t, 1)
+
+	summary := &block.TestStateSummary{IDV: ids.GenerateTestID(), HeightV: 10, BytesV: []byte{10}, T: t, CantAccept: true}
+	env.serve(summary)
+
+	var reqID uint32
+	env.sender.SendGetStateSummaryFrontierF = func(_ ids.ShortSet, requestID uint32) { reqID = requestID }
+	env.sender.SendGetAcceptedStateSummaryF = func(_ ids.ShortSet, requestID uint32, _ []uint64) { reqID = requestID }
+
+	syncer := env.newStateSyncer()
+	assert.NoError(syncer.Start(0))
+	assert.NoError(syncer.Connected(env.beacons[0], version.CurrentApp))
+	assert.NoError(syncer.StateSummaryFrontier(env.beacons[0], reqID, summary.Bytes()))
+	assert.NoError(syncer.AcceptedStateSummary(env.beacons[0], reqID, nil))
+	assert.True(env.doneCalled)
+}
+
+func TestStateSyncSkippedBehindLastAccepted(t *testing.T) {
+	assert := assert.New(t)
+	env := newTestEnv(t, 1)
+	env.lastBlock.HeightV = 10
+
+	summary := &block.TestStateSummary{IDV: ids.GenerateTestID(), HeightV: 10, BytesV: []byte{10}, T: t, CantAccept: true}
+	env.serve(summary)
+
+	var reqID uint32
+	env.sender.SendGetStateSummaryFrontierF = func(_ ids.ShortSet, requestID uint32) { reqID = requestID }
+	env.sender.SendGetAcceptedStateSummaryF = func(_ ids.ShortSet, requestID uint32, _ []uint64) { reqID = requestID }
+
+	syncer := env.newStateSyncer()
+	assert.NoError(syncer.Start(0))
+	assert.NoError(syncer.Connected(env.beacons[0], version.CurrentApp))
+	assert.NoError(syncer.StateSummaryFrontier(env.beacons[0], reqID, summary.Bytes()))
+	assert.NoError(syncer.AcceptedStateSummary(env.beacons[0], reqID, []ids.ID{summary.ID()}))
+	assert.True(env.doneCalled)
+}
+
+func TestStateSyncResumesOngoingSummary(t *testing.T) {
+	assert := assert.New(t)
+	env := newTestEnv(t, 1)
+	// The VM accepted the summary before the node was stopped
+	env.lastBlock.HeightV = 10
+
+	summary := &block.TestStateSummary{IDV: ids.GenerateTestID(), HeightV: 10, BytesV: []byte{10}, T: t}
+	env.serve(summary)
+	env.ssVM.GetOngoingSyncStateSummaryF = func() (block.StateSummary, error) { return summary, nil }
+
+	var reqID uint32
+	env.sender.SendGetStateSummaryFrontierF = func(_ ids.ShortSet, requestID uint32) { reqID = requestID }
+	env.sender.SendGetAcceptedStateSummaryF = func(_ ids.ShortSet, requestID uint32, _ []uint64) { reqID = requestID }
+
+	accepted := false
+	summary.AcceptF = func() (bool, error) {
+		accepted = true
+		return false, nil
+	}
+
+	syncer := env.newStateSyncer()
+	assert.NoError(syncer.Start(0))
+	assert.NoError(syncer.Connected(env.beacons[0], version.CurrentApp))
+	assert.NoError(syncer.GetStateSummaryFrontierFailed(env.beacons[0], reqID))
+	assert.NoError(syncer.AcceptedStateSummary(env.beacons[0], reqID, []ids.ID{summary.ID()}))
+	assert.True(accepted)
+	assert.True(env.doneCalled)
+}
@@ -40,6 +40,10 @@ type Benchlist interface {
 	// IsBenched returns true if messages to [validatorID]
 	// should not be sent over the network and should immediately fail.
 	IsBenched(validatorID ids.ShortID) bool
+	// SetParameters changes when validators are benched, and for how long.
+	// Validators that are already benched stay on the bench until the end of
+	// their original duration.
+	SetParameters(threshold int, minimumFailingDuration, duration time.Duration)
 }
 
 // Data about a validator who is benched
@@ -281,6 +285,16 @@ func (b *benchlist) RegisterFailure(validatorID ids.ShortID) {
 	}
 }
 
+// See Benchlist
+func (b *benchlist) SetParameters(threshold int, minimumFailingDuration, duration time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.threshold = threshold
+	b.minimumFailingDuration = minimumFailingDuration
+	b.duration = duration
+}
+
 // Assumes [b.lock] is held
 // Assumes [validatorID] is not already benched
 func (b *benchlist) bench(validatorID ids.ShortID) {
@@ -419,3 +419,54 @@ func TestBenchlistRemove(t *testing.T) {
 
 	assert.Equal(t, 3, count)
 }
+
+// Test that the parameters of a benchlist can be changed
+func TestBenchlistSetParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	validators := validation.NewSet()
+	validator1 := validation.GenerateRandomValidator(50)
+	validator2 := validation.GenerateRandomValidator(50)
+	assert.NoError(validators.AddWeight(validator1.ID(), validator1.Weight()))
+	assert.NoError(validators.AddWeight(validator2.ID(), validator2.Weight()))
+
+	benchable := &TestBenchable{T: t}
+	benchable.Default(true)
+	benched := false
+	benchable.BenchedF = func(ids.ID, ids.ShortID) { benched = true }
+
+	benchIntf, err := NewBenchlist(
+		ids.Empty,
+		logging.NoLog{},
+		benchable,
+		validators,
+		10,
+		minimumFailingDuration,
+		time.Minute,
+		0.5,
+		prometheus.NewRegistry(),
+	)
+	assert.NoError(err)
+	b := benchIntf.(*benchlist)
+	defer b.timer.Stop()
+	now := time.Now()
+	b.clock.Set(now)
+
+	b.SetParameters(2, time.Second, time.Hour)
+
+	b.RegisterFailure(validator1.ID())
+	assert.False(b.IsBenched(validator1.ID()))
+
+	now = now.Add(2 * time.Second)
+	b.clock.Set(now)
+	b.RegisterFailure(validator1.ID())
+	assert.True(b.IsBenched(validator1.ID()))
+	assert.True(benched)
+
+	// The validator is benched for between [duration/2] and [duration]
+	b.lock.Lock()
+	benchedUntil := b.benchedQueue[0].benchedUntil
+	b.lock.Unlock()
+	assert.False(benchedUntil.Before(now.Add(30 * time.Minute)))
+	assert.False(benchedUntil.After(now.Add(time.Hour)))
+}
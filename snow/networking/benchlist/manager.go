@@ -37,6 +37,9 @@ type Manager interface {
 	// [validatorID] is benched. If called on an id.ShortID that does
 	// not map to a validator, it will return an empty array.
 	GetBenched(validatorID ids.ShortID) []ids.ID
+	// SetParameters changes when validators are benched, and for how long, on
+	// all chains. See Benchlist.
+	SetParameters(threshold int, minimumFailingDuration, duration time.Duration)
 }
 
 // Config defines the configuration for a benchlist
@@ -66,8 +69,10 @@ func NewManager(config *Config) Manager {
 	if config.MaxPortion <= 0 {
 		return NewNoBenchlist()
 	}
+	// Copy [config] so that SetParameters doesn't modify the caller's
+	configCopy := *config
 	return &manager{
-		config:          config,
+		config:          &configCopy,
 		chainBenchlists: make(map[ids.ID]Benchlist),
 	}
 }
@@ -152,13 +157,26 @@ func (m *manager) RegisterFailure(chainID ids.ID, validatorID ids.ShortID) {
 	benchlist.RegisterFailure(validatorID)
 }
 
+func (m *manager) SetParameters(threshold int, minimumFailingDuration, duration time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config.Threshold = threshold
+	m.config.MinimumFailingDuration = minimumFailingDuration
+	m.config.Duration = duration
+	for _, benchlist := range m.chainBenchlists {
+		benchlist.SetParameters(threshold, minimumFailingDuration, duration)
+	}
+}
+
 type noBenchlist struct{}
 
 // NewNoBenchlist returns an empty benchlist that will never stop any queries
 func NewNoBenchlist() Manager { return &noBenchlist{} }
 
-func (noBenchlist) RegisterChain(*snow.ConsensusContext) error { return nil }
-func (noBenchlist) RegisterResponse(ids.ID, ids.ShortID)       {}
-func (noBenchlist) RegisterFailure(ids.ID, ids.ShortID)        {}
-func (noBenchlist) IsBenched(ids.ShortID, ids.ID) bool         { return false }
-func (noBenchlist) GetBenched(ids.ShortID) []ids.ID            { return []ids.ID{} }
+func (noBenchlist) RegisterChain(*snow.ConsensusContext) error      { return nil }
+func (noBenchlist) RegisterResponse(ids.ID, ids.ShortID)            {}
+func (noBenchlist) RegisterFailure(ids.ID, ids.ShortID)             {}
+func (noBenchlist) IsBenched(ids.ShortID, ids.ID) bool              { return false }
+func (noBenchlist) GetBenched(ids.ShortID) []ids.ID                 { return []ids.ID{} }
+func (noBenchlist) SetParameters(int, time.Duration, time.Duration) {}
@@ -33,6 +33,8 @@ type Handler interface {
 	common.Timer
 	Context() *snow.ConsensusContext
 	IsValidator(nodeID ids.ShortID) bool
+	SetStateSyncer(engine common.StateSyncer)
+	StateSyncer() common.StateSyncer
 	SetBootstrapper(engine common.BootstrapableEngine)
 	Bootstrapper() common.BootstrapableEngine
 	SetConsensus(engine common.Engine)
@@ -40,6 +42,8 @@ type Handler interface {
 	SetOnStopped(onStopped func())
 	Start(recoverPanic bool)
 	Push(msg message.InboundMessage)
+	// Len returns the number of messages waiting to be handled
+	Len() int
 	Stop()
 	StopWithError(err error)
 	Stopped() chan struct{}
@@ -62,6 +66,8 @@ type handler struct {
 	preemptTimeouts chan struct{}
 	gossipFrequency time.Duration
 
+	// stateSyncer is nil if the chain doesn't state sync
+	stateSyncer  common.StateSyncer
 	bootstrapper common.BootstrapableEngine
 	engine       common.Engine
 	// onStopped is called in a goroutine when this handler finishes shutting
@@ -96,6 +102,7 @@ func New(
 	msgFromVMChan <-chan common.Message,
 	preemptTimeouts chan struct{},
 	gossipFrequency time.Duration,
+	classWeights map[MessageClass]uint64,
 ) (Handler, error) {
 	h := &handler{
 		ctx:             ctx,
@@ -118,11 +125,11 @@ func New(
 	if err != nil {
 		return nil, fmt.Errorf("initializing handler metrics errored with: %w", err)
 	}
-	h.syncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, h.cpuTracker, "handler", h.ctx.Registerer, message.SynchronousOps)
+	h.syncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, h.cpuTracker, "handler", h.ctx.Registerer, message.SynchronousOps, classWeights)
 	if err != nil {
 		return nil, fmt.Errorf("initializing sync message queue errored with: %w", err)
 	}
-	h.asyncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, h.cpuTracker, "handler_async", h.ctx.Registerer, message.AsynchronousOps)
+	h.asyncMessageQueue, err = NewMessageQueue(h.ctx.Log, h.validators, h.cpuTracker, "handler_async", h.ctx.Registerer, message.AsynchronousOps, classWeights)
 	if err != nil {
 		return nil, fmt.Errorf("initializing async message queue errored with: %w", err)
 	}
@@ -137,6 +144,9 @@ func (h *handler) IsValidator(nodeID ids.ShortID) bool {
 		h.validators.Contains(nodeID)
 }
 
+func (h *handler) SetStateSyncer(engine common.StateSyncer) { h.stateSyncer = engine }
+func (h *handler) StateSyncer() common.StateSyncer          { return h.stateSyncer }
+
 func (h *handler) SetBootstrapper(engine common.BootstrapableEngine) { h.bootstrapper = engine }
 func (h *handler) Bootstrapper() common.BootstrapableEngine          { return h.bootstrapper }
 
@@ -173,6 +183,10 @@ func (h *handler) Push(msg message.InboundMessage) {
 	}
 }
 
+func (h *handler) Len() int {
+	return h.syncMessageQueue.Len() + h.asyncMessageQueue.Len()
+}
+
 func (h *handler) RegisterTimeout(d time.Duration) {
 	go func() {
 		timer := time.NewTimer(d)
@@ -210,6 +224,9 @@ func (h *handler) Stop() {
 		// [h.ctx.Lock] until the engine finished executing state transitions,
 		// which may take a long time. As a result, the router would time out on
 		// shutting down this chain.
+		if h.stateSyncer != nil {
+			h.stateSyncer.Halt()
+		}
 		h.bootstrapper.Halt()
 	})
 }
@@ -388,6 +405,44 @@ func (h *handler) handleSyncMsg(msg message.InboundMessage) error {
 		reqID := msg.Get(message.RequestID).(uint32)
 		return engine.GetAcceptedFailed(nodeID, reqID)
 
+	case message.GetStateSummaryFrontier:
+		reqID := msg.Get(message.RequestID).(uint32)
+		return engine.GetStateSummaryFrontier(nodeID, reqID)
+
+	case message.StateSummaryFrontier:
+		reqID := msg.Get(message.RequestID).(uint32)
+		summary := msg.Get(message.SummaryBytes).([]byte)
+		return engine.StateSummaryFrontier(nodeID, reqID, summary)
+
+	case message.GetStateSummaryFrontierFailed:
+		reqID := msg.Get(message.RequestID).(uint32)
+		return engine.GetStateSummaryFrontierFailed(nodeID, reqID)
+
+	case message.GetAcceptedStateSummary:
+		reqID := msg.Get(message.RequestID).(uint32)
+		heights := msg.Get(message.SummaryHeights).([]uint64)
+		return engine.GetAcceptedStateSummary(nodeID, reqID, heights)
+
+	case message.AcceptedStateSummary:
+		reqID := msg.Get(message.RequestID).(uint32)
+		summaryIDs, err := getSummaryIDs(msg)
+		if err != nil {
+			h.ctx.Log.Debug(
+				"Malformed message %s from (%s%s, %d): %s",
+				op,
+				constants.NodeIDPrefix,
+				nodeID,
+				reqID,
+				err,
+			)
+			return engine.GetAcceptedStateSummaryFailed(nodeID, reqID)
+		}
+		return engine.AcceptedStateSummary(nodeID, reqID, summaryIDs)
+
+	case message.GetAcceptedStateSummaryFailed:
+		reqID := msg.Get(message.RequestID).(uint32)
+		return engine.GetAcceptedStateSummaryFailed(nodeID, reqID)
+
 	case message.GetAncestors:
 		reqID := msg.Get(message.RequestID).(uint32)
 		containerID, err := ids.ToID(msg.Get(message.ContainerID).([]byte))
@@ -579,6 +634,11 @@ func (h *handler) handleChanMsg(msg message.InboundMessage) error {
 func (h *handler) getEngine() (common.Engine, error) {
 	state := h.ctx.GetState()
 	switch state {
+	case snow.StateSyncing:
+		if h.stateSyncer == nil {
+			return nil, fmt.Errorf("no state syncer for state %s", state)
+		}
+		return h.stateSyncer, nil
 	case snow.Bootstrapping:
 		return h.bootstrapper, nil
 	case snow.NormalOp:
@@ -39,6 +39,7 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -112,6 +113,7 @@ func TestHandlerClosesOnError(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -178,6 +180,7 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 		nil,
 		nil,
 		1,
+		DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 	handler := handlerIntf.(*handler)
@@ -236,6 +239,7 @@ func TestHandlerDispatchInternal(t *testing.T) {
 		msgFromVMChan,
 		nil,
 		time.Second,
+		DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -258,3 +262,52 @@ func TestHandlerDispatchInternal(t *testing.T) {
 	case <-calledNotify:
 	}
 }
+
+// Test that messages are handled by the state syncer while state syncing
+func TestHandlerDispatchesToStateSyncer(t *testing.T) {
+	received := make(chan []byte, 1)
+	ctx := snow.DefaultConsensusContextTest()
+	validators := validation.NewSet()
+	err := validators.AddWeight(ids.GenerateTestShortID(), 1)
+	assert.NoError(t, err)
+	metrics := prometheus.NewRegistry()
+	mc, err := message.NewCreator(metrics, true, "dummyNamespace", 10*time.Second)
+	assert.NoError(t, err)
+
+	handler, err := New(
+		mc,
+		ctx,
+		validators,
+		nil,
+		nil,
+		time.Second,
+		DefaultMessageClassWeights,
+	)
+	assert.NoError(t, err)
+
+	stateSyncer := &common.StateSyncerTest{
+		EngineTest: common.EngineTest{
+			T: t,
+		},
+	}
+	stateSyncer.Default(false)
+	stateSyncer.ContextF = func() *snow.ConsensusContext { return ctx }
+	stateSyncer.StateSummaryFrontierF = func(_ ids.ShortID, _ uint32, summary []byte) error {
+		received <- summary
+		return nil
+	}
+	handler.SetStateSyncer(stateSyncer)
+	ctx.SetState(snow.StateSyncing)
+
+	handler.Start(false)
+
+	summary := []byte{1, 2, 3}
+	handler.Push(mc.InboundStateSummaryFrontier(ctx.ChainID, 1, summary, ids.ShortEmpty))
+
+	select {
+	case <-time.After(20 * time.Millisecond):
+		t.Fatalf("should have called StateSummaryFrontier")
+	case receivedSummary := <-received:
+		assert.Equal(t, summary, receivedSummary)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"github.com/flare-foundation/flare/message"
+)
+
+// MessageClass groups the ops whose messages are queued together by the
+// handler. Each class is given a share of the handler's time proportional to
+// its weight, so that bulk traffic can't starve consensus.
+type MessageClass byte
+
+const (
+	// ControlClass messages are always handled before the other classes
+	ControlClass MessageClass = iota
+	ConsensusClass
+	BootstrapClass
+	AppClass
+	AppGossipClass
+)
+
+var (
+	// WeightedMessageClasses are the classes that share the handler's time
+	// according to their weight
+	WeightedMessageClasses = []MessageClass{
+		ConsensusClass,
+		BootstrapClass,
+		AppClass,
+		AppGossipClass,
+	}
+
+	DefaultMessageClassWeights = map[MessageClass]uint64{
+		ConsensusClass: 8,
+		BootstrapClass: 1,
+		AppClass:       4,
+		AppGossipClass: 1,
+	}
+)
+
+// MessageClassOf returns the class of the messages of [op]
+func MessageClassOf(op message.Op) MessageClass {
+	switch op {
+	case message.Connected, message.Disconnected:
+		return ControlClass
+	case message.Get, message.Put, message.PushQuery, message.PullQuery, message.Chits,
+		message.GetFailed, message.QueryFailed:
+		return ConsensusClass
+	case message.AppRequest, message.AppResponse, message.AppRequestFailed:
+		return AppClass
+	case message.AppGossip:
+		return AppGossipClass
+	default:
+		// Bootstrapping and state sync messages
+		return BootstrapClass
+	}
+}
+
+func (c MessageClass) String() string {
+	switch c {
+	case ControlClass:
+		return "control"
+	case ConsensusClass:
+		return "consensus"
+	case BootstrapClass:
+		return "bootstrap"
+	case AppClass:
+		return "app"
+	case AppGossipClass:
+		return "app_gossip"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText returns the name of the class, so that it can be used as a JSON
+// map key
+func (c MessageClass) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
@@ -4,7 +4,9 @@
 package handler
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -38,9 +40,19 @@ type MessageQueue interface {
 	Shutdown()
 }
 
-// TODO: Use a better data structure for this.
-// We can do something better than pushing to the back of a queue. A multi-level
-// queue?
+// messageQueue holds a queue per message class, and a queue per node within
+// each class.
+//
+// Control messages are popped first. The other classes, and the nodes within a
+// class, are popped with start-time fair queueing: the class (or node) with the
+// smallest virtual time is popped next, and popping a message advances its
+// virtual time by the inverse of its weight. A class (or node) that had no
+// messages starts at the virtual time of the last popped one, so that it can't
+// save up a burst of messages while idle.
+//
+// The weight of a node is its share of the handler's CPU time, which grows
+// with its stake. Nodes that recently used more than their share of CPU time
+// are skipped if other nodes of the class have messages.
 type messageQueue struct {
 	// Useful for faking time in tests
 	clock   mockable.Clock
@@ -54,12 +66,44 @@ type messageQueue struct {
 
 	cond   *sync.Cond
 	closed bool
-	// Node ID --> Messages this node has in [msgs]
+	// Node ID --> Messages this node has in the queue
 	nodeToUnprocessedMsgs map[ids.ShortID]int
-	// Unprocessed messages
-	msgs []message.InboundMessage
+	// Number of messages in the queue
+	len int
+	// Class --> Queue of the messages of the class
+	classes map[MessageClass]*classQueue
+	// Virtual time of the last popped weighted class
+	virtualTime float64
 }
 
+type classQueue struct {
+	class   MessageClass
+	weight  float64
+	metrics *messageClassMetrics
+
+	virtualTime float64
+	// Virtual time of the last popped node of the class
+	nodesVirtualTime float64
+	// Node ID --> Messages of the class from this node
+	nodes map[ids.ShortID]*nodeQueue
+	// Nodes in the order they first had messages of the class. Used to pop
+	// control messages, and to break ties, in FIFO order.
+	nodeIDs []ids.ShortID
+	len     int
+}
+
+type nodeQueue struct {
+	virtualTime float64
+	msgs        []queuedMessage
+}
+
+type queuedMessage struct {
+	msg      message.InboundMessage
+	pushTime time.Time
+}
+
+// NewMessageQueue returns a queue of the messages of [ops]. The weighted
+// classes of [ops] share the handler's time according to [classWeights].
 func NewMessageQueue(
 	log logging.Logger,
 	validators validation.Set,
@@ -67,6 +111,7 @@ func NewMessageQueue(
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
 	ops []message.Op,
+	classWeights map[MessageClass]uint64,
 ) (MessageQueue, error) {
 	m := &messageQueue{
 		log:                   log,
@@ -74,8 +119,36 @@ func NewMessageQueue(
 		cpuTracker:            cpuTracker,
 		cond:                  sync.NewCond(&sync.Mutex{}),
 		nodeToUnprocessedMsgs: make(map[ids.ShortID]int),
+		classes:               make(map[MessageClass]*classQueue),
+	}
+	if err := m.metrics.initialize(metricsNamespace, metricsRegisterer, ops); err != nil {
+		return nil, err
 	}
-	return m, m.metrics.initialize(metricsNamespace, metricsRegisterer, ops)
+
+	for _, op := range ops {
+		class := MessageClassOf(op)
+		if _, ok := m.classes[class]; ok {
+			continue
+		}
+		weight := uint64(1)
+		if class != ControlClass {
+			weight = classWeights[class]
+			if weight == 0 {
+				return nil, fmt.Errorf("weight of the %s message class must be > 0", class)
+			}
+		}
+		classMetrics, err := newMessageClassMetrics(metricsNamespace, class, metricsRegisterer)
+		if err != nil {
+			return nil, err
+		}
+		m.classes[class] = &classQueue{
+			class:   class,
+			weight:  float64(weight),
+			metrics: classMetrics,
+			nodes:   make(map[ids.ShortID]*nodeQueue),
+		}
+	}
+	return m, nil
 }
 
 func (m *messageQueue) Push(msg message.InboundMessage) {
@@ -87,21 +160,42 @@ func (m *messageQueue) Push(msg message.InboundMessage) {
 		return
 	}
 
+	class, ok := m.classes[MessageClassOf(msg.Op())]
+	if !ok {
+		m.log.Error("dropping %s message from unexpected message class", msg.Op())
+		msg.OnFinishedHandling()
+		return
+	}
+	if class.len == 0 && class.class != ControlClass && class.virtualTime < m.virtualTime {
+		class.virtualTime = m.virtualTime
+	}
+
 	// Add the message to the queue
-	m.msgs = append(m.msgs, msg)
-	m.nodeToUnprocessedMsgs[msg.NodeID()]++
+	nodeID := msg.NodeID()
+	node, ok := class.nodes[nodeID]
+	if !ok {
+		node = &nodeQueue{virtualTime: class.nodesVirtualTime}
+		class.nodes[nodeID] = node
+		class.nodeIDs = append(class.nodeIDs, nodeID)
+	}
+	node.msgs = append(node.msgs, queuedMessage{
+		msg:      msg,
+		pushTime: m.clock.Time(),
+	})
+	class.len++
+	m.len++
+	m.nodeToUnprocessedMsgs[nodeID]++
 
 	// Update metrics
 	m.metrics.nodesWithMessages.Set(float64(len(m.nodeToUnprocessedMsgs)))
 	m.metrics.len.Inc()
 	m.metrics.ops[msg.Op()].Inc()
+	class.metrics.len.Inc()
 
 	// Signal a waiting thread
 	m.cond.Signal()
 }
 
-// FIFO, but skip over messages whose senders whose messages have caused us to
-// use excessive CPU recently.
 func (m *messageQueue) Pop() (message.InboundMessage, bool) {
 	m.cond.L.Lock()
 	defer m.cond.L.Unlock()
@@ -110,51 +204,56 @@ func (m *messageQueue) Pop() (message.InboundMessage, bool) {
 		if m.closed {
 			return nil, false
 		}
-		if len(m.msgs) != 0 {
+		if m.len != 0 {
 			break
 		}
 		m.cond.Wait()
 	}
 
-	n := len(m.msgs)
-	i := 0
-	for {
-		if i == n {
-			m.log.Debug("canPop is false for all %d unprocessed messages", n)
-		}
-		msg := m.msgs[0]
-		m.msgs[0] = nil
-		nodeID := msg.NodeID()
-		// See if it's OK to process [msg] next
-		if m.canPop(msg) || i == n { // i should never == n but handle anyway as a fail-safe
-			if cap(m.msgs) == 1 {
-				m.msgs = nil // Give back memory if possible
-			} else {
-				m.msgs = m.msgs[1:]
-			}
-			m.nodeToUnprocessedMsgs[nodeID]--
-			if m.nodeToUnprocessedMsgs[nodeID] == 0 {
-				delete(m.nodeToUnprocessedMsgs, nodeID)
-			}
-			m.metrics.nodesWithMessages.Set(float64(len(m.nodeToUnprocessedMsgs)))
-			m.metrics.len.Dec()
-			m.metrics.ops[msg.Op()].Dec()
-			return msg, true
-		}
-		// [msg.nodeID] is causing excessive CPU usage.
-		// Push [msg] to back of [m.msgs] and handle it later.
-		m.msgs = append(m.msgs, msg)
-		m.msgs = m.msgs[1:]
-		i++
-		m.metrics.numExcessiveCPU.Inc()
+	class := m.nextClass()
+	var nodeID ids.ShortID
+	if class.class == ControlClass {
+		nodeID = class.nodeIDs[0]
+	} else {
+		nodeID = m.nextNode(class)
+		m.virtualTime = class.virtualTime
+		class.virtualTime += 1 / class.weight
+	}
+
+	node := class.nodes[nodeID]
+	if class.class != ControlClass {
+		class.nodesVirtualTime = node.virtualTime
+		node.virtualTime += 1 / m.share(nodeID)
+	}
+	queuedMsg := node.msgs[0]
+	node.msgs[0] = queuedMessage{}
+	if len(node.msgs) == 1 {
+		delete(class.nodes, nodeID)
+		class.removeNodeID(nodeID)
+	} else {
+		node.msgs = node.msgs[1:]
+	}
+	class.len--
+	m.len--
+	m.nodeToUnprocessedMsgs[nodeID]--
+	if m.nodeToUnprocessedMsgs[nodeID] == 0 {
+		delete(m.nodeToUnprocessedMsgs, nodeID)
 	}
+
+	msg := queuedMsg.msg
+	m.metrics.nodesWithMessages.Set(float64(len(m.nodeToUnprocessedMsgs)))
+	m.metrics.len.Dec()
+	m.metrics.ops[msg.Op()].Dec()
+	class.metrics.len.Dec()
+	class.metrics.wait.Observe(float64(m.clock.Time().Sub(queuedMsg.pushTime)))
+	return msg, true
 }
 
 func (m *messageQueue) Len() int {
 	m.cond.L.Lock()
 	defer m.cond.L.Unlock()
 
-	return len(m.msgs)
+	return m.len
 }
 
 func (m *messageQueue) Shutdown() {
@@ -162,10 +261,18 @@ func (m *messageQueue) Shutdown() {
 	defer m.cond.L.Unlock()
 
 	// Remove all the current messages from the queue
-	for _, msg := range m.msgs {
-		msg.OnFinishedHandling()
+	for _, class := range m.classes {
+		for _, node := range class.nodes {
+			for _, queuedMsg := range node.msgs {
+				queuedMsg.msg.OnFinishedHandling()
+			}
+		}
+		class.nodes = nil
+		class.nodeIDs = nil
+		class.len = 0
+		class.metrics.len.Set(0)
 	}
-	m.msgs = nil
+	m.len = 0
 	m.nodeToUnprocessedMsgs = nil
 
 	// Update metrics
@@ -177,22 +284,82 @@ func (m *messageQueue) Shutdown() {
 	m.cond.Broadcast()
 }
 
-// canPop will return true for at least one message in [m.msgs]
-func (m *messageQueue) canPop(msg message.InboundMessage) bool {
-	// Always pop connected and disconnected messages.
-	if op := msg.Op(); op == message.Connected || op == message.Disconnected {
-		return true
+// nextClass returns the class to pop a message from. Assumes the queue isn't
+// empty.
+func (m *messageQueue) nextClass() *classQueue {
+	if control, ok := m.classes[ControlClass]; ok && control.len != 0 {
+		return control
+	}
+
+	var next *classQueue
+	for _, class := range WeightedMessageClasses {
+		queue, ok := m.classes[class]
+		if !ok || queue.len == 0 {
+			continue
+		}
+		if next == nil || queue.virtualTime < next.virtualTime {
+			next = queue
+		}
+	}
+	return next
+}
+
+// nextNode returns the node to pop a message of [class] from. Assumes [class]
+// isn't empty.
+func (m *messageQueue) nextNode(class *classQueue) ids.ShortID {
+	// The node with the smallest virtual time, and the one with the smallest
+	// virtual time out of the nodes that didn't use excessive CPU.
+	var (
+		next, nextWithinCPU       ids.ShortID
+		hasNext, hasNextWithinCPU bool
+	)
+	for _, nodeID := range class.nodeIDs {
+		node := class.nodes[nodeID]
+		if !hasNext || node.virtualTime < class.nodes[next].virtualTime {
+			next = nodeID
+			hasNext = true
+		}
+	}
+	if m.canPop(class, next) {
+		return next
 	}
+	for _, nodeID := range class.nodeIDs {
+		node := class.nodes[nodeID]
+		if hasNextWithinCPU && node.virtualTime >= class.nodes[nextWithinCPU].virtualTime {
+			continue
+		}
+		if nodeID != next && m.canPop(class, nodeID) {
+			nextWithinCPU = nodeID
+			hasNextWithinCPU = true
+		}
+	}
+
+	m.metrics.numExcessiveCPU.Inc()
+	if !hasNextWithinCPU {
+		m.log.Debug("canPop is false for all %d nodes with %s messages", len(class.nodeIDs), class.class)
+		return next
+	}
+	return nextWithinCPU
+}
 
-	// If the deadline to handle [msg] has passed, always pop it.
-	// It will be dropped immediately.
+// canPop returns false if [nodeID] recently used more than its share of CPU
+// time, unless its next message of [class] is expired. Expired messages are
+// always popped, they will be dropped immediately.
+func (m *messageQueue) canPop(class *classQueue, nodeID ids.ShortID) bool {
+	msg := class.nodes[nodeID].msgs[0].msg
 	if expirationTime := msg.ExpirationTime(); !expirationTime.IsZero() && m.clock.Time().After(expirationTime) {
 		return true
 	}
-	// Every node has some allowed CPU allocation depending on
-	// the number of nodes with unprocessed messages.
+	recentCPUUtilized := m.cpuTracker.Utilization(nodeID, m.clock.Time())
+	return recentCPUUtilized <= m.share(nodeID)
+}
+
+// share returns the share of the handler's CPU time [nodeID] is allowed to
+// use. Every node has some allowed CPU allocation depending on the number of
+// nodes with unprocessed messages. Validators are allowed to use more CPU. More
+// weight --> more CPU use allowed.
+func (m *messageQueue) share(nodeID ids.ShortID) float64 {
 	baseMaxCPU := 1 / float64(len(m.nodeToUnprocessedMsgs))
-	nodeID := msg.NodeID()
 	weight, isVdr := m.validators.GetWeight(nodeID)
 	if !isVdr {
 		weight = 0
@@ -204,8 +371,15 @@ func (m *messageQueue) canPop(msg message.InboundMessage) bool {
 	if totalVdrsWeight != 0 {
 		portionWeight = float64(weight) / float64(totalVdrsWeight)
 	}
-	// Validators are allowed to use more CPm. More weight --> more CPU use allowed.
-	recentCPUUtilized := m.cpuTracker.Utilization(nodeID, m.clock.Time())
-	maxCPU := baseMaxCPU + (1.0-baseMaxCPU)*portionWeight
-	return recentCPUUtilized <= maxCPU
+	return baseMaxCPU + (1.0-baseMaxCPU)*portionWeight
+}
+
+func (c *classQueue) removeNodeID(nodeID ids.ShortID) {
+	for i, id := range c.nodeIDs {
+		if id == nodeID {
+			copy(c.nodeIDs[i:], c.nodeIDs[i+1:])
+			c.nodeIDs = c.nodeIDs[:len(c.nodeIDs)-1]
+			return
+		}
+	}
 }
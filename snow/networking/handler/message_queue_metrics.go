@@ -9,6 +9,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/flare-foundation/flare/message"
+	"github.com/flare-foundation/flare/utils/metric"
 	"github.com/flare-foundation/flare/utils/wrappers"
 )
 
@@ -62,3 +63,33 @@ func (m *messageQueueMetrics) initialize(
 	)
 	return errs.Err
 }
+
+type messageClassMetrics struct {
+	len  prometheus.Gauge
+	wait metric.Averager
+}
+
+func newMessageClassMetrics(
+	metricsNamespace string,
+	class MessageClass,
+	metricsRegisterer prometheus.Registerer,
+) (*messageClassMetrics, error) {
+	namespace := fmt.Sprintf("%s_%s", metricsNamespace, "unprocessed_msgs")
+	errs := wrappers.Errs{}
+	m := &messageClassMetrics{
+		len: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s_len", class),
+			Help:      fmt.Sprintf("Messages of the %s class ready to be processed", class),
+		}),
+		wait: metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_wait", class),
+			fmt.Sprintf("time (in ns) messages of the %s class spent in the queue", class),
+			metricsRegisterer,
+			&errs,
+		),
+	}
+	errs.Add(metricsRegisterer.Register(m.len))
+	return m, errs.Err
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/flare-foundation/flare/snow/networking/tracker"
 	"github.com/flare-foundation/flare/snow/validation"
 	"github.com/flare-foundation/flare/utils/logging"
+	"github.com/flare-foundation/flare/version"
 )
 
 func TestQueue(t *testing.T) {
@@ -25,7 +26,7 @@ func TestQueue(t *testing.T) {
 	vdr1ID, vdr2ID := ids.GenerateTestShortID(), ids.GenerateTestShortID()
 	assert.NoError(validators.AddWeight(vdr1ID, 1))
 	assert.NoError(validators.AddWeight(vdr2ID, 1))
-	mIntf, err := NewMessageQueue(logging.NoLog{}, validators, cpuTracker, "", prometheus.NewRegistry(), message.SynchronousOps)
+	mIntf, err := NewMessageQueue(logging.NoLog{}, validators, cpuTracker, "", prometheus.NewRegistry(), message.SynchronousOps, DefaultMessageClassWeights)
 	assert.NoError(err)
 	u := mIntf.(*messageQueue)
 	currentTime := time.Now()
@@ -121,7 +122,7 @@ func TestQueue(t *testing.T) {
 	// msg1 should get popped first because nonVdrNodeID1 and nonVdrNodeID2
 	// exceeded their limit
 	cpuTracker.On("Utilization", nonVdrNodeID1, mock.Anything).Return(.34).Once()
-	cpuTracker.On("Utilization", nonVdrNodeID2, mock.Anything).Return(.34).Times(3)
+	cpuTracker.On("Utilization", nonVdrNodeID2, mock.Anything).Return(.34).Once()
 	cpuTracker.On("Utilization", vdr1ID, mock.Anything).Return(0.0).Once()
 
 	// u.msgs is [msg3, msg4, msg1]
@@ -129,13 +130,154 @@ func TestQueue(t *testing.T) {
 	assert.True(ok)
 	assert.EqualValues(msg1, gotMsg1)
 	// u.msgs is [msg3, msg4]
-	cpuTracker.On("Utilization", nonVdrNodeID1, mock.Anything).Return(.51).Twice()
-	gotMsg4, ok := u.Pop()
-	assert.True(ok)
-	assert.EqualValues(msg4, gotMsg4)
-	// u.msgs is [msg3]
+	// When no node is within its limit, the node that was popped the least
+	// is popped
+	cpuTracker.On("Utilization", nonVdrNodeID1, mock.Anything).Return(.51).Once()
+	cpuTracker.On("Utilization", nonVdrNodeID2, mock.Anything).Return(.51).Twice()
 	gotMsg3, ok := u.Pop()
 	assert.True(ok)
 	assert.EqualValues(msg3, gotMsg3)
+	// u.msgs is [msg4]
+	gotMsg4, ok := u.Pop()
+	assert.True(ok)
+	assert.EqualValues(msg4, gotMsg4)
+	assert.EqualValues(0, u.Len())
+}
+
+func newTestMessageQueue(
+	t *testing.T,
+	validators validation.Set,
+	cpuTracker tracker.TimeTracker,
+	ops []message.Op,
+	classWeights map[MessageClass]uint64,
+) (*messageQueue, message.Creator) {
+	mIntf, err := NewMessageQueue(logging.NoLog{}, validators, cpuTracker, "", prometheus.NewRegistry(), ops, classWeights)
+	assert.NoError(t, err)
+	u := mIntf.(*messageQueue)
+	currentTime := time.Now()
+	u.clock.Set(currentTime)
+
+	mc, err := message.NewCreator(prometheus.NewRegistry(), true, "dummyNamespace", 10*time.Second)
+	assert.NoError(t, err)
+	mc.SetTime(currentTime)
+	return u, mc
+}
+
+func TestQueueControlMessagesFirst(t *testing.T) {
+	assert := assert.New(t)
+	cpuTracker := &tracker.MockTimeTracker{}
+	cpuTracker.On("Utilization", mock.Anything, mock.Anything).Return(0.0)
+	vdrID := ids.GenerateTestShortID()
+	u, mc := newTestMessageQueue(t, validation.NewSet(), cpuTracker, message.SynchronousOps, DefaultMessageClassWeights)
+
+	putMsg := mc.InboundPut(ids.Empty, 0, ids.Empty, nil, vdrID)
+	connectedMsg := mc.InternalConnected(vdrID, version.CurrentApp)
+	disconnectedMsg := mc.InternalDisconnected(vdrID)
+	u.Push(putMsg)
+	u.Push(connectedMsg)
+	u.Push(disconnectedMsg)
+
+	for _, expectedMsg := range []message.InboundMessage{connectedMsg, disconnectedMsg, putMsg} {
+		gotMsg, ok := u.Pop()
+		assert.True(ok)
+		assert.EqualValues(expectedMsg, gotMsg)
+	}
 	assert.EqualValues(0, u.Len())
 }
+
+func TestQueueClassWeights(t *testing.T) {
+	assert := assert.New(t)
+	cpuTracker := &tracker.MockTimeTracker{}
+	cpuTracker.On("Utilization", mock.Anything, mock.Anything).Return(0.0)
+	vdrID := ids.GenerateTestShortID()
+	classWeights := map[MessageClass]uint64{
+		ConsensusClass: 3,
+		BootstrapClass: 1,
+		AppClass:       1,
+		AppGossipClass: 1,
+	}
+	ops := append(append([]message.Op{}, message.SynchronousOps...), message.AsynchronousOps...)
+	u, mc := newTestMessageQueue(t, validation.NewSet(), cpuTracker, ops, classWeights)
+
+	for i := 0; i < 8; i++ {
+		u.Push(mc.InboundGetAcceptedFrontier(ids.Empty, uint32(i), 0, vdrID))
+		u.Push(mc.InboundPut(ids.Empty, uint32(i), ids.Empty, nil, vdrID))
+	}
+
+	// Consensus messages are popped 3 times as often as bootstrap messages
+	numPopped := make(map[MessageClass]int)
+	for i := 0; i < 8; i++ {
+		msg, ok := u.Pop()
+		assert.True(ok)
+		numPopped[MessageClassOf(msg.Op())]++
+	}
+	assert.Equal(6, numPopped[ConsensusClass])
+	assert.Equal(2, numPopped[BootstrapClass])
+
+	// A class that had no messages can't pop more than its share because it
+	// was idle
+	for i := 0; i < 8; i++ {
+		u.Push(mc.InboundAppRequest(ids.Empty, uint32(i), time.Minute, nil, vdrID))
+	}
+	numPopped = make(map[MessageClass]int)
+	for i := 0; i < 5; i++ {
+		msg, ok := u.Pop()
+		assert.True(ok)
+		numPopped[MessageClassOf(msg.Op())]++
+	}
+	assert.LessOrEqual(numPopped[AppClass], 2)
+	assert.GreaterOrEqual(numPopped[ConsensusClass], 2)
+}
+
+func TestQueueStakeWeightedFairness(t *testing.T) {
+	assert := assert.New(t)
+	cpuTracker := &tracker.MockTimeTracker{}
+	cpuTracker.On("Utilization", mock.Anything, mock.Anything).Return(0.0)
+	validators := validation.NewSet()
+	vdrID, nonVdrID := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	assert.NoError(validators.AddWeight(vdrID, 1))
+	u, mc := newTestMessageQueue(t, validators, cpuTracker, message.SynchronousOps, DefaultMessageClassWeights)
+
+	for i := 0; i < 10; i++ {
+		u.Push(mc.InboundPut(ids.Empty, uint32(i), ids.Empty, nil, nonVdrID))
+		u.Push(mc.InboundPut(ids.Empty, uint32(i), ids.Empty, nil, vdrID))
+	}
+
+	// The validator has all the stake, so its share is twice the share of
+	// the non-validator
+	numPopped := make(map[ids.ShortID]int)
+	for i := 0; i < 9; i++ {
+		msg, ok := u.Pop()
+		assert.True(ok)
+		numPopped[msg.NodeID()]++
+	}
+	assert.Equal(6, numPopped[vdrID])
+	assert.Equal(3, numPopped[nonVdrID])
+}
+
+func TestQueueShutdown(t *testing.T) {
+	assert := assert.New(t)
+	cpuTracker := &tracker.MockTimeTracker{}
+	vdrID := ids.GenerateTestShortID()
+	u, mc := newTestMessageQueue(t, validation.NewSet(), cpuTracker, message.SynchronousOps, DefaultMessageClassWeights)
+
+	u.Push(mc.InboundPut(ids.Empty, 0, ids.Empty, nil, vdrID))
+	u.Push(mc.InboundGetAcceptedFrontier(ids.Empty, 0, 0, vdrID))
+	assert.EqualValues(2, u.Len())
+
+	u.Shutdown()
+	assert.EqualValues(0, u.Len())
+	_, ok := u.Pop()
+	assert.False(ok)
+
+	u.Push(mc.InboundPut(ids.Empty, 0, ids.Empty, nil, vdrID))
+	assert.EqualValues(0, u.Len())
+}
+
+func TestQueueZeroClassWeight(t *testing.T) {
+	classWeights := map[MessageClass]uint64{
+		ConsensusClass: 1,
+	}
+	_, err := NewMessageQueue(logging.NoLog{}, validation.NewSet(), &tracker.MockTimeTracker{}, "", prometheus.NewRegistry(), message.SynchronousOps, classWeights)
+	assert.Error(t, err)
+}
@@ -10,10 +10,21 @@ import (
 	"github.com/flare-foundation/flare/message"
 )
 
-var errDuplicatedContainerID = errors.New("inbound message contains duplicated container ID")
+var (
+	errDuplicatedContainerID = errors.New("inbound message contains duplicated container ID")
+	errDuplicatedSummaryID   = errors.New("inbound message contains duplicated summary ID")
+)
 
 func getContainerIDs(msg message.InboundMessage) ([]ids.ID, error) {
-	containerIDsBytes := msg.Get(message.ContainerIDs).([][]byte)
+	return getIDs(msg, message.ContainerIDs, errDuplicatedContainerID)
+}
+
+func getSummaryIDs(msg message.InboundMessage) ([]ids.ID, error) {
+	return getIDs(msg, message.SummaryIDs, errDuplicatedSummaryID)
+}
+
+func getIDs(msg message.InboundMessage, field message.Field, errDuplicated error) ([]ids.ID, error) {
+	containerIDsBytes := msg.Get(field).([][]byte)
 	res := make([]ids.ID, len(containerIDsBytes))
 	idSet := ids.NewSet(len(containerIDsBytes))
 	for i, containerIDBytes := range containerIDsBytes {
@@ -22,7 +33,7 @@ func getContainerIDs(msg message.InboundMessage) ([]ids.ID, error) {
 			return nil, err
 		}
 		if idSet.Contains(containerID) {
-			return nil, errDuplicatedContainerID
+			return nil, errDuplicated
 		}
 		res[i] = containerID
 		idSet.Add(containerID)
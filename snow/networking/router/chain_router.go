@@ -379,6 +379,14 @@ func (cr *ChainRouter) Unbenched(chainID ids.ID, validatorID ids.ShortID) {
 	}
 }
 
+// SetHealthConfig changes the thresholds of the health check
+func (cr *ChainRouter) SetHealthConfig(healthConfig HealthConfig) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	cr.healthConfig = healthConfig
+}
+
 // HealthCheck returns results of router health checks. Returns:
 // 1) Information about health check results
 // 2) An error if the health check reports unhealthy
@@ -65,6 +65,7 @@ func TestShutdown(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -164,6 +165,7 @@ func TestShutdownTimesOut(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -272,6 +274,7 @@ func TestRouterTimeout(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -385,6 +388,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -498,6 +502,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
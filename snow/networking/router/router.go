@@ -37,6 +37,8 @@ type Router interface {
 	) error
 	Shutdown()
 	AddChain(chain handler.Handler)
+	// SetHealthConfig changes the thresholds of the health check
+	SetHealthConfig(healthConfig HealthConfig)
 	health.Checker
 }
 
@@ -103,6 +103,7 @@ func TestTimeout(t *testing.T) {
 		nil,
 		nil,
 		time.Hour,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -189,6 +190,7 @@ func TestReliableMessages(t *testing.T) {
 		nil,
 		nil,
 		1,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -281,6 +283,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		nil,
 		nil,
 		time.Second,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -14,10 +14,13 @@ var ErrUnknownState = errors.New("unknown node state")
 const (
 	Bootstrapping = iota + 1
 	NormalOp
+	StateSyncing
 )
 
 func (st State) String() string {
 	switch st {
+	case StateSyncing:
+		return "State syncing state"
 	case Bootstrapping:
 		return "Bootstrapping state"
 	case NormalOp:
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validation
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/flare-foundation/flare/ids"
+)
+
+// WeightChange describes a validator that is part of two validator sets with a
+// different weight in each of them.
+type WeightChange struct {
+	NodeID     ids.ShortID `serialize:"true"`
+	PrevWeight uint64      `serialize:"true"`
+	Weight     uint64      `serialize:"true"`
+}
+
+// Diff describes the changes that turn one validator set into another one.
+// All validators are sorted by their node ID.
+type Diff struct {
+	Added      []Validator
+	Removed    []Validator
+	Reweighted []WeightChange
+}
+
+// Empty returns true if both validator sets of the diff were identical.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Reweighted) == 0
+}
+
+// NewDiff returns the changes that have to be applied to [from] in order to
+// obtain [to]. A nil set is treated as an empty set.
+func NewDiff(from Set, to Set) Diff {
+	prev := weightsOf(from)
+	next := weightsOf(to)
+
+	var diff Diff
+	for nodeID, weight := range next {
+		prevWeight, ok := prev[nodeID]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, NewValidator(nodeID, weight))
+		case prevWeight != weight:
+			diff.Reweighted = append(diff.Reweighted, WeightChange{
+				NodeID:     nodeID,
+				PrevWeight: prevWeight,
+				Weight:     weight,
+			})
+		}
+	}
+	for nodeID, weight := range prev {
+		if _, ok := next[nodeID]; !ok {
+			diff.Removed = append(diff.Removed, NewValidator(nodeID, weight))
+		}
+	}
+
+	sort.Sort(sortValidatorsByID(diff.Added))
+	sort.Sort(sortValidatorsByID(diff.Removed))
+	sort.Slice(diff.Reweighted, func(i, j int) bool {
+		return bytes.Compare(diff.Reweighted[i].NodeID.Bytes(), diff.Reweighted[j].NodeID.Bytes()) < 0
+	})
+	return diff
+}
+
+func weightsOf(s Set) map[ids.ShortID]uint64 {
+	if s == nil {
+		return nil
+	}
+	list := s.List()
+	weights := make(map[ids.ShortID]uint64, len(list))
+	for _, vdr := range list {
+		weights[vdr.ID()] = vdr.Weight()
+	}
+	return weights
+}
+
+type sortValidatorsByID []Validator
+
+func (s sortValidatorsByID) Len() int      { return len(s) }
+func (s sortValidatorsByID) Swap(i, j int) { s[j], s[i] = s[i], s[j] }
+
+func (s sortValidatorsByID) Less(i, j int) bool {
+	return bytes.Compare(s[i].ID().Bytes(), s[j].ID().Bytes()) < 0
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/ids"
+)
+
+func TestNewDiff(t *testing.T) {
+	kept := ids.ShortID{1}
+	reweighted := ids.ShortID{2}
+	removed := ids.ShortID{3}
+	added := ids.ShortID{4}
+
+	from := NewSet()
+	require.NoError(t, from.AddWeight(kept, 10))
+	require.NoError(t, from.AddWeight(reweighted, 20))
+	require.NoError(t, from.AddWeight(removed, 30))
+
+	to := NewSet()
+	require.NoError(t, to.AddWeight(kept, 10))
+	require.NoError(t, to.AddWeight(reweighted, 25))
+	require.NoError(t, to.AddWeight(added, 40))
+
+	diff := NewDiff(from, to)
+	assert.False(t, diff.Empty())
+
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, added, diff.Added[0].ID())
+	assert.EqualValues(t, 40, diff.Added[0].Weight())
+
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, removed, diff.Removed[0].ID())
+	assert.EqualValues(t, 30, diff.Removed[0].Weight())
+
+	assert.Equal(t, []WeightChange{{
+		NodeID:     reweighted,
+		PrevWeight: 20,
+		Weight:     25,
+	}}, diff.Reweighted)
+}
+
+func TestNewDiffIdentical(t *testing.T) {
+	set := loadCostonValidators(t)
+
+	diff := NewDiff(set, set)
+	assert.True(t, diff.Empty())
+}
+
+func TestNewDiffFromNil(t *testing.T) {
+	set := loadCostonValidators(t)
+
+	diff := NewDiff(nil, set)
+	assert.Len(t, diff.Added, set.Len())
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Reweighted)
+
+	for i := 1; i < len(diff.Added); i++ {
+		assert.Less(t, diff.Added[i-1].ID().Hex(), diff.Added[i].ID().Hex())
+	}
+}
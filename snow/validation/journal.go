@@ -0,0 +1,327 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/codec"
+	"github.com/flare-foundation/flare/codec/linearcodec"
+	"github.com/flare-foundation/flare/codec/reflectcodec"
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/database/prefixdb"
+	"github.com/flare-foundation/flare/database/versiondb"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/wrappers"
+
+	safemath "github.com/flare-foundation/flare/utils/math"
+)
+
+const (
+	// MaxFetchedJournalEntries is the maximum number of journal entries that
+	// can be fetched at a time in a call to GetEntries
+	MaxFetchedJournalEntries = 1024
+
+	journalCodecVersion = uint16(0)
+)
+
+var (
+	// Maps to the byte representation of the index of the next entry
+	journalNextIndexKey = []byte{0x00}
+	// Maps to the validator set after the last recorded entry
+	journalCurrentKey  = []byte{0x01}
+	journalEntryPrefix = []byte{0x02}
+
+	errNoJournalEntries  = errors.New("no validator changes have been recorded")
+	errInvalidNumToFetch = fmt.Errorf("numToFetch must be in [1,%d]", MaxFetchedJournalEntries)
+
+	_ Journal = &journal{}
+)
+
+// JournalValidator is a validator as it is persisted in the journal
+type JournalValidator struct {
+	NodeID ids.ShortID `serialize:"true"`
+	Weight uint64      `serialize:"true"`
+}
+
+// JournalEntry describes a change of the validator set that was applied when
+// a block was accepted
+type JournalEntry struct {
+	// Index of this entry in the journal
+	Index uint64 `serialize:"true"`
+	// ID, height and timestamp of the accepted block
+	BlockID   ids.ID `serialize:"true"`
+	Height    uint64 `serialize:"true"`
+	Timestamp int64  `serialize:"true"`
+	// Changes of the validator set, each sorted by node ID
+	Added      []JournalValidator `serialize:"true"`
+	Removed    []JournalValidator `serialize:"true"`
+	Reweighted []WeightChange     `serialize:"true"`
+}
+
+// Journal persists every change that is applied to the validator set
+// Journal is thread-safe.
+type Journal interface {
+	// Record adds an entry to the journal if [validators] differs from the
+	// validator set of the last recorded entry.
+	Record(blockID ids.ID, height uint64, timestamp time.Time, validators Set) error
+
+	// NumEntries returns the number of recorded entries.
+	NumEntries() uint64
+
+	// GetEntries returns the entries at indices [startIndex], [startIndex+1],
+	// ..., [startIndex+numToFetch-1], limited to the recorded entries.
+	GetEntries(startIndex uint64, numToFetch uint64) ([]JournalEntry, error)
+}
+
+type journal struct {
+	codec   codec.Manager
+	metrics *journalMetrics
+
+	lock sync.RWMutex
+	// The index of the next entry
+	nextIndex uint64
+	// The validator set after the last recorded entry
+	current Set
+	// When [vDB] is committed, writes to the underlying database
+	vDB *versiondb.Database
+	// Index --> Entry, with [vDB] underneath
+	entries database.Database
+}
+
+// NewJournal returns a new, thread-safe Journal stored in [db].
+func NewJournal(db database.Database, namespace string, registerer prometheus.Registerer) (Journal, error) {
+	c := linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32)
+	manager := codec.NewManager(math.MaxInt32)
+	if err := manager.RegisterCodec(journalCodecVersion, c); err != nil {
+		return nil, fmt.Errorf("couldn't register codec: %w", err)
+	}
+
+	metrics, err := newJournalMetrics(namespace, registerer)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't register metrics: %w", err)
+	}
+
+	vDB := versiondb.New(db)
+	j := &journal{
+		codec:   manager,
+		metrics: metrics,
+		current: NewSet(),
+		vDB:     vDB,
+		entries: prefixdb.New(journalEntryPrefix, vDB),
+	}
+
+	nextIndex, err := database.GetUInt64(vDB, journalNextIndexKey)
+	switch {
+	case err == database.ErrNotFound:
+		return j, nil
+	case err != nil:
+		return nil, fmt.Errorf("couldn't get next journal index from database: %w", err)
+	}
+	j.nextIndex = nextIndex
+
+	currentBytes, err := vDB.Get(journalCurrentKey)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get current validators from database: %w", err)
+	}
+	var current []JournalValidator
+	if _, err := j.codec.Unmarshal(currentBytes, &current); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal current validators: %w", err)
+	}
+	for _, vdr := range current {
+		if err := j.current.AddWeight(vdr.NodeID, vdr.Weight); err != nil {
+			return nil, err
+		}
+	}
+
+	j.metrics.entries.Add(float64(j.nextIndex))
+	j.metrics.validators.Set(float64(j.current.Len()))
+	j.metrics.weight.Set(float64(j.current.Weight()))
+	return j, nil
+}
+
+func (j *journal) Record(blockID ids.ID, height uint64, timestamp time.Time, validators Set) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	diff := NewDiff(j.current, validators)
+	if diff.Empty() {
+		return nil
+	}
+
+	entry := JournalEntry{
+		Index:      j.nextIndex,
+		BlockID:    blockID,
+		Height:     height,
+		Timestamp:  timestamp.Unix(),
+		Added:      toJournalValidators(diff.Added),
+		Removed:    toJournalValidators(diff.Removed),
+		Reweighted: diff.Reweighted,
+	}
+	entryBytes, err := j.codec.Marshal(journalCodecVersion, entry)
+	if err != nil {
+		return fmt.Errorf("couldn't serialize journal entry: %w", err)
+	}
+	if err := j.entries.Put(database.PackUInt64(entry.Index), entryBytes); err != nil {
+		return fmt.Errorf("couldn't put journal entry %d: %w", entry.Index, err)
+	}
+
+	currentBytes, err := j.codec.Marshal(journalCodecVersion, toJournalValidators(validators.List()))
+	if err != nil {
+		return fmt.Errorf("couldn't serialize current validators: %w", err)
+	}
+	if err := j.vDB.Put(journalCurrentKey, currentBytes); err != nil {
+		return fmt.Errorf("couldn't put current validators: %w", err)
+	}
+
+	nextIndex, err := safemath.Add64(j.nextIndex, 1)
+	if err != nil {
+		return err
+	}
+	if err := database.PutUInt64(j.vDB, journalNextIndexKey, nextIndex); err != nil {
+		return fmt.Errorf("couldn't put next journal index: %w", err)
+	}
+	if err := j.vDB.Commit(); err != nil {
+		return err
+	}
+
+	current := NewSet()
+	if err := current.Set(validators.List()); err != nil {
+		return err
+	}
+	j.current = current
+	j.nextIndex = nextIndex
+	j.metrics.record(entry, current)
+	return nil
+}
+
+func (j *journal) NumEntries() uint64 {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return j.nextIndex
+}
+
+func (j *journal) GetEntries(startIndex uint64, numToFetch uint64) ([]JournalEntry, error) {
+	if numToFetch == 0 || numToFetch > MaxFetchedJournalEntries {
+		return nil, errInvalidNumToFetch
+	}
+
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	if j.nextIndex == 0 {
+		return nil, errNoJournalEntries
+	}
+	if startIndex >= j.nextIndex {
+		return nil, fmt.Errorf("start index (%d) > last journal index (%d)", startIndex, j.nextIndex-1)
+	}
+
+	lastIndex := safemath.Min64(startIndex+numToFetch, j.nextIndex)
+	entries := make([]JournalEntry, 0, lastIndex-startIndex)
+	for index := startIndex; index < lastIndex; index++ {
+		entryBytes, err := j.entries.Get(database.PackUInt64(index))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get journal entry %d: %w", index, err)
+		}
+		var entry JournalEntry
+		if _, err := j.codec.Unmarshal(entryBytes, &entry); err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal journal entry %d: %w", index, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func toJournalValidators(vdrs []Validator) []JournalValidator {
+	journalVdrs := make([]JournalValidator, len(vdrs))
+	for i, vdr := range vdrs {
+		journalVdrs[i] = JournalValidator{
+			NodeID: vdr.ID(),
+			Weight: vdr.Weight(),
+		}
+	}
+	return journalVdrs
+}
+
+type journalMetrics struct {
+	entries, added, removed, reweighted prometheus.Counter
+	validators, weight                  prometheus.Gauge
+	lastChangeHeight, lastChangeTime    prometheus.Gauge
+}
+
+func newJournalMetrics(namespace string, registerer prometheus.Registerer) (*journalMetrics, error) {
+	m := &journalMetrics{
+		entries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "journal_entries",
+			Help:      "Number of recorded changes of the validator set",
+		}),
+		added: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "journal_added",
+			Help:      "Number of validators added to the validator set",
+		}),
+		removed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "journal_removed",
+			Help:      "Number of validators removed from the validator set",
+		}),
+		reweighted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "journal_reweighted",
+			Help:      "Number of weight changes of validators in the validator set",
+		}),
+		validators: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "journal_validators",
+			Help:      "Number of validators in the validator set",
+		}),
+		weight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "journal_weight",
+			Help:      "Total weight of the validator set",
+		}),
+		lastChangeHeight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "journal_last_change_height",
+			Help:      "Height of the block that last changed the validator set",
+		}),
+		lastChangeTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "journal_last_change_timestamp",
+			Help:      "Unix timestamp of the block that last changed the validator set",
+		}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.entries),
+		registerer.Register(m.added),
+		registerer.Register(m.removed),
+		registerer.Register(m.reweighted),
+		registerer.Register(m.validators),
+		registerer.Register(m.weight),
+		registerer.Register(m.lastChangeHeight),
+		registerer.Register(m.lastChangeTime),
+	)
+	return m, errs.Err
+}
+
+func (m *journalMetrics) record(entry JournalEntry, current Set) {
+	m.entries.Inc()
+	m.added.Add(float64(len(entry.Added)))
+	m.removed.Add(float64(len(entry.Removed)))
+	m.reweighted.Add(float64(len(entry.Reweighted)))
+	m.validators.Set(float64(current.Len()))
+	m.weight.Set(float64(current.Weight()))
+	m.lastChangeHeight.Set(float64(entry.Height))
+	m.lastChangeTime.Set(float64(entry.Timestamp))
+}
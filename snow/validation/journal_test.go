@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/database/memdb"
+	"github.com/flare-foundation/flare/ids"
+)
+
+func TestJournalRecord(t *testing.T) {
+	db := memdb.New()
+	j, err := NewJournal(db, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, j.NumEntries())
+
+	_, err = j.GetEntries(0, 1)
+	assert.ErrorIs(t, err, errNoJournalEntries)
+
+	first := NewSet()
+	require.NoError(t, first.AddWeight(ids.ShortID{1}, 10))
+	require.NoError(t, first.AddWeight(ids.ShortID{2}, 20))
+	require.NoError(t, j.Record(ids.ID{1}, 1, time.Unix(100, 0), first))
+	assert.EqualValues(t, 1, j.NumEntries())
+
+	// Recording an unchanged set doesn't add an entry
+	require.NoError(t, j.Record(ids.ID{2}, 2, time.Unix(200, 0), first))
+	assert.EqualValues(t, 1, j.NumEntries())
+
+	second := NewSet()
+	require.NoError(t, second.AddWeight(ids.ShortID{1}, 15))
+	require.NoError(t, second.AddWeight(ids.ShortID{3}, 30))
+	require.NoError(t, j.Record(ids.ID{3}, 3, time.Unix(300, 0), second))
+	assert.EqualValues(t, 2, j.NumEntries())
+
+	entries, err := j.GetEntries(0, MaxFetchedJournalEntries)
+	require.NoError(t, err)
+	assert.Equal(t, []JournalEntry{
+		{
+			Index:     0,
+			BlockID:   ids.ID{1},
+			Height:    1,
+			Timestamp: 100,
+			Added: []JournalValidator{
+				{NodeID: ids.ShortID{1}, Weight: 10},
+				{NodeID: ids.ShortID{2}, Weight: 20},
+			},
+			Removed:    []JournalValidator{},
+			Reweighted: []WeightChange{},
+		},
+		{
+			Index:     1,
+			BlockID:   ids.ID{3},
+			Height:    3,
+			Timestamp: 300,
+			Added: []JournalValidator{
+				{NodeID: ids.ShortID{3}, Weight: 30},
+			},
+			Removed: []JournalValidator{
+				{NodeID: ids.ShortID{2}, Weight: 20},
+			},
+			Reweighted: []WeightChange{
+				{NodeID: ids.ShortID{1}, PrevWeight: 10, Weight: 15},
+			},
+		},
+	}, entries)
+
+	entries, err = j.GetEntries(1, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.EqualValues(t, 1, entries[0].Index)
+
+	_, err = j.GetEntries(2, 1)
+	assert.Error(t, err)
+	_, err = j.GetEntries(0, 0)
+	assert.ErrorIs(t, err, errInvalidNumToFetch)
+}
+
+func TestJournalRestart(t *testing.T) {
+	db := memdb.New()
+	j, err := NewJournal(db, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	set := loadCostonValidators(t)
+	require.NoError(t, j.Record(ids.ID{1}, 1, time.Unix(100, 0), set))
+
+	// The journal picks up where it left off, so recording the same set again
+	// after a restart doesn't add an entry
+	j, err = NewJournal(db, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, j.NumEntries())
+	require.NoError(t, j.Record(ids.ID{2}, 2, time.Unix(200, 0), set))
+	assert.EqualValues(t, 1, j.NumEntries())
+
+	require.NoError(t, j.Record(ids.ID{3}, 3, time.Unix(300, 0), NewSet()))
+	entries, err := j.GetEntries(1, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Len(t, entries[0].Removed, set.Len())
+}
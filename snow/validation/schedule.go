@@ -0,0 +1,152 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+)
+
+// ScheduleVersion is the only version of the validator schedule format that
+// is currently supported
+const ScheduleVersion = 1
+
+var (
+	errEmptySchedule          = errors.New("validator schedule has no entries")
+	errDuplicateHeight        = errors.New("validator schedule has multiple entries for the same height")
+	errNoScheduledValidators  = errors.New("no validators are scheduled at this height")
+	errUnknownScheduleFormat  = errors.New("unknown validator schedule format")
+	errUnknownScheduleVersion = fmt.Errorf("validator schedule version must be %d", ScheduleVersion)
+
+	_ Retriever = &ScheduleRetriever{}
+)
+
+// ScheduleFile is the content of a file that specifies which validators are
+// active starting at which height of the chain providing the validator sets.
+type ScheduleFile struct {
+	Version  uint32              `json:"version" yaml:"version"`
+	Schedule []ScheduleFileEntry `json:"schedule" yaml:"schedule"`
+}
+
+// ScheduleFileEntry activates a validator set at a given height
+type ScheduleFileEntry struct {
+	Height     uint64                  `json:"height" yaml:"height"`
+	Validators []ScheduleFileValidator `json:"validators" yaml:"validators"`
+}
+
+// ScheduleFileValidator is a validator as specified in a schedule file
+type ScheduleFileValidator struct {
+	NodeID string `json:"nodeID" yaml:"nodeID"`
+	Weight uint64 `json:"weight" yaml:"weight"`
+}
+
+// Schedule is a parsed validator schedule, with its validator sets sorted by
+// activation height.
+type Schedule struct {
+	heights []uint64
+	sets    []Set
+}
+
+// ParseSchedule parses a validator schedule encoded in [format], which is
+// either "json" or "yaml".
+func ParseSchedule(b []byte, format string) (*Schedule, error) {
+	file := ScheduleFile{}
+	switch format {
+	case "json":
+		if err := json.Unmarshal(b, &file); err != nil {
+			return nil, err
+		}
+	case "yaml":
+		if err := yaml.Unmarshal(b, &file); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownScheduleFormat, format)
+	}
+	return NewSchedule(file)
+}
+
+// NewSchedule verifies [file] and returns the schedule it specifies.
+func NewSchedule(file ScheduleFile) (*Schedule, error) {
+	if file.Version != ScheduleVersion {
+		return nil, errUnknownScheduleVersion
+	}
+	if len(file.Schedule) == 0 {
+		return nil, errEmptySchedule
+	}
+
+	entries := make([]ScheduleFileEntry, len(file.Schedule))
+	copy(entries, file.Schedule)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Height < entries[j].Height
+	})
+
+	s := &Schedule{
+		heights: make([]uint64, len(entries)),
+		sets:    make([]Set, len(entries)),
+	}
+	for i, entry := range entries {
+		if i > 0 && entry.Height == entries[i-1].Height {
+			return nil, fmt.Errorf("%w: %d", errDuplicateHeight, entry.Height)
+		}
+		set := NewSet()
+		for _, vdr := range entry.Validators {
+			nodeID, err := ids.ShortFromPrefixedString(vdr.NodeID, constants.NodeIDPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("invalid node ID %q at height %d: %w", vdr.NodeID, entry.Height, err)
+			}
+			if err := set.AddWeight(nodeID, vdr.Weight); err != nil {
+				return nil, fmt.Errorf("invalid validator %q at height %d: %w", vdr.NodeID, entry.Height, err)
+			}
+		}
+		s.heights[i] = entry.Height
+		s.sets[i] = set
+	}
+	return s, nil
+}
+
+// At returns the validator set that is active at [height]. The returned set
+// must not be modified.
+func (s *Schedule) At(height uint64) (Set, error) {
+	// Index of the first entry that activates after [height]
+	i := sort.Search(len(s.heights), func(i int) bool {
+		return s.heights[i] > height
+	})
+	if i == 0 {
+		return nil, fmt.Errorf("%w: %d", errNoScheduledValidators, height)
+	}
+	return s.sets[i-1], nil
+}
+
+// ScheduleRetriever provides the validators of a block according to a static
+// schedule, based on the height of the block.
+type ScheduleRetriever struct {
+	schedule  *Schedule
+	getHeight func(blockID ids.ID) (uint64, error)
+}
+
+// NewScheduleRetriever returns a retriever that looks up the validators of
+// blocks in [schedule], using [getHeight] to get the height of a block.
+func NewScheduleRetriever(schedule *Schedule, getHeight func(blockID ids.ID) (uint64, error)) *ScheduleRetriever {
+	r := ScheduleRetriever{
+		schedule:  schedule,
+		getHeight: getHeight,
+	}
+	return &r
+}
+
+func (r *ScheduleRetriever) GetValidators(blockID ids.ID) (Set, error) {
+	height, err := r.getHeight(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get height of block %s: %w", blockID, err)
+	}
+	return r.schedule.At(height)
+}
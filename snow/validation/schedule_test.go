@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+)
+
+const (
+	testScheduleJSON = `{
+	"version": 1,
+	"schedule": [
+		{
+			"height": 10,
+			"validators": [
+				{"nodeID": "NodeID-GWPcbFJZFfZreETSoWjPimr846mXEKCtu", "weight": 2},
+				{"nodeID": "NodeID-K9vx5sYL3aAq4Jt4SmXY1FasP2hpwpPNu", "weight": 3}
+			]
+		},
+		{
+			"height": 0,
+			"validators": [
+				{"nodeID": "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", "weight": 1}
+			]
+		}
+	]
+}`
+	testScheduleYAML = `
+version: 1
+schedule:
+  - height: 0
+    validators:
+      - nodeID: NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg
+        weight: 1
+  - height: 10
+    validators:
+      - nodeID: NodeID-GWPcbFJZFfZreETSoWjPimr846mXEKCtu
+        weight: 2
+      - nodeID: NodeID-K9vx5sYL3aAq4Jt4SmXY1FasP2hpwpPNu
+        weight: 3
+`
+)
+
+func TestParseSchedule(t *testing.T) {
+	first, err := ids.ShortFromPrefixedString("NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", constants.NodeIDPrefix)
+	require.NoError(t, err)
+
+	for format, content := range map[string]string{
+		"json": testScheduleJSON,
+		"yaml": testScheduleYAML,
+	} {
+		t.Run(format, func(t *testing.T) {
+			schedule, err := ParseSchedule([]byte(content), format)
+			require.NoError(t, err)
+
+			for _, height := range []uint64{0, 9} {
+				set, err := schedule.At(height)
+				require.NoError(t, err)
+				assert.Equal(t, 1, set.Len())
+				assert.True(t, set.Contains(first))
+			}
+			for _, height := range []uint64{10, 1000} {
+				set, err := schedule.At(height)
+				require.NoError(t, err)
+				assert.Equal(t, 2, set.Len())
+				assert.EqualValues(t, 5, set.Weight())
+			}
+		})
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		format  string
+		err     error
+	}{
+		"unknown format": {
+			content: testScheduleJSON,
+			format:  "toml",
+			err:     errUnknownScheduleFormat,
+		},
+		"unknown version": {
+			content: `{"version": 2, "schedule": [{"height": 0}]}`,
+			format:  "json",
+			err:     errUnknownScheduleVersion,
+		},
+		"no entries": {
+			content: `{"version": 1}`,
+			format:  "json",
+			err:     errEmptySchedule,
+		},
+		"duplicate height": {
+			content: `{"version": 1, "schedule": [{"height": 5}, {"height": 5}]}`,
+			format:  "json",
+			err:     errDuplicateHeight,
+		},
+		"invalid node ID": {
+			content: `{"version": 1, "schedule": [{"height": 0, "validators": [{"nodeID": "foo", "weight": 1}]}]}`,
+			format:  "json",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSchedule([]byte(test.content), test.format)
+			require.Error(t, err)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestScheduleRetriever(t *testing.T) {
+	schedule, err := ParseSchedule([]byte(testScheduleYAML), "yaml")
+	require.NoError(t, err)
+
+	errUnknownBlock := errors.New("unknown block")
+	heights := map[ids.ID]uint64{
+		{1}: 5,
+		{2}: 15,
+	}
+	r := NewScheduleRetriever(schedule, func(blockID ids.ID) (uint64, error) {
+		height, ok := heights[blockID]
+		if !ok {
+			return 0, errUnknownBlock
+		}
+		return height, nil
+	})
+
+	set, err := r.GetValidators(ids.ID{1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, set.Len())
+
+	set, err = r.GetValidators(ids.ID{2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, set.Len())
+
+	_, err = r.GetValidators(ids.ID{3})
+	assert.ErrorIs(t, err, errUnknownBlock)
+}
+
+func TestScheduleBeforeFirstEntry(t *testing.T) {
+	schedule, err := NewSchedule(ScheduleFile{
+		Version:  ScheduleVersion,
+		Schedule: []ScheduleFileEntry{{Height: 10}},
+	})
+	require.NoError(t, err)
+
+	_, err = schedule.At(9)
+	assert.ErrorIs(t, err, errNoScheduledValidators)
+}
@@ -4,13 +4,15 @@
 package validation
 
 import (
+	"time"
+
 	"github.com/flare-foundation/flare/ids"
 )
 
 type TestUpdater struct {
-	UpdateValidatorsFunc func(blockID ids.ID) error
+	UpdateValidatorsFunc func(blockID ids.ID, height uint64, timestamp time.Time) error
 }
 
-func (m *TestUpdater) UpdateValidators(blockID ids.ID) error {
-	return m.UpdateValidatorsFunc(blockID)
+func (m *TestUpdater) UpdateValidators(blockID ids.ID, height uint64, timestamp time.Time) error {
+	return m.UpdateValidatorsFunc(blockID, height, timestamp)
 }
@@ -5,20 +5,28 @@ package validation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/logging"
 )
 
 type Updater interface {
-	UpdateValidators(blockID ids.ID) error
+	// UpdateValidators sets the validators to the ones at the accepted block
+	// with the given ID, height and timestamp.
+	UpdateValidators(blockID ids.ID, height uint64, timestamp time.Time) error
 }
 
-func NewRetrievingUpdater(log logging.Logger, validators Set, retrieve Retriever) *RetrievingUpdater {
+// NewRetrievingUpdater returns an updater that retrieves the validators of
+// accepted blocks from [retrieve] and writes them into [validators]. Every
+// change of the validators is recorded in [journal], unless it is nil. Failing
+// to record a change is logged, but doesn't fail the update.
+func NewRetrievingUpdater(log logging.Logger, validators Set, retrieve Retriever, journal Journal) *RetrievingUpdater {
 	u := RetrievingUpdater{
 		log:        log,
 		validators: validators,
 		retrieve:   retrieve,
+		journal:    journal,
 	}
 	return &u
 }
@@ -27,9 +35,10 @@ type RetrievingUpdater struct {
 	log        logging.Logger
 	validators Set
 	retrieve   Retriever
+	journal    Journal
 }
 
-func (u *RetrievingUpdater) UpdateValidators(blockID ids.ID) error {
+func (u *RetrievingUpdater) UpdateValidators(blockID ids.ID, height uint64, timestamp time.Time) error {
 	validators, err := u.retrieve.GetValidators(blockID)
 	if err != nil {
 		return fmt.Errorf("could not get validators for updating: %w", err)
@@ -38,6 +47,11 @@ func (u *RetrievingUpdater) UpdateValidators(blockID ids.ID) error {
 	if err != nil {
 		return fmt.Errorf("could not set validators: %w", err)
 	}
+	if u.journal != nil {
+		if err := u.journal.Record(blockID, height, timestamp, validators); err != nil {
+			u.log.Error("could not record validators of block %s: %s", blockID, err)
+		}
+	}
 	u.log.Debug("validators updated: %s", validators)
 	return nil
 }
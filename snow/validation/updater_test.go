@@ -3,10 +3,13 @@ package validation
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flare-foundation/flare/database/memdb"
 	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/utils/logging"
 )
@@ -32,9 +35,9 @@ func TestNewRetrievingUpdater(t *testing.T) {
 			},
 		}
 		cr := NewCachingRetriever(retrieverMock)
-		u := NewRetrievingUpdater(logging.NoLog{}, loadCostonValidators(t), cr)
+		u := NewRetrievingUpdater(logging.NoLog{}, loadCostonValidators(t), cr, nil)
 
-		err := u.UpdateValidators(testBlockID)
+		err := u.UpdateValidators(testBlockID, 1, time.Unix(0, 0))
 		require.NoError(t, err)
 		assert.Equal(t, 1, callsCount)
 
@@ -53,9 +56,51 @@ func TestNewRetrievingUpdater(t *testing.T) {
 			},
 		}
 		cr := NewCachingRetriever(retrieverMock)
-		u := NewRetrievingUpdater(logging.NoLog{}, NewSet(), cr)
+		u := NewRetrievingUpdater(logging.NoLog{}, NewSet(), cr, nil)
 
-		err := u.UpdateValidators(testBlockID)
+		err := u.UpdateValidators(testBlockID, 1, time.Unix(0, 0))
 		require.Error(t, err)
 	})
+
+	t.Run("records changes in journal", func(t *testing.T) {
+		t.Parallel()
+
+		retrieverMock := &TestRetriever{
+			GetValidatorsByBlockIDFunc: func(blockID ids.ID) (Set, error) {
+				return costonSet, nil
+			},
+		}
+		j, err := NewJournal(memdb.New(), "", prometheus.NewRegistry())
+		require.NoError(t, err)
+		u := NewRetrievingUpdater(logging.NoLog{}, NewSet(), retrieverMock, j)
+
+		err = u.UpdateValidators(testBlockID, 1, time.Unix(0, 0))
+		require.NoError(t, err)
+
+		entries, err := j.GetEntries(0, 1)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, testBlockID, entries[0].BlockID)
+		assert.Len(t, entries[0].Added, costonSet.Len())
+	})
+
+	t.Run("ignores failure to record changes", func(t *testing.T) {
+		t.Parallel()
+
+		retrieverMock := &TestRetriever{
+			GetValidatorsByBlockIDFunc: func(blockID ids.ID) (Set, error) {
+				return costonSet, nil
+			},
+		}
+		db := memdb.New()
+		j, err := NewJournal(db, "", prometheus.NewRegistry())
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		validators := NewSet()
+		u := NewRetrievingUpdater(logging.NoLog{}, validators, retrieverMock, j)
+
+		err = u.UpdateValidators(testBlockID, 1, time.Unix(0, 0))
+		require.NoError(t, err)
+		assert.Equal(t, costonSet.Len(), validators.Len())
+	})
 }
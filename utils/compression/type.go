@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errUnknownCompressionType = errors.New("unknown compression type")
+
+// Type is the compression algorithm of a message payload. Its value is sent
+// over the wire, so existing values must not be changed.
+type Type byte
+
+const (
+	TypeNone Type = iota
+	TypeGzip
+	TypeZstd
+)
+
+// DecompressibleTypes are the compressed types this node can decompress
+var DecompressibleTypes = []Type{TypeGzip, TypeZstd}
+
+func (t Type) String() string {
+	switch t {
+	case TypeNone:
+		return "none"
+	case TypeGzip:
+		return "gzip"
+	case TypeZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// TypeFromString returns the compression type named [s]
+func TypeFromString(s string) (Type, error) {
+	switch s {
+	case TypeNone.String():
+		return TypeNone, nil
+	case TypeGzip.String():
+		return TypeGzip, nil
+	case TypeZstd.String():
+		return TypeZstd, nil
+	default:
+		return TypeNone, fmt.Errorf("%w: %q", errUnknownCompressionType, s)
+	}
+}
+
+func (t Type) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", t)), nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var _ Compressor = &zstdCompressor{}
+
+// zstdCompressor is safe for concurrent use, the encoder and decoder keep a
+// pool of states for concurrent calls to EncodeAll and DecodeAll.
+type zstdCompressor struct {
+	maxSize int64
+
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// Compress [msg] and returns the compressed bytes.
+func (z *zstdCompressor) Compress(msg []byte) ([]byte, error) {
+	if int64(len(msg)) > z.maxSize {
+		return nil, fmt.Errorf("msg length (%d) > maximum msg length (%d)", len(msg), z.maxSize)
+	}
+	return z.encoder.EncodeAll(msg, nil), nil
+}
+
+// Decompress decompresses [msg].
+func (z *zstdCompressor) Decompress(msg []byte) ([]byte, error) {
+	// The decoder refuses to decompress more than [z.maxSize] bytes.
+	decompressed, err := z.decoder.DecodeAll(msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decompressed)) > z.maxSize {
+		return nil, fmt.Errorf("msg length > maximum msg length (%d)", z.maxSize)
+	}
+	return decompressed, nil
+}
+
+// NewZstdCompressor returns a new zstd Compressor that compresses and
+// decompresses messages of up to [maxSize] bytes
+func NewZstdCompressor(maxSize int64) (Compressor, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(maxSize)))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdCompressor{
+		maxSize: maxSize,
+		encoder: encoder,
+		decoder: decoder,
+	}, nil
+}
@@ -402,6 +402,51 @@ func TestPackerUnpackFixedByteSlices(t *testing.T) {
 	}
 }
 
+func TestPackerPackLongs(t *testing.T) {
+	p := Packer{MaxSize: 20}
+
+	p.PackLongs([]uint64{0x0102030405060708, 42})
+
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+
+	expected := []byte("\x00\x00\x00\x02\x01\x02\x03\x04\x05\x06\x07\x08\x00\x00\x00\x00\x00\x00\x00\x2a")
+	if !bytes.Equal(p.Bytes, expected) {
+		t.Fatalf("Packer.PackLongs wrote:\n%v\nExpected:\n%v", p.Bytes, expected)
+	}
+
+	p.PackLongs([]uint64{1})
+	if !p.Errored() {
+		t.Fatal("Packer.PackLongs did not fail when attempt was beyond p.MaxSize")
+	}
+}
+
+func TestPackerUnpackLongs(t *testing.T) {
+	var (
+		p           = Packer{Bytes: []byte("\x00\x00\x00\x02\x01\x02\x03\x04\x05\x06\x07\x08\x00\x00\x00\x00\x00\x00\x00\x2a")}
+		actual      = p.UnpackLongs()
+		expected    = []uint64{0x0102030405060708, 42}
+		expectedLen = 20
+	)
+
+	switch {
+	case p.Errored():
+		t.Fatalf("Packer.UnpackLongs unexpectedly raised %s", p.Err)
+	case !reflect.DeepEqual(actual, expected):
+		t.Fatalf("Packer.UnpackLongs returned %d, but expected %d", actual, expected)
+	case p.Offset != expectedLen:
+		t.Fatalf("Packer.UnpackLongs left Offset %d, expected %d", p.Offset, expectedLen)
+	}
+
+	actual = p.UnpackLongs()
+	if !p.Errored() {
+		t.Fatalf("Packer.UnpackLongs should have set error, due to attempted out of bounds read")
+	} else if actual != nil {
+		t.Fatalf("Packer.UnpackLongs returned %v, expected sentinal value %v", actual, nil)
+	}
+}
+
 func TestPackerString(t *testing.T) {
 	p := Packer{MaxSize: 6}
 
@@ -5,39 +5,35 @@ package avm
 
 import (
 	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/ids"
 	"github.com/flare-foundation/flare/pubsub"
 	"github.com/flare-foundation/flare/vms/components/avax"
 )
 
-var _ pubsub.Filterer = &filterer{}
-
-type filterer struct {
-	tx *Tx
-}
-
+// NewPubSubFilterer returns a filterer that publishes the ID of [tx] to the
+// connections that filter on its output addresses, its asset IDs, its type, or
+// the UTXOs it consumes or produces.
 func NewPubSubFilterer(tx *Tx) pubsub.Filterer {
-	return &filterer{tx: tx}
-}
+	fields := pubsub.Fields{
+		AssetIDs: tx.AssetIDs().List(),
+		TxType:   pubsub.TypeName(tx.UnsignedTx),
+	}
+	inputUTXOs := tx.InputUTXOs()
+	utxos := tx.UTXOs()
+	fields.UTXOIDs = make([]ids.ID, 0, len(inputUTXOs)+len(utxos))
+	for _, utxoID := range inputUTXOs {
+		fields.UTXOIDs = append(fields.UTXOIDs, utxoID.InputID())
+	}
+	for _, utxo := range utxos {
+		fields.UTXOIDs = append(fields.UTXOIDs, utxo.InputID())
 
-// Apply the filter on the addresses.
-func (f *filterer) Filter(filters []pubsub.Filter) ([]bool, interface{}) {
-	resp := make([]bool, len(filters))
-	for _, utxo := range f.tx.UTXOs() {
 		addressable, ok := utxo.Out.(avax.Addressable)
 		if !ok {
 			continue
 		}
-
-		for _, address := range addressable.Addresses() {
-			for i, c := range filters {
-				if resp[i] {
-					continue
-				}
-				resp[i] = c.Check(address)
-			}
-		}
-	}
-	return resp, api.JSONTxID{
-		TxID: f.tx.ID(),
+		fields.Addresses = append(fields.Addresses, addressable.Addresses()...)
 	}
+	return pubsub.NewFilterer(fields, api.JSONTxID{
+		TxID: tx.ID(),
+	})
 }
@@ -23,6 +23,15 @@ func (f *mockFilter) Check(addr []byte) bool {
 	return bytes.Equal(addr, f.addr)
 }
 
+func (f *mockFilter) Match(fields *pubsub.Fields) bool {
+	for _, addr := range fields.Addresses {
+		if f.Check(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 func TestFilter(t *testing.T) {
 	assert := assert.New(t)
 
@@ -48,3 +57,49 @@ func TestFilter(t *testing.T) {
 	fr, _ := parser.Filter([]pubsub.Filter{&mockFilter{addr: addrBytes}})
 	assert.Equal([]bool{true}, fr)
 }
+
+func TestFilterFields(t *testing.T) {
+	assert := assert.New(t)
+
+	assetID := ids.GenerateTestID()
+	inputUTXOID := avax.UTXOID{TxID: ids.GenerateTestID()}
+	tx := Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		Ins: []*avax.TransferableInput{
+			{
+				UTXOID: inputUTXOID,
+				Asset:  avax.Asset{ID: assetID},
+				In:     &secp256k1fx.TransferInput{},
+			},
+		},
+		Outs: []*avax.TransferableOutput{
+			{
+				Asset: avax.Asset{ID: assetID},
+				Out:   &secp256k1fx.TransferOutput{},
+			},
+		},
+	}}}
+
+	assetFilter := pubsub.NewFilterParam()
+	assert.NoError(assetFilter.AddAssetIDs(assetID))
+	otherAssetFilter := pubsub.NewFilterParam()
+	assert.NoError(otherAssetFilter.AddAssetIDs(ids.GenerateTestID()))
+	typeFilter := pubsub.NewFilterParam()
+	assert.NoError(typeFilter.AddTxTypes("BaseTx"))
+	utxoFilter := pubsub.NewFilterParam()
+	assert.NoError(utxoFilter.AddUTXOIDs(inputUTXOID.InputID()))
+	// Every configured filter must match
+	typeAndAssetFilter := pubsub.NewFilterParam()
+	assert.NoError(typeAndAssetFilter.AddTxTypes("ExportTx"))
+	assert.NoError(typeAndAssetFilter.AddAssetIDs(assetID))
+
+	parser := NewPubSubFilterer(&tx)
+	fr, _ := parser.Filter([]pubsub.Filter{
+		assetFilter,
+		otherAssetFilter,
+		typeFilter,
+		utxoFilter,
+		typeAndAssetFilter,
+		pubsub.NewFilterParam(),
+	})
+	assert.Equal([]bool{true, false, true, true, false, false}, fr)
+}
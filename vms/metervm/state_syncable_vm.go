@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import (
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+)
+
+var _ block.StateSyncableVM = &blockVM{}
+
+func (vm *blockVM) StateSyncEnabled() (bool, error) {
+	ssVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return false, nil
+	}
+	return ssVM.StateSyncEnabled()
+}
+
+func (vm *blockVM) GetOngoingSyncStateSummary() (block.StateSummary, error) {
+	ssVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	return ssVM.GetOngoingSyncStateSummary()
+}
+
+func (vm *blockVM) GetLastStateSummary() (block.StateSummary, error) {
+	ssVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	return ssVM.GetLastStateSummary()
+}
+
+func (vm *blockVM) ParseStateSummary(summaryBytes []byte) (block.StateSummary, error) {
+	ssVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	return ssVM.ParseStateSummary(summaryBytes)
+}
+
+func (vm *blockVM) GetStateSummary(height uint64) (block.StateSummary, error) {
+	ssVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	return ssVM.GetStateSummary(height)
+}
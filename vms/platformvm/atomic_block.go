@@ -184,6 +184,7 @@ func (ab *AtomicBlock) Accept() error {
 			)
 		}
 	}
+	ab.vm.pubsub.Publish(NewPubSubFilterer(&ab.Tx))
 
 	ab.free()
 	return nil
@@ -31,7 +31,21 @@ func (c *CommitBlock) Accept() error {
 			c.vm.metrics.numVotesLost.Inc()
 		}
 	}
-	return c.DoubleDecisionBlock.Accept()
+
+	// The parent proposal is freed once this block is accepted
+	parentIntf, err := c.parentBlock()
+	if err != nil {
+		return err
+	}
+	parent, ok := parentIntf.(*ProposalBlock)
+	if !ok {
+		return errInvalidBlockType
+	}
+	if err := c.DoubleDecisionBlock.Accept(); err != nil {
+		return err
+	}
+	c.vm.pubsub.Publish(NewPubSubFilterer(&parent.Tx))
+	return nil
 }
 
 // Verify this block performs a valid state transition.
@@ -0,0 +1,38 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/flare-foundation/flare/database/inspect"
+)
+
+// AddLayout adds the prefixes of the VM's state to [layout], where [db] is the
+// prefix of the database given to the VM.
+//
+// Reward UTXOs, validator diffs and chains are kept under prefixes derived from
+// transaction IDs, heights and subnet IDs, so they are attributed to the VM
+// itself.
+func AddLayout(layout *inspect.Layout, chain string, db inspect.Prefix) {
+	baseDB := db.Wrap()
+
+	validatorsDB := baseDB.New(validatorsPrefix)
+	for _, validators := range []struct {
+		prefix []byte
+		name   string
+	}{
+		{prefix: currentPrefix, name: "current"},
+		{prefix: pendingPrefix, name: "pending"},
+	} {
+		stakersDB := validatorsDB.New(validators.prefix)
+		layout.Add(chain, "platformvm/"+validators.name+" validators", stakersDB.New(validatorPrefix))
+		layout.Add(chain, "platformvm/"+validators.name+" delegators", stakersDB.New(delegatorPrefix))
+		layout.Add(chain, "platformvm/"+validators.name+" subnet validators", stakersDB.New(subnetValidatorPrefix))
+	}
+
+	layout.Add(chain, "platformvm/blocks", baseDB.New(blockPrefix))
+	layout.Add(chain, "platformvm/txs", baseDB.New(txPrefix))
+	layout.Add(chain, "platformvm/utxos", baseDB.New(utxoPrefix))
+	layout.Add(chain, "platformvm/subnets", baseDB.New(subnetPrefix))
+	layout.Add(chain, "platformvm/singletons", baseDB.New(singletonPrefix))
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"github.com/flare-foundation/flare/api"
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/pubsub"
+	"github.com/flare-foundation/flare/vms/components/avax"
+)
+
+// NewPubSubFilterer returns a filterer that publishes the ID of [tx] to the
+// connections that filter on the addresses it pays or stakes to, its asset
+// IDs, its type, or the UTXOs it consumes or produces.
+func NewPubSubFilterer(tx *Tx) pubsub.Filterer {
+	txID := tx.ID()
+	fields := pubsub.Fields{
+		TxType:  pubsub.TypeName(tx.UnsignedTx),
+		UTXOIDs: tx.UnsignedTx.InputIDs().List(),
+	}
+
+	var (
+		base   *BaseTx
+		ins    []*avax.TransferableInput
+		outs   []*avax.TransferableOutput
+		owners []interface{}
+	)
+	switch utx := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		base = &utx.BaseTx
+		outs = append(outs, utx.Stake...)
+		owners = append(owners, utx.RewardsOwner)
+	case *UnsignedAddDelegatorTx:
+		base = &utx.BaseTx
+		outs = append(outs, utx.Stake...)
+		owners = append(owners, utx.RewardsOwner)
+	case *UnsignedAddSubnetValidatorTx:
+		base = &utx.BaseTx
+	case *UnsignedCreateChainTx:
+		base = &utx.BaseTx
+	case *UnsignedCreateSubnetTx:
+		base = &utx.BaseTx
+		owners = append(owners, utx.Owner)
+	case *UnsignedImportTx:
+		base = &utx.BaseTx
+		ins = append(ins, utx.ImportedInputs...)
+	case *UnsignedExportTx:
+		base = &utx.BaseTx
+		outs = append(outs, utx.ExportedOutputs...)
+		// The exported outputs are produced as UTXOs of this tx in the
+		// destination chain
+		for i := range utx.ExportedOutputs {
+			utxoID := avax.UTXOID{
+				TxID:        txID,
+				OutputIndex: uint32(len(utx.Outs) + i),
+			}
+			fields.UTXOIDs = append(fields.UTXOIDs, utxoID.InputID())
+		}
+	}
+	if base != nil {
+		ins = append(ins, base.Ins...)
+		outs = append(outs, base.Outs...)
+		// The outputs of the base tx are produced as UTXOs of this tx
+		for i := range base.Outs {
+			utxoID := avax.UTXOID{
+				TxID:        txID,
+				OutputIndex: uint32(i),
+			}
+			fields.UTXOIDs = append(fields.UTXOIDs, utxoID.InputID())
+		}
+	}
+
+	assetIDs := ids.Set{}
+	for _, in := range ins {
+		assetIDs.Add(in.AssetID())
+	}
+	for _, out := range outs {
+		assetIDs.Add(out.AssetID())
+		owners = append(owners, out.Out)
+	}
+	fields.AssetIDs = assetIDs.List()
+	for _, owner := range owners {
+		if addressable, ok := owner.(avax.Addressable); ok {
+			fields.Addresses = append(fields.Addresses, addressable.Addresses()...)
+		}
+	}
+	return pubsub.NewFilterer(fields, api.JSONTxID{
+		TxID: txID,
+	})
+}
@@ -219,6 +219,9 @@ func (sb *StandardBlock) Accept() error {
 			return fmt.Errorf("failed to execute onAcceptFunc: %w", err)
 		}
 	}
+	for _, tx := range sb.Txs {
+		sb.vm.pubsub.Publish(NewPubSubFilterer(tx))
+	}
 
 	sb.free()
 	return nil
@@ -17,6 +17,7 @@ import (
 	"github.com/flare-foundation/flare/codec/linearcodec"
 	"github.com/flare-foundation/flare/database/manager"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/pubsub"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/choices"
 	"github.com/flare-foundation/flare/snow/consensus/snowman"
@@ -78,6 +79,9 @@ type VM struct {
 
 	blockBuilder blockBuilder
 
+	// Publishes accepted txs to websocket subscribers
+	pubsub *pubsub.Server
+
 	uptimeManager uptime.Manager
 
 	rewards reward.Calculator
@@ -150,6 +154,8 @@ func (vm *VM) Initialize(
 
 	vm.fx = &secp256k1fx.Fx{}
 
+	vm.pubsub = pubsub.New(ctx.NetworkID, ctx.Log)
+
 	vm.ctx = ctx
 	vm.dbManager = dbManager
 
@@ -398,6 +404,10 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		"": {
 			Handler: server,
 		},
+		"/events": {
+			LockOptions: common.NoLock,
+			Handler:     vm.pubsub,
+		},
 	}, nil
 }
 
@@ -2100,6 +2100,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		msgChan,
 		nil,
 		time.Hour,
+		handler.DefaultMessageClassWeights,
 	)
 	assert.NoError(t, err)
 
@@ -876,14 +876,14 @@ func initTestRemoteProposerVM(
 		}
 	}
 
-	proVM := New(coreVM, proBlkStartTime, 0, false)
+	proVM := New(coreVM, proBlkStartTime, 0, false, nil)
 
 	valState := &validation.TestState{
 		T: t,
 	}
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
 	updater := &validation.TestUpdater{
-		UpdateValidatorsFunc: func(blockID ids.ID) error {
+		UpdateValidatorsFunc: func(blockID ids.ID, height uint64, timestamp time.Time) error {
 			return nil
 		},
 	}
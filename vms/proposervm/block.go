@@ -11,7 +11,6 @@ import (
 	"github.com/flare-foundation/flare/snow/choices"
 	"github.com/flare-foundation/flare/snow/consensus/snowman"
 	"github.com/flare-foundation/flare/vms/proposervm/block"
-	"github.com/flare-foundation/flare/vms/proposervm/proposer"
 )
 
 const (
@@ -118,7 +117,7 @@ func (p *postForkCommonComponents) Verify(parentTimestamp time.Time, parentPChai
 
 		childHeight := child.Height()
 		proposerID := child.Proposer()
-		minDelay, err := p.vm.Windower.Delay(childHeight, innerParentID, proposerID)
+		minDelay, err := p.vm.Windower.Delay(childHeight, parentTimestamp, innerParentID, proposerID)
 		if err != nil {
 			return err
 		}
@@ -129,7 +128,7 @@ func (p *postForkCommonComponents) Verify(parentTimestamp time.Time, parentPChai
 		}
 
 		// Verify the signature of the node
-		shouldHaveProposer := delay < proposer.MaxDelay
+		shouldHaveProposer := delay < p.vm.Windower.MaxDelay(childHeight, parentTimestamp)
 		if err := child.SignedBlock.Verify(shouldHaveProposer, p.vm.ctx.ChainID); err != nil {
 			return err
 		}
@@ -161,11 +160,12 @@ func (p *postForkCommonComponents) buildChild(
 		return nil, err
 	}
 
+	childHeight := p.innerBlk.Height() + 1
 	delay := newTimestamp.Sub(parentTimestamp)
-	if delay < proposer.MaxDelay {
-		parentHeight := p.innerBlk.Height()
+	maxDelay := p.vm.Windower.MaxDelay(childHeight, parentTimestamp)
+	if delay < maxDelay {
 		proposerID := p.vm.ctx.NodeID
-		minDelay, err := p.vm.Windower.Delay(parentHeight+1, innerParentID, proposerID)
+		minDelay, err := p.vm.Windower.Delay(childHeight, parentTimestamp, innerParentID, proposerID)
 		if err != nil {
 			return nil, err
 		}
@@ -193,7 +193,7 @@ func (p *postForkCommonComponents) buildChild(
 
 	// Build the child
 	var statelessChild block.SignedBlock
-	if delay >= proposer.MaxDelay {
+	if delay >= maxDelay {
 		statelessChild, err = block.BuildUnsigned(
 			parentID,
 			newTimestamp,
@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/json"
+	"github.com/flare-foundation/flare/utils/rpc"
+)
+
+// Interface compliance
+var _ Client = &client{}
+
+// Client interface for interacting with the proposervm of a chain
+type Client interface {
+	// GetProposerSchedule returns the proposers of the block at [height] on top
+	// of [parentID]. If [parentID] is empty, the preferred block is used. If
+	// [height] is 0, the height after the parent is used.
+	GetProposerSchedule(ctx context.Context, height uint64, parentID ids.ID) (*GetProposerScheduleReply, error)
+	// GetBlockProposer returns the proposer of [blockID]
+	GetBlockProposer(ctx context.Context, blockID ids.ID) (*GetBlockProposerReply, error)
+	// GetProposalStats returns the proposal stats of [nodeIDs], or of all
+	// tracked validators if [nodeIDs] is empty
+	GetProposalStats(ctx context.Context, nodeIDs []string) ([]APIProposalStats, error)
+}
+
+// Client implementation for interacting with the proposervm of a chain
+type client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a Client for interacting with the proposervm of [chain]
+func NewClient(uri, chain string) Client {
+	return &client{
+		requester: rpc.NewEndpointRequester(uri, fmt.Sprintf("/ext/%s%s", constants.ChainAliasPrefix+chain, proposerEndpoint), "proposervm"),
+	}
+}
+
+func (c *client) GetProposerSchedule(ctx context.Context, height uint64, parentID ids.ID) (*GetProposerScheduleReply, error) {
+	args := &GetProposerScheduleArgs{
+		ParentID: parentID,
+	}
+	if height != 0 {
+		jsonHeight := json.Uint64(height)
+		args.Height = &jsonHeight
+	}
+	res := &GetProposerScheduleReply{}
+	err := c.requester.SendRequest(ctx, "getProposerSchedule", args, res)
+	return res, err
+}
+
+func (c *client) GetBlockProposer(ctx context.Context, blockID ids.ID) (*GetBlockProposerReply, error) {
+	res := &GetBlockProposerReply{}
+	err := c.requester.SendRequest(ctx, "getBlockProposer", &GetBlockProposerArgs{
+		BlockID: blockID,
+	}, res)
+	return res, err
+}
+
+func (c *client) GetProposalStats(ctx context.Context, nodeIDs []string) ([]APIProposalStats, error) {
+	res := &GetProposalStatsReply{}
+	err := c.requester.SendRequest(ctx, "getProposalStats", &GetProposalStatsArgs{
+		NodeIDs: nodeIDs,
+	}, res)
+	return res.Stats, err
+}
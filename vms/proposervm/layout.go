@@ -0,0 +1,17 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/flare-foundation/flare/database/inspect"
+	"github.com/flare-foundation/flare/vms/proposervm/state"
+)
+
+// AddLayout adds the prefixes of the proposervm to [layout], where [db] is the
+// prefix of the database given to the VM. The inner VM gets the same database.
+func AddLayout(layout *inspect.Layout, chain string, db inspect.Prefix) {
+	vmDB := db.New(dbPrefix).Wrap()
+	layout.Add(chain, "proposervm", vmDB)
+	state.AddLayout(layout, chain, vmDB)
+}
@@ -22,6 +22,7 @@ type postForkBlock struct {
 // 2) Updates the validator set.
 // 3) Persists this block in storage
 // 4) Calls Reject() on siblings of this block and their descendants.
+// 5) Records whether the scheduled proposers proposed this block.
 func (b *postForkBlock) Accept() error {
 	blkID := b.ID()
 	if err := b.vm.State.SetLastAccepted(blkID); err != nil {
@@ -44,12 +45,18 @@ func (b *postForkBlock) Accept() error {
 	}
 
 	innerID := b.innerBlk.ID()
-	if err := b.vm.ctx.ValidatorsUpdater.UpdateValidators(innerID); err != nil {
+	if err := b.vm.ctx.ValidatorsUpdater.UpdateValidators(innerID, b.innerBlk.Height(), b.innerBlk.Timestamp()); err != nil {
 		return err
 	}
 
 	b.vm.ctx.Log.Debug("updated validators to post-fork block (hash: %s)", innerID.Hex())
 
+	// Failing to track the proposers must not prevent the block from being
+	// accepted
+	if err := b.vm.recordProposal(b); err != nil {
+		b.vm.ctx.Log.Warn("couldn't record proposer of block %s: %s", blkID, err)
+	}
+	b.vm.publishAccepted(b)
 	return nil
 }
 
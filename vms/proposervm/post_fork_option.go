@@ -49,12 +49,13 @@ func (b *postForkOption) Accept() error {
 	}
 
 	innerID := b.innerBlk.ID()
-	if err := b.vm.ctx.ValidatorsUpdater.UpdateValidators(innerID); err != nil {
+	if err := b.vm.ctx.ValidatorsUpdater.UpdateValidators(innerID, b.innerBlk.Height(), b.innerBlk.Timestamp()); err != nil {
 		return err
 	}
 
 	b.vm.ctx.Log.Debug("updated validators to post-fork option (hash: %s)", innerID.Hex())
 
+	b.vm.publishAccepted(b)
 	return nil
 }
 
@@ -27,7 +27,7 @@ func (b *preForkBlock) Accept() error {
 	}
 
 	innerID := b.Block.ID()
-	if err := b.vm.ctx.ValidatorsUpdater.UpdateValidators(innerID); err != nil {
+	if err := b.vm.ctx.ValidatorsUpdater.UpdateValidators(innerID, b.Block.Height(), b.Block.Timestamp()); err != nil {
 		return err
 	}
 
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/wrappers"
+)
+
+// ProposalStats counts how a validator used its proposer windows
+type ProposalStats struct {
+	// Number of accepted blocks for which the validator had the first window
+	ScheduledFirst uint64
+	// Number of accepted blocks the validator proposed
+	Proposed uint64
+	// Number of windows of the validator that passed without the validator
+	// proposing the block that got accepted
+	MissedWindows uint64
+}
+
+// proposalTracker compares the proposer schedule of accepted blocks with their
+// actual proposers.
+// Not safe for concurrent use; the context lock of the chain must be held.
+type proposalTracker struct {
+	stats map[ids.ShortID]*ProposalStats
+
+	scheduledFirst *prometheus.CounterVec
+	proposed       *prometheus.CounterVec
+	missedWindows  *prometheus.CounterVec
+}
+
+func newProposalTracker(namespace string, registerer prometheus.Registerer) (*proposalTracker, error) {
+	t := &proposalTracker{
+		stats: make(map[ids.ShortID]*ProposalStats),
+		scheduledFirst: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scheduled_first",
+			Help:      "Number of accepted blocks for which the validator had the first proposer window",
+		}, []string{"nodeID"}),
+		proposed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "proposed",
+			Help:      "Number of accepted blocks proposed by the validator",
+		}, []string{"nodeID"}),
+		missedWindows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "missed_windows",
+			Help:      "Number of proposer windows of the validator that passed before the accepted block was proposed",
+		}, []string{"nodeID"}),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(t.scheduledFirst),
+		registerer.Register(t.proposed),
+		registerer.Register(t.missedWindows),
+	)
+	return t, errs.Err
+}
+
+// record an accepted block that was scheduled for [schedule] and proposed by
+// [proposer]. [proposer] is empty if the block was proposed after all windows
+// had passed.
+func (t *proposalTracker) record(schedule []ids.ShortID, proposer ids.ShortID) {
+	if len(schedule) > 0 {
+		t.getStats(schedule[0]).ScheduledFirst++
+		t.scheduledFirst.WithLabelValues(nodeIDLabel(schedule[0])).Inc()
+	}
+
+	for _, nodeID := range schedule {
+		if nodeID == proposer {
+			break
+		}
+		t.getStats(nodeID).MissedWindows++
+		t.missedWindows.WithLabelValues(nodeIDLabel(nodeID)).Inc()
+	}
+
+	if proposer != ids.ShortEmpty {
+		t.getStats(proposer).Proposed++
+		t.proposed.WithLabelValues(nodeIDLabel(proposer)).Inc()
+	}
+}
+
+func (t *proposalTracker) getStats(nodeID ids.ShortID) *ProposalStats {
+	stats, ok := t.stats[nodeID]
+	if !ok {
+		stats = &ProposalStats{}
+		t.stats[nodeID] = stats
+	}
+	return stats
+}
+
+func nodeIDLabel(nodeID ids.ShortID) string {
+	return nodeID.PrefixedString(constants.NodeIDPrefix)
+}
+
+// recordProposal compares the proposer of the accepted block [blk] with the
+// proposers that were scheduled for it.
+func (vm *VM) recordProposal(blk *postForkBlock) error {
+	parent, err := vm.getBlock(blk.Parent())
+	if err != nil {
+		return fmt.Errorf("couldn't get parent block: %w", err)
+	}
+	schedule, err := vm.Windower.Proposers(blk.Height(), parent.Timestamp(), blk.innerBlk.Parent())
+	if err != nil {
+		return fmt.Errorf("couldn't get proposer schedule: %w", err)
+	}
+	vm.proposals.record(schedule, blk.Proposer())
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+)
+
+func TestProposalTrackerRecord(t *testing.T) {
+	assert := assert.New(t)
+
+	registerer := prometheus.NewRegistry()
+	tracker, err := newProposalTracker("", registerer)
+	require.NoError(t, err)
+
+	nodeID0 := ids.ShortID{1}
+	nodeID1 := ids.ShortID{2}
+	nodeID2 := ids.ShortID{3}
+	schedule := []ids.ShortID{nodeID0, nodeID1, nodeID2}
+
+	// The first scheduled validator proposed the block
+	tracker.record(schedule, nodeID0)
+	// The second scheduled validator proposed the block
+	tracker.record(schedule, nodeID1)
+	// Nobody proposed the block within the windows
+	tracker.record(schedule, ids.ShortEmpty)
+
+	assert.Equal(ProposalStats{ScheduledFirst: 3, Proposed: 1, MissedWindows: 2}, *tracker.stats[nodeID0])
+	assert.Equal(ProposalStats{Proposed: 1, MissedWindows: 1}, *tracker.stats[nodeID1])
+	assert.Equal(ProposalStats{MissedWindows: 1}, *tracker.stats[nodeID2])
+
+	metrics, err := registerer.Gather()
+	require.NoError(t, err)
+
+	missed := map[string]float64{}
+	for _, family := range metrics {
+		if family.GetName() != "missed_windows" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			missed[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(map[string]float64{
+		nodeID0.PrefixedString(constants.NodeIDPrefix): 2,
+		nodeID1.PrefixedString(constants.NodeIDPrefix): 1,
+		nodeID2.PrefixedString(constants.NodeIDPrefix): 1,
+	}, missed)
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Windowing policies
+const (
+	// WeightedPolicy samples the proposers of a block by validator weight.
+	WeightedPolicy = "weighted"
+	// RoundRobinPolicy rotates through the validators sorted by node ID, giving
+	// every validator the first window in turn.
+	RoundRobinPolicy = "round-robin"
+	// AnyonePolicy gives no validator a window of its own, so that anyone can
+	// propose once all windows have passed.
+	AnyonePolicy = "anyone"
+)
+
+var (
+	errUnknownPolicy        = errors.New("unknown windowing policy")
+	errNoWindows            = errors.New("windowing policy needs at least one window")
+	errNegativeNumWindows   = errors.New("number of windows can't be negative")
+	errNonPositiveWindowDur = errors.New("window duration must be positive")
+
+	// DefaultPolicy is in effect until the first configured policy activates
+	DefaultPolicy = Policy{
+		Kind:           WeightedPolicy,
+		NumWindows:     MaxWindows,
+		WindowDuration: WindowDuration,
+	}
+)
+
+// Policy determines which validators get to propose a block in which window
+// after the timestamp of the parent block. After [NumWindows] windows, anyone
+// can propose a block.
+type Policy struct {
+	// The policy is in effect for blocks at [ActivationHeight] and above, whose
+	// parent block's timestamp is not before [ActivationTime].
+	ActivationHeight uint64
+	ActivationTime   time.Time
+
+	Kind           string
+	NumWindows     int
+	WindowDuration time.Duration
+}
+
+// MaxDelay returns the delay after which anyone can propose a block
+func (p *Policy) MaxDelay() time.Duration {
+	return time.Duration(p.NumWindows) * p.WindowDuration
+}
+
+func (p *Policy) activeAt(height uint64, parentTimestamp time.Time) bool {
+	return height >= p.ActivationHeight && !parentTimestamp.Before(p.ActivationTime)
+}
+
+func (p *Policy) verify() error {
+	switch p.Kind {
+	case WeightedPolicy, RoundRobinPolicy:
+		if p.NumWindows < 1 {
+			return errNoWindows
+		}
+	case AnyonePolicy:
+		if p.NumWindows < 0 {
+			return errNegativeNumWindows
+		}
+	default:
+		return fmt.Errorf("%w: %q", errUnknownPolicy, p.Kind)
+	}
+	if p.WindowDuration <= 0 {
+		return errNonPositiveWindowDur
+	}
+	return nil
+}
+
+type policyJSON struct {
+	ActivationHeight uint64    `json:"activationHeight"`
+	ActivationTime   time.Time `json:"activationTime"`
+	Policy           string    `json:"policy"`
+	NumWindows       int       `json:"numWindows"`
+	WindowDuration   string    `json:"windowDuration"`
+}
+
+type policiesJSON struct {
+	Windowing []policyJSON `json:"windowing"`
+}
+
+// ParsePolicies parses the windowing policies of a chain's proposer config.
+// For example:
+//
+//	{
+//	  "windowing": [
+//	    {"activationHeight": 1000, "policy": "round-robin", "numWindows": 3, "windowDuration": "2s"},
+//	    {"activationTime": "2022-06-01T00:00:00Z", "policy": "anyone", "numWindows": 1, "windowDuration": "5s"}
+//	  ]
+//	}
+//
+// The last listed policy that is active for a block is the one in effect.
+func ParsePolicies(b []byte) ([]Policy, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	config := policiesJSON{}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal proposer config: %w", err)
+	}
+
+	policies := make([]Policy, len(config.Windowing))
+	for i, p := range config.Windowing {
+		windowDuration, err := time.ParseDuration(p.WindowDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window duration of windowing policy %d: %w", i, err)
+		}
+		policies[i] = Policy{
+			ActivationHeight: p.ActivationHeight,
+			ActivationTime:   p.ActivationTime,
+			Kind:             p.Policy,
+			NumWindows:       p.NumWindows,
+			WindowDuration:   windowDuration,
+		}
+		if err := policies[i].verify(); err != nil {
+			return nil, fmt.Errorf("invalid windowing policy %d: %w", i, err)
+		}
+	}
+	return policies, nil
+}
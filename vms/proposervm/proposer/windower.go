@@ -15,7 +15,7 @@ import (
 	"github.com/flare-foundation/flare/utils/wrappers"
 )
 
-// Proposer list constants
+// Proposer list constants of the default policy
 const (
 	MaxWindows     = 6
 	WindowDuration = 5 * time.Second
@@ -27,9 +27,31 @@ var _ Windower = &windower{}
 type Windower interface {
 	Delay(
 		height uint64,
+		parentTimestamp time.Time,
 		parentID ids.ID,
 		validatorID ids.ShortID,
 	) (time.Duration, error)
+
+	// MaxDelay returns the delay after which anyone can propose the block at
+	// [height].
+	MaxDelay(
+		height uint64,
+		parentTimestamp time.Time,
+	) time.Duration
+
+	// Proposers returns the validators that can propose the block at [height]
+	// before [MaxDelay], in the order of their windows.
+	Proposers(
+		height uint64,
+		parentTimestamp time.Time,
+		parentID ids.ID,
+	) ([]ids.ShortID, error)
+
+	// Policy returns the windowing policy in effect for the block at [height].
+	Policy(
+		height uint64,
+		parentTimestamp time.Time,
+	) Policy
 }
 
 // windower interfaces with P-Chain and it is responsible for calculating the
@@ -38,26 +60,75 @@ type windower struct {
 	retriever validation.Retriever
 	nonce     uint64
 	sampler   sampler.WeightedWithoutReplacement
+	policies  []Policy
 }
 
-func New(retriever validation.Retriever, chainID ids.ID) Windower {
+// New returns a windower that applies [DefaultPolicy] until one of [policies]
+// activates.
+func New(retriever validation.Retriever, chainID ids.ID, policies ...Policy) Windower {
 	w := wrappers.Packer{Bytes: chainID[:]}
 	return &windower{
 		retriever: retriever,
 		nonce:     w.UnpackLong(),
 		sampler:   sampler.NewDeterministicWeightedWithoutReplacement(),
+		policies:  policies,
 	}
 }
 
-func (w *windower) Delay(height uint64, parentID ids.ID, validatorID ids.ShortID) (time.Duration, error) {
+func (w *windower) Delay(height uint64, parentTimestamp time.Time, parentID ids.ID, validatorID ids.ShortID) (time.Duration, error) {
+	policy := w.policyAt(height, parentTimestamp)
+	if validatorID == ids.ShortEmpty || policy.Kind == AnyonePolicy {
+		return policy.MaxDelay(), nil
+	}
 
-	if validatorID == ids.ShortEmpty {
-		return MaxDelay, nil
+	proposers, err := w.proposers(policy, height, parentID)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := time.Duration(0)
+	for _, nodeID := range proposers {
+		if nodeID == validatorID {
+			return delay, nil
+		}
+		delay += policy.WindowDuration
+	}
+
+	return delay, nil
+}
+
+func (w *windower) MaxDelay(height uint64, parentTimestamp time.Time) time.Duration {
+	policy := w.policyAt(height, parentTimestamp)
+	return policy.MaxDelay()
+}
+
+func (w *windower) Proposers(height uint64, parentTimestamp time.Time, parentID ids.ID) ([]ids.ShortID, error) {
+	policy := w.policyAt(height, parentTimestamp)
+	return w.proposers(policy, height, parentID)
+}
+
+func (w *windower) Policy(height uint64, parentTimestamp time.Time) Policy {
+	return *w.policyAt(height, parentTimestamp)
+}
+
+func (w *windower) policyAt(height uint64, parentTimestamp time.Time) *Policy {
+	policy := &DefaultPolicy
+	for i := range w.policies {
+		if w.policies[i].activeAt(height, parentTimestamp) {
+			policy = &w.policies[i]
+		}
+	}
+	return policy
+}
+
+func (w *windower) proposers(policy *Policy, height uint64, parentID ids.ID) ([]ids.ShortID, error) {
+	if policy.Kind == AnyonePolicy {
+		return nil, nil
 	}
 
 	validators, err := w.retriever.GetValidators(parentID)
 	if err != nil {
-		return 0, fmt.Errorf("could not get validators for windowing: %w", err)
+		return nil, fmt.Errorf("could not get validators for windowing: %w", err)
 	}
 
 	// canonically sort validators
@@ -66,24 +137,32 @@ func (w *windower) Delay(height uint64, parentID ids.ID, validatorID ids.ShortID
 	validatorList := validators.List()
 	sort.Sort(sortByID(validatorList))
 
-	// Then, create slices of weights and IDs for sampling.
+	if policy.Kind == RoundRobinPolicy {
+		return roundRobinProposers(policy, height, validatorList), nil
+	}
+	return w.weightedProposers(policy, height, validatorList)
+}
+
+func (w *windower) weightedProposers(policy *Policy, height uint64, validatorList []validation.Validator) ([]ids.ShortID, error) {
+	// Create slices of weights and IDs for sampling.
 	totalWeight := uint64(0)
 	validatorIDs := make([]ids.ShortID, 0, len(validatorList))
 	weights := make([]uint64, 0, len(validatorList))
 	for _, validator := range validatorList {
+		var err error
 		totalWeight, err = math.Add64(totalWeight, validator.Weight())
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 		validatorIDs = append(validatorIDs, validator.ID())
 		weights = append(weights, validator.Weight())
 	}
 
 	if err := w.sampler.Initialize(weights); err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	numToSample := MaxWindows
+	numToSample := policy.NumWindows
 	if totalWeight < uint64(numToSample) {
 		numToSample = int(totalWeight)
 	}
@@ -93,17 +172,30 @@ func (w *windower) Delay(height uint64, parentID ids.ID, validatorID ids.ShortID
 
 	indices, err := w.sampler.Sample(numToSample)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	delay := time.Duration(0)
-	for _, index := range indices {
-		nodeID := validatorIDs[index]
-		if nodeID == validatorID {
-			return delay, nil
-		}
-		delay += WindowDuration
+	proposers := make([]ids.ShortID, len(indices))
+	for i, index := range indices {
+		proposers[i] = validatorIDs[index]
 	}
+	return proposers, nil
+}
 
-	return delay, nil
+// roundRobinProposers gives the first window at [height] to the validator at
+// index [height] modulo the number of validators, and the following windows to
+// the validators after it.
+func roundRobinProposers(policy *Policy, height uint64, validatorList []validation.Validator) []ids.ShortID {
+	numValidators := uint64(len(validatorList))
+	numWindows := uint64(policy.NumWindows)
+	if numValidators < numWindows {
+		numWindows = numValidators
+	}
+
+	proposers := make([]ids.ShortID, numWindows)
+	for i := range proposers {
+		index := (height%numValidators + uint64(i)) % numValidators
+		proposers[i] = validatorList[index].ID()
+	}
+	return proposers
 }
@@ -31,7 +31,7 @@ func TestWindowerNoValidators(t *testing.T) {
 
 	w := New(retriever, chainID)
 
-	delay, err := w.Delay(1, ids.ID{}, nodeID)
+	delay, err := w.Delay(1, time.Time{}, ids.ID{}, nodeID)
 	assert.NoError(err)
 	assert.EqualValues(0, delay)
 }
@@ -53,11 +53,11 @@ func TestWindowerRepeatedValidator(t *testing.T) {
 
 	w := New(retriever, chainID)
 
-	validatorDelay, err := w.Delay(1, ids.ID{}, validatorID)
+	validatorDelay, err := w.Delay(1, time.Time{}, ids.ID{}, validatorID)
 	assert.NoError(err)
 	assert.EqualValues(0, validatorDelay)
 
-	nonValidatorDelay, err := w.Delay(1, ids.ID{}, nonValidatorID)
+	nonValidatorDelay, err := w.Delay(1, time.Time{}, ids.ID{}, nonValidatorID)
 	assert.NoError(err)
 	assert.EqualValues(MaxDelay, nonValidatorDelay)
 }
@@ -94,7 +94,7 @@ func TestWindowerChangeByHeight(t *testing.T) {
 	for i, expectedDelay := range expectedDelays1 {
 		vdrID := validatorIDs[i]
 		fmt.Println(vdrID)
-		validatorDelay, err := w.Delay(1, ids.ID{}, vdrID)
+		validatorDelay, err := w.Delay(1, time.Time{}, ids.ID{}, vdrID)
 		assert.NoError(err)
 		assert.EqualValues(expectedDelay, validatorDelay)
 	}
@@ -109,7 +109,7 @@ func TestWindowerChangeByHeight(t *testing.T) {
 	}
 	for i, expectedDelay := range expectedDelays2 {
 		vdrID := validatorIDs[i]
-		validatorDelay, err := w.Delay(2, ids.ID{}, vdrID)
+		validatorDelay, err := w.Delay(2, time.Time{}, ids.ID{}, vdrID)
 		assert.NoError(err)
 		assert.EqualValues(expectedDelay, validatorDelay)
 	}
@@ -152,7 +152,7 @@ func TestWindowerChangeByChain(t *testing.T) {
 	}
 	for i, expectedDelay := range expectedDelays0 {
 		vdrID := validatorIDs[i]
-		validatorDelay, err := w0.Delay(1, ids.ID{}, vdrID)
+		validatorDelay, err := w0.Delay(1, time.Time{}, ids.ID{}, vdrID)
 		assert.NoError(err)
 		assert.EqualValues(expectedDelay, validatorDelay)
 	}
@@ -167,8 +167,146 @@ func TestWindowerChangeByChain(t *testing.T) {
 	}
 	for i, expectedDelay := range expectedDelays1 {
 		vdrID := validatorIDs[i]
-		validatorDelay, err := w1.Delay(1, ids.ID{}, vdrID)
+		validatorDelay, err := w1.Delay(1, time.Time{}, ids.ID{}, vdrID)
 		assert.NoError(err)
 		assert.EqualValues(expectedDelay, validatorDelay)
 	}
 }
+
+func TestWindowerRoundRobin(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID := ids.GenerateTestID()
+	validatorIDs := []ids.ShortID{{1}, {2}, {3}, {4}}
+
+	retriever := &validation.TestRetriever{
+		GetValidatorsByBlockIDFunc: func(blockID ids.ID) (validation.Set, error) {
+			s := validation.NewSet()
+			for i, id := range validatorIDs {
+				_ = s.AddWeight(id, uint64(i+1))
+			}
+			return s, nil
+		},
+	}
+
+	w := New(retriever, chainID, Policy{
+		Kind:           RoundRobinPolicy,
+		NumWindows:     3,
+		WindowDuration: time.Second,
+	})
+	assert.Equal(3*time.Second, w.MaxDelay(5, time.Time{}))
+
+	// Height 5 starts at the validator at index 1 of the sorted list
+	expectedDelays := []time.Duration{
+		3 * time.Second,
+		0 * time.Second,
+		1 * time.Second,
+		2 * time.Second,
+	}
+	for i, expectedDelay := range expectedDelays {
+		validatorDelay, err := w.Delay(5, time.Time{}, ids.ID{}, validatorIDs[i])
+		assert.NoError(err)
+		assert.Equal(expectedDelay, validatorDelay)
+	}
+}
+
+func TestWindowerAnyone(t *testing.T) {
+	assert := assert.New(t)
+
+	retriever := &validation.TestRetriever{
+		GetValidatorsByBlockIDFunc: func(blockID ids.ID) (validation.Set, error) {
+			t.Fatal("validators shouldn't be needed")
+			return nil, nil
+		},
+	}
+
+	w := New(retriever, ids.GenerateTestID(), Policy{
+		Kind:           AnyonePolicy,
+		NumWindows:     2,
+		WindowDuration: time.Second,
+	})
+
+	delay, err := w.Delay(1, time.Time{}, ids.ID{}, ids.ShortID{1})
+	assert.NoError(err)
+	assert.Equal(2*time.Second, delay)
+}
+
+func TestWindowerPolicyActivation(t *testing.T) {
+	assert := assert.New(t)
+
+	validatorID := ids.ShortID{1}
+	retriever := &validation.TestRetriever{
+		GetValidatorsByBlockIDFunc: func(blockID ids.ID) (validation.Set, error) {
+			s := validation.NewSet()
+			_ = s.AddWeight(validatorID, 1)
+			return s, nil
+		},
+	}
+
+	activationTime := time.Unix(1000, 0)
+	w := New(retriever, ids.GenerateTestID(),
+		Policy{
+			ActivationHeight: 10,
+			Kind:             RoundRobinPolicy,
+			NumWindows:       2,
+			WindowDuration:   time.Second,
+		},
+		Policy{
+			ActivationHeight: 10,
+			ActivationTime:   activationTime,
+			Kind:             AnyonePolicy,
+			NumWindows:       1,
+			WindowDuration:   time.Second,
+		},
+	)
+
+	assert.Equal(MaxDelay, w.MaxDelay(9, activationTime))
+	assert.Equal(2*time.Second, w.MaxDelay(10, activationTime.Add(-time.Second)))
+	assert.Equal(time.Second, w.MaxDelay(10, activationTime))
+
+	delay, err := w.Delay(10, activationTime.Add(-time.Second), ids.ID{}, validatorID)
+	assert.NoError(err)
+	assert.EqualValues(0, delay)
+
+	delay, err = w.Delay(10, activationTime, ids.ID{}, validatorID)
+	assert.NoError(err)
+	assert.Equal(time.Second, delay)
+}
+
+func TestParsePolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	policies, err := ParsePolicies(nil)
+	assert.NoError(err)
+	assert.Empty(policies)
+
+	policies, err = ParsePolicies([]byte(`{
+		"windowing": [
+			{"activationHeight": 1000, "policy": "round-robin", "numWindows": 3, "windowDuration": "2s"},
+			{"activationTime": "2022-06-01T00:00:00Z", "policy": "anyone", "numWindows": 0, "windowDuration": "5s"}
+		]
+	}`))
+	assert.NoError(err)
+	assert.Equal([]Policy{
+		{
+			ActivationHeight: 1000,
+			Kind:             RoundRobinPolicy,
+			NumWindows:       3,
+			WindowDuration:   2 * time.Second,
+		},
+		{
+			ActivationTime: time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Kind:           AnyonePolicy,
+			WindowDuration: 5 * time.Second,
+		},
+	}, policies)
+
+	_, err = ParsePolicies([]byte(`{"windowing": [{"policy": "random", "numWindows": 1, "windowDuration": "1s"}]}`))
+	assert.ErrorIs(err, errUnknownPolicy)
+
+	_, err = ParsePolicies([]byte(`{"windowing": [{"policy": "weighted", "numWindows": 0, "windowDuration": "1s"}]}`))
+	assert.ErrorIs(err, errNoWindows)
+
+	_, err = ParsePolicies([]byte(`{"windowing": [{"policy": "weighted", "numWindows": 1, "windowDuration": "soon"}]}`))
+	assert.Error(err)
+}
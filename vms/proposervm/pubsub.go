@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/pubsub"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/json"
+
+	statelessblock "github.com/flare-foundation/flare/vms/proposervm/block"
+)
+
+const (
+	// eventsEndpoint is the extension of [proposerEndpoint] under which
+	// accepted blocks are published
+	eventsEndpoint = "/events"
+
+	// Types of the published blocks, which subscribers can filter on
+	blockType  = "Block"
+	optionType = "Option"
+)
+
+// AcceptedBlock is published to the subscribers of the proposervm events when
+// a post fork block is accepted
+type AcceptedBlock struct {
+	BlockID      ids.ID      `json:"blockID"`
+	ParentID     ids.ID      `json:"parentID"`
+	InnerBlockID ids.ID      `json:"innerBlockID"`
+	Height       json.Uint64 `json:"height"`
+	Type         string      `json:"type"`
+	// Proposer is empty if anyone could propose the block, and for options
+	Proposer string `json:"proposer,omitempty"`
+}
+
+// publishAccepted publishes [blk] to the subscribers that filter on its type
+// or, if it has a proposer, on the proposer's node ID as an address.
+func (vm *VM) publishAccepted(blk PostForkBlock) {
+	msg := &AcceptedBlock{
+		BlockID:      blk.ID(),
+		ParentID:     blk.Parent(),
+		InnerBlockID: blk.getInnerBlk().ID(),
+		Height:       json.Uint64(blk.Height()),
+		Type:         optionType,
+	}
+	fields := pubsub.Fields{}
+	if signedBlk, ok := blk.getStatelessBlk().(statelessblock.SignedBlock); ok {
+		msg.Type = blockType
+		if proposer := signedBlk.Proposer(); proposer != ids.ShortEmpty {
+			msg.Proposer = proposer.PrefixedString(constants.NodeIDPrefix)
+			fields.Addresses = [][]byte{proposer[:]}
+		}
+	}
+	fields.TxType = msg.Type
+	vm.pubsub.Publish(pubsub.NewFilterer(fields, msg))
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/json"
+
+	statelessblock "github.com/flare-foundation/flare/vms/proposervm/block"
+)
+
+var (
+	errHeightMismatch = errors.New("height doesn't follow the parent block")
+	errNoProposer     = errors.New("block has no proposer")
+)
+
+// Service defines the API calls that can be made to the proposervm of a chain
+type Service struct{ vm *VM }
+
+// GetProposerScheduleArgs are the arguments for calling GetProposerSchedule
+type GetProposerScheduleArgs struct {
+	// Height of the block to propose. Defaults to the height after the parent.
+	Height *json.Uint64 `json:"height"`
+	// ParentID of the block to propose. Defaults to the preferred block.
+	ParentID ids.ID `json:"parentID"`
+}
+
+// APIProposerWindow is the window in which a validator can propose a block
+type APIProposerWindow struct {
+	NodeID    string    `json:"nodeID"`
+	Delay     string    `json:"delay"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+// GetProposerScheduleReply are the results from calling GetProposerSchedule
+type GetProposerScheduleReply struct {
+	Height          json.Uint64         `json:"height"`
+	ParentID        ids.ID              `json:"parentID"`
+	ParentTimestamp time.Time           `json:"parentTimestamp"`
+	Policy          string              `json:"policy"`
+	WindowDuration  string              `json:"windowDuration"`
+	Proposers       []APIProposerWindow `json:"proposers"`
+	// Anyone can propose the block after [AnyoneAfter]
+	AnyoneAfter time.Time `json:"anyoneAfter"`
+}
+
+// GetProposerSchedule returns the validators that can propose a block on top of
+// [args.ParentID], in the order of their windows.
+func (service *Service) GetProposerSchedule(_ *http.Request, args *GetProposerScheduleArgs, reply *GetProposerScheduleReply) error {
+	vm := service.vm
+	vm.ctx.Log.Debug("proposervm: GetProposerSchedule called with Height: %v, ParentID: %s", args.Height, args.ParentID)
+
+	parentID := args.ParentID
+	if parentID == ids.Empty {
+		parentID = vm.preferred
+	}
+	if parentID == ids.Empty {
+		var err error
+		parentID, err = vm.LastAccepted()
+		if err != nil {
+			return fmt.Errorf("couldn't get last accepted block ID: %w", err)
+		}
+	}
+	parent, err := vm.getBlock(parentID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", parentID, err)
+	}
+
+	height := parent.Height() + 1
+	if args.Height != nil && uint64(*args.Height) != height {
+		return fmt.Errorf("%w: expected %d but got %d", errHeightMismatch, height, *args.Height)
+	}
+	parentTimestamp := parent.Timestamp()
+	if parentTimestamp.Before(vm.activationTime) {
+		return errProposersNotActivated
+	}
+
+	innerParentID := parent.getInnerBlk().ID()
+	proposers, err := vm.Windower.Proposers(height, parentTimestamp, innerParentID)
+	if err != nil {
+		return err
+	}
+	policy := vm.Windower.Policy(height, parentTimestamp)
+
+	reply.Height = json.Uint64(height)
+	reply.ParentID = parentID
+	reply.ParentTimestamp = parentTimestamp
+	reply.Policy = policy.Kind
+	reply.WindowDuration = policy.WindowDuration.String()
+	reply.Proposers = make([]APIProposerWindow, len(proposers))
+	for i, nodeID := range proposers {
+		delay := time.Duration(i) * policy.WindowDuration
+		reply.Proposers[i] = APIProposerWindow{
+			NodeID:    nodeID.PrefixedString(constants.NodeIDPrefix),
+			Delay:     delay.String(),
+			StartTime: parentTimestamp.Add(delay),
+			EndTime:   parentTimestamp.Add(delay + policy.WindowDuration),
+		}
+	}
+	reply.AnyoneAfter = parentTimestamp.Add(policy.MaxDelay())
+	return nil
+}
+
+// GetBlockProposerArgs are the arguments for calling GetBlockProposer
+type GetBlockProposerArgs struct {
+	BlockID ids.ID `json:"blockID"`
+}
+
+// GetBlockProposerReply are the results from calling GetBlockProposer
+type GetBlockProposerReply struct {
+	BlockID      ids.ID      `json:"blockID"`
+	Height       json.Uint64 `json:"height"`
+	Timestamp    time.Time   `json:"timestamp"`
+	PChainHeight json.Uint64 `json:"pChainHeight"`
+	// Proposer is empty if the block was proposed after all proposer windows
+	// had passed, which leaves the block unsigned
+	Proposer string `json:"proposer"`
+}
+
+// GetBlockProposer returns the validator that proposed the given block
+func (service *Service) GetBlockProposer(_ *http.Request, args *GetBlockProposerArgs, reply *GetBlockProposerReply) error {
+	vm := service.vm
+	vm.ctx.Log.Debug("proposervm: GetBlockProposer called with BlockID: %s", args.BlockID)
+
+	blk, err := vm.getPostForkBlock(args.BlockID)
+	if err != nil {
+		return fmt.Errorf("couldn't get post-fork block %s: %w", args.BlockID, err)
+	}
+	signedBlk, ok := blk.getStatelessBlk().(statelessblock.SignedBlock)
+	if !ok {
+		// Options are built by every node on its own and aren't signed
+		return fmt.Errorf("%w: %s is an option", errNoProposer, args.BlockID)
+	}
+
+	reply.BlockID = args.BlockID
+	reply.Height = json.Uint64(blk.Height())
+	reply.Timestamp = signedBlk.Timestamp()
+	reply.PChainHeight = json.Uint64(signedBlk.PChainHeight())
+	if proposer := signedBlk.Proposer(); proposer != ids.ShortEmpty {
+		reply.Proposer = proposer.PrefixedString(constants.NodeIDPrefix)
+	}
+	return nil
+}
+
+// GetProposalStatsArgs are the arguments for calling GetProposalStats
+type GetProposalStatsArgs struct {
+	// NodeIDs to return the stats of. Defaults to all tracked validators.
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+// APIProposalStats counts how a validator used its proposer windows
+type APIProposalStats struct {
+	NodeID         string      `json:"nodeID"`
+	ScheduledFirst json.Uint64 `json:"scheduledFirst"`
+	Proposed       json.Uint64 `json:"proposed"`
+	MissedWindows  json.Uint64 `json:"missedWindows"`
+}
+
+// GetProposalStatsReply are the results from calling GetProposalStats
+type GetProposalStatsReply struct {
+	Stats []APIProposalStats `json:"stats"`
+}
+
+// GetProposalStats returns, for the blocks accepted since this node started,
+// how often each validator had the first proposer window, how many of the
+// blocks it proposed and how many of its windows it missed.
+func (service *Service) GetProposalStats(_ *http.Request, args *GetProposalStatsArgs, reply *GetProposalStatsReply) error {
+	vm := service.vm
+	vm.ctx.Log.Debug("proposervm: GetProposalStats called with %d node IDs", len(args.NodeIDs))
+
+	nodeIDs := make([]ids.ShortID, 0, len(args.NodeIDs))
+	for _, nodeIDStr := range args.NodeIDs {
+		nodeID, err := ids.ShortFromPrefixedString(nodeIDStr, constants.NodeIDPrefix)
+		if err != nil {
+			return fmt.Errorf("couldn't parse node ID %q: %w", nodeIDStr, err)
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	if len(args.NodeIDs) == 0 {
+		for nodeID := range vm.proposals.stats {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+
+	reply.Stats = make([]APIProposalStats, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		stats := ProposalStats{}
+		if tracked, ok := vm.proposals.stats[nodeID]; ok {
+			stats = *tracked
+		}
+		reply.Stats[i] = APIProposalStats{
+			NodeID:         nodeID.PrefixedString(constants.NodeIDPrefix),
+			ScheduledFirst: json.Uint64(stats.ScheduledFirst),
+			Proposed:       json.Uint64(stats.Proposed),
+			MissedWindows:  json.Uint64(stats.MissedWindows),
+		}
+	}
+	sort.Slice(reply.Stats, func(i, j int) bool {
+		return reply.Stats[i].NodeID < reply.Stats[j].NodeID
+	})
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/validation"
+	"github.com/flare-foundation/flare/utils/constants"
+	"github.com/flare-foundation/flare/utils/json"
+	"github.com/flare-foundation/flare/vms/proposervm/proposer"
+)
+
+func TestServiceGetProposerSchedule(t *testing.T) {
+	_, _, _, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	service := &Service{vm: proVM}
+
+	reply := GetProposerScheduleReply{}
+	err := service.GetProposerSchedule(nil, &GetProposerScheduleArgs{}, &reply)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, reply.Height)
+	assert.Equal(t, coreGenBlk.ID(), reply.ParentID)
+	assert.Equal(t, proposer.WeightedPolicy, reply.Policy)
+	assert.Equal(t, genesisTimestamp.Add(proposer.MaxDelay), reply.AnyoneAfter)
+	require.Len(t, reply.Proposers, proposer.MaxWindows)
+	assert.Equal(t, genesisTimestamp, reply.Proposers[0].StartTime)
+
+	// The window of this node matches the delay the windower enforces
+	delay, err := proVM.Delay(1, genesisTimestamp, coreGenBlk.ID(), proVM.ctx.NodeID)
+	require.NoError(t, err)
+	nodeID := proVM.ctx.NodeID.PrefixedString(constants.NodeIDPrefix)
+	for _, window := range reply.Proposers {
+		if window.NodeID == nodeID {
+			assert.Equal(t, genesisTimestamp.Add(delay), window.StartTime)
+			break
+		}
+	}
+
+	height := json.Uint64(2)
+	err = service.GetProposerSchedule(nil, &GetProposerScheduleArgs{Height: &height}, &reply)
+	assert.ErrorIs(t, err, errHeightMismatch)
+}
+
+func TestServiceGetBlockProposer(t *testing.T) {
+	coreVM, _, retriever, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	service := &Service{vm: proVM}
+
+	// This node is the only proposer
+	retriever.GetValidatorsByBlockIDFunc = func(blockID ids.ID) (validation.Set, error) {
+		s := validation.NewSet()
+		_ = s.AddWeight(proVM.ctx.NodeID, 10)
+		return s, nil
+	}
+
+	// The first post-fork block is never signed
+	coreBlk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(111),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: genesisTimestamp,
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk1, nil }
+	proVM.Set(genesisTimestamp)
+
+	builtBlk1, err := proVM.BuildBlock()
+	require.NoError(t, err)
+	require.NoError(t, builtBlk1.Verify())
+	require.NoError(t, proVM.SetPreference(builtBlk1.ID()))
+
+	reply := GetBlockProposerReply{}
+	err = service.GetBlockProposer(nil, &GetBlockProposerArgs{BlockID: builtBlk1.ID()}, &reply)
+	require.NoError(t, err)
+	assert.Equal(t, builtBlk1.ID(), reply.BlockID)
+	assert.EqualValues(t, 1, reply.Height)
+	assert.Empty(t, reply.Proposer)
+
+	// A block built within this node's window is signed by this node
+	delay, err := proVM.Delay(2, builtBlk1.Timestamp(), coreBlk1.ID(), proVM.ctx.NodeID)
+	require.NoError(t, err)
+	proVM.Set(builtBlk1.Timestamp().Add(delay))
+
+	coreBlk2 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(222),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{2},
+		ParentV:    coreBlk1.ID(),
+		HeightV:    coreBlk1.Height() + 1,
+		TimestampV: builtBlk1.Timestamp().Add(delay),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk2, nil }
+
+	builtBlk2, err := proVM.BuildBlock()
+	require.NoError(t, err)
+	require.NoError(t, builtBlk2.Verify())
+
+	err = service.GetBlockProposer(nil, &GetBlockProposerArgs{BlockID: builtBlk2.ID()}, &reply)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, reply.Height)
+	assert.Equal(t, proVM.ctx.NodeID.PrefixedString(constants.NodeIDPrefix), reply.Proposer)
+
+	err = service.GetBlockProposer(nil, &GetBlockProposerArgs{BlockID: coreGenBlk.ID()}, &reply)
+	assert.Error(t, err)
+}
+
+func TestServiceGetProposalStats(t *testing.T) {
+	_, _, _, _, proVM, _, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	service := &Service{vm: proVM}
+
+	nodeID0 := ids.ShortID{1}
+	nodeID1 := ids.ShortID{2}
+	proVM.proposals.record([]ids.ShortID{nodeID1, nodeID0}, nodeID0)
+
+	reply := GetProposalStatsReply{}
+	require.NoError(t, service.GetProposalStats(nil, &GetProposalStatsArgs{}, &reply))
+	assert.Equal(t, []APIProposalStats{
+		{
+			NodeID:   nodeID0.PrefixedString(constants.NodeIDPrefix),
+			Proposed: 1,
+		},
+		{
+			NodeID:         nodeID1.PrefixedString(constants.NodeIDPrefix),
+			ScheduledFirst: 1,
+			MissedWindows:  1,
+		},
+	}, reply.Stats)
+
+	// Validators that weren't scheduled yet have empty stats
+	untracked := ids.ShortID{3}.PrefixedString(constants.NodeIDPrefix)
+	args := &GetProposalStatsArgs{NodeIDs: []string{untracked}}
+	require.NoError(t, service.GetProposalStats(nil, args, &reply))
+	assert.Equal(t, []APIProposalStats{{NodeID: untracked}}, reply.Stats)
+
+	args = &GetProposalStatsArgs{NodeIDs: []string{"invalid"}}
+	assert.Error(t, service.GetProposalStats(nil, args, &reply))
+}
@@ -0,0 +1,19 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"github.com/flare-foundation/flare/database/inspect"
+)
+
+// AddLayout adds the prefixes of the state to [layout], where [db] is the
+// prefix of the versiondb given to [New].
+func AddLayout(layout *inspect.Layout, chain string, db inspect.Prefix) {
+	layout.Add(chain, "proposervm/chain", db.New(chainStatePrefix))
+	layout.Add(chain, "proposervm/blocks", db.New(blockStatePrefix))
+
+	heightDB := db.New(heightIndexPrefix)
+	layout.Add(chain, "proposervm/height index", heightDB.New(heightPrefix))
+	layout.Add(chain, "proposervm/height index metadata", heightDB.New(metadataPrefix))
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flare-foundation/flare/database"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/vms/proposervm/summary"
+)
+
+var (
+	_ block.StateSyncableVM = &VM{}
+
+	errUnexpectedSummaryBlock = errors.New("state summary block isn't a signed block")
+)
+
+// stateSummary wraps the summary of the inner VM with the proposervm block at
+// its height, so that the proposervm chain can be synced along with the inner
+// chain.
+type stateSummary struct {
+	summary.StateSummary
+
+	innerSummary block.StateSummary
+	// block is nil if the summary height is before the fork height
+	block *postForkBlock
+	vm    *VM
+}
+
+func (s *stateSummary) Height() uint64 { return s.innerSummary.Height() }
+
+// Accept marks the proposervm block of the summary as the last accepted block
+// before handing the inner summary to the inner VM. The validators are updated
+// once the inner VM is done syncing, either when Accept returns or, if it
+// syncs in the background, when bootstrapping starts.
+func (s *stateSummary) Accept() (bool, error) {
+	if s.block != nil {
+		if err := s.vm.acceptSummaryBlock(s.block); err != nil {
+			return false, err
+		}
+	}
+
+	syncing, err := s.innerSummary.Accept()
+	if err != nil {
+		return false, err
+	}
+	if syncing {
+		s.vm.stateSyncing = true
+		return true, nil
+	}
+	return false, s.vm.updateValidatorsToLastAccepted()
+}
+
+// acceptSummaryBlock persists [blk] as the last accepted block, along with its
+// height index entry and the fork height if it isn't set yet. Everything is
+// committed at once, so that a crash can't leave the state partially written.
+func (vm *VM) acceptSummaryBlock(blk *postForkBlock) error {
+	defer vm.db.Abort()
+
+	blkID := blk.ID()
+	if err := vm.State.SetLastAccepted(blkID); err != nil {
+		return err
+	}
+	blk.setStatus(choices.Accepted)
+	if err := vm.State.PutBlock(blk.getStatelessBlk(), choices.Accepted); err != nil {
+		return err
+	}
+	if err := vm.updateHeightIndex(blk.Height(), blkID); err != nil {
+		return err
+	}
+	if err := vm.db.Commit(); err != nil {
+		return err
+	}
+
+	vm.lastAcceptedTime = blk.Timestamp()
+	return nil
+}
+
+func (vm *VM) StateSyncEnabled() (bool, error) {
+	innerSSVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return false, nil
+	}
+	return innerSSVM.StateSyncEnabled()
+}
+
+func (vm *VM) GetOngoingSyncStateSummary() (block.StateSummary, error) {
+	innerSSVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	innerSummary, err := innerSSVM.GetOngoingSyncStateSummary()
+	if err != nil {
+		return nil, err
+	}
+	return vm.buildStateSummary(innerSummary)
+}
+
+func (vm *VM) GetLastStateSummary() (block.StateSummary, error) {
+	innerSSVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	innerSummary, err := innerSSVM.GetLastStateSummary()
+	if err != nil {
+		return nil, err
+	}
+	return vm.buildStateSummary(innerSummary)
+}
+
+func (vm *VM) ParseStateSummary(summaryBytes []byte) (block.StateSummary, error) {
+	innerSSVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	statelessSummary, err := summary.Parse(summaryBytes)
+	if err != nil {
+		return nil, err
+	}
+	innerSummary, err := innerSSVM.ParseStateSummary(statelessSummary.InnerSummaryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	s := &stateSummary{
+		StateSummary: statelessSummary,
+		innerSummary: innerSummary,
+		vm:           vm,
+	}
+	if blockBytes := statelessSummary.BlockBytes(); len(blockBytes) != 0 {
+		parsedBlk, err := vm.parsePostForkBlock(blockBytes)
+		if err != nil {
+			return nil, err
+		}
+		// The timestamp of an option is the one of its parent, which isn't
+		// synced, so summaries are only built at the height of signed blocks.
+		blk, ok := parsedBlk.(*postForkBlock)
+		if !ok {
+			return nil, errUnexpectedSummaryBlock
+		}
+		if blk.Height() != innerSummary.Height() {
+			return nil, fmt.Errorf("summary block height %d doesn't match the summary height %d", blk.Height(), innerSummary.Height())
+		}
+		s.block = blk
+	}
+	return s, nil
+}
+
+func (vm *VM) GetStateSummary(height uint64) (block.StateSummary, error) {
+	innerSSVM, ok := vm.ChainVM.(block.StateSyncableVM)
+	if !ok {
+		return nil, block.ErrStateSyncableVMNotImplemented
+	}
+	innerSummary, err := innerSSVM.GetStateSummary(height)
+	if err != nil {
+		return nil, err
+	}
+	return vm.buildStateSummary(innerSummary)
+}
+
+// buildStateSummary wraps [innerSummary] with the proposervm block at its
+// height, if the fork was reached by then.
+func (vm *VM) buildStateSummary(innerSummary block.StateSummary) (block.StateSummary, error) {
+	s := &stateSummary{
+		innerSummary: innerSummary,
+		vm:           vm,
+	}
+
+	height := innerSummary.Height()
+	forkHeight, err := vm.State.GetForkHeight()
+	switch {
+	case err == database.ErrNotFound || (err == nil && height < forkHeight):
+		// The summary is before the fork, there is no proposervm block to
+		// sync.
+	case err != nil:
+		return nil, err
+	default:
+		if !vm.hIndexer.IsRepaired() {
+			return nil, block.ErrIndexIncomplete
+		}
+		blkID, err := vm.State.GetBlockIDAtHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get block at summary height %d: %w", height, err)
+		}
+		blk, err := vm.getPostForkBlock(blkID)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		s.block, ok = blk.(*postForkBlock)
+		if !ok {
+			return nil, block.ErrStateSummaryNotFound
+		}
+	}
+
+	var blockBytes []byte
+	if s.block != nil {
+		blockBytes = s.block.Bytes()
+	}
+	s.StateSummary, err = summary.Build(blockBytes, innerSummary.Bytes())
+	return s, err
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/snow"
+	"github.com/flare-foundation/flare/snow/choices"
+	"github.com/flare-foundation/flare/snow/consensus/snowman"
+	"github.com/flare-foundation/flare/snow/engine/snowman/block"
+	"github.com/flare-foundation/flare/snow/validation"
+	"github.com/flare-foundation/flare/utils/timer/mockable"
+	"github.com/flare-foundation/flare/vms/proposervm/proposer"
+)
+
+var errUnknownSummary = errors.New("unknown summary")
+
+type stateSyncableTestVM struct {
+	*block.TestVM
+	*block.TestStateSyncableVM
+	*validation.TestRetriever
+}
+
+// initStateSyncableTestVM makes the inner VM of [proVM] serve [innerSummary].
+// The returned slice holds the IDs of the blocks the validators are updated
+// to.
+func initStateSyncableTestVM(t *testing.T, coreVM *block.TestVM, proVM *VM, innerSummary *block.TestStateSummary) *[]ids.ID {
+	innerSSVM := &block.TestStateSyncableVM{T: t}
+	innerSSVM.StateSyncEnabledF = func() (bool, error) { return true, nil }
+	innerSSVM.GetLastStateSummaryF = func() (block.StateSummary, error) { return innerSummary, nil }
+	innerSSVM.GetStateSummaryF = func(height uint64) (block.StateSummary, error) {
+		if height != innerSummary.Height() {
+			return nil, block.ErrStateSummaryNotFound
+		}
+		return innerSummary, nil
+	}
+	innerSSVM.ParseStateSummaryF = func(summaryBytes []byte) (block.StateSummary, error) {
+		if !bytes.Equal(summaryBytes, innerSummary.Bytes()) {
+			return nil, errUnknownSummary
+		}
+		return innerSummary, nil
+	}
+	retriever := &validation.TestRetriever{
+		GetValidatorsByBlockIDFunc: func(ids.ID) (validation.Set, error) { return validation.NewSet(), nil },
+	}
+	proVM.ChainVM = stateSyncableTestVM{TestVM: coreVM, TestStateSyncableVM: innerSSVM, TestRetriever: retriever}
+
+	updates := []ids.ID{}
+	proVM.ctx.ValidatorsUpdater = &validation.TestUpdater{
+		UpdateValidatorsFunc: func(blockID ids.ID, _ uint64, _ time.Time) error {
+			updates = append(updates, blockID)
+			return nil
+		},
+	}
+	return &updates
+}
+
+func TestStateSyncNotImplemented(t *testing.T) {
+	assert := assert.New(t)
+	_, _, _, _, proVM, _, _ := initTestProposerVM(t, time.Time{}, 0)
+
+	enabled, err := proVM.StateSyncEnabled()
+	assert.NoError(err)
+	assert.False(enabled)
+
+	_, err = proVM.GetLastStateSummary()
+	assert.ErrorIs(err, block.ErrStateSyncableVMNotImplemented)
+	_, err = proVM.GetStateSummary(1)
+	assert.ErrorIs(err, block.ErrStateSyncableVMNotImplemented)
+}
+
+func TestStateSummaryPreFork(t *testing.T) {
+	assert := assert.New(t)
+	coreVM, _, _, _, proVM, _, _ := initTestProposerVM(t, mockable.MaxTime, 0) // disable ProBlks
+
+	innerSummary := &block.TestStateSummary{
+		IDV:     ids.GenerateTestID(),
+		HeightV: 5,
+		BytesV:  []byte{5},
+		T:       t,
+	}
+	updates := initStateSyncableTestVM(t, coreVM, proVM, innerSummary)
+
+	enabled, err := proVM.StateSyncEnabled()
+	assert.NoError(err)
+	assert.True(enabled)
+
+	summary, err := proVM.GetLastStateSummary()
+	assert.NoError(err)
+	assert.Equal(innerSummary.Height(), summary.Height())
+	assert.Empty(summary.(*stateSummary).BlockBytes())
+
+	parsedSummary, err := proVM.ParseStateSummary(summary.Bytes())
+	assert.NoError(err)
+	assert.Equal(summary.ID(), parsedSummary.ID())
+	assert.Nil(parsedSummary.(*stateSummary).block)
+
+	innerAccepted := false
+	innerSummary.AcceptF = func() (bool, error) {
+		innerAccepted = true
+		return false, nil
+	}
+	syncing, err := parsedSummary.Accept()
+	assert.NoError(err)
+	assert.False(syncing)
+	assert.True(innerAccepted)
+
+	// The inner VM is done syncing, so the validators are updated right away
+	lastAccepted, err := coreVM.LastAccepted()
+	assert.NoError(err)
+	assert.Equal([]ids.ID{lastAccepted}, *updates)
+}
+
+func TestStateSummaryPostFork(t *testing.T) {
+	assert := assert.New(t)
+	coreVM, _, _, _, proVM, coreGenBlk, _ := initTestProposerVM(t, time.Time{}, 0) // enable ProBlks
+	proVM.hIndexer.MarkRepaired()
+
+	coreBlk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(111),
+			StatusV: choices.Processing,
+		},
+		BytesV:     []byte{1},
+		ParentV:    coreGenBlk.ID(),
+		HeightV:    coreGenBlk.Height() + 1,
+		TimestampV: coreGenBlk.Timestamp().Add(proposer.MaxDelay),
+	}
+	coreVM.BuildBlockF = func() (snowman.Block, error) { return coreBlk, nil }
+	coreVM.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(b, coreBlk.Bytes()):
+			return coreBlk, nil
+		case bytes.Equal(b, coreGenBlk.Bytes()):
+			return coreGenBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	proBlk, err := proVM.BuildBlock()
+	assert.NoError(err)
+	assert.NoError(proBlk.Verify())
+	assert.NoError(proBlk.Accept())
+
+	innerSummary := &block.TestStateSummary{
+		IDV:     ids.GenerateTestID(),
+		HeightV: proBlk.Height(),
+		BytesV:  []byte{1},
+		T:       t,
+	}
+	updates := initStateSyncableTestVM(t, coreVM, proVM, innerSummary)
+
+	summary, err := proVM.GetStateSummary(proBlk.Height())
+	assert.NoError(err)
+	assert.Equal(proBlk.Bytes(), summary.(*stateSummary).BlockBytes())
+
+	parsedSummary, err := proVM.ParseStateSummary(summary.Bytes())
+	assert.NoError(err)
+	assert.Equal(summary.ID(), parsedSummary.ID())
+	assert.Equal(proBlk.ID(), parsedSummary.(*stateSummary).block.ID())
+
+	innerSummary.AcceptF = func() (bool, error) { return true, nil }
+	syncing, err := parsedSummary.Accept()
+	assert.NoError(err)
+	assert.True(syncing)
+
+	lastAccepted, err := proVM.LastAccepted()
+	assert.NoError(err)
+	assert.Equal(proBlk.ID(), lastAccepted)
+	indexedID, err := proVM.State.GetBlockIDAtHeight(proBlk.Height())
+	assert.NoError(err)
+	assert.Equal(proBlk.ID(), indexedID)
+
+	// The validators are updated once the inner VM is done syncing in the
+	// background
+	assert.Empty(*updates)
+	coreVM.SetStateF = func(snow.State) error { return nil }
+	coreVM.LastAcceptedF = func() (ids.ID, error) { return coreBlk.ID(), nil }
+	coreVM.GetBlockF = func(ids.ID) (snowman.Block, error) { return coreBlk, nil }
+	assert.NoError(proVM.SetState(snow.Bootstrapping))
+	assert.Equal([]ids.ID{coreBlk.ID()}, *updates)
+	assert.NoError(proVM.SetState(snow.NormalOp))
+	assert.Len(*updates, 1)
+
+	_, err = proVM.ParseStateSummary([]byte{0, 0, 1})
+	assert.Error(err)
+}
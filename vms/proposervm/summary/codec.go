@@ -0,0 +1,21 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package summary
+
+import (
+	"github.com/flare-foundation/flare/codec"
+	"github.com/flare-foundation/flare/codec/linearcodec"
+)
+
+const version = 0
+
+var c codec.Manager
+
+func init() {
+	lc := linearcodec.NewDefault()
+	c = codec.NewDefaultManager()
+	if err := c.RegisterCodec(version, lc); err != nil {
+		panic(err)
+	}
+}
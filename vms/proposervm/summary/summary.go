@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package summary
+
+import (
+	"fmt"
+
+	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/utils/hashing"
+)
+
+// StateSummary is the state summary of a chain run by the proposervm. It
+// carries the proposervm block at the summary height along with the summary
+// of the inner VM.
+type StateSummary interface {
+	ID() ids.ID
+	// BlockBytes is empty if the summary height is before the fork height
+	BlockBytes() []byte
+	InnerSummaryBytes() []byte
+	Bytes() []byte
+}
+
+type stateSummary struct {
+	Block        []byte `serialize:"true"`
+	InnerSummary []byte `serialize:"true"`
+
+	id    ids.ID
+	bytes []byte
+}
+
+func (s *stateSummary) ID() ids.ID                { return s.id }
+func (s *stateSummary) BlockBytes() []byte        { return s.Block }
+func (s *stateSummary) InnerSummaryBytes() []byte { return s.InnerSummary }
+func (s *stateSummary) Bytes() []byte             { return s.bytes }
+
+func (s *stateSummary) initialize(bytes []byte) {
+	s.id = hashing.ComputeHash256Array(bytes)
+	s.bytes = bytes
+}
+
+func Build(blockBytes, innerSummaryBytes []byte) (StateSummary, error) {
+	summary := &stateSummary{
+		Block:        blockBytes,
+		InnerSummary: innerSummaryBytes,
+	}
+	bytes, err := c.Marshal(version, summary)
+	if err != nil {
+		return nil, err
+	}
+	summary.initialize(bytes)
+	return summary, nil
+}
+
+func Parse(bytes []byte) (StateSummary, error) {
+	summary := &stateSummary{}
+	parsedVersion, err := c.Unmarshal(bytes, summary)
+	if err != nil {
+		return nil, err
+	}
+	if parsedVersion != version {
+		return nil, fmt.Errorf("expected codec version %d but got %d", version, parsedVersion)
+	}
+	summary.initialize(bytes)
+	return summary, nil
+}
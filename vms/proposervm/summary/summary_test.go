@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package summary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	blockBytes := []byte{1, 2, 3}
+	innerSummaryBytes := []byte{4, 5}
+
+	builtSummary, err := Build(blockBytes, innerSummaryBytes)
+	assert.NoError(err)
+	assert.Equal(blockBytes, builtSummary.BlockBytes())
+	assert.Equal(innerSummaryBytes, builtSummary.InnerSummaryBytes())
+
+	parsedSummary, err := Parse(builtSummary.Bytes())
+	assert.NoError(err)
+	assert.Equal(builtSummary.ID(), parsedSummary.ID())
+	assert.Equal(builtSummary.Bytes(), parsedSummary.Bytes())
+	assert.Equal(blockBytes, parsedSummary.BlockBytes())
+	assert.Equal(innerSummaryBytes, parsedSummary.InnerSummaryBytes())
+}
+
+func TestParsePreFork(t *testing.T) {
+	assert := assert.New(t)
+
+	builtSummary, err := Build(nil, []byte{1})
+	assert.NoError(err)
+
+	parsedSummary, err := Parse(builtSummary.Bytes())
+	assert.NoError(err)
+	assert.Empty(parsedSummary.BlockBytes())
+	assert.Equal(builtSummary.ID(), parsedSummary.ID())
+}
+
+func TestParseGarbage(t *testing.T) {
+	_, err := Parse([]byte{0, 0, 1})
+	assert.Error(t, err)
+}
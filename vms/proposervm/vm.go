@@ -8,11 +8,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/gorilla/rpc/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/flare-foundation/flare/api/metrics"
 	"github.com/flare-foundation/flare/database"
 	"github.com/flare-foundation/flare/database/manager"
 	"github.com/flare-foundation/flare/database/prefixdb"
 	"github.com/flare-foundation/flare/database/versiondb"
 	"github.com/flare-foundation/flare/ids"
+	"github.com/flare-foundation/flare/pubsub"
 	"github.com/flare-foundation/flare/snow"
 	"github.com/flare-foundation/flare/snow/choices"
 	"github.com/flare-foundation/flare/snow/consensus/snowman"
@@ -20,6 +25,7 @@ import (
 	"github.com/flare-foundation/flare/snow/engine/snowman/block"
 	"github.com/flare-foundation/flare/snow/validation"
 	"github.com/flare-foundation/flare/utils"
+	"github.com/flare-foundation/flare/utils/json"
 	"github.com/flare-foundation/flare/utils/math"
 	"github.com/flare-foundation/flare/utils/timer/mockable"
 	"github.com/flare-foundation/flare/vms/proposervm/indexer"
@@ -37,6 +43,10 @@ const (
 	minBlockDelay                = time.Second
 	checkIndexedFrequency        = 10 * time.Second
 	optimalHeightDelay    uint64 = 256
+
+	// proposerEndpoint is the extension of the chain's endpoint under which
+	// the proposervm API is served
+	proposerEndpoint = "/proposervm"
 )
 
 var (
@@ -51,11 +61,18 @@ type VM struct {
 	block.ChainVM
 	activationTime      time.Time
 	minimumPChainHeight uint64
+	windowingPolicies   []proposer.Policy
 
 	state.State
 	resetHeightIndexOngoing utils.AtomicBool
 	hIndexer                indexer.HeightIndexer
 
+	// Tracks the scheduled and actual proposers of accepted blocks
+	proposals *proposalTracker
+
+	// Publishes accepted blocks to websocket subscribers
+	pubsub *pubsub.Server
+
 	proposer.Windower
 	tree.Tree
 	scheduler.Scheduler
@@ -71,8 +88,11 @@ type VM struct {
 	verifiedBlocks map[ids.ID]PostForkBlock
 	preferred      ids.ID
 	bootstrapped   bool
-	context        context.Context
-	onShutdown     func()
+	// stateSyncing is true while the inner VM syncs to an accepted state
+	// summary in the background
+	stateSyncing bool
+	context      context.Context
+	onShutdown   func()
 
 	// lastAcceptedOptionTime is set to the last accepted PostForkBlock's
 	// timestamp if the last accepted block has been a PostForkOption block
@@ -85,11 +105,13 @@ func New(
 	activationTime time.Time,
 	minimumPChainHeight uint64,
 	resetHeightIndex bool,
+	windowingPolicies []proposer.Policy,
 ) *VM {
 	proVM := &VM{
 		ChainVM:             vm,
 		activationTime:      activationTime,
 		minimumPChainHeight: minimumPChainHeight,
+		windowingPolicies:   windowingPolicies,
 	}
 
 	proVM.resetHeightIndexOngoing.SetValue(resetHeightIndex)
@@ -111,8 +133,29 @@ func (vm *VM) Initialize(
 	prefixDB := prefixdb.New(dbPrefix, rawDB)
 	vm.db = versiondb.New(prefixDB)
 	vm.State = state.New(vm.db)
-	vm.Windower = proposer.New(ctx.ValidatorsRetriever, ctx.ChainID)
+	vm.Windower = proposer.New(ctx.ValidatorsRetriever, ctx.ChainID, vm.windowingPolicies...)
 	vm.Tree = tree.New()
+	vm.pubsub = pubsub.New(ctx.NetworkID, ctx.Log)
+
+	registerer := prometheus.NewRegistry()
+	proposals, err := newProposalTracker("", registerer)
+	if err != nil {
+		return err
+	}
+	vm.proposals = proposals
+
+	optionalGatherer := metrics.NewOptionalGatherer()
+	multiGatherer := metrics.NewMultiGatherer()
+	if err := multiGatherer.Register("proposervm", registerer); err != nil {
+		return err
+	}
+	if err := multiGatherer.Register("", optionalGatherer); err != nil {
+		return err
+	}
+	if err := ctx.Metrics.Register(multiGatherer); err != nil {
+		return err
+	}
+	ctx.Metrics = optionalGatherer
 
 	indexerDB := versiondb.New(vm.db)
 	indexerState := state.New(indexerDB)
@@ -131,7 +174,7 @@ func (vm *VM) Initialize(
 	vm.context = context
 	vm.onShutdown = cancel
 
-	err := vm.ChainVM.Initialize(
+	err = vm.ChainVM.Initialize(
 		ctx,
 		dbManager,
 		genesisBytes,
@@ -153,16 +196,8 @@ func (vm *VM) Initialize(
 		return err
 	}
 
-	if _, ok := vm.ChainVM.(validation.Retriever); ok {
-		lastAcceptedID, err := vm.ChainVM.LastAccepted()
-		if err != nil {
-			return fmt.Errorf("could not get last accepted: %w", err)
-		}
-		err = vm.ctx.ValidatorsUpdater.UpdateValidators(lastAcceptedID)
-		if err != nil {
-			return fmt.Errorf("could not update validators: %w", err)
-		}
-		vm.ctx.Log.Debug("initialized validators with last accepted block (hash: %s)", lastAcceptedID.Hex())
+	if err := vm.updateValidatorsToLastAccepted(); err != nil {
+		return err
 	}
 
 	// check and possibly rebuild height index
@@ -259,9 +294,46 @@ func (vm *VM) Shutdown() error {
 	return vm.ChainVM.Shutdown()
 }
 
+// CreateHandlers adds the proposervm API to the handlers of the inner VM
+func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
+	handlers, err := vm.ChainVM.CreateHandlers()
+	if err != nil {
+		return nil, err
+	}
+
+	server := rpc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+	if err := server.RegisterService(&Service{vm: vm}, "proposervm"); err != nil {
+		return nil, err
+	}
+
+	if handlers == nil {
+		handlers = make(map[string]*common.HTTPHandler, 1)
+	}
+	handlers[proposerEndpoint] = &common.HTTPHandler{
+		Handler: server,
+	}
+	handlers[proposerEndpoint+eventsEndpoint] = &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     vm.pubsub,
+	}
+	return handlers, nil
+}
+
 func (vm *VM) SetState(state snow.State) error {
 	vm.bootstrapped = (state == snow.NormalOp)
-	return vm.ChainVM.SetState(state)
+	if err := vm.ChainVM.SetState(state); err != nil {
+		return err
+	}
+
+	// Bootstrapping starts once the inner VM is done syncing, so the
+	// validators at the synced state can be retrieved
+	if state == snow.Bootstrapping && vm.stateSyncing {
+		vm.stateSyncing = false
+		return vm.updateValidatorsToLastAccepted()
+	}
+	return nil
 }
 
 func (vm *VM) BuildBlock() (snowman.Block, error) {
@@ -301,7 +373,7 @@ func (vm *VM) SetPreference(preferred ids.ID) error {
 	}
 
 	// reset scheduler
-	minDelay, err := vm.Windower.Delay(blk.Height()+1, innerID, vm.ctx.NodeID)
+	minDelay, err := vm.Windower.Delay(blk.Height()+1, blk.Timestamp(), innerID, vm.ctx.NodeID)
 	if err != nil {
 		vm.ctx.Log.Debug("failed to fetch the expected delay due to: %s", err)
 		// A nil error is returned here because it is possible that
@@ -548,6 +620,28 @@ func (vm *VM) storePostForkBlock(blk PostForkBlock) error {
 	return vm.db.Commit()
 }
 
+// updateValidatorsToLastAccepted sets the validators to the ones at the last
+// accepted block of the inner VM, if it provides validators
+func (vm *VM) updateValidatorsToLastAccepted() error {
+	if _, ok := vm.ChainVM.(validation.Retriever); !ok {
+		return nil
+	}
+	lastAcceptedID, err := vm.ChainVM.LastAccepted()
+	if err != nil {
+		return fmt.Errorf("could not get last accepted: %w", err)
+	}
+	lastAccepted, err := vm.ChainVM.GetBlock(lastAcceptedID)
+	if err != nil {
+		return fmt.Errorf("could not get last accepted block: %w", err)
+	}
+	err = vm.ctx.ValidatorsUpdater.UpdateValidators(lastAcceptedID, lastAccepted.Height(), lastAccepted.Timestamp())
+	if err != nil {
+		return fmt.Errorf("could not update validators: %w", err)
+	}
+	vm.ctx.Log.Debug("updated validators to last accepted block (hash: %s)", lastAcceptedID.Hex())
+	return nil
+}
+
 func (vm *VM) verifyAndRecordInnerBlk(postFork PostForkBlock) error {
 	// If inner block's Verify returned true, don't call it again.
 	//
@@ -104,7 +104,7 @@ func initTestProposerVM(
 		}
 	}
 
-	proVM := New(coreVM, proBlkStartTime, minPChainHeight, false)
+	proVM := New(coreVM, proBlkStartTime, minPChainHeight, false, nil)
 
 	valState := &validation.TestState{
 		T: t,
@@ -122,7 +122,7 @@ func initTestProposerVM(
 		},
 	}
 	updater := &validation.TestUpdater{
-		UpdateValidatorsFunc: func(blockID ids.ID) error {
+		UpdateValidatorsFunc: func(blockID ids.ID, height uint64, timestamp time.Time) error {
 			return nil
 		},
 	}
@@ -849,7 +849,7 @@ func TestExpiredBuildBlock(t *testing.T) {
 		}
 	}
 
-	proVM := New(coreVM, time.Time{}, 0, false)
+	proVM := New(coreVM, time.Time{}, 0, false, nil)
 
 	valState := &validation.TestState{
 		T: t,
@@ -857,7 +857,7 @@ func TestExpiredBuildBlock(t *testing.T) {
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
 
 	updater := &validation.TestUpdater{
-		UpdateValidatorsFunc: func(blockID ids.ID) error {
+		UpdateValidatorsFunc: func(blockID ids.ID, height uint64, timestamp time.Time) error {
 			return nil
 		},
 	}
@@ -1142,7 +1142,7 @@ func TestInnerVMRollback(t *testing.T) {
 	valState.GetCurrentHeightF = func() (uint64, error) { return defaultPChainHeight, nil }
 
 	updater := &validation.TestUpdater{
-		UpdateValidatorsFunc: func(blockID ids.ID) error {
+		UpdateValidatorsFunc: func(blockID ids.ID, height uint64, timestamp time.Time) error {
 			return nil
 		},
 	}
@@ -1198,7 +1198,7 @@ func TestInnerVMRollback(t *testing.T) {
 
 	dbManager := manager.NewMemDB(version.DefaultVersion1_0_0)
 
-	proVM := New(coreVM, time.Time{}, 0, false)
+	proVM := New(coreVM, time.Time{}, 0, false, nil)
 
 	if err := proVM.Initialize(ctx, dbManager, nil, nil, nil, nil, nil, nil); err != nil {
 		t.Fatalf("failed to initialize proposerVM with %s", err)
@@ -1280,7 +1280,7 @@ func TestInnerVMRollback(t *testing.T) {
 
 	coreBlk.StatusV = choices.Processing
 
-	proVM = New(coreVM, time.Time{}, 0, false)
+	proVM = New(coreVM, time.Time{}, 0, false, nil)
 
 	if err := proVM.Initialize(ctx, dbManager, nil, nil, nil, nil, nil, nil); err != nil {
 		t.Fatalf("failed to initialize proposerVM with %s", err)